@@ -0,0 +1,70 @@
+// Command import-journal brings the reports table back in line with the TXT report journal.
+//
+//	go run ./cmd/import-journal -from 2024-03-01 -to 2024-03-31 -dry-run
+//
+// The database and journal folder come from the same environment or .env as the server. Reports missing
+// from the table are inserted, deletions that never reached it are applied and newer journal versions
+// replace older rows. With -dry-run it only prints what differs and exits with status 1 when anything would
+// change; otherwise it exits with status 1 when a report could not be written.
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/report"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/usecase"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		fail("%v", err)
+	}
+
+	dir := flag.String("dir", cfg.StaticPath, "folder with the YYYY-MM-DD.txt report files")
+	from := flag.String("from", "", "first day to import, YYYY-MM-DD (defaults to the oldest file)")
+	to := flag.String("to", "", "last day to import, YYYY-MM-DD (defaults to today)")
+	dryRun := flag.Bool("dry-run", false, "only print what differs")
+	flag.Parse()
+	cfg.StaticPath = *dir
+
+	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database)
+	db, err := sql.Open(cfg.Driver, psqlInfo)
+	if err != nil {
+		fail("%v", err)
+	}
+	defer db.Close()
+
+	// the import writes the table only, the sink is never called
+	reportRepository := repository.NewReportRepository(db, report.NewReportToTXT(cfg.PathConfig))
+	importer := usecase.NewJournalImportUsecase(reportRepository, report.NewJournalReader(cfg.PathConfig))
+
+	result, err := importer.Import(*from, *to, *dryRun)
+	if err != nil {
+		fail("%v", err)
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(output))
+	pending := 0
+	for _, diff := range append(result.Missing, result.Divergent...) {
+		if diff.Apply {
+			pending++
+		}
+	}
+	if len(result.Failed) > 0 || (*dryRun && pending > 0) {
+		os.Exit(1)
+	}
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(2)
+}
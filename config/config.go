@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -30,7 +31,55 @@ type TokenConfig struct {
 }
 
 type PathConfig struct {
-	StaticPath string `json:"static_path"`
+	StaticPath  string   `json:"static_path"`
+	ReportSinks []string `json:"report_sinks"`
+	ReportFsync bool     `json:"report_fsync"`
+	// ReportHmacKey signs every report journal entry when set
+	ReportHmacKey []byte `json:"-"`
+}
+
+type TrashConfig struct {
+	RetentionDays int           `json:"retention_days"`
+	PurgeInterval time.Duration `json:"purge_interval"`
+}
+
+type ReminderConfig struct {
+	ReminderDaysBefore    int           `json:"reminder_days_before"`
+	ReminderEscalateAfter int           `json:"reminder_escalate_after"`
+	ReminderInterval      time.Duration `json:"reminder_interval"`
+}
+
+type MailConfig struct {
+	MailDriver         string        `json:"mail_driver"`
+	SmtpHost           string        `json:"smtp_host"`
+	SmtpPort           string        `json:"smtp_port"`
+	SmtpUser           string        `json:"smtp_user"`
+	SmtpPassword       string        `json:"smtp_password"`
+	MailFrom           string        `json:"mail_from"`
+	MailOutboxDir      string        `json:"mail_outbox_dir"`
+	MailDefaultMode    string        `json:"mail_default_mode"`
+	MailSendInterval   time.Duration `json:"mail_send_interval"`
+	MailDigestInterval time.Duration `json:"mail_digest_interval"`
+}
+
+type WebhookConfig struct {
+	WebhookInterval    time.Duration `json:"webhook_interval"`
+	WebhookMaxAttempts int           `json:"webhook_max_attempts"`
+	WebhookTimeout     time.Duration `json:"webhook_timeout"`
+}
+
+type StreamConfig struct {
+	StreamHistorySize int `json:"stream_history_size"`
+}
+
+type StatusReportConfig struct {
+	StatusReportInterval time.Duration `json:"status_report_interval"`
+}
+
+type ReportArchiveConfig struct {
+	ReportArchiveAfterDays int           `json:"report_archive_after_days"`
+	ReportMaxAgeDays       int           `json:"report_max_age_days"`
+	ReportArchiveInterval  time.Duration `json:"report_archive_interval"`
 }
 
 type Config struct {
@@ -38,6 +87,13 @@ type Config struct {
 	ApiConfig
 	TokenConfig
 	PathConfig
+	TrashConfig
+	ReminderConfig
+	MailConfig
+	WebhookConfig
+	StreamConfig
+	StatusReportConfig
+	ReportArchiveConfig
 }
 
 func (c *Config) ConfigConfiguration() error {
@@ -75,6 +131,137 @@ func (c *Config) ConfigConfiguration() error {
 	if c.PathConfig.StaticPath == "" {
 		return fmt.Errorf("missing requirement FILE_PATH in .env ")
 	}
+	//config report sinks, a comma separated list of txt, jsonl, csv and md
+	c.ReportSinks = strings.Split(os.Getenv("REPORT_SINKS"), ",")
+	if os.Getenv("REPORT_SINKS") == "" {
+		c.ReportSinks = []string{"txt"}
+	}
+	//REPORT_FSYNC=false skips syncing report files to disk after every entry
+	c.ReportFsync = os.Getenv("REPORT_FSYNC") != "false"
+	c.ReportHmacKey = []byte(os.Getenv("REPORT_HMAC_KEY"))
+
+	//config trash retention, soft-deleted rows older than this are purged
+	retentionDays, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || retentionDays <= 0 {
+		retentionDays = 30
+	}
+	purgeInterval, err := strconv.Atoi(os.Getenv("TRASH_PURGE_INTERVAL"))
+	if err != nil || purgeInterval <= 0 {
+		purgeInterval = 24
+	}
+	c.TrashConfig = TrashConfig{
+		RetentionDays: retentionDays,
+		PurgeInterval: time.Duration(purgeInterval) * time.Hour,
+	}
+
+	//config deadline reminders, projects without their own setting use these days
+	daysBefore, err := strconv.Atoi(os.Getenv("REMINDER_DAYS_BEFORE"))
+	if err != nil || daysBefore < 0 {
+		daysBefore = 1
+	}
+	escalateAfter, err := strconv.Atoi(os.Getenv("REMINDER_ESCALATE_AFTER_DAYS"))
+	if err != nil || escalateAfter < 0 {
+		escalateAfter = 2
+	}
+	reminderInterval, err := strconv.Atoi(os.Getenv("REMINDER_INTERVAL"))
+	if err != nil || reminderInterval <= 0 {
+		reminderInterval = 1
+	}
+	c.ReminderConfig = ReminderConfig{
+		ReminderDaysBefore:    daysBefore,
+		ReminderEscalateAfter: escalateAfter,
+		ReminderInterval:      time.Duration(reminderInterval) * time.Hour,
+	}
+
+	//config email, MAIL_DRIVER=smtp sends through SMTP_HOST, anything else writes .eml files to MAIL_OUTBOX_DIR
+	c.MailConfig = MailConfig{
+		MailDriver:      os.Getenv("MAIL_DRIVER"),
+		SmtpHost:        os.Getenv("SMTP_HOST"),
+		SmtpPort:        os.Getenv("SMTP_PORT"),
+		SmtpUser:        os.Getenv("SMTP_USER"),
+		SmtpPassword:    os.Getenv("SMTP_PASSWORD"),
+		MailFrom:        os.Getenv("MAIL_FROM"),
+		MailOutboxDir:   os.Getenv("MAIL_OUTBOX_DIR"),
+		MailDefaultMode: os.Getenv("MAIL_DEFAULT_MODE"),
+	}
+	if c.MailDriver == "smtp" && c.SmtpHost == "" {
+		return fmt.Errorf("missing requirement SMTP_HOST in .env ")
+	}
+	if c.SmtpPort == "" {
+		c.SmtpPort = "587"
+	}
+	if c.MailFrom == "" {
+		c.MailFrom = "pmh@localhost"
+	}
+	if c.MailOutboxDir == "" {
+		c.MailOutboxDir = "outbox"
+	}
+	if c.MailDefaultMode != "off" && c.MailDefaultMode != "digest" {
+		c.MailDefaultMode = "immediate"
+	}
+	sendInterval, err := strconv.Atoi(os.Getenv("MAIL_SEND_INTERVAL"))
+	if err != nil || sendInterval <= 0 {
+		sendInterval = 1
+	}
+	digestInterval, err := strconv.Atoi(os.Getenv("MAIL_DIGEST_INTERVAL"))
+	if err != nil || digestInterval <= 0 {
+		digestInterval = 24
+	}
+	c.MailSendInterval = time.Duration(sendInterval) * time.Minute
+	c.MailDigestInterval = time.Duration(digestInterval) * time.Hour
+
+	//config webhooks, failed deliveries are retried with backoff until WEBHOOK_MAX_ATTEMPTS
+	webhookInterval, err := strconv.Atoi(os.Getenv("WEBHOOK_INTERVAL"))
+	if err != nil || webhookInterval <= 0 {
+		webhookInterval = 10
+	}
+	webhookMaxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || webhookMaxAttempts <= 0 {
+		webhookMaxAttempts = 8
+	}
+	webhookTimeout, err := strconv.Atoi(os.Getenv("WEBHOOK_TIMEOUT"))
+	if err != nil || webhookTimeout <= 0 {
+		webhookTimeout = 10
+	}
+	c.WebhookConfig = WebhookConfig{
+		WebhookInterval:    time.Duration(webhookInterval) * time.Second,
+		WebhookMaxAttempts: webhookMaxAttempts,
+		WebhookTimeout:     time.Duration(webhookTimeout) * time.Second,
+	}
+
+	//config real-time streams, reconnecting clients can resume from the last STREAM_HISTORY_SIZE events
+	streamHistorySize, err := strconv.Atoi(os.Getenv("STREAM_HISTORY_SIZE"))
+	if err != nil || streamHistorySize <= 0 {
+		streamHistorySize = 1000
+	}
+	c.StreamConfig = StreamConfig{StreamHistorySize: streamHistorySize}
+
+	//config weekly status reports, checked every STATUS_REPORT_INTERVAL hours
+	statusReportInterval, err := strconv.Atoi(os.Getenv("STATUS_REPORT_INTERVAL"))
+	if err != nil || statusReportInterval <= 0 {
+		statusReportInterval = 6
+	}
+	c.StatusReportConfig = StatusReportConfig{StatusReportInterval: time.Duration(statusReportInterval) * time.Hour}
+
+	//config report file retention, day files older than REPORT_ARCHIVE_AFTER_DAYS are zipped per month
+	//and files older than REPORT_MAX_AGE_DAYS are deleted; 0 keeps them forever
+	archiveAfterDays, err := strconv.Atoi(os.Getenv("REPORT_ARCHIVE_AFTER_DAYS"))
+	if err != nil || archiveAfterDays <= 0 {
+		archiveAfterDays = 30
+	}
+	maxAgeDays, err := strconv.Atoi(os.Getenv("REPORT_MAX_AGE_DAYS"))
+	if err != nil || maxAgeDays < 0 {
+		maxAgeDays = 0
+	}
+	archiveInterval, err := strconv.Atoi(os.Getenv("REPORT_ARCHIVE_INTERVAL"))
+	if err != nil || archiveInterval <= 0 {
+		archiveInterval = 24
+	}
+	c.ReportArchiveConfig = ReportArchiveConfig{
+		ReportArchiveAfterDays: archiveAfterDays,
+		ReportMaxAgeDays:       maxAgeDays,
+		ReportArchiveInterval:  time.Duration(archiveInterval) * time.Hour,
+	}
 
 	return nil
 }
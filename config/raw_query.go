@@ -3,22 +3,38 @@ package config
 const (
 	//users
 	DeleteUserById = "UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
-	GetAllUser     = "SELECT id, name, email, role, created_at, updated_at FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2"
-	GetUserByID    = "SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE id = $1 AND deleted_at IS NULL"
-	GetUserByEmail = "SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL"
-	CreateUser     = "INSERT INTO users(name, email, password, role, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, email, password, role, created_at, updated_at"
-	UpdateUser     = "UPDATE users SET name = $2, email = $3, password = $4, role = $5, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING id, name, email, password, role, created_at, updated_at"
+	GetAllUser     = "SELECT id, name, email, role, created_at, updated_at, version FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2"
+	GetUserByID    = "SELECT id, name, email, password, role, created_at, updated_at, version FROM users WHERE id = $1 AND deleted_at IS NULL"
+	GetUserByEmail = "SELECT id, name, email, password, role, created_at, updated_at, version FROM users WHERE email = $1 AND deleted_at IS NULL"
+	CreateUser     = "INSERT INTO users(name, email, password, role, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, email, password, role, created_at, updated_at, version"
+	UpdateUser     = "UPDATE users SET name = $2, email = $3, password = $4, role = $5, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND ($6 = 0 OR version = $6) AND deleted_at IS NULL RETURNING id, name, email, password, role, created_at, updated_at, version"
 	CountAllUser   = "SELECT COUNT(*) FROM users WHERE deleted_at IS NULL"
+	GetUserVersion = "SELECT version FROM users WHERE id = $1 AND deleted_at IS NULL"
+
+	GetUserDeleteImpact         = "SELECT (SELECT COUNT(*) FROM projects WHERE manager_id = $1 AND deleted_at IS NULL), (SELECT COUNT(*) FROM tasks WHERE person_in_charge = $1 AND deleted_at IS NULL), (SELECT COUNT(*) FROM project_members WHERE member_id = $1 AND deleted_at IS NULL)"
+	EnsureReassignedMemberships = "INSERT INTO project_members(member_id, project_id) SELECT DISTINCT $2::uuid, project_id FROM tasks WHERE person_in_charge = $1 AND deleted_at IS NULL ON CONFLICT (member_id, project_id) DO UPDATE SET deleted_at = NULL"
+	ReassignUserTasks           = "UPDATE tasks SET person_in_charge = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE person_in_charge = $1 AND deleted_at IS NULL"
+	ReassignUserProjects        = "UPDATE projects SET manager_id = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE manager_id = $1 AND deleted_at IS NULL"
+	DeleteUserMemberships       = "UPDATE project_members SET deleted_at = CURRENT_TIMESTAMP WHERE member_id = $1 AND deleted_at IS NULL"
 
 	//projects
-	GetAllProject         = "SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT $1 OFFSET $2"
-	GetProjectByID        = "SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND id = $1"
-	GetProjectByManagerID = "SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND manager_id = $1"
-	GetProjectByDeadline  = "SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND deadline = $1"
+	GetAllProject         = "SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT $1 OFFSET $2"
+	GetProjectByID        = "SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND id = $1"
+	GetProjectByManagerID = "SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND manager_id = $1"
+	GetProjectByDeadline  = "SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND deadline = $1"
+
+	CreateProject     = "INSERT INTO projects(name, manager_id, deadline, key, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, manager_id, deadline, created_at, updated_at, version, key"
+	UpdateProject     = "UPDATE projects SET name = $2, manager_id = $3, deadline = $4, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND ($5 = 0 OR version = $5) AND deleted_at IS NULL RETURNING id, name, manager_id, deadline, created_at, updated_at, version, key"
+	DeleteProject     = "UPDATE projects SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	GetProjectVersion = "SELECT version FROM projects WHERE id = $1 AND deleted_at IS NULL"
 
-	CreateProject = "INSERT INTO projects(name, manager_id, deadline, updated_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP) RETURNING id, name, manager_id, deadline, created_at, updated_at"
-	UpdateProject = "UPDATE projects SET name = $2, manager_id = $3, deadline = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING id, name, manager_id, deadline, created_at, updated_at"
-	DeleteProject = "UPDATE projects SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	GetProjectDeleteImpact  = "SELECT (SELECT COUNT(*) FROM tasks WHERE project_id = $1 AND deleted_at IS NULL), (SELECT COUNT(*) FROM reports WHERE deleted_at IS NULL AND task_id IN (SELECT id FROM tasks WHERE project_id = $1 AND deleted_at IS NULL)), (SELECT COUNT(*) FROM project_members WHERE project_id = $1 AND deleted_at IS NULL)"
+	DeleteProjectReports    = "UPDATE reports SET deleted_at = CURRENT_TIMESTAMP WHERE deleted_at IS NULL AND task_id IN (SELECT id FROM tasks WHERE project_id = $1 AND deleted_at IS NULL) RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	DeleteProjectTasks      = "UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at IS NULL"
+	DeleteProjectMembers    = "UPDATE project_members SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at IS NULL"
+	DeleteProjectMilestones = "UPDATE milestones SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at IS NULL"
+	DeleteProjectSprints    = "UPDATE sprints SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at IS NULL"
+	DeleteProjectFields     = "UPDATE custom_field_definitions SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at IS NULL"
 
 	AddProjectMember        = "INSERT INTO project_members(member_id, project_id) VALUES ($1, $2)"
 	GetAllProjectMember     = "SELECT member_id FROM project_members WHERE project_id = $1 AND deleted_at IS NULL"
@@ -26,20 +42,162 @@ const (
 	DeleteProjectMember     = "UPDATE project_members SET deleted_at = CURRENT_TIMESTAMP WHERE member_id = $1 AND project_id = $2"
 
 	//tasks
-	GetAllTask              = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT $1 OFFSET $2"
-	CountAllTask            = "SELECT COUNT(*) FROM tasks WHERE deleted_at IS NULL"
-	GetTaskById             = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE id = $1 AND deleted_at IS NULL"
-	GetTaskByPersonInCharge = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE person_in_charge=$1 AND deleted_at IS NULL"
-	GetTaskByProjectId      = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE project_id=$1 AND deleted_at IS NULL"
-	CreateTask              = "INSERT INTO tasks(name, status, approval, person_in_charge, deadline, project_id, updated_at) VALUES ($1, 'In Progress', false, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, person_in_charge, deadline, project_id, created_at"
-	UpdateTaskByManager     = "UPDATE tasks SET name = $2, status = $3, approval = $4, person_in_charge = $5, deadline = $6, approval_date = CURRENT_TIMESTAMP, feedback = $7, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at"
-	UpdateTaskByMember      = "UPDATE tasks SET status = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND person_in_charge = $2 AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at"
-	DeleteTask              = "UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	GetAllTask                  = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT $1 OFFSET $2"
+	CountAllTask                = "SELECT COUNT(*) FROM tasks WHERE deleted_at IS NULL"
+	GetTaskVersion              = "SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL"
+	GetTaskById                 = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key FROM tasks WHERE id = $1 AND deleted_at IS NULL"
+	GetTaskByKey                = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key FROM tasks WHERE id = (SELECT task_id FROM task_keys WHERE key = $1) AND deleted_at IS NULL"
+	GetTaskByPersonInCharge     = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key FROM tasks WHERE person_in_charge=$1 AND deleted_at IS NULL"
+	GetTaskByProjectId          = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key FROM tasks WHERE project_id=$1 AND deleted_at IS NULL ORDER BY rank, created_at"
+	GetTaskByProjectIdAndFields = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key FROM tasks WHERE project_id=$1 AND custom_fields @> $2::jsonb AND deleted_at IS NULL ORDER BY rank, created_at"
+	CreateTask                  = "INSERT INTO tasks(name, status, approval, person_in_charge, deadline, project_id, story_points, rank, custom_fields, task_key, updated_at) VALUES ($1, 'In Progress', false, $2, $3, $4, COALESCE($5, 0), (SELECT COALESCE(MAX(rank), 0) + 1024 FROM tasks WHERE project_id = $4), COALESCE($6::jsonb, '{}'), $7, CURRENT_TIMESTAMP) RETURNING id, name, person_in_charge, deadline, project_id, created_at, version, story_points, custom_fields, task_key"
+	UpdateTaskByManager         = "UPDATE tasks SET name = $2, status = $3, approval = $4, person_in_charge = $5, deadline = $6, approval_date = CURRENT_TIMESTAMP, feedback = $7, story_points = COALESCE($9, story_points), custom_fields = COALESCE($10::jsonb, custom_fields), updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND ($8 = 0 OR version = $8) AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key"
+	UpdateTaskByMember          = "UPDATE tasks SET status = $3, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND person_in_charge = $2 AND ($4 = 0 OR version = $4) AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key"
+	AllocateTaskKey             = "UPDATE projects SET next_task_number = next_task_number + 1 WHERE id = $1 AND deleted_at IS NULL RETURNING key || '-' || (next_task_number - 1)"
+	InsertTaskKey               = "INSERT INTO task_keys(key, task_id) VALUES ($1, $2)"
+	GetTaskStatus               = "SELECT status FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	MoveTaskToProject           = "UPDATE tasks SET project_id = $2, task_key = $3, milestone_id = NULL, sprint_id = NULL, rank = (SELECT COALESCE(MAX(rank), 0) + 1024 FROM tasks WHERE project_id = $2), custom_fields = (SELECT COALESCE(jsonb_object_agg(f.key, f.value), '{}') FROM jsonb_each(tasks.custom_fields) f WHERE f.key IN (SELECT name FROM custom_field_definitions WHERE project_id = $2 AND deleted_at IS NULL)), updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key"
+	CloneTask                   = "INSERT INTO tasks(name, status, approval, person_in_charge, deadline, project_id, story_points, rank, custom_fields, task_key, updated_at) SELECT COALESCE(NULLIF($3, ''), t.name), 'In Progress', false, t.person_in_charge, t.deadline, $2, CASE WHEN $4 THEN t.story_points ELSE 0 END, (SELECT COALESCE(MAX(rank), 0) + 1024 FROM tasks WHERE project_id = $2), CASE WHEN $5 THEN (SELECT COALESCE(jsonb_object_agg(f.key, f.value), '{}') FROM jsonb_each(t.custom_fields) f WHERE f.key IN (SELECT name FROM custom_field_definitions WHERE project_id = $2 AND deleted_at IS NULL)) ELSE '{}' END, $6, CURRENT_TIMESTAMP FROM tasks t WHERE t.id = $1 AND t.deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key"
+	CloneTaskReports            = "INSERT INTO reports(user_id, report, task_id, standup, created_at, updated_at) SELECT user_id, report, $2, standup, created_at, CURRENT_TIMESTAMP FROM reports WHERE task_id = $1 AND deleted_at IS NULL RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	DeleteTask                  = "UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	InsertTaskStatusHistory     = "INSERT INTO task_status_history(task_id, status) SELECT $1, $2 WHERE $2::task_status IS DISTINCT FROM (SELECT status FROM task_status_history WHERE task_id = $1 ORDER BY id DESC LIMIT 1)"
+
+	// Milestones
+	GetMilestonesByProjectId = "SELECT m.id, m.project_id, m.name, m.description, m.due_date, m.created_at, m.updated_at, COUNT(t.id), COUNT(t.id) FILTER (WHERE t.status = 'Accepted') FROM milestones m LEFT JOIN tasks t ON t.milestone_id = m.id AND t.deleted_at IS NULL WHERE m.project_id = $1 AND m.deleted_at IS NULL GROUP BY m.id ORDER BY m.due_date"
+	GetMilestoneById         = "SELECT m.id, m.project_id, m.name, m.description, m.due_date, m.created_at, m.updated_at, COUNT(t.id), COUNT(t.id) FILTER (WHERE t.status = 'Accepted') FROM milestones m LEFT JOIN tasks t ON t.milestone_id = m.id AND t.deleted_at IS NULL WHERE m.id = $1 AND m.deleted_at IS NULL GROUP BY m.id"
+	CreateMilestone          = "INSERT INTO milestones(project_id, name, description, due_date, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, project_id, name, description, due_date, created_at, updated_at"
+	UpdateMilestone          = "UPDATE milestones SET name = $2, description = $3, due_date = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING id, project_id, name, description, due_date, created_at, updated_at"
+	DeleteMilestone          = "UPDATE milestones SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	DetachMilestoneTasks     = "UPDATE tasks SET milestone_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE milestone_id = $1 AND deleted_at IS NULL"
+	AttachMilestoneTask      = "UPDATE tasks SET milestone_id = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $2 AND deleted_at IS NULL AND project_id = (SELECT project_id FROM milestones WHERE id = $1 AND deleted_at IS NULL)"
+
+	// Custom fields
+	GetCustomFieldsByProjectId = "SELECT id, project_id, name, field_type, options, required, created_at, updated_at FROM custom_field_definitions WHERE project_id = $1 AND deleted_at IS NULL ORDER BY created_at"
+	GetCustomFieldById         = "SELECT id, project_id, name, field_type, options, required, created_at, updated_at FROM custom_field_definitions WHERE id = $1 AND deleted_at IS NULL"
+	CreateCustomField          = "INSERT INTO custom_field_definitions(project_id, name, field_type, options, required, updated_at) VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP) RETURNING id, project_id, name, field_type, options, required, created_at, updated_at"
+	UpdateCustomField          = "UPDATE custom_field_definitions SET options = $2, required = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING id, project_id, name, field_type, options, required, created_at, updated_at"
+	DeleteCustomField          = "UPDATE custom_field_definitions SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING project_id, name"
+	RemoveCustomFieldValues    = "UPDATE tasks SET custom_fields = custom_fields - $2::text, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE project_id = $1 AND custom_fields ? $2"
+
+	// Sprints
+	GetSprintsByProjectId = "SELECT s.id, s.project_id, s.name, s.goal, s.start_date, s.end_date, s.state, s.committed_tasks, s.committed_points, s.completed_tasks, s.completed_points, s.created_at, s.updated_at, COUNT(t.id), COALESCE(SUM(t.story_points), 0), COUNT(t.id) FILTER (WHERE t.status = 'Accepted'), COALESCE(SUM(t.story_points) FILTER (WHERE t.status = 'Accepted'), 0) FROM sprints s LEFT JOIN tasks t ON t.sprint_id = s.id AND t.deleted_at IS NULL WHERE s.project_id = $1 AND s.deleted_at IS NULL GROUP BY s.id ORDER BY s.start_date"
+	GetSprintById         = "SELECT s.id, s.project_id, s.name, s.goal, s.start_date, s.end_date, s.state, s.committed_tasks, s.committed_points, s.completed_tasks, s.completed_points, s.created_at, s.updated_at, COUNT(t.id), COALESCE(SUM(t.story_points), 0), COUNT(t.id) FILTER (WHERE t.status = 'Accepted'), COALESCE(SUM(t.story_points) FILTER (WHERE t.status = 'Accepted'), 0) FROM sprints s LEFT JOIN tasks t ON t.sprint_id = s.id AND t.deleted_at IS NULL WHERE s.id = $1 AND s.deleted_at IS NULL GROUP BY s.id"
+	CreateSprint          = "INSERT INTO sprints(project_id, name, goal, start_date, end_date, updated_at) VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP) RETURNING id, project_id, name, goal, start_date, end_date, state, created_at, updated_at"
+	UpdateSprint          = "UPDATE sprints SET name = $2, goal = $3, start_date = $4, end_date = $5, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND state <> 'closed' AND deleted_at IS NULL RETURNING id, project_id, name, goal, start_date, end_date, state, created_at, updated_at"
+	StartSprint           = "UPDATE sprints SET state = 'active', committed_tasks = (SELECT COUNT(*) FROM tasks WHERE sprint_id = $1 AND deleted_at IS NULL), committed_points = (SELECT COALESCE(SUM(story_points), 0) FROM tasks WHERE sprint_id = $1 AND deleted_at IS NULL), updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND state = 'planned' AND deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM sprints active WHERE active.project_id = sprints.project_id AND active.state = 'active' AND active.deleted_at IS NULL)"
+	CloseSprint           = "UPDATE sprints SET state = 'closed', completed_tasks = (SELECT COUNT(*) FROM tasks WHERE sprint_id = $1 AND status = 'Accepted' AND deleted_at IS NULL), completed_points = (SELECT COALESCE(SUM(story_points), 0) FROM tasks WHERE sprint_id = $1 AND status = 'Accepted' AND deleted_at IS NULL), updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND state = 'active' AND deleted_at IS NULL"
+	RolloverSprintTasks   = "UPDATE tasks SET sprint_id = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE sprint_id = $1 AND status <> 'Accepted' AND deleted_at IS NULL"
+	AddSprintTask         = "UPDATE tasks SET sprint_id = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $2 AND deleted_at IS NULL AND project_id = (SELECT project_id FROM sprints WHERE id = $1 AND state <> 'closed' AND deleted_at IS NULL)"
+	RemoveSprintTask      = "UPDATE tasks SET sprint_id = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $2 AND sprint_id = $1 AND deleted_at IS NULL"
+
+	// Board
+	GetBoardTasks       = "SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key FROM tasks WHERE project_id = $1 AND deleted_at IS NULL ORDER BY rank, created_at"
+	GetColumnRanks      = "SELECT id, rank FROM tasks WHERE project_id = $1 AND status = $2 AND id <> $3 AND deleted_at IS NULL ORDER BY rank, created_at FOR UPDATE"
+	LockBoardColumn     = "SELECT pg_advisory_xact_lock(hashtext(project_id::text || ':' || $2::text)) FROM tasks WHERE id = $1"
+	GetWipColumn        = "SELECT t.status, COALESCE(l.wip_limit, 0), (SELECT COUNT(*) FROM tasks c WHERE c.project_id = t.project_id AND c.status = $2::task_status AND c.id <> t.id AND c.deleted_at IS NULL) FROM tasks t LEFT JOIN board_wip_limits l ON l.project_id = t.project_id AND l.status = $2::task_status WHERE t.id = $1 AND t.deleted_at IS NULL"
+	LockProjectColumn   = "SELECT pg_advisory_xact_lock(hashtext($1::uuid::text || ':' || $2::text))"
+	GetProjectWipColumn = "SELECT COALESCE((SELECT wip_limit FROM board_wip_limits WHERE project_id = $1 AND status = $2::task_status), 0), (SELECT COUNT(*) FROM tasks WHERE project_id = $1 AND status = $2::task_status AND deleted_at IS NULL)"
+	SetTaskRank         = "UPDATE tasks SET rank = $2 WHERE id = $1"
+	MoveTask            = "UPDATE tasks SET status = $2, rank = $3, approval = ($2 = 'Accepted'), approval_date = CASE WHEN $2 = 'Accepted' THEN CURRENT_TIMESTAMP ELSE approval_date END, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND ($4 = 0 OR version = $4) AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE(milestone_id::text, ''), story_points, COALESCE(sprint_id::text, ''), custom_fields, task_key"
+	GetWipLimits        = "SELECT status, wip_limit FROM board_wip_limits WHERE project_id = $1"
+	UpsertWipLimit      = "INSERT INTO board_wip_limits(project_id, status, wip_limit) VALUES ($1, $2, $3) ON CONFLICT (project_id, status) DO UPDATE SET wip_limit = $3"
+	DeleteWipLimit      = "DELETE FROM board_wip_limits WHERE project_id = $1 AND status = $2"
+
+	// Charts
+	GetChartTasksByProjectId = "SELECT t.id, t.story_points, t.created_at, h.status, h.changed_at FROM tasks t LEFT JOIN task_status_history h ON h.task_id = t.id WHERE t.project_id = $1 AND t.deleted_at IS NULL ORDER BY t.created_at, t.id, h.id"
+	GetChartTasksBySprintId  = "SELECT t.id, t.story_points, t.created_at, h.status, h.changed_at FROM tasks t LEFT JOIN task_status_history h ON h.task_id = t.id WHERE t.sprint_id = $1 AND t.deleted_at IS NULL ORDER BY t.created_at, t.id, h.id"
 
 	// Reports
-	CreateReport      = "INSERT INTO reports(user_id, report, task_id, updated_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP) RETURNING id, user_id, report, task_id, created_at, updated_at"
-	DeleteReportById  = "UPDATE reports SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS null"
-	GetReportByUserId = "SELECT id, user_id, report, task_id, created_at, updated_at FROM reports WHERE user_id = $1 AND deleted_at IS null"
-	GetReportByTaskId = "SELECT id, user_id, report, task_id, created_at, updated_at FROM reports WHERE task_id = $1 AND deleted_at IS null"
-	UpdateReport      = "UPDATE reports SET report = $3, task_id = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND deleted_at IS null RETURNING  id, user_id, report, task_id, created_at, updated_at"
+	reportSearchWhere  = "r.deleted_at IS NULL AND t.deleted_at IS NULL AND ($1 = '' OR r.user_id = NULLIF($1, '')::uuid) AND ($2 = '' OR r.task_id = NULLIF($2, '')::uuid) AND ($3 = '' OR t.project_id = NULLIF($3, '')::uuid) AND ($4 = '' OR r.created_at >= $4::date) AND ($5 = '' OR r.created_at < $5::date + 1) AND ($6 = '' OR strpos(lower(r.report), lower($6)) > 0) AND ($7 = '' OR t.project_id IN (SELECT id FROM projects WHERE manager_id = NULLIF($7, '')::uuid))"
+	CreateReport       = "INSERT INTO reports(user_id, report, task_id, standup, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	DeleteReportById   = "UPDATE reports SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS null RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	GetReportByUserId  = "SELECT id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version FROM reports WHERE user_id = $1 AND deleted_at IS null"
+	GetReportByTaskId  = "SELECT id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version FROM reports WHERE task_id = $1 AND deleted_at IS null"
+	SearchReports      = "SELECT r.id, r.user_id, r.report, r.task_id, r.standup, r.review_status, r.review_comment, COALESCE(r.reviewed_by::text, ''), r.reviewed_at, r.created_at, r.updated_at, r.version FROM reports r JOIN tasks t ON t.id = r.task_id WHERE " + reportSearchWhere + " ORDER BY CASE WHEN $8 = 'created_at' AND $9 = 'asc' THEN r.created_at END ASC, CASE WHEN $8 = 'created_at' AND $9 = 'desc' THEN r.created_at END DESC, CASE WHEN $8 = 'updated_at' AND $9 = 'asc' THEN r.updated_at END ASC, CASE WHEN $8 = 'updated_at' AND $9 = 'desc' THEN r.updated_at END DESC, r.id LIMIT $10 OFFSET $11"
+	CountSearchReports = "SELECT COUNT(*) FROM reports r JOIN tasks t ON t.id = r.task_id WHERE " + reportSearchWhere
+	GetReportById      = "SELECT id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version FROM reports WHERE id = $1 AND deleted_at IS null"
+	ReviewReport       = "UPDATE reports SET review_status = $2, review_comment = $3, reviewed_by = $4, reviewed_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS null RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	GetMissingReports  = "SELECT u.id, u.name, u.email, array_agg(t.task_key ORDER BY t.task_key) FROM tasks t JOIN users u ON u.id = t.person_in_charge WHERE t.project_id = $1 AND t.deleted_at IS NULL AND u.deleted_at IS NULL AND t.status IN ('In Progress', 'Blocked', 'Rejected') AND t.created_at < $2::date + 1 AND NOT EXISTS (SELECT 1 FROM reports r JOIN tasks rt ON rt.id = r.task_id WHERE r.user_id = u.id AND rt.project_id = $1 AND r.deleted_at IS NULL AND r.created_at >= $2::date AND r.created_at < $2::date + 1) GROUP BY u.id, u.name, u.email ORDER BY u.name"
+	GetReportsByIds    = "SELECT id, user_id, report, task_id, standup, created_at, updated_at, deleted_at, version FROM reports WHERE id::text = ANY($1)"
+	ImportReport       = "INSERT INTO reports(id, user_id, report, task_id, standup, created_at, updated_at, deleted_at, version) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, GREATEST($9, 1)) ON CONFLICT (id) DO UPDATE SET user_id = $2, report = $3, task_id = $4, standup = $5, updated_at = $7, deleted_at = $8, version = GREATEST($9, 1) WHERE reports.version = $10"
+	GetReportForUpdate = "SELECT id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version FROM reports WHERE id = $1 AND user_id = $2 AND deleted_at IS null FOR UPDATE"
+	UpdateReport       = "UPDATE reports SET report = $3, task_id = $4, standup = $6, review_status = 'submitted', updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND user_id = $2 AND ($5 = 0 OR version = $5) AND deleted_at IS null RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+
+	// Reminders
+	GetReminderSetting    = "SELECT project_id, enabled, days_before, escalate_after_days FROM reminder_settings WHERE project_id = $1"
+	UpsertReminderSetting = "INSERT INTO reminder_settings(project_id, enabled, days_before, escalate_after_days) VALUES ($1, $2, $3, $4) ON CONFLICT (project_id) DO UPDATE SET enabled = $2, days_before = $3, escalate_after_days = $4, updated_at = CURRENT_TIMESTAMP RETURNING project_id, enabled, days_before, escalate_after_days"
+	GetDueTasks           = "SELECT t.id, t.project_id, t.task_key, t.name, t.deadline, t.person_in_charge, p.manager_id, COALESCE(s.days_before, $2), COALESCE(s.escalate_after_days, $3) FROM tasks t JOIN projects p ON p.id = t.project_id AND p.deleted_at IS NULL LEFT JOIN reminder_settings s ON s.project_id = p.id WHERE t.deleted_at IS NULL AND t.status <> 'Accepted' AND COALESCE(s.enabled, TRUE) AND t.deadline <= $1::date + COALESCE(s.days_before, $2) ORDER BY t.deadline, t.id"
+	GetDueProjects        = "SELECT p.id, p.key, p.name, p.deadline, p.manager_id, COALESCE(s.days_before, $2) FROM projects p LEFT JOIN reminder_settings s ON s.project_id = p.id WHERE p.deleted_at IS NULL AND COALESCE(s.enabled, TRUE) AND p.deadline <= $1::date + COALESCE(s.days_before, $2) AND EXISTS (SELECT 1 FROM tasks WHERE tasks.project_id = p.id AND tasks.status <> 'Accepted' AND tasks.deleted_at IS NULL) ORDER BY p.deadline, p.id"
+	InsertTaskReminder    = "INSERT INTO task_reminders(task_id, kind, deadline) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+	InsertProjectReminder = "INSERT INTO project_reminders(project_id, kind, deadline) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+
+	// Notifications
+	CreateNotification           = "INSERT INTO notifications(user_id, type, project_id, task_id, message, data) VALUES ($1, $2, NULLIF($3, '')::uuid, NULLIF($4, '')::uuid, $5, $6)"
+	GetNotifications             = "SELECT id, type, COALESCE(project_id::text, ''), COALESCE(task_id::text, ''), message, data, read_at, created_at FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) ORDER BY created_at DESC, id LIMIT $3 OFFSET $4"
+	CountNotifications           = "SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)"
+	MarkNotificationRead         = "UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP) WHERE id = $1 AND user_id = $2 RETURNING id"
+	MarkAllNotificationsRead     = "UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL"
+	GetNotificationPreferences   = "SELECT event_type, enabled FROM notification_preferences WHERE user_id = $1"
+	UpsertNotificationPreference = "INSERT INTO notification_preferences(user_id, event_type, enabled) VALUES ($1, $2, $3) ON CONFLICT (user_id, event_type) DO UPDATE SET enabled = $3"
+
+	// Mail
+	GetMailMode     = "SELECT mode FROM mail_settings WHERE user_id = $1"
+	UpsertMailMode  = "INSERT INTO mail_settings(user_id, mode) VALUES ($1, $2) ON CONFLICT (user_id) DO UPDATE SET mode = $2"
+	EnqueueMail     = "INSERT INTO mail_queue(user_id, type, message, digest) VALUES ($1, $2, $3, $4)"
+	GetPendingMails = "SELECT id, user_id, type, message, digest, created_at FROM mail_queue WHERE sent_at IS NULL AND digest = $1 ORDER BY user_id, created_at, id"
+	MarkMailsSent   = "UPDATE mail_queue SET sent_at = CURRENT_TIMESTAMP WHERE id = ANY($1)"
+
+	// Webhooks
+	CreateWebhook           = "INSERT INTO webhooks(project_id, url, secret, event_types, active, created_by) VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6) RETURNING id, COALESCE(project_id::text, ''), url, secret, event_types, active, COALESCE(created_by::text, ''), created_at, updated_at"
+	GetWebhookById          = "SELECT id, COALESCE(project_id::text, ''), url, secret, event_types, active, COALESCE(created_by::text, ''), created_at, updated_at FROM webhooks WHERE id = $1"
+	GetWebhooksByProjectId  = "SELECT id, COALESCE(project_id::text, ''), url, secret, event_types, active, COALESCE(created_by::text, ''), created_at, updated_at FROM webhooks WHERE project_id = $1 ORDER BY created_at"
+	GetGlobalWebhooks       = "SELECT id, COALESCE(project_id::text, ''), url, secret, event_types, active, COALESCE(created_by::text, ''), created_at, updated_at FROM webhooks WHERE project_id IS NULL ORDER BY created_at"
+	UpdateWebhook           = "UPDATE webhooks SET url = $2, event_types = $3, active = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING id, COALESCE(project_id::text, ''), url, secret, event_types, active, COALESCE(created_by::text, ''), created_at, updated_at"
+	DeleteWebhook           = "DELETE FROM webhooks WHERE id = $1"
+	EnqueueWebhookEvent     = "INSERT INTO webhook_deliveries(webhook_id, event_type, payload) SELECT id, $2, $3 FROM webhooks WHERE active AND (project_id IS NULL OR project_id = NULLIF($1, '')::uuid OR project_id = NULLIF($4, '')::uuid) AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))"
+	GetDueWebhookDeliveries = "WITH due AS (SELECT id FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= $1 ORDER BY next_attempt_at, created_at LIMIT $2 FOR UPDATE SKIP LOCKED) UPDATE webhook_deliveries d SET next_attempt_at = $3 FROM due, webhooks w WHERE d.id = due.id AND w.id = d.webhook_id RETURNING d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at, w.url, w.secret"
+	SaveWebhookAttempt      = "UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5, last_error = $6, delivered_at = $7 WHERE id = $1"
+	GetWebhookDeliveries    = "SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC, id LIMIT $2 OFFSET $3"
+	CountWebhookDeliveries  = "SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1"
+	GetWebhookDeliveryById  = "SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at FROM webhook_deliveries WHERE id = $1"
+	RedeliverWebhook        = "INSERT INTO webhook_deliveries(webhook_id, event_type, payload) SELECT webhook_id, event_type, payload FROM webhook_deliveries WHERE id = $1 RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at"
+
+	// Trash
+	GetDeletedUsers      = "SELECT id, name, deleted_at FROM users WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1 OFFSET $2"
+	GetDeletedProjects   = "SELECT id, name, deleted_at FROM projects WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1 OFFSET $2"
+	GetDeletedTasks      = "SELECT id, name, deleted_at FROM tasks WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1 OFFSET $2"
+	GetDeletedReports    = "SELECT id, report, deleted_at FROM reports WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1 OFFSET $2"
+	CountDeletedUsers    = "SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL"
+	CountDeletedProjects = "SELECT COUNT(*) FROM projects WHERE deleted_at IS NOT NULL"
+	CountDeletedTasks    = "SELECT COUNT(*) FROM tasks WHERE deleted_at IS NOT NULL"
+	CountDeletedReports  = "SELECT COUNT(*) FROM reports WHERE deleted_at IS NOT NULL"
+
+	RestoreUser               = "UPDATE users SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NOT NULL"
+	GetProjectDeletedAt       = "SELECT deleted_at FROM projects WHERE id = $1 AND deleted_at IS NOT NULL"
+	RestoreProject            = "UPDATE projects SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NOT NULL AND manager_id IN (SELECT id FROM users WHERE deleted_at IS NULL)"
+	RestoreProjectTasks       = "UPDATE tasks SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at >= $2"
+	RestoreProjectTaskReports = "UPDATE reports SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE deleted_at >= $2 AND task_id IN (SELECT id FROM tasks WHERE project_id = $1) RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	RestoreProjectMemberships = "UPDATE project_members SET deleted_at = NULL WHERE project_id = $1 AND deleted_at >= $2"
+	RestoreProjectMilestones  = "UPDATE milestones SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at >= $2"
+	RestoreProjectSprints     = "UPDATE sprints SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at >= $2"
+	RestoreProjectFields      = "UPDATE custom_field_definitions SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE project_id = $1 AND deleted_at >= $2"
+	RestoreTask               = "UPDATE tasks SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NOT NULL AND project_id IN (SELECT id FROM projects WHERE deleted_at IS NULL)"
+	RestoreReport             = "UPDATE reports SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NOT NULL AND task_id IN (SELECT id FROM tasks WHERE deleted_at IS NULL) RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	PurgeReports              = "DELETE FROM reports WHERE deleted_at < $1 RETURNING id, user_id, report, task_id, standup, review_status, review_comment, COALESCE(reviewed_by::text, ''), reviewed_at, created_at, updated_at, version"
+	PurgeTasks                = "DELETE FROM tasks WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM reports WHERE reports.task_id = tasks.id)"
+	PurgeProjectMembers       = "DELETE FROM project_members WHERE deleted_at < $1"
+	PurgeMilestones           = "DELETE FROM milestones WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM tasks WHERE tasks.milestone_id = milestones.id)"
+	PurgeSprints              = "DELETE FROM sprints WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM tasks WHERE tasks.sprint_id = sprints.id)"
+	PurgeCustomFields         = "DELETE FROM custom_field_definitions WHERE deleted_at < $1"
+	PurgeProjects             = "DELETE FROM projects WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM tasks WHERE tasks.project_id = projects.id) AND NOT EXISTS (SELECT 1 FROM project_members WHERE project_members.project_id = projects.id) AND NOT EXISTS (SELECT 1 FROM milestones WHERE milestones.project_id = projects.id) AND NOT EXISTS (SELECT 1 FROM sprints WHERE sprints.project_id = projects.id) AND NOT EXISTS (SELECT 1 FROM custom_field_definitions WHERE custom_field_definitions.project_id = projects.id)"
+	PurgeUsers                = "DELETE FROM users WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM projects WHERE projects.manager_id = users.id) AND NOT EXISTS (SELECT 1 FROM tasks WHERE tasks.person_in_charge = users.id) AND NOT EXISTS (SELECT 1 FROM reports WHERE reports.user_id = users.id) AND NOT EXISTS (SELECT 1 FROM project_members WHERE project_members.member_id = users.id)"
+
+	// Status reports
+	GetStatusReportChanges    = "SELECT t.id, t.task_key, t.name, h.status, COALESCE(h.previous::text, ''), h.changed_at FROM (SELECT id, task_id, status, LAG(status) OVER (PARTITION BY task_id ORDER BY id) AS previous, changed_at FROM task_status_history) h JOIN tasks t ON t.id = h.task_id WHERE t.project_id = $1 AND t.deleted_at IS NULL AND h.changed_at >= $2 AND h.changed_at < $3 ORDER BY h.changed_at, h.id"
+	GetStatusReportOverdue    = "SELECT t.id, t.task_key, t.name, t.status, t.deadline::text, COALESCE(u.name, '') FROM tasks t LEFT JOIN users u ON u.id = t.person_in_charge WHERE t.project_id = $1 AND t.deleted_at IS NULL AND t.status <> 'Accepted' AND t.deadline < $2 ORDER BY t.deadline, t.task_key"
+	GetStatusReportReports    = "SELECT r.user_id, COALESCE(u.name, ''), t.task_key, t.name, r.report, r.created_at FROM reports r JOIN tasks t ON t.id = r.task_id LEFT JOIN users u ON u.id = r.user_id WHERE t.project_id = $1 AND r.deleted_at IS NULL AND t.deleted_at IS NULL AND r.created_at >= $2 AND r.created_at < $3 ORDER BY u.name, r.created_at"
+	UpsertStatusReport        = "INSERT INTO status_reports(project_id, start_date, end_date, markdown, html, created_by) VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid) ON CONFLICT (project_id, start_date, end_date) DO UPDATE SET markdown = $4, html = $5, created_by = NULLIF($6, '')::uuid, created_at = CURRENT_TIMESTAMP RETURNING id, project_id, start_date::text, end_date::text, markdown, html, COALESCE(created_by::text, ''), created_at"
+	GetStatusReportById       = "SELECT id, project_id, start_date::text, end_date::text, markdown, html, COALESCE(created_by::text, ''), created_at FROM status_reports WHERE id = $1"
+	GetStatusReportsByProject = "SELECT id, project_id, start_date::text, end_date::text, COALESCE(created_by::text, ''), created_at FROM status_reports WHERE project_id = $1 ORDER BY start_date DESC, end_date DESC"
+	GetProjectsWithoutStatus  = "SELECT p.id FROM projects p WHERE p.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM status_reports s WHERE s.project_id = p.id AND s.start_date = $1 AND s.end_date = $2) ORDER BY p.id"
 )
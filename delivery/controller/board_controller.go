@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type BoardController struct {
+	boardUC        usecase.BoardUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewBoardController(boardUC usecase.BoardUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *BoardController {
+	return &BoardController{
+		boardUC:        boardUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (b *BoardController) Route() {
+	b.rg.GET("/board/project/:id", b.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), b.GetBoard)
+	b.rg.PUT("/board/move/:id", b.authMiddleware.RequireToken("MANAGER", "TEAM MEMBER"), b.MoveTask)
+	b.rg.PUT("/board/wiplimits/:id", b.authMiddleware.RequireToken("MANAGER"), b.SetWipLimits)
+}
+
+func (b *BoardController) GetBoard(c *gin.Context) {
+	board, err := b.boardUC.GetBoard(c.Param("id"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, board, "OK")
+}
+
+func (b *BoardController) MoveTask(c *gin.Context) {
+	var move model.BoardMove
+	if err := c.ShouldBindJSON(&move); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	move.TaskId = c.Param("id")
+
+	version, err := common.ParseIfMatch(c, move.Version)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+	move.Version = version
+
+	task, err := b.boardUC.MoveTask(c.GetString("user"), move)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SetETag(c, task.Version)
+	common.SendSingleResponse(c, task, "Moved")
+}
+
+func (b *BoardController) SetWipLimits(c *gin.Context) {
+	var request struct {
+		Limits map[string]int `json:"limits"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := b.boardUC.SetWipLimits(c.GetString("user"), c.Param("id"), request.Limits); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, nil, "Success")
+}
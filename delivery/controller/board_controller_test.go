@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BoardControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	bum *usecase_mock.BoardUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *BoardControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.bum = new(usecase_mock.BoardUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("MANAGER"))
+	s.rg = rg
+}
+
+func TestBoardControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(BoardControllerTestSuite))
+}
+
+func (s *BoardControllerTestSuite) TestMoveTask_UsesIfMatch() {
+	s.bum.On("MoveTask", "member1", model.BoardMove{TaskId: "t1", Status: "Blocked", Position: 1, Version: 4}).Return(model.Task{Id: "t1", Status: "Blocked", Version: 5}, nil)
+	boardController := NewBoardController(s.bum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/board/move/t1", bytes.NewBufferString(`{"status":"Blocked","position":1}`))
+	req.Header.Set("If-Match", `"4"`)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "t1")
+	ctx.Set("user", "member1")
+	boardController.MoveTask(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal(`"5"`, w.Header().Get("ETag"))
+}
+
+func (s *BoardControllerTestSuite) TestMoveTask_WipLimitReached() {
+	s.bum.On("MoveTask", "member1", model.BoardMove{TaskId: "t1", Status: "Blocked", Version: 3}).Return(model.Task{}, fmt.Errorf("failed to move task: %w", shared_model.ErrWipLimit))
+	boardController := NewBoardController(s.bum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/board/move/t1", bytes.NewBufferString(`{"status":"Blocked"}`))
+	req.Header.Set("If-Match", `"3"`)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "t1")
+	ctx.Set("user", "member1")
+	boardController.MoveTask(ctx)
+
+	s.Equal(http.StatusConflict, w.Code)
+}
+
+func (s *BoardControllerTestSuite) TestMoveTask_WithoutVersion() {
+	boardController := NewBoardController(s.bum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/board/move/t1", bytes.NewBufferString(`{"status":"Blocked"}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "t1")
+	ctx.Set("user", "member1")
+	boardController.MoveTask(ctx)
+
+	s.Equal(http.StatusPreconditionRequired, w.Code)
+	s.bum.AssertNotCalled(s.T(), "MoveTask", mock.Anything, mock.Anything)
+}
+
+func (s *BoardControllerTestSuite) TestGetBoard_Success() {
+	s.bum.On("GetBoard", "p1").Return(model.Board{ProjectId: "p1", Columns: []model.BoardColumn{{Status: "In Progress", WipLimit: 3}}}, nil)
+	boardController := NewBoardController(s.bum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/board/project/p1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "p1")
+	boardController.GetBoard(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"wip_limit":3`)
+}
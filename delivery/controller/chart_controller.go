@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ChartController struct {
+	chartUC        usecase.ChartUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewChartController(chartUC usecase.ChartUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *ChartController {
+	return &ChartController{
+		chartUC:        chartUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (ch *ChartController) Route() {
+	ch.rg.GET("/charts/burn/project/:id", ch.authMiddleware.RequireToken("ADMIN", "MANAGER"), ch.ProjectBurn)
+	ch.rg.GET("/charts/burn/sprint/:id", ch.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), ch.SprintBurn)
+}
+
+func (ch *ChartController) ProjectBurn(c *gin.Context) {
+	chart, err := ch.chartUC.ProjectBurn(c.Param("id"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, chart, "OK")
+}
+
+func (ch *ChartController) SprintBurn(c *gin.Context) {
+	chart, err := ch.chartUC.SprintBurn(c.Param("id"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, chart, "OK")
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChartControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	cum *usecase_mock.ChartUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *ChartControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.cum = new(usecase_mock.ChartUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("MANAGER"))
+	s.rg = rg
+}
+
+func TestChartControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ChartControllerTestSuite))
+}
+
+func (s *ChartControllerTestSuite) TestProjectBurn_Success() {
+	s.cum.On("ProjectBurn", "p1").Return(model.BurnChart{Scope: "project", ScopeId: "p1", Actual: []model.BurnPoint{{Date: "2024-03-01", TotalTasks: 2, RemainingTasks: 2}}}, nil)
+	chartController := NewChartController(s.cum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/charts/burn/project/p1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "p1")
+	chartController.ProjectBurn(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"remaining_tasks":2`)
+}
+
+func (s *ChartControllerTestSuite) TestSprintBurn_Failed() {
+	s.cum.On("SprintBurn", "s1").Return(model.BurnChart{}, fmt.Errorf("failed to get burn chart. sprint id invalid"))
+	chartController := NewChartController(s.cum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/charts/burn/sprint/s1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "s1")
+	chartController.SprintBurn(ctx)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
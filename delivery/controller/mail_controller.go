@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type MailController struct {
+	mailUC         usecase.MailUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewMailController(mailUC usecase.MailUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *MailController {
+	return &MailController{
+		mailUC:         mailUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (m *MailController) Route() {
+	m.rg.GET("/mail/settings", m.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), m.GetMode)
+	m.rg.PUT("/mail/settings", m.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), m.UpdateMode)
+}
+
+func (m *MailController) GetMode(c *gin.Context) {
+	mode, err := m.mailUC.GetMode(c.GetString("user"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, gin.H{"mode": mode}, "OK")
+}
+
+func (m *MailController) UpdateMode(c *gin.Context) {
+	var request struct {
+		Mode string `json:"mode"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := m.mailUC.UpdateMode(c.GetString("user"), request.Mode); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, gin.H{"mode": request.Mode}, "Updated")
+}
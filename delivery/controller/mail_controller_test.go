@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type MailControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	mum *usecase_mock.MailUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *MailControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.mum = new(usecase_mock.MailUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("TEAM MEMBER"))
+	s.rg = rg
+}
+
+func TestMailControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(MailControllerTestSuite))
+}
+
+func (s *MailControllerTestSuite) TestUpdateMode_Success() {
+	s.mum.On("UpdateMode", "u1", "digest").Return(nil)
+	mailController := NewMailController(s.mum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/mail/settings", bytes.NewBufferString(`{"mode":"digest"}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "u1")
+	mailController.UpdateMode(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *MailControllerTestSuite) TestUpdateMode_Invalid() {
+	s.mum.On("UpdateMode", "u1", "weekly").Return(fmt.Errorf("invalid mail mode. mode: ('off', 'immediate', 'digest')"))
+	mailController := NewMailController(s.mum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/mail/settings", bytes.NewBufferString(`{"mode":"weekly"}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "u1")
+	mailController.UpdateMode(ctx)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
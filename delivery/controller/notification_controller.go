@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController struct {
+	notificationUC usecase.NotificationUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewNotificationController(notificationUC usecase.NotificationUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *NotificationController {
+	return &NotificationController{
+		notificationUC: notificationUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (n *NotificationController) Route() {
+	n.rg.GET("/notifications", n.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), n.GetNotifications)
+	n.rg.GET("/notifications/unread", n.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), n.CountUnread)
+	n.rg.PUT("/notifications/read/:id", n.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), n.MarkRead)
+	n.rg.PUT("/notifications/read", n.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), n.MarkAllRead)
+	n.rg.GET("/notifications/preferences", n.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), n.GetPreferences)
+	n.rg.PUT("/notifications/preferences", n.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), n.UpdatePreferences)
+}
+
+func (n *NotificationController) GetNotifications(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	size, _ := strconv.Atoi(c.Query("size"))
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, paging, err := n.notificationUC.GetNotifications(c.GetString("user"), unreadOnly, page, size)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var resp []interface{}
+	for _, v := range notifications {
+		resp = append(resp, v)
+	}
+
+	log.Println("Success: ")
+	common.SendPagedResponse(c, resp, paging, "OK")
+}
+
+func (n *NotificationController) CountUnread(c *gin.Context) {
+	count, err := n.notificationUC.CountUnread(c.GetString("user"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, gin.H{"unread": count}, "OK")
+}
+
+func (n *NotificationController) MarkRead(c *gin.Context) {
+	if err := n.notificationUC.MarkRead(c.GetString("user"), c.Param("id")); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, nil, "Success")
+}
+
+func (n *NotificationController) MarkAllRead(c *gin.Context) {
+	count, err := n.notificationUC.MarkAllRead(c.GetString("user"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, gin.H{"marked": count}, "Success")
+}
+
+func (n *NotificationController) GetPreferences(c *gin.Context) {
+	preferences, err := n.notificationUC.GetPreferences(c.GetString("user"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, preferences, "OK")
+}
+
+func (n *NotificationController) UpdatePreferences(c *gin.Context) {
+	var request struct {
+		Preferences map[string]bool `json:"preferences"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preferences, err := n.notificationUC.UpdatePreferences(c.GetString("user"), request.Preferences)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, preferences, "Updated")
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	num *usecase_mock.NotificationUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *NotificationControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.num = new(usecase_mock.NotificationUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("TEAM MEMBER"))
+	s.rg = rg
+}
+
+func TestNotificationControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationControllerTestSuite))
+}
+
+func (s *NotificationControllerTestSuite) TestGetNotifications_UnreadOnly() {
+	s.num.On("GetNotifications", "u1", true, 2, 5).Return([]model.Notification{{Id: "n1"}}, shared_model.Paging{Page: 2, RowsPerPage: 5, TotalRows: 6, TotalPages: 2}, nil)
+	notificationController := NewNotificationController(s.num, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/notifications?unread=true&page=2&size=5", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "u1")
+	notificationController.GetNotifications(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.num.AssertExpectations(s.T())
+}
+
+func (s *NotificationControllerTestSuite) TestMarkRead_NotFound() {
+	s.num.On("MarkRead", "u1", "n1").Return(fmt.Errorf("failed to mark notification read. notification id invalid"))
+	notificationController := NewNotificationController(s.num, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/notifications/read/n1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "n1")
+	ctx.Set("user", "u1")
+	notificationController.MarkRead(ctx)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *NotificationControllerTestSuite) TestUpdatePreferences_Success() {
+	preferences := map[string]bool{model.EventTaskOverdue: false}
+	s.num.On("UpdatePreferences", "u1", preferences).Return(preferences, nil)
+	notificationController := NewNotificationController(s.num, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/notifications/preferences", bytes.NewBufferString(`{"preferences":{"task.overdue":false}}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "u1")
+	notificationController.UpdatePreferences(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+}
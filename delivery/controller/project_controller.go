@@ -38,6 +38,15 @@ func (a *ProjectController) Route() {
 	a.rg.GET("/project/allmember/:id", a.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), a.GetAllProjectMember)
 	a.rg.PUT("/project/update", a.authMiddleware.RequireToken("ADMIN", "MANAGER"), a.UpdateProject)
 	a.rg.DELETE("/project/delete/:id", a.authMiddleware.RequireToken("ADMIN"), a.DeleteProject)
+	a.rg.GET("/project/milestones/:id", a.authMiddleware.RequireToken("MANAGER"), a.GetMilestones)
+	a.rg.POST("/project/milestone/create/:id", a.authMiddleware.RequireToken("MANAGER"), a.CreateMilestone)
+	a.rg.PUT("/project/milestone/update/:id", a.authMiddleware.RequireToken("MANAGER"), a.UpdateMilestone)
+	a.rg.DELETE("/project/milestone/delete/:id", a.authMiddleware.RequireToken("MANAGER"), a.DeleteMilestone)
+	a.rg.PUT("/project/milestone/tasks/:id", a.authMiddleware.RequireToken("MANAGER"), a.AttachMilestoneTasks)
+	a.rg.GET("/project/customfields/:id", a.authMiddleware.RequireToken("MANAGER"), a.GetCustomFields)
+	a.rg.POST("/project/customfield/create/:id", a.authMiddleware.RequireToken("MANAGER"), a.CreateCustomField)
+	a.rg.PUT("/project/customfield/update/:id", a.authMiddleware.RequireToken("MANAGER"), a.UpdateCustomField)
+	a.rg.DELETE("/project/customfield/delete/:id", a.authMiddleware.RequireToken("MANAGER"), a.DeleteCustomField)
 
 }
 
@@ -83,6 +92,7 @@ func (pc *ProjectController) GetProjectById(c *gin.Context) {
 	}
 
 	log.Printf("succes Get Resource")
+	common.SetETag(c, project.Version)
 
 	common.SendSingleResponse(c, map[string]interface{}{
 		"project": project,
@@ -147,7 +157,7 @@ func (pc *ProjectController) CreateNewProject(c *gin.Context) {
 	createdProject, err := pc.projectUsecase.CreateNewProject(request)
 	if err != nil {
 		log.Println(err.Error())
-		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusInternalServerError), err.Error())
 		return
 	}
 
@@ -230,14 +240,23 @@ func (pc *ProjectController) UpdateProject(c *gin.Context) {
 		return
 	}
 
+	version, err := common.ParseIfMatch(c, request.Version)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+	request.Version = version
+
 	updatedProject, err := pc.projectUsecase.Update(request)
 	if err != nil {
 		log.Println(err.Error())
-		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusInternalServerError), err.Error())
 		return
 	}
 
 	log.Printf("Successfully updated project with ID: %s", updatedProject.Id)
+	common.SetETag(c, updatedProject.Version)
 
 	common.SendSingleResponse(c, updatedProject, "Success Get Resource")
 }
@@ -245,7 +264,19 @@ func (pc *ProjectController) UpdateProject(c *gin.Context) {
 func (pc *ProjectController) DeleteProject(c *gin.Context) {
 	id := c.Param("id")
 
-	err := pc.projectUsecase.Delete(id)
+	if c.Query("dry_run") == "true" {
+		impact, err := pc.projectUsecase.DeletePreview(id)
+		if err != nil {
+			log.Println(err.Error())
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		common.SendSingleResponse(c, impact, "Delete Project Preview")
+		return
+	}
+
+	err := pc.projectUsecase.Delete(c.GetString("user"), id)
 	if err != nil {
 		log.Println(err.Error())
 
@@ -257,3 +288,173 @@ func (pc *ProjectController) DeleteProject(c *gin.Context) {
 
 	common.SendSingleResponse(c, nil, "Success")
 }
+
+func (pc *ProjectController) GetMilestones(c *gin.Context) {
+	id := c.Param("id")
+
+	milestones, err := pc.projectUsecase.GetMilestones(id, c.GetString("user"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully retrieved milestones for project ID: %s", id)
+
+	common.SendSingleResponse(c, milestones, "Success Get Resource")
+}
+
+func (pc *ProjectController) CreateMilestone(c *gin.Context) {
+	var request model.Milestone
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.ProjectId = c.Param("id")
+
+	milestone, err := pc.projectUsecase.CreateMilestone(c.GetString("user"), request)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully created milestone with ID: %s", milestone.Id)
+
+	common.SendCreatedResponse(c, milestone, "Created")
+}
+
+func (pc *ProjectController) UpdateMilestone(c *gin.Context) {
+	var request model.Milestone
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.Id = c.Param("id")
+
+	milestone, err := pc.projectUsecase.UpdateMilestone(c.GetString("user"), request)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully updated milestone with ID: %s", milestone.Id)
+
+	common.SendSingleResponse(c, milestone, "Success Get Resource")
+}
+
+func (pc *ProjectController) DeleteMilestone(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := pc.projectUsecase.DeleteMilestone(c.GetString("user"), id); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully deleted milestone with ID: %s", id)
+
+	common.SendSingleResponse(c, nil, "Success")
+}
+
+func (pc *ProjectController) AttachMilestoneTasks(c *gin.Context) {
+	id := c.Param("id")
+
+	var request struct {
+		TaskIds []string `json:"task_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := pc.projectUsecase.AttachMilestoneTasks(c.GetString("user"), id, request.TaskIds); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully attached tasks to milestone ID: %s", id)
+
+	common.SendSingleResponse(c, nil, "Success")
+}
+
+func (pc *ProjectController) GetCustomFields(c *gin.Context) {
+	id := c.Param("id")
+
+	fields, err := pc.projectUsecase.GetCustomFields(id, c.GetString("user"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully retrieved custom fields for project ID: %s", id)
+
+	common.SendSingleResponse(c, fields, "Success Get Resource")
+}
+
+func (pc *ProjectController) CreateCustomField(c *gin.Context) {
+	var request model.CustomField
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.ProjectId = c.Param("id")
+
+	field, err := pc.projectUsecase.CreateCustomField(c.GetString("user"), request)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully created custom field with ID: %s", field.Id)
+
+	common.SendCreatedResponse(c, field, "Created")
+}
+
+func (pc *ProjectController) UpdateCustomField(c *gin.Context) {
+	var request model.CustomField
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	request.Id = c.Param("id")
+
+	field, err := pc.projectUsecase.UpdateCustomField(c.GetString("user"), request)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully updated custom field with ID: %s", field.Id)
+
+	common.SendSingleResponse(c, field, "Success Get Resource")
+}
+
+func (pc *ProjectController) DeleteCustomField(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := pc.projectUsecase.DeleteCustomField(c.GetString("user"), id); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Printf("Successfully deleted custom field with ID: %s", id)
+
+	common.SendSingleResponse(c, nil, "Success")
+}
@@ -13,6 +13,7 @@ import (
 	"enigma.com/projectmanagementhub/model"
 	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -249,6 +250,107 @@ func (a *ProjectControllerTestSuite) TestCreateProjectController_Failed() {
 
 // Test Get All Project Member
 
+// Test Delete Project dry run
+func (a *ProjectControllerTestSuite) TestDeleteProjectController_DryRun() {
+	a.ProjectUc.On("DeletePreview", ExpectedProject.Id).Return(model.DeleteImpact{Projects: 1, Tasks: 4}, nil)
+	projectController := NewProjectController(a.ProjectUc, a.authMiddleware, a.rg)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/project/delete/"+ExpectedProject.Id+"?dry_run=true", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", ExpectedProject.Id)
+	projectController.DeleteProject(ctx)
+
+	a.Equal(200, w.Code)
+	a.Contains(w.Body.String(), `"tasks":4`)
+	a.ProjectUc.AssertNotCalled(a.T(), "Delete", mock.Anything, ExpectedProject.Id)
+	a.ProjectUc.AssertExpectations(a.T())
+}
+
+// Test Create Milestone Success
+func (a *ProjectControllerTestSuite) TestCreateMilestoneController_Success() {
+	payload := model.Milestone{ProjectId: ExpectedProject.Id, Name: "Beta", DueDate: "2024-01-15"}
+	a.ProjectUc.On("CreateMilestone", ExpectedProject.ManagerId, payload).Return(model.Milestone{Id: "milestone1", ProjectId: ExpectedProject.Id, Name: "Beta", DueDate: "2024-01-15"}, nil)
+	projectController := NewProjectController(a.ProjectUc, a.authMiddleware, a.rg)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/project/milestone/create/"+ExpectedProject.Id, bytes.NewBufferString(`{"name":"Beta","due_date":"2024-01-15"}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", ExpectedProject.Id)
+	ctx.Set("user", ExpectedProject.ManagerId)
+	projectController.CreateMilestone(ctx)
+
+	a.Equal(201, w.Code)
+	a.ProjectUc.AssertExpectations(a.T())
+}
+
+// Test Get Milestones by another manager
+func (a *ProjectControllerTestSuite) TestGetMilestonesController_Forbidden() {
+	a.ProjectUc.On("GetMilestones", ExpectedProject.Id, "othermanager").Return([]model.Milestone{}, shared_model.ErrForbidden)
+	projectController := NewProjectController(a.ProjectUc, a.authMiddleware, a.rg)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/project/milestones/"+ExpectedProject.Id, nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", ExpectedProject.Id)
+	ctx.Set("user", "othermanager")
+	projectController.GetMilestones(ctx)
+
+	a.Equal(403, w.Code)
+	a.ProjectUc.AssertExpectations(a.T())
+}
+
+// Test Attach Milestone Tasks Success
+func (a *ProjectControllerTestSuite) TestAttachMilestoneTasksController_Success() {
+	a.ProjectUc.On("AttachMilestoneTasks", ExpectedProject.ManagerId, "milestone1", []string{"task1", "task2"}).Return(nil)
+	projectController := NewProjectController(a.ProjectUc, a.authMiddleware, a.rg)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/project/milestone/tasks/milestone1", bytes.NewBufferString(`{"task_ids":["task1","task2"]}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "milestone1")
+	ctx.Set("user", ExpectedProject.ManagerId)
+	projectController.AttachMilestoneTasks(ctx)
+
+	a.Equal(200, w.Code)
+	a.ProjectUc.AssertExpectations(a.T())
+}
+
+// Test Create Custom Field Success
+func (a *ProjectControllerTestSuite) TestCreateCustomFieldController_Success() {
+	payload := model.CustomField{ProjectId: ExpectedProject.Id, Name: "env", Type: "single_select", Options: []string{"dev", "prod"}}
+	created := payload
+	created.Id = "field1"
+	a.ProjectUc.On("CreateCustomField", ExpectedProject.ManagerId, payload).Return(created, nil)
+	projectController := NewProjectController(a.ProjectUc, a.authMiddleware, a.rg)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/project/customfield/create/"+ExpectedProject.Id, bytes.NewBufferString(`{"name":"env","type":"single_select","options":["dev","prod"]}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", ExpectedProject.Id)
+	ctx.Set("user", ExpectedProject.ManagerId)
+	projectController.CreateCustomField(ctx)
+
+	a.Equal(201, w.Code)
+	a.ProjectUc.AssertExpectations(a.T())
+}
+
+// Test Delete Custom Field by another manager
+func (a *ProjectControllerTestSuite) TestDeleteCustomFieldController_Forbidden() {
+	a.ProjectUc.On("DeleteCustomField", "othermanager", "field1").Return(shared_model.ErrForbidden)
+	projectController := NewProjectController(a.ProjectUc, a.authMiddleware, a.rg)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/v1/project/customfield/delete/field1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "field1")
+	ctx.Set("user", "othermanager")
+	projectController.DeleteCustomField(ctx)
+
+	a.Equal(403, w.Code)
+	a.ProjectUc.AssertExpectations(a.T())
+}
+
 func TestProjectControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(ProjectControllerTestSuite))
 }
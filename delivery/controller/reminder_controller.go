@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ReminderController struct {
+	reminderUC     usecase.ReminderUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewReminderController(reminderUC usecase.ReminderUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *ReminderController {
+	return &ReminderController{
+		reminderUC:     reminderUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (r *ReminderController) Route() {
+	r.rg.GET("/reminders/project/:id", r.authMiddleware.RequireToken("MANAGER"), r.GetSetting)
+	r.rg.PUT("/reminders/project/:id", r.authMiddleware.RequireToken("MANAGER"), r.UpdateSetting)
+}
+
+func (r *ReminderController) GetSetting(c *gin.Context) {
+	setting, err := r.reminderUC.GetSetting(c.GetString("user"), c.Param("id"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, setting, "OK")
+}
+
+func (r *ReminderController) UpdateSetting(c *gin.Context) {
+	var setting model.ReminderSetting
+	if err := c.ShouldBindJSON(&setting); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	setting.ProjectId = c.Param("id")
+
+	saved, err := r.reminderUC.UpdateSetting(c.GetString("user"), setting)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, saved, "Updated")
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReminderControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	rum *usecase_mock.ReminderUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *ReminderControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.rum = new(usecase_mock.ReminderUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("MANAGER"))
+	s.rg = rg
+}
+
+func TestReminderControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderControllerTestSuite))
+}
+
+func (s *ReminderControllerTestSuite) TestUpdateSetting_Success() {
+	setting := model.ReminderSetting{ProjectId: "p1", Enabled: true, DaysBefore: 3, EscalateAfterDays: 1}
+	s.rum.On("UpdateSetting", "m1", setting).Return(setting, nil)
+	reminderController := NewReminderController(s.rum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/reminders/project/p1", bytes.NewBufferString(`{"enabled":true,"days_before":3,"escalate_after_days":1}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "p1")
+	ctx.Set("user", "m1")
+	reminderController.UpdateSetting(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ReminderControllerTestSuite) TestGetSetting_Forbidden() {
+	s.rum.On("GetSetting", "m2", "p1").Return(model.ReminderSetting{}, fmt.Errorf("failed to get reminder setting: %w", shared_model.ErrForbidden))
+	reminderController := NewReminderController(s.rum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/reminders/project/p1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "p1")
+	ctx.Set("user", "m2")
+	reminderController.GetSetting(ctx)
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
@@ -1,7 +1,11 @@
 package controller
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"enigma.com/projectmanagementhub/delivery/middleware"
 	"enigma.com/projectmanagementhub/model"
@@ -12,13 +16,15 @@ import (
 
 type ReportController struct {
 	reportUC       usecase.ReportUsecase
+	statusReportUC usecase.StatusReportUsecase
 	authMiddleware middleware.AuthMiddleware
 	rg             *gin.RouterGroup
 }
 
-func NewReportController(reportUC usecase.ReportUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *ReportController {
+func NewReportController(reportUC usecase.ReportUsecase, statusReportUC usecase.StatusReportUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *ReportController {
 	return &ReportController{
 		reportUC:       reportUC,
+		statusReportUC: statusReportUC,
 		authMiddleware: authMiddleware,
 		rg:             rg,
 	}
@@ -32,9 +38,9 @@ func (h *ReportController) CreateNewReportController(c *gin.Context) {
 		return
 	}
 
-	newReport, err = h.reportUC.CreateReport(newReport)
+	newReport, err = h.reportUC.CreateReport(c.GetString("user"), newReport)
 	if err != nil {
-		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
 		return
 	}
 	common.SendSingleResponse(c, newReport, "Success")
@@ -48,18 +54,27 @@ func (h *ReportController) UpdateReportController(c *gin.Context) {
 		return
 	}
 
-	updatedReport, err = h.reportUC.UpdateReport(updatedReport)
+	version, err := common.ParseIfMatch(c, updatedReport.Version)
+	if err != nil {
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+	updatedReport.Version = version
+
+	updatedReport, err = h.reportUC.UpdateReport(c.GetString("user"), updatedReport)
 	if err != nil {
-		common.SendErrorResponse(c, http.StatusBadRequest, "failed to update report "+err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), "failed to update report "+err.Error())
 		return
 	}
 
+	common.SetETag(c, updatedReport.Version)
+
 	common.SendSingleResponse(c, updatedReport, "succesfully updated report")
 }
 
 func (h *ReportController) DeleteReportByIdController(c *gin.Context) {
 	id := c.Query("id")
-	err := h.reportUC.DeleteReportById(id)
+	err := h.reportUC.DeleteReportById(c.GetString("user"), id)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusBadRequest, "failed to delete report "+err.Error())
 		return
@@ -99,6 +114,145 @@ func (h *ReportController) GetReportByUserIdController(c *gin.Context) {
 
 }
 
+func (h *ReportController) SearchReportsController(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	size, _ := strconv.Atoi(c.Query("size"))
+	filter := model.ReportFilter{
+		UserId:    c.Query("user_id"),
+		TaskId:    c.Query("task_id"),
+		ProjectId: c.Query("project_id"),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		Query:     c.Query("q"),
+		Sort:      c.Query("sort"),
+		Order:     c.Query("order"),
+	}
+
+	reports, paging, err := h.reportUC.SearchReports(c.GetString("user"), filter, page, size)
+	if err != nil {
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	var response []interface{}
+	for _, v := range reports {
+		response = append(response, v)
+	}
+	common.SendPagedResponse(c, response, paging, "Success to search reports")
+}
+
+func (h *ReportController) ReviewReportController(c *gin.Context) {
+	var review model.ReportReview
+	if err := c.ShouldBindJSON(&review); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.reportUC.ReviewReport(c.GetString("user"), c.Param("id"), review)
+	if err != nil {
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, report, "Success to review report")
+}
+
+func (h *ReportController) GetMissingReportsController(c *gin.Context) {
+	missing, err := h.reportUC.GetMissingReports(c.GetString("user"), c.Query("project_id"), c.Query("date"))
+	if err != nil {
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, missing, "Success to get missing reports")
+}
+
+func (h *ReportController) VerifyJournalController(c *gin.Context) {
+	result, err := h.reportUC.VerifyJournal(c.Query("from"), c.Query("to"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, "failed to verify report journal "+err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, result, "Success to verify report journal")
+}
+
+// DownloadArchiveController streams a zip of the report files between from and to
+func (h *ReportController) DownloadArchiveController(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	// the filename is built from the parsed dates only, anything else is rejected by the usecase
+	fromDate, fromErr := time.Parse("2006-01-02", from)
+	toDate, toErr := time.Parse("2006-01-02", to)
+	if fromErr == nil && toErr == nil {
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="reports-%s-%s.zip"`, fromDate.Format("2006-01-02"), toDate.Format("2006-01-02")))
+	}
+
+	if err := h.reportUC.DownloadArchive(from, to, c.Writer); err != nil {
+		if c.Writer.Written() {
+			log.Println("ReportController.DownloadArchive", err.Error())
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		common.SendErrorResponse(c, http.StatusBadRequest, "failed to download report archive "+err.Error())
+	}
+}
+
+func (h *ReportController) GenerateStatusReportController(c *gin.Context) {
+	var request struct {
+		ProjectId string `json:"project_id"`
+		From      string `json:"from"`
+		To        string `json:"to"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	statusReport, err := h.statusReportUC.Generate(c.GetString("user"), request.ProjectId, request.From, request.To)
+	if err != nil {
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	common.SendCreatedResponse(c, statusReport, "Success to generate status report")
+}
+
+func (h *ReportController) GetStatusReportsController(c *gin.Context) {
+	statusReports, err := h.statusReportUC.GetStatusReports(c.GetString("user"), c.Query("project_id"))
+	if err != nil {
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, statusReports, "Success to get status reports")
+}
+
+// DownloadStatusReportController serves a stored status report as an attachment, Markdown unless format=html
+func (h *ReportController) DownloadStatusReportController(c *gin.Context) {
+	format := c.DefaultQuery("format", "markdown")
+	if format != "markdown" && format != "html" {
+		common.SendErrorResponse(c, http.StatusBadRequest, "format must be markdown or html")
+		return
+	}
+
+	statusReport, err := h.statusReportUC.GetStatusReport(c.GetString("user"), c.Param("id"))
+	if err != nil {
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	name := fmt.Sprintf("status-report-%s-%s", statusReport.StartDate, statusReport.EndDate)
+	if format == "html" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.html"`, name))
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(statusReport.Html))
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, name))
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(statusReport.Markdown))
+}
+
 // rg meng group end-point2
 func (h *ReportController) Route() {
 	h.rg.GET("/get/reporttaskid", h.authMiddleware.RequireToken("ADMIN", "MANAGER"), h.GetReportByTaskIdController)
@@ -106,4 +260,12 @@ func (h *ReportController) Route() {
 	h.rg.POST("/createreport", h.authMiddleware.RequireToken("TEAM MEMBER"), h.CreateNewReportController)
 	h.rg.PUT("/updatereport", h.authMiddleware.RequireToken("TEAM MEMBER"), h.UpdateReportController)
 	h.rg.DELETE("/deletedreport", h.authMiddleware.RequireToken("ADMIN"), h.DeleteReportByIdController)
+	h.rg.GET("/report/search", h.authMiddleware.RequireToken("ADMIN", "MANAGER"), h.SearchReportsController)
+	h.rg.PUT("/report/review/:id", h.authMiddleware.RequireToken("MANAGER"), h.ReviewReportController)
+	h.rg.GET("/report/missing", h.authMiddleware.RequireToken("ADMIN", "MANAGER"), h.GetMissingReportsController)
+	h.rg.GET("/report/verify", h.authMiddleware.RequireToken("ADMIN"), h.VerifyJournalController)
+	h.rg.GET("/report/archive", h.authMiddleware.RequireToken("ADMIN"), h.DownloadArchiveController)
+	h.rg.POST("/report/status", h.authMiddleware.RequireToken("ADMIN", "MANAGER"), h.GenerateStatusReportController)
+	h.rg.GET("/report/status", h.authMiddleware.RequireToken("ADMIN", "MANAGER"), h.GetStatusReportsController)
+	h.rg.GET("/report/status/:id/download", h.authMiddleware.RequireToken("ADMIN", "MANAGER"), h.DownloadStatusReportController)
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,13 +12,16 @@ import (
 	"enigma.com/projectmanagementhub/mock/middleware_mock"
 	"enigma.com/projectmanagementhub/mock/usecase_mock"
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type ReportControllerTestSuite struct {
 	suite.Suite
 	ReportUc       *usecase_mock.ReportUsecaseMock
+	StatusReportUc *usecase_mock.StatusReportUsecaseMock
 	rg             *gin.RouterGroup
 	authMiddleware *middleware_mock.AuthMiddlewareMock
 }
@@ -34,6 +38,7 @@ var ExpectedReport = model.Report{
 
 func (t *ReportControllerTestSuite) SetupTest() {
 	t.ReportUc = new(usecase_mock.ReportUsecaseMock)
+	t.StatusReportUc = new(usecase_mock.StatusReportUsecaseMock)
 	t.authMiddleware = new(middleware_mock.AuthMiddlewareMock)
 	r := gin.Default()
 	rg := r.Group("/pmh-api/v1")
@@ -42,8 +47,8 @@ func (t *ReportControllerTestSuite) SetupTest() {
 }
 
 func (t *ReportControllerTestSuite) TestCreateNewReportController() {
-	t.ReportUc.On("CreateReport", model.Report{}).Return(ExpectedReport, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	t.ReportUc.On("CreateReport", "", model.Report{}).Return(ExpectedReport, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	requestBody := `{"user_id":"09effbb3-34fe-4719-a1f6-33619f926577","report":"report","task_id":"35fc1b48-a4d1-4bf2-9d34-c35271fc282f"}`
 	request, err := http.NewRequest("POST", "/pmh-api/v1/createreport", strings.NewReader(requestBody))
@@ -58,8 +63,8 @@ func (t *ReportControllerTestSuite) TestCreateNewReportController() {
 }
 
 func (t *ReportControllerTestSuite) TestCreateNewReportController_Failed() {
-	t.ReportUc.On("CreateReport", model.Report{}).Return(ExpectedReport, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	t.ReportUc.On("CreateReport", "", model.Report{}).Return(ExpectedReport, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	requestBody := `{"user_id":"09effbb3-34fe-4719-a1f6-33619f926577","report":"report","task_id":"35fc1b48-a4d1-4bf2-9d34-c35271fc282f"}`
 	request, err := http.NewRequest("POST", "/pmh-api/v1/createreport", strings.NewReader(requestBody))
@@ -74,12 +79,13 @@ func (t *ReportControllerTestSuite) TestCreateNewReportController_Failed() {
 }
 
 func (t *ReportControllerTestSuite) TestUpdateReportController() {
-	t.ReportUc.On("UpdateReport", model.Report{}).Return(ExpectedReport, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	t.ReportUc.On("UpdateReport", "", model.Report{Version: 1}).Return(ExpectedReport, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	requestBody := `{user_id:09effbb3-34fe-4719-a1f6-33619f926577,report:report,task_id:35fc1b48-a4d1-4bf2-9d34-c35271fc282f}`
 	request, err := http.NewRequest("PUT", "/pmh-api/v1/updatereport", strings.NewReader(requestBody))
 	t.Nil(err)
+	request.Header.Set("If-Match", `"1"`)
 
 	record := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(record)
@@ -90,12 +96,13 @@ func (t *ReportControllerTestSuite) TestUpdateReportController() {
 }
 
 func (t *ReportControllerTestSuite) TestUpdateReportController_Failed() {
-	t.ReportUc.On("UpdateReport", model.Report{}).Return(ExpectedReport, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	t.ReportUc.On("UpdateReport", "", model.Report{Version: 1}).Return(ExpectedReport, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	requestBody := `{user_id:09effbb3-34fe-4719-a1f6-33619f926577,report:report,task_id:35fc1b48-a4d1-4bf2-9d34-c35271fc282f}`
 	request, err := http.NewRequest("PUT", "/pmh-api/v1/updatereport", strings.NewReader(requestBody))
 	t.Nil(err)
+	request.Header.Set("If-Match", `"1"`)
 
 	record := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(record)
@@ -107,7 +114,7 @@ func (t *ReportControllerTestSuite) TestUpdateReportController_Failed() {
 
 func (t *ReportControllerTestSuite) TestGetReportByTaskIdController() {
 	t.ReportUc.On("GetReportByTaskId", ExpectedReport.Task_id).Return([]model.Report{ExpectedReport}, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	request, err := http.NewRequest("GET", "/pmh-api/v1/get/reporttaskid?taskId=35fc1b48-a4d1-4bf2-9d34-c35271fc282f", nil)
 	t.Nil(err)
@@ -122,7 +129,7 @@ func (t *ReportControllerTestSuite) TestGetReportByTaskIdController() {
 
 func (t *ReportControllerTestSuite) TestGetReportByTaskIdController_failed() {
 	t.ReportUc.On("GetReportByTaskId", ExpectedReport.Task_id).Return([]model.Report{ExpectedReport}, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 
 	request, err := http.NewRequest("GET", "/pmh-api/v1/get/reporttaskid?taskId=35fc1b48-a4d1-4bf2-9d34-c35271fc282f", nil)
@@ -139,7 +146,7 @@ func (t *ReportControllerTestSuite) TestGetReportByTaskIdController_failed() {
 
 func (t *ReportControllerTestSuite) TestGetReportByUserIdController() {
 	t.ReportUc.On("GetReportByUserId", ExpectedReport.User_id).Return([]model.Report{ExpectedReport}, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	request, err := http.NewRequest("GET", "/pmh-api/v1/get/reportuserid?userId=09effbb3-34fe-4719-a1f6-33619f926577", nil)
 	t.NoError(err)
@@ -154,7 +161,7 @@ func (t *ReportControllerTestSuite) TestGetReportByUserIdController() {
 
 func (t *ReportControllerTestSuite) TestGetReportByUserIdController_failed() {
 	t.ReportUc.On("GetReportByUserId", ExpectedReport.User_id).Return([]model.Report{ExpectedReport}, nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	request, err := http.NewRequest("GET", "/pmh-api/v1/get/reportuserid?userId=09effbb3-34fe-4719-a1f6-33619f926577", nil)
 	t.NoError(err)
@@ -168,8 +175,8 @@ func (t *ReportControllerTestSuite) TestGetReportByUserIdController_failed() {
 }
 
 func (t *ReportControllerTestSuite) TestDeleteReportByIdController() {
-	t.ReportUc.On("DeleteReportById", ExpectedReport.Id).Return(nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	t.ReportUc.On("DeleteReportById", "a1", ExpectedReport.Id).Return(nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	request, err := http.NewRequest("DELETE", "/pmh-api/v1/delete/report?id=ed09d2f3-1878-4e11-adaf-a14326c81657", nil)
 	t.NoError(err)
@@ -178,13 +185,14 @@ func (t *ReportControllerTestSuite) TestDeleteReportByIdController() {
 	ctx, _ := gin.CreateTestContext(record)
 	ctx.Request = request
 	ctx.Set("ADMIN", true)
+	ctx.Set("user", "a1")
 	reportController.DeleteReportByIdController(ctx)
 	t.Equal(http.StatusOK, record.Code)
 }
 
 func (t *ReportControllerTestSuite) TestDeleteReportByIdController_failed() {
-	t.ReportUc.On("DeleteReportById", ExpectedReport.Id).Return(nil)
-	reportController := NewReportController(t.ReportUc, t.authMiddleware, t.rg)
+	t.ReportUc.On("DeleteReportById", "", ExpectedReport.Id).Return(nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
 	reportController.Route()
 	request, err := http.NewRequest("DELETE", "/pmh-api/v1/delete/report?id=ed09d2f3-1878-4e11-adaf-a14326c81657", nil)
 	t.NoError(err)
@@ -197,6 +205,179 @@ func (t *ReportControllerTestSuite) TestDeleteReportByIdController_failed() {
 	t.NotEqual(http.StatusUnauthorized, record.Code)
 }
 
+func (t *ReportControllerTestSuite) TestVerifyJournalController() {
+	broken := model.JournalVerification{From: "2024-03-01", To: "2024-03-07", Files: 2, Broken: &model.JournalBreak{File: "2024-03-02.txt", Entry: 3, Reason: "entry does not match its hash"}}
+	t.ReportUc.On("VerifyJournal", "2024-03-01", "2024-03-07").Return(broken, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("GET", "/pmh-api/v1/report/verify?from=2024-03-01&to=2024-03-07", nil)
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	reportController.VerifyJournalController(ctx)
+	t.Equal(http.StatusOK, record.Code)
+	t.Contains(record.Body.String(), `"file":"2024-03-02.txt"`)
+}
+
+func (t *ReportControllerTestSuite) TestDownloadArchiveController() {
+	t.ReportUc.On("DownloadArchive", "2024-03-01", "2024-03-31", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(2).(io.Writer).Write([]byte("PK"))
+	}).Return(nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("GET", "/pmh-api/v1/report/archive?from=2024-03-01&to=2024-03-31", nil)
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	reportController.DownloadArchiveController(ctx)
+	t.Equal(http.StatusOK, record.Code)
+	t.Equal("application/zip", record.Header().Get("Content-Type"))
+	t.Equal(`attachment; filename="reports-2024-03-01-2024-03-31.zip"`, record.Header().Get("Content-Disposition"))
+	t.Equal("PK", record.Body.String())
+}
+
+func (t *ReportControllerTestSuite) TestDownloadArchiveController_Invalid() {
+	t.ReportUc.On("DownloadArchive", "2024-03-01", "", mock.Anything).Return(fmt.Errorf("from and to dates are required"))
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("GET", "/pmh-api/v1/report/archive?from=2024-03-01", nil)
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	reportController.DownloadArchiveController(ctx)
+	t.Equal(http.StatusBadRequest, record.Code)
+	t.Empty(record.Header().Get("Content-Disposition"))
+	t.Contains(record.Header().Get("Content-Type"), "application/json")
+}
+
+func (t *ReportControllerTestSuite) TestDownloadArchiveController_MalformedDate() {
+	t.ReportUc.On("DownloadArchive", "2024-03-01\"\r\nX-Injected: 1", "2024-03-31", mock.Anything).Return(fmt.Errorf("invalid from date, expected YYYY-MM-DD"))
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("GET", "/pmh-api/v1/report/archive?from=2024-03-01%22%0D%0AX-Injected:%201&to=2024-03-31", nil)
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	reportController.DownloadArchiveController(ctx)
+	t.Equal(http.StatusBadRequest, record.Code)
+	t.Empty(record.Header().Get("Content-Disposition"))
+	t.Empty(record.Header().Get("X-Injected"))
+}
+
+func (t *ReportControllerTestSuite) TestGenerateStatusReportController() {
+	expected := model.StatusReport{Id: "s1", ProjectId: "p1", StartDate: "2024-03-04", EndDate: "2024-03-10"}
+	t.StatusReportUc.On("Generate", "m1", "p1", "2024-03-04", "2024-03-10").Return(expected, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("POST", "/pmh-api/v1/report/status", strings.NewReader(`{"project_id":"p1","from":"2024-03-04","to":"2024-03-10"}`))
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	ctx.Set("user", "m1")
+	reportController.GenerateStatusReportController(ctx)
+	t.Equal(http.StatusCreated, record.Code)
+	t.Contains(record.Body.String(), `"start_date":"2024-03-04"`)
+}
+
+func (t *ReportControllerTestSuite) TestGenerateStatusReportController_Forbidden() {
+	t.StatusReportUc.On("Generate", "m1", "p1", "", "").Return(model.StatusReport{}, fmt.Errorf("failed to generate status report: %w", shared_model.ErrForbidden))
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("POST", "/pmh-api/v1/report/status", strings.NewReader(`{"project_id":"p1"}`))
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	ctx.Set("user", "m1")
+	reportController.GenerateStatusReportController(ctx)
+	t.Equal(http.StatusForbidden, record.Code)
+}
+
+func (t *ReportControllerTestSuite) TestDownloadStatusReportController() {
+	stored := model.StatusReport{Id: "s1", ProjectId: "p1", StartDate: "2024-03-04", EndDate: "2024-03-10", Markdown: "# Status report", Html: "<h1>Status report</h1>"}
+	t.StatusReportUc.On("GetStatusReport", "m1", "s1").Return(stored, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+
+	for format, body := range map[string]string{"": stored.Markdown, "html": stored.Html} {
+		request, err := http.NewRequest("GET", "/pmh-api/v1/report/status/s1/download?format="+format, nil)
+		t.NoError(err)
+		if format == "" {
+			request.URL.RawQuery = ""
+		}
+
+		record := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(record)
+		ctx.Request = request
+		ctx.Params = gin.Params{{Key: "id", Value: "s1"}}
+		ctx.Set("user", "m1")
+		reportController.DownloadStatusReportController(ctx)
+		t.Equal(http.StatusOK, record.Code)
+		t.Equal(body, record.Body.String())
+		t.Contains(record.Header().Get("Content-Disposition"), "status-report-2024-03-04-2024-03-10")
+	}
+}
+
+func (t *ReportControllerTestSuite) TestSearchReportsController() {
+	filter := model.ReportFilter{ProjectId: "p1", From: "2024-03-04", To: "2024-03-10", Query: "login", Sort: "updated_at", Order: "asc"}
+	paging := shared_model.Paging{Page: 2, RowsPerPage: 5, TotalRows: 6, TotalPages: 2}
+	t.ReportUc.On("SearchReports", "m1", filter, 2, 5).Return([]model.Report{ExpectedReport}, paging, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("GET", "/pmh-api/v1/report/search?project_id=p1&from=2024-03-04&to=2024-03-10&q=login&sort=updated_at&order=asc&page=2&size=5", nil)
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	ctx.Set("user", "m1")
+	reportController.SearchReportsController(ctx)
+	t.Equal(http.StatusOK, record.Code)
+	t.Contains(record.Body.String(), `"totalRows":6`)
+	t.Contains(record.Body.String(), ExpectedReport.Id)
+}
+
+func (t *ReportControllerTestSuite) TestReviewReportController() {
+	review := model.ReportReview{Status: model.ReportNeedsMoreInfo, Comment: "which endpoint?"}
+	reviewed := ExpectedReport
+	reviewed.ReviewStatus, reviewed.ReviewComment = review.Status, review.Comment
+	t.ReportUc.On("ReviewReport", "m1", ExpectedReport.Id, review).Return(reviewed, nil)
+	t.ReportUc.On("ReviewReport", "m2", ExpectedReport.Id, review).Return(model.Report{}, fmt.Errorf("failed to review report: %w", shared_model.ErrForbidden))
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+
+	for user, code := range map[string]int{"m1": http.StatusOK, "m2": http.StatusForbidden} {
+		request, err := http.NewRequest("PUT", "/pmh-api/v1/report/review/"+ExpectedReport.Id, strings.NewReader(`{"status":"needs-more-info","comment":"which endpoint?"}`))
+		t.NoError(err)
+
+		record := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(record)
+		ctx.Request = request
+		ctx.Params = gin.Params{{Key: "id", Value: ExpectedReport.Id}}
+		ctx.Set("user", user)
+		reportController.ReviewReportController(ctx)
+		t.Equal(code, record.Code)
+	}
+}
+
+func (t *ReportControllerTestSuite) TestGetMissingReportsController() {
+	missing := []model.MissingReport{{UserId: "u1", Name: "Budi", TaskKeys: []string{"WEB-1"}}}
+	t.ReportUc.On("GetMissingReports", "m1", "p1", "2024-03-04").Return(missing, nil)
+	reportController := NewReportController(t.ReportUc, t.StatusReportUc, t.authMiddleware, t.rg)
+	request, err := http.NewRequest("GET", "/pmh-api/v1/report/missing?project_id=p1&date=2024-03-04", nil)
+	t.NoError(err)
+
+	record := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(record)
+	ctx.Request = request
+	ctx.Set("user", "m1")
+	reportController.GetMissingReportsController(ctx)
+	t.Equal(http.StatusOK, record.Code)
+	t.Contains(record.Body.String(), `"task_keys":["WEB-1"]`)
+}
+
 func TestReportControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(ReportControllerTestSuite))
 }
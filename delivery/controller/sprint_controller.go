@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type SprintController struct {
+	sprintUC       usecase.SprintUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewSprintController(sprintUC usecase.SprintUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *SprintController {
+	return &SprintController{
+		sprintUC:       sprintUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (s *SprintController) Route() {
+	s.rg.GET("/sprints/getbyprojectid/:id", s.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), s.GetByProjectId)
+	s.rg.GET("/sprints/getbyid/:id", s.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), s.GetById)
+	s.rg.POST("/sprints/create/:id", s.authMiddleware.RequireToken("MANAGER"), s.CreateSprint)
+	s.rg.PUT("/sprints/update/:id", s.authMiddleware.RequireToken("MANAGER"), s.UpdateSprint)
+	s.rg.PUT("/sprints/start/:id", s.authMiddleware.RequireToken("MANAGER"), s.StartSprint)
+	s.rg.PUT("/sprints/close/:id", s.authMiddleware.RequireToken("MANAGER"), s.CloseSprint)
+	s.rg.PUT("/sprints/addtasks/:id", s.authMiddleware.RequireToken("MANAGER"), s.AddTasks)
+	s.rg.DELETE("/sprints/removetasks/:id", s.authMiddleware.RequireToken("MANAGER"), s.RemoveTasks)
+}
+
+func (s *SprintController) GetByProjectId(c *gin.Context) {
+	id := c.Param("id")
+
+	sprints, err := s.sprintUC.GetByProjectId(id)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, sprints, "OK")
+}
+
+func (s *SprintController) GetById(c *gin.Context) {
+	id := c.Param("id")
+
+	sprint, err := s.sprintUC.GetById(id)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, sprint, "OK")
+}
+
+func (s *SprintController) CreateSprint(c *gin.Context) {
+	var payload model.Sprint
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.ProjectId = c.Param("id")
+
+	sprint, err := s.sprintUC.CreateSprint(c.GetString("user"), payload)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendCreatedResponse(c, sprint, "Created")
+}
+
+func (s *SprintController) UpdateSprint(c *gin.Context) {
+	var payload model.Sprint
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.Id = c.Param("id")
+
+	sprint, err := s.sprintUC.UpdateSprint(c.GetString("user"), payload)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, sprint, "Updated")
+}
+
+func (s *SprintController) StartSprint(c *gin.Context) {
+	sprint, err := s.sprintUC.StartSprint(c.GetString("user"), c.Param("id"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, sprint, "Started")
+}
+
+func (s *SprintController) CloseSprint(c *gin.Context) {
+	var request struct {
+		RolloverTo string `json:"rollover_to"`
+	}
+	// an empty body sends unfinished tasks back to the backlog
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Println(err.Error())
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	sprint, err := s.sprintUC.CloseSprint(c.GetString("user"), c.Param("id"), request.RolloverTo)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, sprint, "Closed")
+}
+
+func (s *SprintController) AddTasks(c *gin.Context) {
+	var request struct {
+		TaskIds []string `json:"task_ids"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.sprintUC.AddTasks(c.GetString("user"), c.Param("id"), request.TaskIds); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, nil, "Success")
+}
+
+func (s *SprintController) RemoveTasks(c *gin.Context) {
+	var request struct {
+		TaskIds []string `json:"task_ids"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.sprintUC.RemoveTasks(c.GetString("user"), c.Param("id"), request.TaskIds); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, nil, "Success")
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type SprintControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	sum *usecase_mock.SprintUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *SprintControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.sum = new(usecase_mock.SprintUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("MANAGER"))
+	s.rg = rg
+}
+
+func TestSprintControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(SprintControllerTestSuite))
+}
+
+func (s *SprintControllerTestSuite) TestCreateSprint_Success() {
+	payload := model.Sprint{ProjectId: "p1", Name: "Sprint 1", StartDate: "2024-03-04", EndDate: "2024-03-15"}
+	s.sum.On("CreateSprint", "manager1", payload).Return(model.Sprint{Id: "s1", ProjectId: "p1", Name: "Sprint 1", State: "planned"}, nil)
+	sprintController := NewSprintController(s.sum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pmh-api/v1/sprints/create/p1", bytes.NewBufferString(`{"name":"Sprint 1","start_date":"2024-03-04","end_date":"2024-03-15"}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "p1")
+	ctx.Set("user", "manager1")
+	sprintController.CreateSprint(ctx)
+
+	s.Equal(http.StatusCreated, w.Code)
+	s.sum.AssertExpectations(s.T())
+}
+
+func (s *SprintControllerTestSuite) TestCloseSprint_EmptyBodyRollsOverToBacklog() {
+	s.sum.On("CloseSprint", "manager1", "s1", "").Return(model.Sprint{Id: "s1", State: "closed", Summary: model.SprintSummary{RemainingTasks: 2}}, nil)
+	sprintController := NewSprintController(s.sum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/sprints/close/s1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "s1")
+	ctx.Set("user", "manager1")
+	sprintController.CloseSprint(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"remaining_tasks":2`)
+	s.sum.AssertExpectations(s.T())
+}
+
+func (s *SprintControllerTestSuite) TestStartSprint_Forbidden() {
+	s.sum.On("StartSprint", "manager2", "s1").Return(model.Sprint{}, shared_model.ErrForbidden)
+	sprintController := NewSprintController(s.sum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/pmh-api/v1/sprints/start/s1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "s1")
+	ctx.Set("user", "manager2")
+	sprintController.StartSprint(ctx)
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type StreamController struct {
+	streamUC       usecase.StreamUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+// streamHeartbeat keeps idle connections from being closed by proxies
+const streamHeartbeat = 30 * time.Second
+
+func NewStreamController(streamUC usecase.StreamUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *StreamController {
+	return &StreamController{
+		streamUC:       streamUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+// Route registers the stream endpoints. Browsers cannot set headers on an EventSource, so the
+// streams authenticate with a short-lived ticket in the query instead of the access token. The
+// ticket stays valid while its stream is open and briefly after, for the EventSource's reconnects.
+// A client that reconnects later asks for a new ticket and sends last_event_id to resume.
+func (s *StreamController) Route() {
+	s.rg.POST("/stream/ticket", s.authMiddleware.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"), s.IssueTicket)
+	s.rg.GET("/stream/project/:id", s.requireTicket, s.StreamProject)
+	s.rg.GET("/stream/user", s.requireTicket, s.StreamUser)
+}
+
+func (s *StreamController) IssueTicket(c *gin.Context) {
+	ticket, err := s.streamUC.IssueTicket(c.GetString("user"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendCreatedResponse(c, ticket, "Success")
+}
+
+// requireTicket redeems the ticket query parameter, sets the user it was issued to and releases
+// the ticket when the stream ends
+func (s *StreamController) requireTicket(c *gin.Context) {
+	ticket := c.Query("ticket")
+	userId, err := s.streamUC.RedeemTicket(ticket)
+	if err != nil {
+		log.Println("StreamController.requireTicket", err.Error())
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	defer s.streamUC.ReleaseTicket(ticket)
+
+	c.Set("user", userId)
+	c.Next()
+}
+
+func (s *StreamController) StreamProject(c *gin.Context) {
+	lastEventId, err := lastEventId(c)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	subscription, err := s.streamUC.SubscribeProject(c.GetString("user"), c.Param("id"), lastEventId)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	userId, projectId := c.GetString("user"), c.Param("id")
+	s.stream(c, subscription, func() error {
+		return s.streamUC.CheckProjectAccess(userId, projectId)
+	})
+}
+
+func (s *StreamController) StreamUser(c *gin.Context) {
+	lastEventId, err := lastEventId(c)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.stream(c, s.streamUC.SubscribeUser(c.GetString("user"), lastEventId), nil)
+}
+
+// stream writes the subscription as Server-Sent Events until the client disconnects.
+// A "reset" event tells the client that events were missed and it should reload.
+// When recheck is set it runs on every heartbeat and ends the stream once it fails.
+func (s *StreamController) stream(c *gin.Context, subscription *service.StreamSubscription, recheck func() error) {
+	defer s.streamUC.Unsubscribe(subscription)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if subscription.Reset {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	for _, event := range subscription.Replay {
+		writeStreamEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			writeStreamEvent(c, event)
+		case <-heartbeat.C:
+			if recheck != nil {
+				if err := recheck(); err != nil {
+					log.Println("StreamController.stream", err.Error())
+					return
+				}
+			}
+			fmt.Fprint(c.Writer, ": keepalive\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeStreamEvent(c *gin.Context, event model.StreamEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Println("StreamController.writeStreamEvent", err.Error())
+		return
+	}
+
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+}
+
+// lastEventId reads the Last-Event-ID header sent by reconnecting EventSource clients, or the
+// last_event_id query parameter for clients that cannot set it
+func lastEventId(c *gin.Context) (uint64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid last event id")
+	}
+
+	return id, nil
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	sum *usecase_mock.StreamUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *StreamControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.sum = new(usecase_mock.StreamUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("ADMIN", "MANAGER", "TEAM MEMBER"))
+	s.rg = rg
+}
+
+func TestStreamControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamControllerTestSuite))
+}
+
+func (s *StreamControllerTestSuite) TestStreamUser_ReplaysAndStreams() {
+	events := make(chan model.StreamEvent, 1)
+	events <- model.StreamEvent{Id: 8, Event: model.Event{Type: model.EventTaskAssigned}}
+	close(events)
+	subscription := &service.StreamSubscription{
+		Reset:  true,
+		Replay: []model.StreamEvent{{Id: 7, Event: model.Event{Type: model.EventTaskApproved}}},
+		Events: events,
+	}
+	s.sum.On("SubscribeUser", "u1", uint64(5)).Return(subscription)
+	s.sum.On("Unsubscribe", subscription).Return()
+	streamController := NewStreamController(s.sum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/stream/user", nil)
+	req.Header.Set("Last-Event-ID", "5")
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "u1")
+	streamController.StreamUser(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("text/event-stream", w.Header().Get("Content-Type"))
+	s.Contains(w.Body.String(), "event: reset\n")
+	s.Contains(w.Body.String(), "id: 7\nevent: task.approved\n")
+	s.Contains(w.Body.String(), "id: 8\nevent: task.assigned\n")
+	s.sum.AssertExpectations(s.T())
+}
+
+func (s *StreamControllerTestSuite) TestStreamProject_Forbidden() {
+	s.sum.On("SubscribeProject", "u2", "p1", uint64(0)).Return((*service.StreamSubscription)(nil), fmt.Errorf("failed to subscribe: %w", shared_model.ErrForbidden))
+	streamController := NewStreamController(s.sum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/stream/project/p1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "p1")
+	ctx.Set("user", "u2")
+	streamController.StreamProject(ctx)
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func (s *StreamControllerTestSuite) TestStreamUser_InvalidLastEventId() {
+	streamController := NewStreamController(s.sum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/stream/user?last_event_id=abc", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "u1")
+	streamController.StreamUser(ctx)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *StreamControllerTestSuite) TestStreamUser_RequiresTicket() {
+	s.sum.On("RedeemTicket", "used").Return("", fmt.Errorf("invalid or expired stream ticket"))
+	router := gin.New()
+	NewStreamController(s.sum, s.amm, router.Group("/pmh-api/v1")).Route()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/stream/user?ticket=used", nil)
+	router.ServeHTTP(w, req)
+
+	s.Equal(http.StatusUnauthorized, w.Code)
+	s.sum.AssertNotCalled(s.T(), "SubscribeUser", mock.Anything, mock.Anything)
+}
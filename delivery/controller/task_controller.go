@@ -35,6 +35,8 @@ func (t *TaskController) Route() {
 	t.rg.POST("/tasks/create", t.authMiddleware.RequireToken("MANAGER"), t.CreateTask)
 	t.rg.PUT("/tasks/update/:id", t.authMiddleware.RequireToken("MANAGER", "TEAM MEMBER"), t.UpdateTask)
 	t.rg.DELETE("/tasks/delete/:id", t.authMiddleware.RequireToken("MANAGER"), t.DeleteTask)
+	t.rg.PUT("/tasks/move/:id", t.authMiddleware.RequireToken("MANAGER"), t.MoveTask)
+	t.rg.POST("/tasks/clone/:id", t.authMiddleware.RequireToken("MANAGER"), t.CloneTask)
 }
 
 func (t *TaskController) CreateTask(c *gin.Context) {
@@ -80,12 +82,27 @@ func (t *TaskController) GetTaskById(c *gin.Context) {
 	}
 
 	log.Println("Success: ")
+	common.SetETag(c, task.Version)
 	common.SendSingleResponse(c, task, "Success")
 
 }
 
 func (t *TaskController) GetTaskByProjectId(c *gin.Context) {
 	projectid := c.Param("id")
+
+	// ?field[name]=value filters on custom field values
+	if filters := c.QueryMap("field"); len(filters) > 0 {
+		tasks, err := t.taskUC.FilterByProjectId(projectid, filters)
+		if err != nil {
+			log.Println(err.Error())
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		common.SendSingleResponse(c, tasks, "Success")
+		return
+	}
+
 	tasks, err := t.taskUC.GetByProjectId(projectid)
 	if err != nil {
 		log.Println(err.Error())
@@ -128,15 +145,24 @@ func (t *TaskController) UpdateTask(c *gin.Context) {
 		return
 	}
 
+	version, err := common.ParseIfMatch(c, newtask.Version)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+	newtask.Version = version
+
 	//disini cekrole if manager >> updattaskbymanager, if pic >> updatetaskbymember
 	task, err := t.taskUC.UpdateTask(userId, newtask)
 	if err != nil {
 		log.Println(err.Error())
-		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusInternalServerError), err.Error())
 		return
 	}
 
 	log.Println("Success: ")
+	common.SetETag(c, task.Version)
 	common.SendSingleResponse(c, task, "Success")
 }
 
@@ -154,3 +180,48 @@ func (t *TaskController) DeleteTask(c *gin.Context) {
 	log.Println("Success: ")
 	common.SendSingleResponse(c, nil, "Success")
 }
+
+func (t *TaskController) MoveTask(c *gin.Context) {
+	id := c.Param("id")
+
+	var request struct {
+		ProjectId string `json:"project_id"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := t.taskUC.MoveTask(c.GetString("user"), id, request.ProjectId)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SetETag(c, task.Version)
+	common.SendSingleResponse(c, task, "Success")
+}
+
+func (t *TaskController) CloneTask(c *gin.Context) {
+	id := c.Param("id")
+
+	var request model.TaskClone
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := t.taskUC.CloneTask(c.GetString("user"), id, request)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendCreatedResponse(c, task, "Created")
+}
@@ -376,3 +376,116 @@ func (s *TaskControllerTestSuite) TestGetTaskByProjectId_Fail() {
 	s.Contains(w.Body.String(), "not found")
 	s.tum.AssertExpectations(s.T())
 }
+
+func (s *TaskControllerTestSuite) TestGetTaskByProjectId_FilterByCustomField() {
+	// Arrange
+	projectId := "1"
+	s.tum.On("FilterByProjectId", projectId, map[string]string{"env": "prod"}).Return([]model.Task{{Id: "1", Name: "Task name 1"}}, nil)
+	taskController := NewTaskController(s.tum, s.amm, s.rg)
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/tasks/getbyprojectid/1?field[env]=prod", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", projectId)
+	taskController.GetTaskByProjectId(ctx)
+	// Assert
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "Task name 1")
+	s.tum.AssertNotCalled(s.T(), "GetByProjectId", projectId)
+}
+
+func (s *TaskControllerTestSuite) TestUpdateTask_Success() {
+	// Arrange
+	s.tum.On("UpdateTask", "1", model.Task{Id: "1", Status: "Blocked", Version: 2}).Return(model.Task{Id: "1", Status: "Blocked", Version: 3}, nil)
+	taskController := NewTaskController(s.tum, s.amm, s.rg)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/tasks/update/1", bytes.NewBufferString(`{"id": "1", "status": "Blocked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "1")
+	taskController.UpdateTask(ctx)
+
+	// Assert
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal(`"3"`, w.Header().Get("ETag"))
+	s.tum.AssertExpectations(s.T())
+}
+
+func (s *TaskControllerTestSuite) TestUpdateTask_VersionConflict() {
+	// Arrange
+	s.tum.On("UpdateTask", "1", mock.Anything).Return(model.Task{}, shared_model.ErrVersionConflict)
+	taskController := NewTaskController(s.tum, s.amm, s.rg)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/tasks/update/1", bytes.NewBufferString(`{"id": "1", "status": "Blocked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "1")
+	taskController.UpdateTask(ctx)
+
+	// Assert
+	s.Equal(http.StatusPreconditionFailed, w.Code)
+	s.tum.AssertExpectations(s.T())
+}
+
+func (s *TaskControllerTestSuite) TestUpdateTask_InvalidIfMatch() {
+	// Arrange
+	taskController := NewTaskController(s.tum, s.amm, s.rg)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/tasks/update/1", bytes.NewBufferString(`{"id": "1", "status": "Blocked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"abc"`)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "1")
+	taskController.UpdateTask(ctx)
+
+	// Assert
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.tum.AssertNotCalled(s.T(), "UpdateTask")
+}
+
+func (s *TaskControllerTestSuite) TestMoveTask_Success() {
+	// Arrange
+	s.tum.On("MoveTask", "manager", "WEB-1", "2").Return(model.Task{Id: "1", Key: "APP-1", ProjectId: "2", Version: 3}, nil)
+	taskController := NewTaskController(s.tum, s.amm, s.rg)
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/tasks/move/WEB-1", bytes.NewBufferString(`{"project_id":"2"}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "WEB-1")
+	ctx.Set("user", "manager")
+	taskController.MoveTask(ctx)
+	// Assert
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "APP-1")
+	s.tum.AssertExpectations(s.T())
+}
+
+func (s *TaskControllerTestSuite) TestCloneTask_Forbidden() {
+	// Arrange
+	s.tum.On("CloneTask", "manager", "1", model.TaskClone{ProjectId: "2", CopyReports: true}).Return(model.Task{}, fmt.Errorf("failed to clone task. %w", shared_model.ErrForbidden))
+	taskController := NewTaskController(s.tum, s.amm, s.rg)
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/tasks/clone/1", bytes.NewBufferString(`{"project_id":"2","copy_reports":true}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "1")
+	ctx.Set("user", "manager")
+	taskController.CloneTask(ctx)
+	// Assert
+	s.Equal(http.StatusForbidden, w.Code)
+	s.tum.AssertExpectations(s.T())
+}
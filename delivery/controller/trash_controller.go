@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController struct {
+	trashUC        usecase.TrashUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewTrashController(trashUC usecase.TrashUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *TrashController {
+	return &TrashController{
+		trashUC:        trashUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (t *TrashController) Route() {
+	t.rg.GET("/trash/:type", t.authMiddleware.RequireToken("ADMIN"), t.GetDeleted)
+	t.rg.PUT("/trash/:type/restore/:id", t.authMiddleware.RequireToken("ADMIN"), t.Restore)
+	t.rg.DELETE("/trash/purge", t.authMiddleware.RequireToken("ADMIN"), t.Purge)
+}
+
+func (t *TrashController) GetDeleted(c *gin.Context) {
+	entity := c.Param("type")
+	page, _ := strconv.Atoi(c.Query("page"))
+	size, _ := strconv.Atoi(c.Query("size"))
+
+	items, paging, err := t.trashUC.FindDeleted(entity, page, size)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var resp []interface{}
+	for _, v := range items {
+		resp = append(resp, v)
+	}
+
+	log.Println("Success: ")
+	common.SendPagedResponse(c, resp, paging, "OK")
+}
+
+func (t *TrashController) Restore(c *gin.Context) {
+	entity := c.Param("type")
+	id := c.Param("id")
+
+	if err := t.trashUC.Restore(c.GetString("user"), entity, id); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, nil, "Success")
+}
+
+func (t *TrashController) Purge(c *gin.Context) {
+	result, err := t.trashUC.PurgeExpired()
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, result, "Success")
+}
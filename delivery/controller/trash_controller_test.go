@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	tum *usecase_mock.TrashUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *TrashControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.tum = new(usecase_mock.TrashUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("ADMIN"))
+	s.rg = rg
+}
+
+func TestTrashControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashControllerTestSuite))
+}
+
+func (s *TrashControllerTestSuite) TestGetDeleted_Success() {
+	s.tum.On("FindDeleted", "tasks", 1, 5).Return([]model.TrashItem{{Id: "1", Type: "tasks", Name: "Deleted Task"}}, shared_model.Paging{Page: 1}, nil)
+	trashController := NewTrashController(s.tum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pmh-api/v1/trash/tasks?page=1&size=5", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("type", "tasks")
+	trashController.GetDeleted(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "Deleted Task")
+	s.tum.AssertExpectations(s.T())
+}
+
+func (s *TrashControllerTestSuite) TestRestore_Failure() {
+	s.tum.On("Restore", "1", "projects", "1").Return(fmt.Errorf("failed to restore projects"))
+	trashController := NewTrashController(s.tum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/pmh-api/v1/trash/projects/restore/1", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "1")
+	ctx.AddParam("type", "projects")
+	ctx.AddParam("id", "1")
+	trashController.Restore(ctx)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Contains(w.Body.String(), "failed to restore projects")
+	s.tum.AssertExpectations(s.T())
+}
+
+func (s *TrashControllerTestSuite) TestPurge_Success() {
+	s.tum.On("PurgeExpired").Return(model.PurgeResult{Tasks: 2}, nil)
+	trashController := NewTrashController(s.tum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/pmh-api/v1/trash/purge", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	trashController.Purge(ctx)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"tasks":2`)
+	s.tum.AssertExpectations(s.T())
+}
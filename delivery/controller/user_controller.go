@@ -73,6 +73,7 @@ func (a *UserController) FindUserById(c *gin.Context) {
 	// Log if success
 	log.Println("Success Get Resource")
 	// Return Success
+	common.SetETag(c, user.Version)
 	common.SendSingleResponse(c, user, "Success")
 }
 
@@ -130,19 +131,31 @@ func (a *UserController) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// Version from If-Match takes precedence over the body
+	version, err := common.ParseIfMatch(c, user.Version)
+	if err != nil {
+		// Log For Bad Request
+		log.Println("Failed to parse If-Match: " + err.Error())
+		// Return Bad Request, or Precondition Required without any version
+		common.SendErrorResponse(c, common.ErrorStatus(err, 400), err.Error())
+		return
+	}
+	user.Version = version
+
 	// Update User
 	updatedUser, err := a.userUC.UpdateUser(user)
 	if err != nil {
 		// Log For Update User Error
 		log.Println("Failed to update user: " + err.Error())
-		// Return Internal Server Error
-		common.SendErrorResponse(c, 500, err.Error())
+		// Return Precondition Failed on stale version, otherwise Internal Server Error
+		common.SendErrorResponse(c, common.ErrorStatus(err, 500), err.Error())
 		return
 	}
 
 	// Log For Success
 	log.Println("Success Update User")
 	// Return Success
+	common.SetETag(c, updatedUser.Version)
 	common.SendSingleResponse(c, updatedUser, "Success")
 }
 
@@ -159,8 +172,29 @@ func (a *UserController) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	// Reassignment targets come from the query string
+	reassign := model.UserReassignment{
+		TasksTo:    c.Query("tasks_to"),
+		ProjectsTo: c.Query("projects_to"),
+	}
+
+	// Dry run only previews what will be affected
+	if c.Query("dry_run") == "true" {
+		impact, err := a.userUC.DeleteUserPreview(id, reassign)
+		if err != nil {
+			// Log For Error
+			log.Println("Failed to preview delete user: " + err.Error())
+			// Return Bad Request
+			common.SendErrorResponse(c, 400, fmt.Sprintf("Failed to preview delete user: %s", err))
+			return
+		}
+		// Return Preview
+		common.SendSingleResponse(c, impact, "Delete User Preview")
+		return
+	}
+
 	// Delete User
-	err := a.userUC.DeleteUser(id)
+	err := a.userUC.DeleteUser(id, reassign)
 	if err != nil {
 		// Log For Error
 		log.Println("Failed to delete user: " + err.Error())
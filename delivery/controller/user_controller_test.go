@@ -163,12 +163,13 @@ func (a *userControllerTestSuite) TestCreateUserController_Failed() {
 
 // Test Update User Success
 func (a *userControllerTestSuite) TestUpdateUserController_Success() {
-	a.UserUc.On("UpdateUser", model.User{}).Return(ExpectedUser, nil)
+	a.UserUc.On("UpdateUser", model.User{Version: 1}).Return(ExpectedUser, nil)
 	userController := NewUserController(a.rg, a.authMiddleware, a.UserUc)
 	userController.Route()
 	requestBody := `{"name":"","email":"","password":"","role":""}`
 	request, err := http.NewRequest("PUT", "/pmh-api/v1/update/:id", strings.NewReader(requestBody))
 	a.Nil(err)
+	request.Header.Set("If-Match", `"1"`)
 
 	record := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(record)
@@ -181,12 +182,13 @@ func (a *userControllerTestSuite) TestUpdateUserController_Success() {
 // Test Update User Failed
 func (a *userControllerTestSuite) TestUpdateUserController_Failed() {
 	errorMessage := "update user failed"
-	a.UserUc.On("UpdateUser", model.User{}).Return(model.User{}, errors.New(errorMessage))
+	a.UserUc.On("UpdateUser", model.User{Version: 1}).Return(model.User{}, errors.New(errorMessage))
 	userController := NewUserController(a.rg, a.authMiddleware, a.UserUc)
 	userController.Route()
 	requestBody := `{"name":"","email":"","password":"","role":""}`
 	request, err := http.NewRequest("PUT", "/pmh-api/v1/update/:id", strings.NewReader(requestBody))
 	a.Nil(err)
+	request.Header.Set("If-Match", `"1"`)
 
 	record := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(record)
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"enigma.com/projectmanagementhub/delivery/middleware"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/common"
+	"enigma.com/projectmanagementhub/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	webhookUC      usecase.WebhookUsecase
+	authMiddleware middleware.AuthMiddleware
+	rg             *gin.RouterGroup
+}
+
+func NewWebhookController(webhookUC usecase.WebhookUsecase, authMiddleware middleware.AuthMiddleware, rg *gin.RouterGroup) *WebhookController {
+	return &WebhookController{
+		webhookUC:      webhookUC,
+		authMiddleware: authMiddleware,
+		rg:             rg,
+	}
+}
+
+func (w *WebhookController) Route() {
+	w.rg.POST("/webhooks", w.authMiddleware.RequireToken("ADMIN", "MANAGER"), w.CreateWebhook)
+	w.rg.GET("/webhooks", w.authMiddleware.RequireToken("ADMIN", "MANAGER"), w.GetWebhooks)
+	w.rg.PUT("/webhooks/:id", w.authMiddleware.RequireToken("ADMIN", "MANAGER"), w.UpdateWebhook)
+	w.rg.DELETE("/webhooks/:id", w.authMiddleware.RequireToken("ADMIN", "MANAGER"), w.DeleteWebhook)
+	w.rg.GET("/webhooks/:id/deliveries", w.authMiddleware.RequireToken("ADMIN", "MANAGER"), w.GetDeliveries)
+	w.rg.POST("/webhooks/deliveries/:id/redeliver", w.authMiddleware.RequireToken("ADMIN", "MANAGER"), w.Redeliver)
+}
+
+func (w *WebhookController) CreateWebhook(c *gin.Context) {
+	payload := model.Webhook{Active: true}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	webhook, err := w.webhookUC.CreateWebhook(c.GetString("user"), payload)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendCreatedResponse(c, webhook, "Created")
+}
+
+func (w *WebhookController) GetWebhooks(c *gin.Context) {
+	webhooks, err := w.webhookUC.GetWebhooks(c.GetString("user"), c.Query("project_id"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, webhooks, "OK")
+}
+
+func (w *WebhookController) UpdateWebhook(c *gin.Context) {
+	payload := model.Webhook{Active: true}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.Id = c.Param("id")
+
+	webhook, err := w.webhookUC.UpdateWebhook(c.GetString("user"), payload)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, webhook, "Updated")
+}
+
+func (w *WebhookController) DeleteWebhook(c *gin.Context) {
+	if err := w.webhookUC.DeleteWebhook(c.GetString("user"), c.Param("id")); err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendSingleResponse(c, nil, "Deleted")
+}
+
+func (w *WebhookController) GetDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	size, _ := strconv.Atoi(c.Query("size"))
+
+	deliveries, paging, err := w.webhookUC.GetDeliveries(c.GetString("user"), c.Param("id"), page, size)
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	var resp []interface{}
+	for _, v := range deliveries {
+		resp = append(resp, v)
+	}
+
+	log.Println("Success: ")
+	common.SendPagedResponse(c, resp, paging, "OK")
+}
+
+func (w *WebhookController) Redeliver(c *gin.Context) {
+	delivery, err := w.webhookUC.Redeliver(c.GetString("user"), c.Param("id"))
+	if err != nil {
+		log.Println(err.Error())
+		common.SendErrorResponse(c, common.ErrorStatus(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	log.Println("Success: ")
+	common.SendCreatedResponse(c, delivery, "Queued")
+}
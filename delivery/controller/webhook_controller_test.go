@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/middleware_mock"
+	"enigma.com/projectmanagementhub/mock/usecase_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookControllerTestSuite struct {
+	suite.Suite
+	rg  *gin.RouterGroup
+	wum *usecase_mock.WebhookUsecaseMock
+	amm *middleware_mock.AuthMiddlewareMock
+}
+
+func (s *WebhookControllerTestSuite) SetupTest() {
+	s.amm = new(middleware_mock.AuthMiddlewareMock)
+	s.wum = new(usecase_mock.WebhookUsecaseMock)
+
+	router := gin.Default()
+	gin.SetMode(gin.TestMode)
+	rg := router.Group("/pmh-api/v1")
+	rg.Use(s.amm.RequireToken("ADMIN", "MANAGER"))
+	s.rg = rg
+}
+
+func TestWebhookControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookControllerTestSuite))
+}
+
+func (s *WebhookControllerTestSuite) TestCreateWebhook_DefaultsToActive() {
+	payload := model.Webhook{ProjectId: "p1", Url: "https://ci.example.com/hook", EventTypes: []string{model.EventTaskCreated}, Active: true}
+	s.wum.On("CreateWebhook", "m1", payload).Return(model.Webhook{Id: "w1", ProjectId: "p1", Secret: "s"}, nil)
+	webhookController := NewWebhookController(s.wum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pmh-api/v1/webhooks", bytes.NewBufferString(`{"project_id":"p1","url":"https://ci.example.com/hook","event_types":["task.created"]}`))
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "m1")
+	webhookController.CreateWebhook(ctx)
+
+	s.Equal(http.StatusCreated, w.Code)
+	s.wum.AssertExpectations(s.T())
+}
+
+func (s *WebhookControllerTestSuite) TestGetWebhooks_Forbidden() {
+	s.wum.On("GetWebhooks", "m1", "").Return([]model.Webhook(nil), fmt.Errorf("failed to get webhooks: %w", shared_model.ErrForbidden))
+	webhookController := NewWebhookController(s.wum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/pmh-api/v1/webhooks", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.Set("user", "m1")
+	webhookController.GetWebhooks(ctx)
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func (s *WebhookControllerTestSuite) TestRedeliver_Success() {
+	s.wum.On("Redeliver", "m1", "d1").Return(model.WebhookDelivery{Id: "d2", Status: model.DeliveryPending}, nil)
+	webhookController := NewWebhookController(s.wum, s.amm, s.rg)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pmh-api/v1/webhooks/deliveries/d1/redeliver", nil)
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+	ctx.AddParam("id", "d1")
+	ctx.Set("user", "m1")
+	webhookController.Redeliver(ctx)
+
+	s.Equal(http.StatusCreated, w.Code)
+}
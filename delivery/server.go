@@ -1,13 +1,16 @@
 package delivery
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"enigma.com/projectmanagementhub/config"
 	"enigma.com/projectmanagementhub/delivery/controller"
 	"enigma.com/projectmanagementhub/delivery/middleware"
 	"enigma.com/projectmanagementhub/report"
+	"enigma.com/projectmanagementhub/scheduler"
 	"enigma.com/projectmanagementhub/shared/service"
 
 	"enigma.com/projectmanagementhub/repository"
@@ -16,18 +19,40 @@ import (
 )
 
 type Server struct {
-	userUC     usecase.UserUseCase
-	taskUC     usecase.TaskUsecase
-	projectUC  usecase.ProjectUseCase
-	reportUC   usecase.ReportUsecase
-	authUC     usecase.AuthUsecase
-	engine     *gin.Engine
-	jwtService service.JwtService
-	host       string
+	userUC         usecase.UserUseCase
+	taskUC         usecase.TaskUsecase
+	projectUC      usecase.ProjectUseCase
+	reportUC       usecase.ReportUsecase
+	authUC         usecase.AuthUsecase
+	trashUC        usecase.TrashUsecase
+	sprintUC       usecase.SprintUsecase
+	chartUC        usecase.ChartUsecase
+	boardUC        usecase.BoardUsecase
+	reminderUC     usecase.ReminderUsecase
+	notificationUC usecase.NotificationUsecase
+	mailUC         usecase.MailUsecase
+	webhookUC      usecase.WebhookUsecase
+	streamUC       usecase.StreamUsecase
+	statusReportUC usecase.StatusReportUsecase
+	purgeJob       *scheduler.PurgeJob
+	reminderJob    *scheduler.ReminderJob
+	mailJob        *scheduler.MailJob
+	webhookJob     *scheduler.WebhookJob
+	statusJob      *scheduler.StatusReportJob
+	archiveJob     *scheduler.ArchiveJob
+	engine         *gin.Engine
+	jwtService     service.JwtService
+	host           string
 }
 
 func (s *Server) Run() {
 	s.initRoute()
+	s.purgeJob.Start(context.Background())
+	s.reminderJob.Start(context.Background())
+	s.mailJob.Start(context.Background())
+	s.webhookJob.Start(context.Background())
+	s.statusJob.Start(context.Background())
+	s.archiveJob.Start(context.Background())
 	if err := s.engine.Run(s.host); err != nil {
 		panic(fmt.Errorf("failed to start server: %v", err))
 	}
@@ -40,8 +65,17 @@ func (s *Server) initRoute() {
 	controller.NewUserController(rg, authMiddleware, s.userUC).Route()
 	controller.NewTaskController(s.taskUC, authMiddleware, rg).Route()
 	controller.NewProjectController(s.projectUC, authMiddleware, rg).Route()
-	controller.NewReportController(s.reportUC, authMiddleware, rg).Route()
+	controller.NewReportController(s.reportUC, s.statusReportUC, authMiddleware, rg).Route()
 	controller.NewAuthController(s.authUC, rg).Route()
+	controller.NewTrashController(s.trashUC, authMiddleware, rg).Route()
+	controller.NewSprintController(s.sprintUC, authMiddleware, rg).Route()
+	controller.NewChartController(s.chartUC, authMiddleware, rg).Route()
+	controller.NewBoardController(s.boardUC, authMiddleware, rg).Route()
+	controller.NewReminderController(s.reminderUC, authMiddleware, rg).Route()
+	controller.NewNotificationController(s.notificationUC, authMiddleware, rg).Route()
+	controller.NewMailController(s.mailUC, authMiddleware, rg).Route()
+	controller.NewWebhookController(s.webhookUC, authMiddleware, rg).Route()
+	controller.NewStreamController(s.streamUC, authMiddleware, rg).Route()
 
 }
 
@@ -58,19 +92,50 @@ func NewServer() *Server {
 		panic(err)
 	}
 
-	report := report.NewReportToTXT(cfg.PathConfig)
+	reportSink, err := report.NewReportSink(cfg.PathConfig)
+	if err != nil {
+		panic(err)
+	}
 
 	//inject db ke repository
-	taskRepository := repository.NewTaskRepository(db)
+	taskRepository := repository.NewTaskRepository(db, reportSink)
 	userRepository := repository.NewUserRepository(db)
-	projectRepository := repository.NewProjectRepository(db)
-	reportRepository := repository.NewReportRepository(db, report)
+	projectRepository := repository.NewProjectRepository(db, reportSink)
+	reportRepository := repository.NewReportRepository(db, reportSink)
+	trashRepository := repository.NewTrashRepository(db, reportSink)
+	milestoneRepository := repository.NewMilestoneRepository(db)
+	sprintRepository := repository.NewSprintRepository(db)
+	chartRepository := repository.NewChartRepository(db)
+	boardRepository := repository.NewBoardRepository(db)
+	customFieldRepository := repository.NewCustomFieldRepository(db)
+	reminderRepository := repository.NewReminderRepository(db)
+	notificationRepository := repository.NewNotificationRepository(db)
+	mailRepository := repository.NewMailRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+	statusReportRepository := repository.NewStatusReportRepository(db)
+
+	eventService := service.NewEventService()
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepository)
+	eventService.Subscribe(notificationUsecase.Notify)
+	mailUsecase := usecase.NewMailUsecase(mailRepository, userRepository, notificationRepository, service.NewMailer(cfg.MailConfig), cfg.MailDefaultMode)
+	eventService.Subscribe(mailUsecase.Notify)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepository, projectRepository, userRepository, service.NewWebhookClient(cfg.WebhookTimeout), cfg.WebhookMaxAttempts)
+	eventService.Subscribe(webhookUsecase.Dispatch)
+	streamService := service.NewStreamService(cfg.StreamHistorySize)
+	eventService.Subscribe(streamService.Publish)
+	streamUsecase := usecase.NewStreamUsecase(streamService, projectRepository, userRepository)
 
 	//inject repository ke usecase
 	UserUseCase := usecase.NewUserUseCase(userRepository)
-	taskUsecase := usecase.NewTaskUsecase(taskRepository, userRepository, projectRepository)
-	projectUsecase := usecase.NewProjectUseCase(projectRepository, userRepository)
-	reportUsecase := usecase.NewReportUsecase(reportRepository, taskRepository)
+	taskUsecase := usecase.NewTaskUsecase(taskRepository, userRepository, projectRepository, customFieldRepository, eventService)
+	projectUsecase := usecase.NewProjectUseCase(projectRepository, userRepository, milestoneRepository, customFieldRepository, eventService)
+	reportUsecase := usecase.NewReportUsecase(reportRepository, taskRepository, projectRepository, userRepository, eventService, report.NewJournalVerifier(cfg.PathConfig), report.NewJournalArchiver(cfg.PathConfig, cfg.ReportArchiveConfig))
+	sprintUsecase := usecase.NewSprintUsecase(sprintRepository, projectRepository)
+	chartUsecase := usecase.NewChartUsecase(chartRepository, projectRepository, sprintRepository)
+	boardUsecase := usecase.NewBoardUsecase(boardRepository, taskRepository, userRepository, projectRepository, eventService)
+	trashUsecase := usecase.NewTrashUsecase(trashRepository, time.Duration(cfg.RetentionDays)*24*time.Hour)
+	statusReportUsecase := usecase.NewStatusReportUsecase(statusReportRepository, projectRepository, userRepository)
+	reminderUsecase := usecase.NewReminderUsecase(reminderRepository, projectRepository, eventService, cfg.ReminderDaysBefore, cfg.ReminderEscalateAfter)
 
 	jwtService := service.NewJwtService(cfg.TokenConfig)
 	authUsecase := usecase.NewAuthUsecase(UserUseCase, jwtService)
@@ -79,13 +144,29 @@ func NewServer() *Server {
 	host := cfg.ApiPort
 
 	return &Server{
-		userUC:     UserUseCase,
-		taskUC:     taskUsecase,
-		projectUC:  projectUsecase,
-		reportUC:   reportUsecase,
-		engine:     engine,
-		host:       host,
-		authUC:     authUsecase,
-		jwtService: jwtService,
+		userUC:         UserUseCase,
+		taskUC:         taskUsecase,
+		projectUC:      projectUsecase,
+		reportUC:       reportUsecase,
+		engine:         engine,
+		host:           host,
+		authUC:         authUsecase,
+		trashUC:        trashUsecase,
+		sprintUC:       sprintUsecase,
+		chartUC:        chartUsecase,
+		boardUC:        boardUsecase,
+		reminderUC:     reminderUsecase,
+		notificationUC: notificationUsecase,
+		mailUC:         mailUsecase,
+		webhookUC:      webhookUsecase,
+		streamUC:       streamUsecase,
+		statusReportUC: statusReportUsecase,
+		purgeJob:       scheduler.NewPurgeJob(trashUsecase, cfg.PurgeInterval),
+		reminderJob:    scheduler.NewReminderJob(reminderUsecase, cfg.ReminderInterval),
+		mailJob:        scheduler.NewMailJob(mailUsecase, cfg.MailSendInterval, cfg.MailDigestInterval),
+		webhookJob:     scheduler.NewWebhookJob(webhookUsecase, cfg.WebhookInterval),
+		statusJob:      scheduler.NewStatusReportJob(statusReportUsecase, cfg.StatusReportInterval),
+		archiveJob:     scheduler.NewArchiveJob(reportUsecase, cfg.ReportArchiveInterval),
+		jwtService:     jwtService,
 	}
 }
@@ -0,0 +1,23 @@
+package report_mock
+
+import (
+	"io"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type JournalArchiverMock struct {
+	mock.Mock
+}
+
+func (m *JournalArchiverMock) Archive(now time.Time) (model.JournalArchive, error) {
+	args := m.Called(now)
+	return args.Get(0).(model.JournalArchive), args.Error(1)
+}
+
+func (m *JournalArchiverMock) Export(from time.Time, to time.Time, w io.Writer) error {
+	args := m.Called(from, to, w)
+	return args.Error(0)
+}
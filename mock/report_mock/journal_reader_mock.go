@@ -0,0 +1,17 @@
+package report_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type JournalReaderMock struct {
+	mock.Mock
+}
+
+func (m *JournalReaderMock) Read(from time.Time, to time.Time) ([]model.JournalEntry, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]model.JournalEntry), args.Error(1)
+}
@@ -0,0 +1,17 @@
+package report_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type JournalVerifierMock struct {
+	mock.Mock
+}
+
+func (m *JournalVerifierMock) Verify(from time.Time, to time.Time) (model.JournalVerification, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(model.JournalVerification), args.Error(1)
+}
@@ -0,0 +1,30 @@
+package repository_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type BoardRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *BoardRepositoryMock) GetTasks(projectId string) ([]model.Task, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.Task), args.Error(1)
+}
+
+func (m *BoardRepositoryMock) GetWipLimits(projectId string) (map[string]int, error) {
+	args := m.Called(projectId)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *BoardRepositoryMock) SetWipLimits(projectId string, limits map[string]int) error {
+	args := m.Called(projectId, limits)
+	return args.Error(0)
+}
+
+func (m *BoardRepositoryMock) MoveTask(move model.BoardMove) (model.Task, error) {
+	args := m.Called(move)
+	return args.Get(0).(model.Task), args.Error(1)
+}
@@ -0,0 +1,20 @@
+package repository_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type ChartRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ChartRepositoryMock) GetProjectTasks(projectId string) ([]model.ChartTask, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.ChartTask), args.Error(1)
+}
+
+func (m *ChartRepositoryMock) GetSprintTasks(sprintId string) ([]model.ChartTask, error) {
+	args := m.Called(sprintId)
+	return args.Get(0).([]model.ChartTask), args.Error(1)
+}
@@ -0,0 +1,35 @@
+package repository_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type CustomFieldRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CustomFieldRepositoryMock) GetByProjectId(projectId string) ([]model.CustomField, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepositoryMock) GetById(id string) (model.CustomField, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepositoryMock) Create(payload model.CustomField) (model.CustomField, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepositoryMock) Update(payload model.CustomField) (model.CustomField, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldRepositoryMock) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,35 @@
+package repository_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MailRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *MailRepositoryMock) GetMode(userId string) (string, error) {
+	args := m.Called(userId)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MailRepositoryMock) SaveMode(userId string, mode string) error {
+	args := m.Called(userId, mode)
+	return args.Error(0)
+}
+
+func (m *MailRepositoryMock) Enqueue(payload model.QueuedMail) error {
+	args := m.Called(payload)
+	return args.Error(0)
+}
+
+func (m *MailRepositoryMock) GetPending(digest bool) ([]model.QueuedMail, error) {
+	args := m.Called(digest)
+	return args.Get(0).([]model.QueuedMail), args.Error(1)
+}
+
+func (m *MailRepositoryMock) MarkSent(ids []int64) error {
+	args := m.Called(ids)
+	return args.Error(0)
+}
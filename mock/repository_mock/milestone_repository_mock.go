@@ -0,0 +1,40 @@
+package repository_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MilestoneRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *MilestoneRepositoryMock) GetByProjectId(projectId string) ([]model.Milestone, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.Milestone), args.Error(1)
+}
+
+func (m *MilestoneRepositoryMock) GetById(id string) (model.Milestone, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Milestone), args.Error(1)
+}
+
+func (m *MilestoneRepositoryMock) Create(payload model.Milestone) (model.Milestone, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Milestone), args.Error(1)
+}
+
+func (m *MilestoneRepositoryMock) Update(payload model.Milestone) (model.Milestone, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Milestone), args.Error(1)
+}
+
+func (m *MilestoneRepositoryMock) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MilestoneRepositoryMock) AttachTasks(id string, taskIds []string) error {
+	args := m.Called(id, taskIds)
+	return args.Error(0)
+}
@@ -0,0 +1,46 @@
+package repository_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/mock"
+)
+
+type NotificationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *NotificationRepositoryMock) Create(payload model.Notification) error {
+	args := m.Called(payload)
+	return args.Error(0)
+}
+
+func (m *NotificationRepositoryMock) GetByUserId(userId string, unreadOnly bool, page int, size int) ([]model.Notification, shared_model.Paging, error) {
+	args := m.Called(userId, unreadOnly, page, size)
+	return args.Get(0).([]model.Notification), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *NotificationRepositoryMock) CountUnread(userId string) (int, error) {
+	args := m.Called(userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) MarkRead(userId string, id string) error {
+	args := m.Called(userId, id)
+	return args.Error(0)
+}
+
+func (m *NotificationRepositoryMock) MarkAllRead(userId string) (int64, error) {
+	args := m.Called(userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) GetPreferences(userId string) (map[string]bool, error) {
+	args := m.Called(userId)
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *NotificationRepositoryMock) SetPreferences(userId string, preferences map[string]bool) error {
+	args := m.Called(userId, preferences)
+	return args.Error(0)
+}
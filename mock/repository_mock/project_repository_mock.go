@@ -60,7 +60,12 @@ func (m *ProjectRepositoryMock) Update(payload model.Project) (model.Project, er
 	return args.Get(0).(model.Project), args.Error(1)
 }
 
-func (m *ProjectRepositoryMock) Delete(id string) error {
-	args := m.Called(id)
+func (m *ProjectRepositoryMock) Delete(id string, actorId string) error {
+	args := m.Called(id, actorId)
 	return args.Error(0)
 }
+
+func (m *ProjectRepositoryMock) GetDeleteImpact(id string) (model.DeleteImpact, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DeleteImpact), args.Error(1)
+}
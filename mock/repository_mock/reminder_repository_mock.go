@@ -0,0 +1,37 @@
+package repository_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type ReminderRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReminderRepositoryMock) GetSetting(projectId string) (model.ReminderSetting, error) {
+	args := m.Called(projectId)
+	return args.Get(0).(model.ReminderSetting), args.Error(1)
+}
+
+func (m *ReminderRepositoryMock) SaveSetting(setting model.ReminderSetting) (model.ReminderSetting, error) {
+	args := m.Called(setting)
+	return args.Get(0).(model.ReminderSetting), args.Error(1)
+}
+
+func (m *ReminderRepositoryMock) GetDueTasks(today time.Time, daysBefore int, escalateAfterDays int) ([]model.DueItem, error) {
+	args := m.Called(today, daysBefore, escalateAfterDays)
+	return args.Get(0).([]model.DueItem), args.Error(1)
+}
+
+func (m *ReminderRepositoryMock) GetDueProjects(today time.Time, daysBefore int) ([]model.DueItem, error) {
+	args := m.Called(today, daysBefore)
+	return args.Get(0).([]model.DueItem), args.Error(1)
+}
+
+func (m *ReminderRepositoryMock) MarkSent(reminder model.Reminder) (bool, error) {
+	args := m.Called(reminder)
+	return args.Bool(0), args.Error(1)
+}
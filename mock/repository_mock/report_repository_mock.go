@@ -2,6 +2,7 @@ package repository_mock
 
 import (
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -34,8 +35,8 @@ func (m *ReportRepositoryMock) UpdateReport(payload model.Report) (model.Report,
 	return result, args.Error(1)
 }
 
-func (m *ReportRepositoryMock) DeleteReportById(id string) error {
-	args := m.Called(id)
+func (m *ReportRepositoryMock) DeleteReportById(id string, actorId string) error {
+	args := m.Called(id, actorId)
 	return args.Error(0)
 }
 
@@ -60,3 +61,33 @@ func (m *ReportRepositoryMock) GetReportByUserId(userId string) ([]model.Report,
 
 	return result, args.Error(1)
 }
+
+func (m *ReportRepositoryMock) SearchReports(filter model.ReportFilter, page int, size int) ([]model.Report, shared_model.Paging, error) {
+	args := m.Called(filter, page, size)
+	return args.Get(0).([]model.Report), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *ReportRepositoryMock) GetReportById(id string) (model.Report, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Report), args.Error(1)
+}
+
+func (m *ReportRepositoryMock) ReviewReport(id string, reviewerId string, review model.ReportReview) (model.Report, error) {
+	args := m.Called(id, reviewerId, review)
+	return args.Get(0).(model.Report), args.Error(1)
+}
+
+func (m *ReportRepositoryMock) GetMissingReports(projectId string, date string) ([]model.MissingReport, error) {
+	args := m.Called(projectId, date)
+	return args.Get(0).([]model.MissingReport), args.Error(1)
+}
+
+func (m *ReportRepositoryMock) GetReportsByIds(ids []string) (map[string]model.Report, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[string]model.Report), args.Error(1)
+}
+
+func (m *ReportRepositoryMock) ImportReport(payload model.Report, readVersion int) error {
+	args := m.Called(payload, readVersion)
+	return args.Error(0)
+}
@@ -0,0 +1,50 @@
+package repository_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type SprintRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SprintRepositoryMock) GetByProjectId(projectId string) ([]model.Sprint, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.Sprint), args.Error(1)
+}
+
+func (m *SprintRepositoryMock) GetById(id string) (model.Sprint, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintRepositoryMock) Create(payload model.Sprint) (model.Sprint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintRepositoryMock) Update(payload model.Sprint) (model.Sprint, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintRepositoryMock) Start(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *SprintRepositoryMock) Close(id string, rolloverTo string) (int64, error) {
+	args := m.Called(id, rolloverTo)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *SprintRepositoryMock) AddTasks(id string, taskIds []string) error {
+	args := m.Called(id, taskIds)
+	return args.Error(0)
+}
+
+func (m *SprintRepositoryMock) RemoveTasks(id string, taskIds []string) error {
+	args := m.Called(id, taskIds)
+	return args.Error(0)
+}
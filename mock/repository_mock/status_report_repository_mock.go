@@ -0,0 +1,47 @@
+package repository_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type StatusReportRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *StatusReportRepositoryMock) GetStatusChanges(projectId string, start time.Time, end time.Time) ([]model.TaskStatusChange, error) {
+	args := m.Called(projectId, start, end)
+	return args.Get(0).([]model.TaskStatusChange), args.Error(1)
+}
+
+func (m *StatusReportRepositoryMock) GetOverdueTasks(projectId string, asOf time.Time) ([]model.OverdueTask, error) {
+	args := m.Called(projectId, asOf)
+	return args.Get(0).([]model.OverdueTask), args.Error(1)
+}
+
+func (m *StatusReportRepositoryMock) GetMemberReports(projectId string, start time.Time, end time.Time) ([]model.MemberReport, error) {
+	args := m.Called(projectId, start, end)
+	return args.Get(0).([]model.MemberReport), args.Error(1)
+}
+
+func (m *StatusReportRepositoryMock) Save(payload model.StatusReport) (model.StatusReport, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.StatusReport), args.Error(1)
+}
+
+func (m *StatusReportRepositoryMock) GetById(id string) (model.StatusReport, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StatusReport), args.Error(1)
+}
+
+func (m *StatusReportRepositoryMock) GetByProjectId(projectId string) ([]model.StatusReport, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.StatusReport), args.Error(1)
+}
+
+func (m *StatusReportRepositoryMock) GetProjectsWithoutReport(startDate string, endDate string) ([]string, error) {
+	args := m.Called(startDate, endDate)
+	return args.Get(0).([]string), args.Error(1)
+}
@@ -25,11 +25,31 @@ func (m *TaskRepositoryMock) GetByPersonInCharge(Id string) ([]model.Task, error
 	return args.Get(0).([]model.Task), args.Error(1)
 }
 
+func (m *TaskRepositoryMock) MoveToProject(id string, projectId string) (model.Task, error) {
+	args := m.Called(id, projectId)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
+func (m *TaskRepositoryMock) Clone(id string, payload model.TaskClone, actorId string) (model.Task, error) {
+	args := m.Called(id, payload, actorId)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
+func (m *TaskRepositoryMock) GetByKey(key string) (model.Task, error) {
+	args := m.Called(key)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
 func (m *TaskRepositoryMock) GetByProjectId(Id string) ([]model.Task, error) {
 	args := m.Called(Id)
 	return args.Get(0).([]model.Task), args.Error(1)
 }
 
+func (m *TaskRepositoryMock) GetByProjectIdAndFields(Id string, fields model.CustomFieldValues) ([]model.Task, error) {
+	args := m.Called(Id, fields)
+	return args.Get(0).([]model.Task), args.Error(1)
+}
+
 func (m *TaskRepositoryMock) CreateTask(payload model.Task) (model.Task, error) {
 	args := m.Called(payload)
 	return args.Get(0).(model.Task), args.Error(1)
@@ -0,0 +1,43 @@
+package repository_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/mock"
+)
+
+type TrashRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *TrashRepositoryMock) GetDeleted(entity string, page int, size int) ([]model.TrashItem, shared_model.Paging, error) {
+	args := m.Called(entity, page, size)
+	return args.Get(0).([]model.TrashItem), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *TrashRepositoryMock) RestoreUser(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *TrashRepositoryMock) RestoreProject(id string, actorId string) error {
+	args := m.Called(id, actorId)
+	return args.Error(0)
+}
+
+func (m *TrashRepositoryMock) RestoreTask(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *TrashRepositoryMock) RestoreReport(id string, actorId string) error {
+	args := m.Called(id, actorId)
+	return args.Error(0)
+}
+
+func (m *TrashRepositoryMock) Purge(before time.Time) (model.PurgeResult, error) {
+	args := m.Called(before)
+	return args.Get(0).(model.PurgeResult), args.Error(1)
+}
@@ -35,7 +35,12 @@ func (m *UserRepositoryMock) Update(payload model.User) (model.User, error) {
 	return args.Get(0).(model.User), args.Error(1)
 }
 
-func (m *UserRepositoryMock) Delete(id string) error {
-	args := m.Called(id)
+func (m *UserRepositoryMock) Delete(id string, reassign model.UserReassignment) error {
+	args := m.Called(id, reassign)
 	return args.Error(0)
 }
+
+func (m *UserRepositoryMock) GetDeleteImpact(id string) (model.DeleteImpact, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DeleteImpact), args.Error(1)
+}
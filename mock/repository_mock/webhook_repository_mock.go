@@ -0,0 +1,68 @@
+package repository_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *WebhookRepositoryMock) Create(payload model.Webhook) (model.Webhook, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) GetById(id string) (model.Webhook, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) GetByProjectId(projectId string) ([]model.Webhook, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Update(payload model.Webhook) (model.Webhook, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) Enqueue(event model.Event, payload []byte) (int64, error) {
+	args := m.Called(event, payload)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) GetDueDeliveries(now time.Time, claimUntil time.Time, limit int) ([]model.WebhookDelivery, error) {
+	args := m.Called(now, claimUntil, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) SaveAttempt(delivery model.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) GetDeliveries(webhookId string, page int, size int) ([]model.WebhookDelivery, shared_model.Paging, error) {
+	args := m.Called(webhookId, page, size)
+	return args.Get(0).([]model.WebhookDelivery), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *WebhookRepositoryMock) GetDeliveryById(id string) (model.WebhookDelivery, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) Redeliver(id string) (model.WebhookDelivery, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.WebhookDelivery), args.Error(1)
+}
@@ -0,0 +1,15 @@
+package service_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MailerMock struct {
+	mock.Mock
+}
+
+func (m *MailerMock) Send(mail model.Mail) error {
+	args := m.Called(mail)
+	return args.Error(0)
+}
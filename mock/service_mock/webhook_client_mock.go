@@ -0,0 +1,14 @@
+package service_mock
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookClientMock struct {
+	mock.Mock
+}
+
+func (m *WebhookClientMock) Post(url string, secret string, eventType string, deliveryId string, body []byte) (int, error) {
+	args := m.Called(url, secret, eventType, deliveryId, body)
+	return args.Int(0), args.Error(1)
+}
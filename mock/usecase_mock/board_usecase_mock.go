@@ -0,0 +1,25 @@
+package usecase_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type BoardUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *BoardUsecaseMock) GetBoard(projectId string) (model.Board, error) {
+	args := m.Called(projectId)
+	return args.Get(0).(model.Board), args.Error(1)
+}
+
+func (m *BoardUsecaseMock) MoveTask(userId string, move model.BoardMove) (model.Task, error) {
+	args := m.Called(userId, move)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
+func (m *BoardUsecaseMock) SetWipLimits(userId string, projectId string, limits map[string]int) error {
+	args := m.Called(userId, projectId, limits)
+	return args.Error(0)
+}
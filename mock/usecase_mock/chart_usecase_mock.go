@@ -0,0 +1,20 @@
+package usecase_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type ChartUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *ChartUsecaseMock) ProjectBurn(projectId string) (model.BurnChart, error) {
+	args := m.Called(projectId)
+	return args.Get(0).(model.BurnChart), args.Error(1)
+}
+
+func (m *ChartUsecaseMock) SprintBurn(sprintId string) (model.BurnChart, error) {
+	args := m.Called(sprintId)
+	return args.Get(0).(model.BurnChart), args.Error(1)
+}
@@ -0,0 +1,36 @@
+package usecase_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MailUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *MailUsecaseMock) Notify(event model.Event) {
+	m.Called(event)
+}
+
+func (m *MailUsecaseMock) SendPending() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MailUsecaseMock) SendDigests(dueBefore time.Time) (int, error) {
+	args := m.Called(dueBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MailUsecaseMock) GetMode(userId string) (string, error) {
+	args := m.Called(userId)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MailUsecaseMock) UpdateMode(userId string, mode string) error {
+	args := m.Called(userId, mode)
+	return args.Error(0)
+}
@@ -0,0 +1,45 @@
+package usecase_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/mock"
+)
+
+type NotificationUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *NotificationUsecaseMock) Notify(event model.Event) {
+	m.Called(event)
+}
+
+func (m *NotificationUsecaseMock) GetNotifications(userId string, unreadOnly bool, page int, size int) ([]model.Notification, shared_model.Paging, error) {
+	args := m.Called(userId, unreadOnly, page, size)
+	return args.Get(0).([]model.Notification), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *NotificationUsecaseMock) CountUnread(userId string) (int, error) {
+	args := m.Called(userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *NotificationUsecaseMock) MarkRead(userId string, id string) error {
+	args := m.Called(userId, id)
+	return args.Error(0)
+}
+
+func (m *NotificationUsecaseMock) MarkAllRead(userId string) (int64, error) {
+	args := m.Called(userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationUsecaseMock) GetPreferences(userId string) (map[string]bool, error) {
+	args := m.Called(userId)
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *NotificationUsecaseMock) UpdatePreferences(userId string, preferences map[string]bool) (map[string]bool, error) {
+	args := m.Called(userId, preferences)
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
@@ -60,7 +60,57 @@ func (m *ProjectUseCaseMock) Update(project model.Project) (model.Project, error
 	return args.Get(0).(model.Project), args.Error(1)
 }
 
-func (m *ProjectUseCaseMock) Delete(id string) error {
+func (m *ProjectUseCaseMock) Delete(userId string, id string) error {
+	args := m.Called(userId, id)
+	return args.Error(0)
+}
+
+func (m *ProjectUseCaseMock) DeletePreview(id string) (model.DeleteImpact, error) {
 	args := m.Called(id)
+	return args.Get(0).(model.DeleteImpact), args.Error(1)
+}
+
+func (m *ProjectUseCaseMock) GetMilestones(projectId string, userId string) ([]model.Milestone, error) {
+	args := m.Called(projectId, userId)
+	return args.Get(0).([]model.Milestone), args.Error(1)
+}
+
+func (m *ProjectUseCaseMock) CreateMilestone(userId string, payload model.Milestone) (model.Milestone, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.Milestone), args.Error(1)
+}
+
+func (m *ProjectUseCaseMock) UpdateMilestone(userId string, payload model.Milestone) (model.Milestone, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.Milestone), args.Error(1)
+}
+
+func (m *ProjectUseCaseMock) DeleteMilestone(userId string, id string) error {
+	args := m.Called(userId, id)
+	return args.Error(0)
+}
+
+func (m *ProjectUseCaseMock) AttachMilestoneTasks(userId string, id string, taskIds []string) error {
+	args := m.Called(userId, id, taskIds)
+	return args.Error(0)
+}
+
+func (m *ProjectUseCaseMock) GetCustomFields(projectId string, userId string) ([]model.CustomField, error) {
+	args := m.Called(projectId, userId)
+	return args.Get(0).([]model.CustomField), args.Error(1)
+}
+
+func (m *ProjectUseCaseMock) CreateCustomField(userId string, payload model.CustomField) (model.CustomField, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.CustomField), args.Error(1)
+}
+
+func (m *ProjectUseCaseMock) UpdateCustomField(userId string, payload model.CustomField) (model.CustomField, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.CustomField), args.Error(1)
+}
+
+func (m *ProjectUseCaseMock) DeleteCustomField(userId string, id string) error {
+	args := m.Called(userId, id)
 	return args.Error(0)
 }
@@ -0,0 +1,27 @@
+package usecase_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type ReminderUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *ReminderUsecaseMock) GetSetting(userId string, projectId string) (model.ReminderSetting, error) {
+	args := m.Called(userId, projectId)
+	return args.Get(0).(model.ReminderSetting), args.Error(1)
+}
+
+func (m *ReminderUsecaseMock) UpdateSetting(userId string, setting model.ReminderSetting) (model.ReminderSetting, error) {
+	args := m.Called(userId, setting)
+	return args.Get(0).(model.ReminderSetting), args.Error(1)
+}
+
+func (m *ReminderUsecaseMock) SendDue(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
@@ -1,7 +1,10 @@
 package usecase_mock
 
 import (
+	"io"
+
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -11,18 +14,18 @@ type ReportUsecaseMock struct {
 
 // GetReportByTaskId implements usecase.ReportUsecase.
 
-func (r *ReportUsecaseMock) CreateReport(payload model.Report) (model.Report, error) {
-	args := r.Called(payload)
+func (r *ReportUsecaseMock) CreateReport(userId string, payload model.Report) (model.Report, error) {
+	args := r.Called(userId, payload)
 	return args.Get(0).(model.Report), args.Error(1)
 }
 
-func (r *ReportUsecaseMock) UpdateReport(payload model.Report) (model.Report, error) {
-	args := r.Called(payload)
+func (r *ReportUsecaseMock) UpdateReport(userId string, payload model.Report) (model.Report, error) {
+	args := r.Called(userId, payload)
 	return args.Get(0).(model.Report), args.Error(1)
 }
 
-func (r *ReportUsecaseMock) DeleteReportById(id string) error {
-	args := r.Called(id)
+func (r *ReportUsecaseMock) DeleteReportById(userId string, id string) error {
+	args := r.Called(userId, id)
 	return args.Error(0)
 }
 
@@ -39,3 +42,33 @@ func (r *ReportUsecaseMock) GetReportByTaskId(taskId string) ([]model.Report, er
 func NewReportsUsecaseMock() ReportUsecaseMock {
 	return ReportUsecaseMock{}
 }
+
+func (m *ReportUsecaseMock) VerifyJournal(from string, to string) (model.JournalVerification, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(model.JournalVerification), args.Error(1)
+}
+
+func (m *ReportUsecaseMock) ArchiveJournal() (model.JournalArchive, error) {
+	args := m.Called()
+	return args.Get(0).(model.JournalArchive), args.Error(1)
+}
+
+func (m *ReportUsecaseMock) DownloadArchive(from string, to string, w io.Writer) error {
+	args := m.Called(from, to, w)
+	return args.Error(0)
+}
+
+func (m *ReportUsecaseMock) SearchReports(userId string, filter model.ReportFilter, page int, size int) ([]model.Report, shared_model.Paging, error) {
+	args := m.Called(userId, filter, page, size)
+	return args.Get(0).([]model.Report), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *ReportUsecaseMock) ReviewReport(userId string, id string, review model.ReportReview) (model.Report, error) {
+	args := m.Called(userId, id, review)
+	return args.Get(0).(model.Report), args.Error(1)
+}
+
+func (m *ReportUsecaseMock) GetMissingReports(userId string, projectId string, date string) ([]model.MissingReport, error) {
+	args := m.Called(userId, projectId, date)
+	return args.Get(0).([]model.MissingReport), args.Error(1)
+}
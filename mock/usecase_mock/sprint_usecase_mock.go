@@ -0,0 +1,50 @@
+package usecase_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type SprintUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *SprintUsecaseMock) GetByProjectId(projectId string) ([]model.Sprint, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.Sprint), args.Error(1)
+}
+
+func (m *SprintUsecaseMock) GetById(id string) (model.Sprint, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintUsecaseMock) CreateSprint(userId string, payload model.Sprint) (model.Sprint, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintUsecaseMock) UpdateSprint(userId string, payload model.Sprint) (model.Sprint, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintUsecaseMock) StartSprint(userId string, id string) (model.Sprint, error) {
+	args := m.Called(userId, id)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintUsecaseMock) CloseSprint(userId string, id string, rolloverTo string) (model.Sprint, error) {
+	args := m.Called(userId, id, rolloverTo)
+	return args.Get(0).(model.Sprint), args.Error(1)
+}
+
+func (m *SprintUsecaseMock) AddTasks(userId string, id string, taskIds []string) error {
+	args := m.Called(userId, id, taskIds)
+	return args.Error(0)
+}
+
+func (m *SprintUsecaseMock) RemoveTasks(userId string, id string, taskIds []string) error {
+	args := m.Called(userId, id, taskIds)
+	return args.Error(0)
+}
@@ -0,0 +1,32 @@
+package usecase_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type StatusReportUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *StatusReportUsecaseMock) Generate(userId string, projectId string, from string, to string) (model.StatusReport, error) {
+	args := m.Called(userId, projectId, from, to)
+	return args.Get(0).(model.StatusReport), args.Error(1)
+}
+
+func (m *StatusReportUsecaseMock) GetStatusReports(userId string, projectId string) ([]model.StatusReport, error) {
+	args := m.Called(userId, projectId)
+	return args.Get(0).([]model.StatusReport), args.Error(1)
+}
+
+func (m *StatusReportUsecaseMock) GetStatusReport(userId string, id string) (model.StatusReport, error) {
+	args := m.Called(userId, id)
+	return args.Get(0).(model.StatusReport), args.Error(1)
+}
+
+func (m *StatusReportUsecaseMock) GenerateWeekly(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,44 @@
+package usecase_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
+	"github.com/stretchr/testify/mock"
+)
+
+type StreamUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *StreamUsecaseMock) SubscribeProject(userId string, projectId string, lastEventId uint64) (*service.StreamSubscription, error) {
+	args := m.Called(userId, projectId, lastEventId)
+	return args.Get(0).(*service.StreamSubscription), args.Error(1)
+}
+
+func (m *StreamUsecaseMock) SubscribeUser(userId string, lastEventId uint64) *service.StreamSubscription {
+	args := m.Called(userId, lastEventId)
+	return args.Get(0).(*service.StreamSubscription)
+}
+
+func (m *StreamUsecaseMock) Unsubscribe(subscription *service.StreamSubscription) {
+	m.Called(subscription)
+}
+
+func (m *StreamUsecaseMock) CheckProjectAccess(userId string, projectId string) error {
+	args := m.Called(userId, projectId)
+	return args.Error(0)
+}
+
+func (m *StreamUsecaseMock) IssueTicket(userId string) (model.StreamTicket, error) {
+	args := m.Called(userId)
+	return args.Get(0).(model.StreamTicket), args.Error(1)
+}
+
+func (m *StreamUsecaseMock) RedeemTicket(ticket string) (string, error) {
+	args := m.Called(ticket)
+	return args.String(0), args.Error(1)
+}
+
+func (m *StreamUsecaseMock) ReleaseTicket(ticket string) {
+	m.Called(ticket)
+}
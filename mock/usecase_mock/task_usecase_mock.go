@@ -40,6 +40,21 @@ func (m *TaskUsecaseMock) GetByProjectId(Id string) ([]model.Task, error) {
 	return args.Get(0).([]model.Task), args.Error(1)
 }
 
+func (m *TaskUsecaseMock) MoveTask(userId string, id string, projectId string) (model.Task, error) {
+	args := m.Called(userId, id, projectId)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
+func (m *TaskUsecaseMock) CloneTask(userId string, id string, payload model.TaskClone) (model.Task, error) {
+	args := m.Called(userId, id, payload)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
+func (m *TaskUsecaseMock) FilterByProjectId(Id string, filters map[string]string) ([]model.Task, error) {
+	args := m.Called(Id, filters)
+	return args.Get(0).([]model.Task), args.Error(1)
+}
+
 func (m *TaskUsecaseMock) CreateTask(payload model.Task) (model.Task, error) {
 	args := m.Called(payload)
 	return args.Get(0).(model.Task), args.Error(1)
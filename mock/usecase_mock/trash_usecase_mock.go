@@ -0,0 +1,26 @@
+package usecase_mock
+
+import (
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/mock"
+)
+
+type TrashUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *TrashUsecaseMock) FindDeleted(entity string, page int, size int) ([]model.TrashItem, shared_model.Paging, error) {
+	args := m.Called(entity, page, size)
+	return args.Get(0).([]model.TrashItem), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *TrashUsecaseMock) Restore(userId string, entity string, id string) error {
+	args := m.Called(userId, entity, id)
+	return args.Error(0)
+}
+
+func (m *TrashUsecaseMock) PurgeExpired() (model.PurgeResult, error) {
+	args := m.Called()
+	return args.Get(0).(model.PurgeResult), args.Error(1)
+}
@@ -35,7 +35,12 @@ func (a *UserUseCaseMock) UpdateUser(payload model.User) (model.User, error) {
 	return args.Get(0).(model.User), args.Error(1)
 }
 
-func (a *UserUseCaseMock) DeleteUser(id string) error {
-	args := a.Called(id)
+func (a *UserUseCaseMock) DeleteUser(id string, reassign model.UserReassignment) error {
+	args := a.Called(id, reassign)
 	return args.Error(0)
 }
+
+func (a *UserUseCaseMock) DeleteUserPreview(id string, reassign model.UserReassignment) (model.DeleteImpact, error) {
+	args := a.Called(id, reassign)
+	return args.Get(0).(model.DeleteImpact), args.Error(1)
+}
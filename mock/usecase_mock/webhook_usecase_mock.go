@@ -0,0 +1,52 @@
+package usecase_mock
+
+import (
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookUsecaseMock struct {
+	mock.Mock
+}
+
+func (m *WebhookUsecaseMock) Dispatch(event model.Event) {
+	m.Called(event)
+}
+
+func (m *WebhookUsecaseMock) SendDue(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *WebhookUsecaseMock) CreateWebhook(userId string, payload model.Webhook) (model.Webhook, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *WebhookUsecaseMock) GetWebhooks(userId string, projectId string) ([]model.Webhook, error) {
+	args := m.Called(userId, projectId)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (m *WebhookUsecaseMock) UpdateWebhook(userId string, payload model.Webhook) (model.Webhook, error) {
+	args := m.Called(userId, payload)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *WebhookUsecaseMock) DeleteWebhook(userId string, id string) error {
+	args := m.Called(userId, id)
+	return args.Error(0)
+}
+
+func (m *WebhookUsecaseMock) GetDeliveries(userId string, webhookId string, page int, size int) ([]model.WebhookDelivery, shared_model.Paging, error) {
+	args := m.Called(userId, webhookId, page, size)
+	return args.Get(0).([]model.WebhookDelivery), args.Get(1).(shared_model.Paging), args.Error(2)
+}
+
+func (m *WebhookUsecaseMock) Redeliver(userId string, deliveryId string) (model.WebhookDelivery, error) {
+	args := m.Called(userId, deliveryId)
+	return args.Get(0).(model.WebhookDelivery), args.Error(1)
+}
@@ -0,0 +1,24 @@
+package model
+
+// Board shows the tasks of a project in one column per task status
+type Board struct {
+	ProjectId string        `json:"project_id"`
+	Columns   []BoardColumn `json:"columns"`
+}
+
+// BoardColumn holds the tasks of one status in board order
+type BoardColumn struct {
+	Status   string `json:"status"`
+	WipLimit int    `json:"wip_limit"`
+	Count    int    `json:"count"`
+	Tasks    []Task `json:"tasks"`
+}
+
+// BoardMove places a task at a position within the column of a status
+type BoardMove struct {
+	TaskId    string `json:"-"`
+	ProjectId string `json:"-"`
+	Status    string `json:"status"`
+	Position  int    `json:"position"`
+	Version   int    `json:"version"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// DeleteImpact previews the rows a cascading delete will touch
+type DeleteImpact struct {
+	Projects int `json:"projects"`
+	Tasks    int `json:"tasks"`
+	Reports  int `json:"reports"`
+	Members  int `json:"members"`
+}
+
+// UserReassignment names who takes over a deleted user's tasks and projects
+type UserReassignment struct {
+	TasksTo    string `json:"tasks_to" form:"tasks_to"`
+	ProjectsTo string `json:"projects_to" form:"projects_to"`
+}
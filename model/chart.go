@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// ChartTask is a task with its status history, used to rebuild charts
+type ChartTask struct {
+	Id          string         `json:"id"`
+	StoryPoints int            `json:"story_points"`
+	CreatedAt   time.Time      `json:"created_at"`
+	History     []StatusChange `json:"history"`
+}
+
+// StatusChange is one entry of a task's status history
+type StatusChange struct {
+	Status    string    `json:"status"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// BurnChart holds daily burndown/burnup data for a project or sprint
+type BurnChart struct {
+	Scope     string       `json:"scope"`
+	ScopeId   string       `json:"scope_id"`
+	StartDate string       `json:"start_date"`
+	EndDate   string       `json:"end_date"`
+	Actual    []BurnPoint  `json:"actual"`
+	Ideal     []IdealPoint `json:"ideal"`
+}
+
+// BurnPoint is the state of the work at the end of a day
+type BurnPoint struct {
+	Date            string `json:"date"`
+	TotalTasks      int    `json:"total_tasks"`
+	CompletedTasks  int    `json:"completed_tasks"`
+	RemainingTasks  int    `json:"remaining_tasks"`
+	TotalPoints     int    `json:"total_points"`
+	CompletedPoints int    `json:"completed_points"`
+	RemainingPoints int    `json:"remaining_points"`
+}
+
+// IdealPoint is the remaining work of a steady burn down to the end date
+type IdealPoint struct {
+	Date            string  `json:"date"`
+	RemainingTasks  float64 `json:"remaining_tasks"`
+	RemainingPoints float64 `json:"remaining_points"`
+}
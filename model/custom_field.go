@@ -0,0 +1,50 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CustomField defines an extra field that tasks of a project can carry
+type CustomField struct {
+	Id        string     `json:"id"`
+	ProjectId string     `json:"project_id"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Options   []string   `json:"options"`
+	Required  bool       `json:"required"`
+	CreatedAt time.Time  `json:"-"`
+	UpdatedAt time.Time  `json:"-"`
+	DeletedAt *time.Time `json:"-"`
+}
+
+// CustomFieldValues holds a task's custom field values keyed by field name, stored as jsonb
+type CustomFieldValues map[string]any
+
+// Scan implements sql.Scanner.
+func (c *CustomFieldValues) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+	return fmt.Errorf("cannot scan %T into CustomFieldValues", src)
+}
+
+// Value implements driver.Valuer.
+func (c CustomFieldValues) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
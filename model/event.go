@@ -0,0 +1,75 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event types published on the notification channel
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskAssigned      = "task.assigned"
+	EventTaskApproved      = "task.approved"
+	EventTaskRejected      = "task.rejected"
+	EventTaskBlocked       = "task.blocked"
+	EventTaskMoved         = "task.moved"
+	EventReportCreated     = "report.created"
+	EventReportReviewed    = "report.reviewed"
+	EventMemberAdded       = "project.member_added"
+	EventTaskDueSoon       = "task.due_soon"
+	EventTaskOverdue       = "task.overdue"
+	EventTaskEscalated     = "task.escalated"
+	EventProjectDueSoon    = "project.due_soon"
+	EventProjectOverdue    = "project.overdue"
+)
+
+// EventTypes are all event types, which webhooks can subscribe to
+var EventTypes = []string{EventTaskCreated, EventTaskStatusChanged, EventTaskAssigned, EventTaskApproved, EventTaskRejected, EventTaskBlocked, EventTaskMoved, EventReportCreated, EventReportReviewed, EventMemberAdded, EventTaskDueSoon, EventTaskOverdue, EventTaskEscalated, EventProjectDueSoon, EventProjectOverdue}
+
+// NotificationTypes are the event types users can be notified about
+var NotificationTypes = []string{EventTaskAssigned, EventTaskApproved, EventTaskRejected, EventTaskBlocked, EventReportCreated, EventReportReviewed, EventMemberAdded, EventTaskDueSoon, EventTaskOverdue, EventTaskEscalated, EventProjectDueSoon, EventProjectOverdue}
+
+// Event is something that happened which users may want to hear about
+type Event struct {
+	Type      string `json:"type"`
+	ProjectId string `json:"project_id"`
+	// FromProjectId is the project a task.moved event left; ProjectId is the one it moved to
+	FromProjectId string    `json:"from_project_id,omitempty"`
+	TaskId        string    `json:"task_id,omitempty"`
+	ActorId       string    `json:"actor_id,omitempty"`
+	RecipientIds  []string  `json:"-"`
+	Data          EventData `json:"data"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// EventData carries the details of an event, stored as jsonb
+type EventData map[string]any
+
+// Scan implements sql.Scanner.
+func (e *EventData) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	}
+	return fmt.Errorf("cannot scan %T into EventData", src)
+}
+
+// Value implements driver.Valuer.
+func (e EventData) Value() (driver.Value, error) {
+	if e == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,73 @@
+package model
+
+// JournalVerification is the result of checking the hash chain of the report journal
+// between two days. Unsealed counts entries written before the journal was chained.
+type JournalVerification struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Valid    bool          `json:"valid"`
+	Files    int           `json:"files"`
+	Entries  int           `json:"entries"`
+	Unsealed int           `json:"unsealed"`
+	Broken   *JournalBreak `json:"broken,omitempty"`
+}
+
+// JournalBreak is the first entry that does not fit the chain. Entry counts from 1 within File.
+type JournalBreak struct {
+	File   string `json:"file"`
+	Entry  int    `json:"entry"`
+	Reason string `json:"reason"`
+}
+
+// JournalEntry is one parsed entry of the TXT journal. Action is create, update or delete and Date is the
+// day it was written. Entry counts from 1 within File; Error is set when the entry could not be read.
+// Time, Actor and Before are empty for entries written before the journal recorded them.
+type JournalEntry struct {
+	File   string  `json:"file"`
+	Entry  int     `json:"entry"`
+	Action string  `json:"action"`
+	Date   string  `json:"date"`
+	Time   string  `json:"time,omitempty"`
+	Actor  string  `json:"actor,omitempty"`
+	Before *Report `json:"before,omitempty"`
+	Report Report  `json:"report"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// JournalImport is the result of reconciling the TXT journal with the reports table. Missing reports are
+// in the journal but not in the table, divergent ones differ from the journal's last state, and duplicates
+// are entries that repeat an earlier one. Applied counts the rows written; a dry run writes none.
+type JournalImport struct {
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	DryRun     bool           `json:"dry_run"`
+	Files      int            `json:"files"`
+	Entries    int            `json:"entries"`
+	Reports    int            `json:"reports"`
+	Missing    []JournalDiff  `json:"missing"`
+	Divergent  []JournalDiff  `json:"divergent"`
+	Duplicates []JournalDiff  `json:"duplicates"`
+	Unreadable []JournalBreak `json:"unreadable"`
+	Applied    int            `json:"applied"`
+	Failed     []JournalDiff  `json:"failed"`
+}
+
+// JournalDiff describes one report that does not match the journal. File and Entry point at the
+// journal entry involved; Apply tells whether the import brings the table in line with the journal.
+type JournalDiff struct {
+	ReportId string  `json:"report_id"`
+	File     string  `json:"file"`
+	Entry    int     `json:"entry"`
+	Reason   string  `json:"reason"`
+	Apply    bool    `json:"apply"`
+	Journal  *Report `json:"journal,omitempty"`
+	Database *Report `json:"database,omitempty"`
+}
+
+// JournalArchive is the result of one archive run. Archived lists the day files moved into the monthly
+// archives named in Archives; Deleted lists the day files and archives removed for being too old.
+type JournalArchive struct {
+	Archived []string `json:"archived"`
+	Archives []string `json:"archives"`
+	Deleted  []string `json:"deleted"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Email delivery modes a user can choose
+const (
+	MailOff       = "off"
+	MailImmediate = "immediate"
+	MailDigest    = "digest"
+)
+
+// Mail is one rendered email
+type Mail struct {
+	To       string
+	Subject  string
+	TextBody string
+	HtmlBody string
+}
+
+// QueuedMail is a notification waiting to be emailed, alone or in the user's next digest
+type QueuedMail struct {
+	Id        int64
+	UserId    string
+	Type      string
+	Message   string
+	Digest    bool
+	CreatedAt time.Time
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Milestone groups tasks of a project under an intermediate due date
+type Milestone struct {
+	Id             string     `json:"id"`
+	ProjectId      string     `json:"project_id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	DueDate        string     `json:"due_date"`
+	TotalTasks     int        `json:"total_tasks"`
+	CompletedTasks int        `json:"completed_tasks"`
+	Progress       float64    `json:"progress"`
+	CreatedAt      time.Time  `json:"-"`
+	UpdatedAt      time.Time  `json:"-"`
+	DeletedAt      *time.Time `json:"-"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Notification is one entry in a user's inbox
+type Notification struct {
+	Id        string     `json:"id"`
+	UserId    string     `json:"-"`
+	Type      string     `json:"type"`
+	ProjectId string     `json:"project_id"`
+	TaskId    string     `json:"task_id"`
+	Message   string     `json:"message"`
+	Data      EventData  `json:"data"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -7,6 +7,8 @@ type Project struct {
 	Name      string     `json:"name"`
 	ManagerId string     `json:"manager_id"`
 	Deadline  string     `json:"deadline"`
+	Key       string     `json:"key"`
+	Version   int        `json:"version"`
 	CreatedAt time.Time  `json:"-"`
 	UpdatedAt time.Time  `json:"-"`
 	DeletedAt *time.Time `json:"-"`
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Reminder kinds, each sent once per subject and deadline
+const (
+	ReminderDueSoon   = "due_soon"
+	ReminderOverdue   = "overdue"
+	ReminderEscalated = "escalated"
+)
+
+// ReminderSetting configures deadline reminders of one project
+type ReminderSetting struct {
+	ProjectId         string `json:"project_id"`
+	Enabled           bool   `json:"enabled"`
+	DaysBefore        int    `json:"days_before"`
+	EscalateAfterDays int    `json:"escalate_after_days"`
+}
+
+// DueItem is a task or project whose deadline is near or past, together with the reminder setting of its project.
+// TaskId is empty when the project itself is due.
+type DueItem struct {
+	TaskId            string
+	ProjectId         string
+	Key               string
+	Name              string
+	Deadline          time.Time
+	PersonInCharge    string
+	ManagerId         string
+	DaysBefore        int
+	EscalateAfterDays int
+}
+
+// Reminder is one notification about a deadline, deduplicated by subject, kind and deadline
+type Reminder struct {
+	TaskId      string
+	ProjectId   string
+	Kind        string
+	Deadline    time.Time
+	RecipientId string
+}
@@ -1,19 +1,99 @@
 package model
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type Report struct {
-	Id         string     `json:"id"`
-	User_id    string     `json:"user_id"`
-	Report     string     `json:"report"`
-	Task_id    string     `json:"task_id"`
-	Created_at time.Time  `json:"-"`
-	Updated_at time.Time  `json:"-"`
-	DeletedAt  *time.Time `json:"-"`
+	Id            string     `json:"id"`
+	User_id       string     `json:"user_id"`
+	Report        string     `json:"report"`
+	Task_id       string     `json:"task_id"`
+	Standup       *Standup   `json:"standup,omitempty"`
+	ReviewStatus  string     `json:"review_status,omitempty"`
+	ReviewComment string     `json:"review_comment,omitempty"`
+	ReviewedBy    string     `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	Version       int        `json:"version"`
+	Created_at    time.Time  `json:"-"`
+	Updated_at    time.Time  `json:"-"`
+	DeletedAt     *time.Time `json:"-"`
 }
 
-// Struktur untuk laporan yang ditampilkan
+// Report review states. A report is submitted until the project manager reviews it;
+// editing a report submits it again.
+const (
+	ReportSubmitted     = "submitted"
+	ReportAcknowledged  = "acknowledged"
+	ReportNeedsMoreInfo = "needs-more-info"
+)
+
+// ReportReview is a manager's verdict on a report
+type ReportReview struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment"`
+}
+
+// MissingReport is a member with active tasks in a project who wrote no report on a given day
+type MissingReport struct {
+	UserId   string   `json:"user_id"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	TaskKeys []string `json:"task_keys"`
+}
+
+// Standup is the structured form of a daily report. TaskIds links the tasks the standup is about;
+// blockers flag those tasks as Blocked. Stored as jsonb.
+type Standup struct {
+	Yesterday string   `json:"yesterday"`
+	Today     string   `json:"today"`
+	Blockers  string   `json:"blockers"`
+	TaskIds   []string `json:"task_ids"`
+}
+
+// Scan implements sql.Scanner.
+func (s *Standup) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return fmt.Errorf("cannot scan %T into Standup", src)
+}
+
+// Value implements driver.Valuer.
+func (s Standup) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// ReportFilter narrows a report search. Empty fields match everything; From and To are inclusive YYYY-MM-DD
+// dates on created_at and Query is a case-insensitive substring of the report text. ManagerId, set by the
+// usecase for non-admin callers, keeps the search to projects that user manages.
+type ReportFilter struct {
+	UserId    string
+	TaskId    string
+	ProjectId string
+	From      string
+	To        string
+	Query     string
+	ManagerId string
+	Sort      string
+	Order     string
+}
+
+// ShowReport is one change written to the report journal. Content is the report after the change, or as
+// it was when deleted; Before is the previous version of an updated report. ActorId made the change.
 type ShowReport struct {
 	Date    time.Time
+	ActorId string
+	Before  *Report
 	Content Report
 }
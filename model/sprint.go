@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Sprint is a time-box of a project that tasks are planned into
+type Sprint struct {
+	Id        string        `json:"id"`
+	ProjectId string        `json:"project_id"`
+	Name      string        `json:"name"`
+	Goal      string        `json:"goal"`
+	StartDate string        `json:"start_date"`
+	EndDate   string        `json:"end_date"`
+	State     string        `json:"state"`
+	Summary   SprintSummary `json:"summary"`
+	Snapshot  SprintSummary `json:"-"` // taken when the sprint starts (committed) and closes (completed)
+	Current   SprintSummary `json:"-"` // counted from the tasks currently in the sprint
+	CreatedAt time.Time     `json:"-"`
+	UpdatedAt time.Time     `json:"-"`
+	DeletedAt *time.Time    `json:"-"`
+}
+
+// SprintSummary compares the work committed to a sprint with the work completed in it
+type SprintSummary struct {
+	CommittedTasks  int `json:"committed_tasks"`
+	CommittedPoints int `json:"committed_points"`
+	CompletedTasks  int `json:"completed_tasks"`
+	CompletedPoints int `json:"completed_points"`
+	RemainingTasks  int `json:"remaining_tasks"`
+	RemainingPoints int `json:"remaining_points"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// StatusReport is a rendered project status report for an inclusive date range, kept as a downloadable artifact.
+// CreatedBy is empty for reports generated by the weekly job.
+type StatusReport struct {
+	Id        string    `json:"id"`
+	ProjectId string    `json:"project_id"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+	Markdown  string    `json:"-"`
+	Html      string    `json:"-"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StatusReportData is what a status report is rendered from
+type StatusReportData struct {
+	Project       Project
+	StartDate     string
+	EndDate       string
+	GeneratedAt   time.Time
+	StatusChanges []TaskStatusChange
+	Completed     []TaskStatusChange
+	Overdue       []OverdueTask
+	Reports       []MemberReport
+}
+
+// TaskStatusChange is one entry of a task's status history with the status it replaced
+type TaskStatusChange struct {
+	TaskId         string
+	Key            string
+	Name           string
+	Status         string
+	PreviousStatus string
+	ChangedAt      time.Time
+}
+
+// OverdueTask is an unaccepted task past its deadline
+type OverdueTask struct {
+	TaskId         string
+	Key            string
+	Name           string
+	Status         string
+	Deadline       string
+	PersonInCharge string
+}
+
+// MemberReport is a report written on one of the project's tasks
+type MemberReport struct {
+	UserId    string
+	UserName  string
+	TaskKey   string
+	TaskName  string
+	Report    string
+	CreatedAt time.Time
+}
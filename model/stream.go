@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// StreamEvent is an event as sent to real-time clients. Id increases with every published event
+// and is what clients send back as Last-Event-ID to resume after a reconnect.
+type StreamEvent struct {
+	Id uint64 `json:"id"`
+	Event
+}
+
+// StreamTicket authenticates one stream connection in place of the access token,
+// which must not travel in the URL. It can be used once, before ExpiresAt.
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
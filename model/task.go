@@ -3,16 +3,31 @@ package model
 import "time"
 
 type Task struct {
-	Id             string     `json:"id"`
-	Name           string     `json:"name"`
-	Status         string     `json:"status"`
-	Approval       bool       `json:"approval"`
-	ApprovalDate   *time.Time `json:"approval_date"`
-	Feedback       string     `json:"feedback"`
-	PersonInCharge string     `json:"person_in_charge"`
-	ProjectId      string     `json:"project_id"`
-	Deadline       string     `json:"deadline"`
-	CreatedAt      time.Time  `json:"-"`
-	UpdatedAt      time.Time  `json:"-"`
-	DeletedAt      *time.Time `json:"-"`
+	Id             string            `json:"id"`
+	Key            string            `json:"key"`
+	Name           string            `json:"name"`
+	Status         string            `json:"status"`
+	Approval       bool              `json:"approval"`
+	ApprovalDate   *time.Time        `json:"approval_date"`
+	Feedback       string            `json:"feedback"`
+	PersonInCharge string            `json:"person_in_charge"`
+	ProjectId      string            `json:"project_id"`
+	Deadline       string            `json:"deadline"`
+	Version        int               `json:"version"`
+	MilestoneId    string            `json:"milestone_id"`
+	StoryPoints    *int              `json:"story_points"`
+	SprintId       string            `json:"sprint_id"`
+	CustomFields   CustomFieldValues `json:"custom_fields"`
+	CreatedAt      time.Time         `json:"-"`
+	UpdatedAt      time.Time         `json:"-"`
+	DeletedAt      *time.Time        `json:"-"`
+}
+
+// TaskClone chooses what a cloned task copies from the original
+type TaskClone struct {
+	ProjectId        string `json:"project_id"`
+	Name             string `json:"name"`
+	CopyStoryPoints  bool   `json:"copy_story_points"`
+	CopyCustomFields bool   `json:"copy_custom_fields"`
+	CopyReports      bool   `json:"copy_reports"`
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// TrashItem is a soft-deleted row as listed in the trash
+type TrashItem struct {
+	Id        string    `json:"id"`
+	Type      string    `json:"type"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// PurgeResult counts the rows removed by a hard purge
+type PurgeResult struct {
+	Reports        int64 `json:"reports"`
+	Tasks          int64 `json:"tasks"`
+	ProjectMembers int64 `json:"project_members"`
+	Milestones     int64 `json:"milestones"`
+	Sprints        int64 `json:"sprints"`
+	CustomFields   int64 `json:"custom_fields"`
+	Projects       int64 `json:"projects"`
+	Users          int64 `json:"users"`
+}
@@ -8,6 +8,7 @@ type User struct {
 	Email     string     `json:"email"`
 	Password  string     `json:"password"`
 	Role      string     `json:"role"`
+	Version   int        `json:"version"`
 	CreatedAt time.Time  `json:"-"`
 	UpdatedAt time.Time  `json:"-"`
 	DeletedAt *time.Time `json:"-"`
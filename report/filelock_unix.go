@@ -0,0 +1,34 @@
+//go:build unix
+
+package report
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive advisory lock on file, waiting for other processes to release it
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+// syncDir flushes directory entries so a new or renamed file survives a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// removeLocked removes the file at path while file still holds its lock, so no other
+// process can append to it in between
+func removeLocked(file *os.File, path string) error {
+	return os.Remove(path)
+}
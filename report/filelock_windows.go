@@ -0,0 +1,31 @@
+//go:build windows
+
+package report
+
+import (
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile takes an exclusive lock on file, waiting for other processes to release it
+func lockFile(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, math.MaxUint32, math.MaxUint32, &windows.Overlapped{})
+}
+
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, math.MaxUint32, math.MaxUint32, &windows.Overlapped{})
+}
+
+// syncDir is a no-op: Windows does not support syncing directories
+func syncDir(dir string) error {
+	return nil
+}
+
+// removeLocked releases and closes file first, since Windows does not remove open files
+func removeLocked(file *os.File, path string) error {
+	unlockFile(file)
+	file.Close()
+	return os.Remove(path)
+}
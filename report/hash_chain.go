@@ -0,0 +1,229 @@
+package report
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+// genesisHash is the previous hash of the first entry of a new journal
+var genesisHash = strings.Repeat("0", 64)
+
+// dayFilePattern matches the TXT journal files
+var dayFilePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\.txt$`)
+
+// hashChain links every TXT entry to the one before it, across day files.
+// Each entry gets a trailer with the previous hash, its own hash and, when a key is
+// configured, an HMAC of its hash:
+//
+//	Prev: <sha256 of the previous entry>
+//	Hash: <sha256 of Prev, a newline and the entry>
+//	Hmac: <hmac-sha256 of Hash>
+type hashChain struct {
+	dir string
+	key []byte
+}
+
+// seal implements sealFunc.
+func (h *hashChain) seal(file *os.File, size int64, entry string) (string, error) {
+	prev, err := lastHash(file, size)
+	if err != nil {
+		return "", err
+	}
+	if prev == "" {
+		if prev, err = h.previousDayHash(filepath.Base(file.Name())); err != nil {
+			return "", err
+		}
+	}
+
+	body := strings.TrimSuffix(entry, "\n")
+	hash := chainHash(prev, body)
+	trailer := "Prev: " + prev + "\nHash: " + hash + "\n"
+	if len(h.key) > 0 {
+		trailer += "Hmac: " + chainHmac(h.key, hash) + "\n"
+	}
+
+	return body + trailer + "\n", nil
+}
+
+// previousDayHash returns the last hash of the newest day file before name,
+// or the genesis hash when there is none
+func (h *hashChain) previousDayHash(name string) (string, error) {
+	files, err := dayFiles(h.dir)
+	if err != nil {
+		return "", err
+	}
+
+	i := sort.SearchStrings(files, name)
+	if i == 0 {
+		return genesisHash, nil
+	}
+
+	hash, err := fileLastHash(filepath.Join(h.dir, files[i-1]))
+	if err != nil || hash == "" {
+		return genesisHash, err
+	}
+	return hash, nil
+}
+
+func chainHash(prev string, body string) string {
+	sum := sha256.Sum256([]byte(prev + "\n" + body))
+	return hex.EncodeToString(sum[:])
+}
+
+func chainHmac(key []byte, hash string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// lastHash finds the Hash line of the last sealed entry in the first size bytes of r
+func lastHash(r io.ReaderAt, size int64) (string, error) {
+	const tailSize = 64 << 10
+
+	start := max(size-tailSize, 0)
+	for {
+		content := make([]byte, size-start)
+		if _, err := r.ReadAt(content, start); err != nil && err != io.EOF {
+			return "", err
+		}
+
+		if i := bytes.LastIndex(content, []byte("\nHash: ")); i >= 0 {
+			line, _, _ := bytes.Cut(content[i+len("\nHash: "):], []byte("\n"))
+			return string(line), nil
+		}
+		if start == 0 {
+			return "", nil
+		}
+		start = 0
+	}
+}
+
+func fileLastHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	return lastHash(file, info.Size())
+}
+
+// dayFiles lists the TXT journal files in dir in date order
+func dayFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && dayFilePattern.MatchString(entry.Name()) {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// JournalVerifier checks that the TXT journal was not edited
+type JournalVerifier interface {
+	Verify(from time.Time, to time.Time) (model.JournalVerification, error)
+}
+
+type journalVerifier struct {
+	dir string
+	key []byte
+}
+
+// Verify implements JournalVerifier.
+// The first entry in range is linked to the last entry of the newest earlier day file; when
+// there is none, for example after old files were archived, its previous hash is trusted.
+// Entries without a trailer are only accepted before the first sealed entry.
+func (j *journalVerifier) Verify(from time.Time, to time.Time) (model.JournalVerification, error) {
+	result := model.JournalVerification{From: from.Format("2006-01-02"), To: to.Format("2006-01-02"), Valid: true}
+
+	files, err := dayFiles(j.dir)
+	if err != nil {
+		return model.JournalVerification{}, fmt.Errorf("failed to list report files: %v", err)
+	}
+
+	var expected string
+	first := sort.SearchStrings(files, result.From+".txt")
+	if first > 0 {
+		if expected, err = fileLastHash(filepath.Join(j.dir, files[first-1])); err != nil {
+			return model.JournalVerification{}, fmt.Errorf("failed to read report file: %v", err)
+		}
+	}
+	sealed := expected != ""
+
+	for _, name := range files[first:] {
+		if name > result.To+".txt" {
+			break
+		}
+		content, err := os.ReadFile(filepath.Join(j.dir, name))
+		if err != nil {
+			return model.JournalVerification{}, fmt.Errorf("failed to read report file: %v", err)
+		}
+		result.Files++
+
+		entries, rest := parseTXTJournal(content)
+		for i, entry := range entries {
+			broken := func(reason string) (model.JournalVerification, error) {
+				result.Valid = false
+				result.Broken = &model.JournalBreak{File: name, Entry: i + 1, Reason: reason}
+				return result, nil
+			}
+
+			if entry.Hash == "" {
+				if sealed {
+					return broken("entry has no hash")
+				}
+				result.Unsealed++
+				continue
+			}
+			if expected != "" && entry.Prev != expected {
+				return broken("previous hash does not match, an entry before this one was removed, added or changed")
+			}
+			if chainHash(entry.Prev, entry.Body) != entry.Hash {
+				return broken("entry does not match its hash")
+			}
+			if len(j.key) > 0 && !hmac.Equal([]byte(entry.Hmac), []byte(chainHmac(j.key, entry.Hash))) {
+				return broken("entry signature does not match")
+			}
+
+			expected, sealed = entry.Hash, true
+			result.Entries++
+		}
+		if len(bytes.TrimSpace(rest)) > 0 {
+			result.Valid = false
+			result.Broken = &model.JournalBreak{File: name, Entry: len(entries) + 1, Reason: "incomplete entry at the end of the file"}
+			return result, nil
+		}
+	}
+
+	return result, nil
+}
+
+func NewJournalVerifier(cfg config.PathConfig) JournalVerifier {
+	return &journalVerifier{dir: cfg.StaticPath, key: cfg.ReportHmacKey}
+}
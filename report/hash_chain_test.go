@@ -0,0 +1,99 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	chainFrom = time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	chainTo   = time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local)
+)
+
+// writeChain writes two entries on the first day and one on the next
+func writeChain(t *testing.T, cfg config.PathConfig) {
+	sink := NewReportToTXT(cfg)
+	for i, day := range []time.Time{chainFrom.Add(9 * time.Hour), chainFrom.Add(10 * time.Hour), chainTo.Add(9 * time.Hour)} {
+		report := model.ShowReport{Date: day, Content: model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "work", Version: i + 1}}
+		assert.NoError(t, sink.WriteReport(report, "update"))
+	}
+}
+
+func TestVerify_IntactChain(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir(), ReportHmacKey: []byte("server key")}
+	writeChain(t, cfg)
+
+	result, err := NewJournalVerifier(cfg).Verify(chainFrom, chainTo)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 2, result.Files)
+	assert.Equal(t, 3, result.Entries)
+
+	second, err := NewJournalVerifier(cfg).Verify(chainTo, chainTo)
+	assert.NoError(t, err)
+	assert.True(t, second.Valid)
+	assert.Equal(t, 1, second.Entries)
+}
+
+func TestVerify_EditedEntry(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir()}
+	writeChain(t, cfg)
+	path := filepath.Join(cfg.StaticPath, "2024-03-01.txt")
+	content, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(strings.Replace(string(content), `"version":2`, `"version":5`, 1)), 0o644)
+
+	result, err := NewJournalVerifier(cfg).Verify(chainFrom, chainTo)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, &model.JournalBreak{File: "2024-03-01.txt", Entry: 2, Reason: "entry does not match its hash"}, result.Broken)
+}
+
+func TestVerify_RemovedEntry(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir()}
+	writeChain(t, cfg)
+	path := filepath.Join(cfg.StaticPath, "2024-03-01.txt")
+	content, _ := os.ReadFile(path)
+	entries := strings.SplitAfter(string(content), "\n\n")
+	os.WriteFile(path, []byte(entries[0]), 0o644)
+
+	result, err := NewJournalVerifier(cfg).Verify(chainFrom, chainTo)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "2024-03-02.txt", result.Broken.File)
+	assert.Equal(t, 1, result.Broken.Entry)
+}
+
+func TestVerify_WrongKey(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir(), ReportHmacKey: []byte("server key")}
+	writeChain(t, cfg)
+	cfg.ReportHmacKey = []byte("other key")
+
+	result, err := NewJournalVerifier(cfg).Verify(chainFrom, chainTo)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "entry signature does not match", result.Broken.Reason)
+}
+
+func TestVerify_LegacyEntriesBeforeChain(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir()}
+	os.WriteFile(filepath.Join(cfg.StaticPath, "2024-02-28.txt"), []byte("Create report\nDate: 2024-02-28\n{\"id\":\"r0\"}\n\n"), 0o644)
+	writeChain(t, cfg)
+
+	result, err := NewJournalVerifier(cfg).Verify(chainFrom.AddDate(0, 0, -2), chainTo)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 1, result.Unsealed)
+	assert.Equal(t, 3, result.Entries)
+}
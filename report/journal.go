@@ -0,0 +1,134 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileMutexes serializes writers of the same file inside this process; lockFile does the same
+// across processes, so several instances can share one FILE_PATH.
+var fileMutexes sync.Map
+
+func fileMutex(path string) *sync.Mutex {
+	mu, _ := fileMutexes.LoadOrStore(path, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// sealFunc completes an entry under the file lock, when it can see the entries before it.
+// size is the length of the complete entries already in file.
+type sealFunc func(file *os.File, size int64, entry string) (string, error)
+
+// appendEntry appends entry to path in a single write while holding both locks. Existing
+// content is never rewritten. header is prepended when the file is new or empty.
+// An entry left half written by a crash is cut off first, so every entry in the file is
+// complete and ends with terminator. seal, when set, may rewrite the entry before it is written.
+func appendEntry(path string, header string, entry string, terminator string, fsync bool, seal sealFunc) error {
+	mu := fileMutex(path)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create report folder: %v", err)
+	}
+
+	file, err := openLocked(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	defer unlockFile(file)
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open report file: %v", err)
+	}
+	size := info.Size()
+
+	if size > 0 {
+		complete, err := completeLength(file, size, terminator)
+		if err != nil {
+			return fmt.Errorf("failed to read report file: %v", err)
+		}
+		if complete < size {
+			if err := file.Truncate(complete); err != nil {
+				return fmt.Errorf("failed to repair report file: %v", err)
+			}
+			size = complete
+		}
+	}
+
+	if seal != nil {
+		if entry, err = seal(file, size, entry); err != nil {
+			return fmt.Errorf("failed to seal report entry: %v", err)
+		}
+	}
+	if size == 0 {
+		entry = header + entry
+	}
+	if _, err := file.WriteString(entry); err != nil {
+		return fmt.Errorf("failed to write report file: %v", err)
+	}
+	if fsync {
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync report file: %v", err)
+		}
+		if info.Size() == 0 {
+			return syncDir(filepath.Dir(path))
+		}
+	}
+
+	return nil
+}
+
+// openLocked opens path for appending and locks it. When the archiver removed the file while
+// this process waited for the lock, the file is opened again so the entry is not lost.
+func openLocked(path string) (*os.File, error) {
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open report file: %v", err)
+		}
+		if err := lockFile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock report file: %v", err)
+		}
+
+		opened, err := file.Stat()
+		if err != nil {
+			unlockFile(file)
+			file.Close()
+			return nil, fmt.Errorf("failed to open report file: %v", err)
+		}
+		if current, err := os.Stat(path); err == nil && os.SameFile(opened, current) {
+			return file, nil
+		}
+		unlockFile(file)
+		file.Close()
+	}
+}
+
+// completeLength returns the length of the file up to and including the last terminator
+func completeLength(file *os.File, size int64, terminator string) (int64, error) {
+	tail := make([]byte, len(terminator))
+	if size >= int64(len(terminator)) {
+		if _, err := file.ReadAt(tail, size-int64(len(terminator))); err != nil {
+			return 0, err
+		}
+		if string(tail) == terminator {
+			return size, nil
+		}
+	}
+
+	content := make([]byte, size)
+	if _, err := file.ReadAt(content, 0); err != nil && err != io.EOF {
+		return 0, err
+	}
+	if i := bytes.LastIndex(content, []byte(terminator)); i >= 0 {
+		return int64(i + len(terminator)), nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,436 @@
+package report
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+// archiveDir is the folder under StaticPath that holds the monthly "YYYY-MM.zip" archives
+const archiveDir = "archive"
+
+// sinkFilePattern matches the day files of every sink
+var sinkFilePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\.(txt|jsonl|csv|md)$`)
+
+// archivePattern matches the monthly archives
+var archivePattern = regexp.MustCompile(`^\d{4}-\d{2}\.zip$`)
+
+// JournalArchiver keeps the report folder from growing forever
+type JournalArchiver interface {
+	Archive(now time.Time) (model.JournalArchive, error)
+	Export(from time.Time, to time.Time, w io.Writer) error
+}
+
+type journalArchiver struct {
+	dir          string
+	archiveAfter int
+	maxAge       int
+	fsync        bool
+}
+
+// Archive implements JournalArchiver.
+// Moves the day files older than archiveAfter days into the archive of their month and deletes day
+// files and archives older than maxAge days, when it is set. The newest TXT file always stays in
+// place so the next entry can still link to the hash chain; the verifier and import only read day
+// files, so an archived period has to be extracted before it can be checked.
+// Each file stays locked until it is removed, so an entry written meanwhile goes to a new file
+// that the next run picks up.
+func (j *journalArchiver) Archive(now time.Time) (model.JournalArchive, error) {
+	result := model.JournalArchive{Archived: []string{}, Archives: []string{}, Deleted: []string{}}
+
+	unlock, err := j.lockArchive()
+	if err != nil {
+		return result, err
+	}
+	defer unlock()
+
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return result, fmt.Errorf("failed to list report files: %v", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && sinkFilePattern.MatchString(entry.Name()) {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+
+	var anchor string
+	for _, name := range files {
+		if strings.HasSuffix(name, ".txt") {
+			anchor = name
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	archiveBefore := today.AddDate(0, 0, -j.archiveAfter).Format("2006-01-02")
+	deleteBefore := ""
+	if j.maxAge > 0 {
+		deleteBefore = today.AddDate(0, 0, -j.maxAge).Format("2006-01-02")
+	}
+
+	months := map[string][]string{}
+	for _, name := range files {
+		day := name[:10]
+		switch {
+		case name == anchor:
+			// kept in place for the hash chain
+		case deleteBefore != "" && day < deleteBefore:
+			if err := j.removeDayFile(name); err != nil {
+				return result, err
+			}
+			result.Deleted = append(result.Deleted, name)
+		case day < archiveBefore:
+			months[day[:7]] = append(months[day[:7]], name)
+		}
+	}
+
+	var names []string
+	for month := range months {
+		names = append(names, month)
+	}
+	sort.Strings(names)
+	for _, month := range names {
+		if err := j.archiveMonth(month, months[month]); err != nil {
+			return result, err
+		}
+		result.Archived = append(result.Archived, months[month]...)
+		result.Archives = append(result.Archives, month+".zip")
+	}
+
+	if deleteBefore == "" {
+		return result, nil
+	}
+	archives, err := j.archives()
+	if err != nil {
+		return result, err
+	}
+	for _, name := range archives {
+		month, _ := time.ParseInLocation("2006-01", strings.TrimSuffix(name, ".zip"), time.Local)
+		if month.AddDate(0, 1, -1).Format("2006-01-02") >= deleteBefore {
+			continue
+		}
+		if err := os.Remove(filepath.Join(j.dir, archiveDir, name)); err != nil {
+			return result, fmt.Errorf("failed to delete report archive: %v", err)
+		}
+		result.Deleted = append(result.Deleted, filepath.Join(archiveDir, name))
+	}
+
+	return result, nil
+}
+
+// Export implements JournalArchiver.
+// Writes a zip with every day file from from to to, archived or not. A day that was written again
+// after it was archived appears once more with a numbered name, "2024-03-01.2.txt".
+func (j *journalArchiver) Export(from time.Time, to time.Time, w io.Writer) error {
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+	inRange := func(name string) bool {
+		return len(name) >= 10 && name[:10] >= first && name[:10] <= last
+	}
+
+	unlock, err := j.lockArchive()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	archive := zip.NewWriter(w)
+	taken := map[string]bool{}
+
+	archives, err := j.archives()
+	if err != nil {
+		return err
+	}
+	for _, name := range archives {
+		if name[:7] < first[:7] || name[:7] > last[:7] {
+			continue
+		}
+		reader, err := zip.OpenReader(filepath.Join(j.dir, archiveDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to open report archive: %v", err)
+		}
+		for _, file := range reader.File {
+			if !inRange(file.Name) {
+				continue
+			}
+			taken[file.Name] = true
+			if err := archive.Copy(file); err != nil {
+				reader.Close()
+				return fmt.Errorf("failed to write report archive: %v", err)
+			}
+		}
+		reader.Close()
+	}
+
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list report files: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !sinkFilePattern.MatchString(entry.Name()) || !inRange(entry.Name()) {
+			continue
+		}
+		// the day file is copied under the writers' locks and streamed from the copy, so a slow
+		// download does not hold up appendEntry
+		snapshot, info, err := j.snapshotDayFile(entry.Name())
+		if err != nil {
+			return err
+		}
+		err = addToZip(archive, uniqueName(entry.Name(), taken), info.ModTime(), snapshot)
+		snapshot.Close()
+		os.Remove(snapshot.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write report archive: %v", err)
+	}
+	return nil
+}
+
+// archiveMonth adds names to the archive of month and removes them. The archive is rewritten to a
+// temporary file and renamed over the old one, so a crash leaves either archive intact, and the day
+// files are only removed after the new archive is in place.
+func (j *journalArchiver) archiveMonth(month string, names []string) error {
+	dir := filepath.Join(j.dir, archiveDir)
+	path := filepath.Join(dir, month+".zip")
+
+	var locked []*os.File
+	defer func() {
+		for _, file := range locked {
+			unlockFile(file)
+			file.Close()
+			fileMutex(file.Name()).Unlock()
+		}
+	}()
+
+	temp, err := os.CreateTemp(dir, month+".zip.*")
+	if err != nil {
+		return fmt.Errorf("failed to create report archive: %v", err)
+	}
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	archive := zip.NewWriter(temp)
+	taken := map[string]bool{}
+	if reader, err := zip.OpenReader(path); err == nil {
+		for _, file := range reader.File {
+			taken[file.Name] = true
+			if err := archive.Copy(file); err != nil {
+				reader.Close()
+				return fmt.Errorf("failed to write report archive: %v", err)
+			}
+		}
+		reader.Close()
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to open report archive: %v", err)
+	}
+
+	for _, name := range names {
+		file, info, err := j.lockDayFile(name)
+		if err != nil {
+			return err
+		}
+		locked = append(locked, file)
+		if err := addToZip(archive, uniqueName(name, taken), info.ModTime(), file); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write report archive: %v", err)
+	}
+	if j.fsync {
+		if err := temp.Sync(); err != nil {
+			return fmt.Errorf("failed to sync report archive: %v", err)
+		}
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write report archive: %v", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace report archive: %v", err)
+	}
+	if j.fsync {
+		if err := syncDir(dir); err != nil {
+			return fmt.Errorf("failed to sync report archive: %v", err)
+		}
+	}
+
+	for _, file := range locked {
+		if err := removeLocked(file, file.Name()); err != nil {
+			return fmt.Errorf("failed to delete archived report file: %v", err)
+		}
+	}
+	return nil
+}
+
+// removeDayFile deletes a day file once no writer holds it
+func (j *journalArchiver) removeDayFile(name string) error {
+	return j.withDayFile(name, func(file *os.File, info os.FileInfo) error {
+		if err := removeLocked(file, file.Name()); err != nil {
+			return fmt.Errorf("failed to delete report file: %v", err)
+		}
+		return nil
+	})
+}
+
+// withDayFile calls fn with the day file locked against writers in this and other processes
+func (j *journalArchiver) withDayFile(name string, fn func(file *os.File, info os.FileInfo) error) error {
+	file, info, err := j.lockDayFile(name)
+	if err != nil {
+		return err
+	}
+	defer fileMutex(file.Name()).Unlock()
+	defer file.Close()
+	defer unlockFile(file)
+
+	return fn(file, info)
+}
+
+// snapshotDayFile copies a day file into a temporary file while holding its locks and returns the
+// copy, positioned at its start. The caller closes and removes it.
+func (j *journalArchiver) snapshotDayFile(name string) (*os.File, os.FileInfo, error) {
+	var snapshot *os.File
+	var modified os.FileInfo
+	err := j.withDayFile(name, func(file *os.File, info os.FileInfo) error {
+		copied, err := os.CreateTemp("", "report-export-*")
+		if err != nil {
+			return fmt.Errorf("failed to copy report file: %v", err)
+		}
+		if _, err := io.Copy(copied, io.LimitReader(file, info.Size())); err != nil {
+			copied.Close()
+			os.Remove(copied.Name())
+			return fmt.Errorf("failed to copy report file: %v", err)
+		}
+		snapshot, modified = copied, info
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := snapshot.Seek(0, io.SeekStart); err != nil {
+		snapshot.Close()
+		os.Remove(snapshot.Name())
+		return nil, nil, fmt.Errorf("failed to copy report file: %v", err)
+	}
+	return snapshot, modified, nil
+}
+
+// lockDayFile opens a day file and takes the same locks as appendEntry. The caller releases them.
+func (j *journalArchiver) lockDayFile(name string) (*os.File, os.FileInfo, error) {
+	path := filepath.Join(j.dir, name)
+	fileMutex(path).Lock()
+
+	file, err := os.Open(path)
+	if err != nil {
+		fileMutex(path).Unlock()
+		return nil, nil, fmt.Errorf("failed to open report file: %v", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		fileMutex(path).Unlock()
+		return nil, nil, fmt.Errorf("failed to lock report file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		unlockFile(file)
+		file.Close()
+		fileMutex(path).Unlock()
+		return nil, nil, fmt.Errorf("failed to open report file: %v", err)
+	}
+
+	return file, info, nil
+}
+
+// lockArchive keeps archive runs and exports of several instances from overlapping
+func (j *journalArchiver) lockArchive() (func(), error) {
+	dir := filepath.Join(j.dir, archiveDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create report archive folder: %v", err)
+	}
+
+	path := filepath.Join(dir, ".lock")
+	mu := fileMutex(path)
+	mu.Lock()
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		mu.Unlock()
+		return nil, fmt.Errorf("failed to open report archive lock: %v", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		mu.Unlock()
+		return nil, fmt.Errorf("failed to lock report archive: %v", err)
+	}
+
+	return func() {
+		unlockFile(file)
+		file.Close()
+		mu.Unlock()
+	}, nil
+}
+
+// archives lists the monthly archives in date order
+func (j *journalArchiver) archives() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(j.dir, archiveDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report archives: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && archivePattern.MatchString(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func addToZip(archive *zip.Writer, name string, modified time.Time, content io.Reader) error {
+	writer, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to write report archive: %v", err)
+	}
+	if _, err := io.Copy(writer, content); err != nil {
+		return fmt.Errorf("failed to write report archive: %v", err)
+	}
+	return nil
+}
+
+// uniqueName numbers name when it is already taken: "2024-03-01.txt" becomes "2024-03-01.2.txt"
+func uniqueName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s.%d%s", name[:10], i, name[10:])
+	}
+	taken[unique] = true
+	return unique
+}
+
+func NewJournalArchiver(cfg config.PathConfig, archive config.ReportArchiveConfig) JournalArchiver {
+	return &journalArchiver{
+		dir:          cfg.StaticPath,
+		archiveAfter: archive.ReportArchiveAfterDays,
+		maxAge:       archive.ReportMaxAgeDays,
+		fsync:        cfg.ReportFsync,
+	}
+}
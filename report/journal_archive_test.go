@@ -0,0 +1,170 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var archiveNow = time.Date(2024, 5, 10, 15, 0, 0, 0, time.Local)
+
+func writeDayFiles(t *testing.T, dir string, names ...string) {
+	for _, name := range names {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name+" content\n"), 0o644))
+	}
+}
+
+func zipContent(t *testing.T, r io.ReaderAt, size int64) map[string]string {
+	reader, err := zip.NewReader(r, size)
+	assert.NoError(t, err)
+
+	content := map[string]string{}
+	for _, file := range reader.File {
+		f, _ := file.Open()
+		data, _ := io.ReadAll(f)
+		f.Close()
+		content[file.Name] = string(data)
+	}
+	return content
+}
+
+func TestArchive_MovesOldFilesIntoMonthlyArchives(t *testing.T) {
+	dir := t.TempDir()
+	writeDayFiles(t, dir, "2024-03-01.txt", "2024-03-01.jsonl", "2024-03-20.txt", "2024-04-02.csv", "2024-05-01.txt", "notes.txt")
+	archiver := NewJournalArchiver(config.PathConfig{StaticPath: dir}, config.ReportArchiveConfig{ReportArchiveAfterDays: 30})
+
+	result, err := archiver.Archive(archiveNow)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-03-01.jsonl", "2024-03-01.txt", "2024-03-20.txt", "2024-04-02.csv"}, result.Archived)
+	assert.Equal(t, []string{"2024-03.zip", "2024-04.zip"}, result.Archives)
+	assert.Empty(t, result.Deleted)
+	for _, name := range result.Archived {
+		assert.NoFileExists(t, filepath.Join(dir, name))
+	}
+	assert.FileExists(t, filepath.Join(dir, "2024-05-01.txt"))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+
+	archive, _ := os.ReadFile(filepath.Join(dir, "archive", "2024-03.zip"))
+	content := zipContent(t, bytes.NewReader(archive), int64(len(archive)))
+	assert.Len(t, content, 3)
+	assert.Equal(t, "2024-03-20.txt content\n", content["2024-03-20.txt"])
+}
+
+func TestArchive_KeepsNewestTXTFileAndAddsRewrittenDays(t *testing.T) {
+	dir := t.TempDir()
+	writeDayFiles(t, dir, "2024-03-01.txt", "2024-03-02.txt")
+	archiver := NewJournalArchiver(config.PathConfig{StaticPath: dir}, config.ReportArchiveConfig{ReportArchiveAfterDays: 30})
+
+	_, err := archiver.Archive(archiveNow)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "2024-03-02.txt"))
+
+	writeDayFiles(t, dir, "2024-03-01.txt", "2024-05-09.txt")
+	result, err := archiver.Archive(archiveNow)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-03-01.txt", "2024-03-02.txt"}, result.Archived)
+	archive, _ := os.ReadFile(filepath.Join(dir, "archive", "2024-03.zip"))
+	content := zipContent(t, bytes.NewReader(archive), int64(len(archive)))
+	assert.Len(t, content, 3)
+	assert.Contains(t, content, "2024-03-01.2.txt")
+}
+
+func TestArchive_DeletesBeyondMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	writeDayFiles(t, dir, "2024-01-15.txt", "2024-02-28.txt", "2024-05-01.txt")
+	os.MkdirAll(filepath.Join(dir, "archive"), os.ModePerm)
+	writeDayFiles(t, filepath.Join(dir, "archive"), "2023-12.zip")
+	archiver := NewJournalArchiver(config.PathConfig{StaticPath: dir}, config.ReportArchiveConfig{ReportArchiveAfterDays: 30, ReportMaxAgeDays: 90})
+
+	result, err := archiver.Archive(archiveNow)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-01-15.txt", filepath.Join("archive", "2023-12.zip")}, result.Deleted)
+	assert.Equal(t, []string{"2024-02-28.txt"}, result.Archived)
+	assert.FileExists(t, filepath.Join(dir, "archive", "2024-02.zip"))
+}
+
+func TestExport_CombinesArchivedAndCurrentFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeDayFiles(t, dir, "2024-03-30.txt", "2024-03-31.jsonl", "2024-04-01.txt", "2024-05-01.txt")
+	archiver := NewJournalArchiver(config.PathConfig{StaticPath: dir}, config.ReportArchiveConfig{ReportArchiveAfterDays: 30})
+	_, err := archiver.Archive(archiveNow)
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	err = archiver.Export(time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local), time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), &output)
+
+	assert.NoError(t, err)
+	content := zipContent(t, bytes.NewReader(output.Bytes()), int64(output.Len()))
+	assert.Len(t, content, 3)
+	assert.Contains(t, content, "2024-03-31.jsonl")
+	assert.Contains(t, content, "2024-04-01.txt")
+	assert.Equal(t, "2024-05-01.txt content\n", content["2024-05-01.txt"])
+}
+
+// appendingWriter appends a journal entry the first time the export writes to it
+type appendingWriter struct {
+	sink     ReportSink
+	appended chan error
+}
+
+func (w *appendingWriter) Write(p []byte) (int, error) {
+	if w.appended != nil {
+		done := make(chan error, 1)
+		go func() { done <- w.sink.WriteReport(sinkReport, "update") }()
+		select {
+		case err := <-done:
+			w.appended <- err
+		case <-time.After(2 * time.Second):
+			w.appended <- fmt.Errorf("append blocked by the export")
+		}
+		w.appended = nil
+	}
+	return len(p), nil
+}
+
+func TestExport_DoesNotBlockWriters(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.PathConfig{StaticPath: dir}
+	sink := NewReportToJSONL(cfg)
+	assert.NoError(t, sink.WriteReport(sinkReport, "create"))
+	// enough incompressible content for the zip to write while the day file is read
+	noise := make([]byte, 1<<20)
+	rand.Read(noise)
+	file, _ := os.OpenFile(filepath.Join(dir, "2024-03-01.jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	file.Write(noise)
+	file.Close()
+
+	appended := make(chan error, 1)
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	err := NewJournalArchiver(cfg, config.ReportArchiveConfig{}).Export(day, day, &appendingWriter{sink: sink, appended: appended})
+
+	assert.NoError(t, err)
+	assert.NoError(t, <-appended)
+}
+
+func TestAppendEntry_AfterArchive(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.PathConfig{StaticPath: dir}
+	sink := NewReportToJSONL(cfg)
+	assert.NoError(t, sink.WriteReport(sinkReport, "create"))
+
+	_, err := NewJournalArchiver(cfg, config.ReportArchiveConfig{ReportArchiveAfterDays: 30}).Archive(archiveNow)
+	assert.NoError(t, err)
+	assert.NoError(t, sink.WriteReport(sinkReport, "update"))
+
+	content, _ := os.ReadFile(filepath.Join(dir, "2024-03-01.jsonl"))
+	assert.Contains(t, string(content), `"action":"update"`)
+	assert.NotContains(t, string(content), `"action":"create"`)
+}
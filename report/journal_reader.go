@@ -0,0 +1,109 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+// JournalReader reads the TXT journal back as report changes
+type JournalReader interface {
+	Read(from time.Time, to time.Time) ([]model.JournalEntry, error)
+}
+
+type journalReader struct {
+	dir string
+}
+
+// Read implements JournalReader.
+// Returns the entries of the day files from from to to in the order they were written. A zero from
+// starts at the oldest file. Files written before the journal was append-only kept their newest entry
+// on top, so the unsealed entries at the head of a file are read bottom up.
+// Entries that cannot be parsed are returned with Error set.
+func (j *journalReader) Read(from time.Time, to time.Time) ([]model.JournalEntry, error) {
+	files, err := dayFiles(j.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report files: %v", err)
+	}
+
+	first := 0
+	if !from.IsZero() {
+		first = sort.SearchStrings(files, from.Format("2006-01-02")+".txt")
+	}
+	last := to.Format("2006-01-02") + ".txt"
+
+	var entries []model.JournalEntry
+	for _, name := range files[first:] {
+		if name > last {
+			break
+		}
+		content, err := os.ReadFile(filepath.Join(j.dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report file: %v", err)
+		}
+
+		parsed, rest := parseTXTJournal(content)
+		legacy := 0
+		for legacy < len(parsed) && parsed[legacy].Hash == "" {
+			legacy++
+		}
+		for i := legacy - 1; i >= 0; i-- {
+			entries = append(entries, journalEntry(name, i+1, parsed[i]))
+		}
+		for i := legacy; i < len(parsed); i++ {
+			entries = append(entries, journalEntry(name, i+1, parsed[i]))
+		}
+
+		if len(bytes.TrimSpace(rest)) > 0 {
+			entries = append(entries, model.JournalEntry{File: name, Entry: len(parsed) + 1, Error: "incomplete entry at the end of the file"})
+		}
+	}
+
+	return entries, nil
+}
+
+// journalEntry decodes the title and JSON of a parsed TXT entry
+func journalEntry(file string, index int, entry txtEntry) model.JournalEntry {
+	result := model.JournalEntry{File: file, Entry: index, Date: entry.Date, Time: entry.Time, Actor: entry.Actor}
+
+	for action, title := range txtStatusMessages {
+		if title == entry.Status {
+			result.Action = action
+		}
+	}
+	if result.Action == "" {
+		result.Error = fmt.Sprintf("unknown entry title %q", entry.Status)
+		return result
+	}
+	if _, err := time.Parse("2006-01-02", entry.Date); err != nil {
+		result.Error = "entry has no valid date"
+		return result
+	}
+	if err := json.Unmarshal([]byte(entry.Content), &result.Report); err != nil {
+		result.Error = fmt.Sprintf("entry content is not a report: %v", err)
+		return result
+	}
+	if result.Report.Id == "" {
+		result.Error = "entry has no report id"
+		return result
+	}
+	if entry.Before != "" {
+		result.Before = &model.Report{}
+		if err := json.Unmarshal([]byte(entry.Before), result.Before); err != nil {
+			result.Error = fmt.Sprintf("entry previous version is not a report: %v", err)
+		}
+	}
+
+	return result
+}
+
+func NewJournalReader(cfg config.PathConfig) JournalReader {
+	return &journalReader{dir: cfg.StaticPath}
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead_LegacyHeadIsReversed(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir()}
+	legacy := "Update report\nDate: 2024-03-01\n{\"id\":\"r0\",\"report\":\"second\"}\n\n" +
+		"Create report\nDate: 2024-03-01\n{\"id\":\"r0\",\"report\":\"first\"}\n\n"
+	os.WriteFile(filepath.Join(cfg.StaticPath, "2024-03-01.txt"), []byte(legacy), 0o644)
+	writeChain(t, cfg)
+
+	entries, err := NewJournalReader(cfg).Read(time.Time{}, chainTo)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5)
+	assert.Equal(t, "create", entries[0].Action)
+	assert.Equal(t, "first", entries[0].Report.Report)
+	assert.Equal(t, 2, entries[0].Entry)
+	assert.Equal(t, "second", entries[1].Report.Report)
+	for i, entry := range entries[2:] {
+		assert.Equal(t, "update", entry.Action)
+		assert.Equal(t, "r1", entry.Report.Id)
+		assert.Equal(t, i+1, entry.Report.Version)
+	}
+	assert.Equal(t, "2024-03-02.txt", entries[4].File)
+}
+
+func TestRead_DateRange(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir()}
+	writeChain(t, cfg)
+
+	entries, err := NewJournalReader(cfg).Read(chainTo, chainTo)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "2024-03-02", entries[0].Date)
+}
+
+func TestRead_UnreadableEntries(t *testing.T) {
+	cfg := config.PathConfig{StaticPath: t.TempDir()}
+	content := "Rename report\nDate: 2024-03-01\n{\"id\":\"r1\"}\n\n" +
+		"Create report\nDate: 2024-03-01\n{\"report\":\"no id\"}\n\n" +
+		"Create report\nDate: 2024-03-01\n{\"id\":"
+	os.WriteFile(filepath.Join(cfg.StaticPath, "2024-03-01.txt"), []byte(content), 0o644)
+
+	entries, err := NewJournalReader(cfg).Read(chainFrom, chainFrom)
+
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		for _, entry := range entries {
+			assert.NotEmpty(t, entry.Error)
+		}
+	}
+}
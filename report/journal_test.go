@@ -0,0 +1,62 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendEntry_ConcurrentWritersKeepEveryEntry(t *testing.T) {
+	dir := t.TempDir()
+	first := NewReportToJSONL(config.PathConfig{StaticPath: dir})
+	second := NewReportToJSONL(config.PathConfig{StaticPath: dir})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); first.WriteReport(sinkReport, "create") }()
+		go func() { defer wg.Done(); second.WriteReport(sinkReport, "update") }()
+	}
+	wg.Wait()
+
+	content, _ := os.ReadFile(filepath.Join(dir, "2024-03-01.jsonl"))
+	assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 100)
+}
+
+func TestAppendEntry_CutsTornEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "day.jsonl")
+	os.WriteFile(path, []byte("{\"a\":1}\n{\"a\":"), 0o644)
+
+	assert.NoError(t, appendEntry(path, "", "{\"a\":2}\n", "\n", true, nil))
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "{\"a\":1}\n{\"a\":2}\n", string(content))
+}
+
+func TestReportToTXT_AppendsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	sink := NewReportToTXT(config.PathConfig{StaticPath: dir})
+	updated := sinkReport
+	updated.Date = sinkReport.Date.Add(time.Hour)
+	updated.Content = model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "updated", Version: 2}
+
+	assert.NoError(t, sink.WriteReport(sinkReport, "create"))
+	assert.NoError(t, sink.WriteReport(updated, "update"))
+
+	content, _ := os.ReadFile(filepath.Join(dir, "2024-03-01.txt"))
+	entries, rest := parseTXTJournal(content)
+	assert.Empty(t, rest)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Create report", entries[0].Status)
+	assert.Equal(t, "2024-03-01", entries[0].Date)
+	assert.Equal(t, `{"id":"r1","user_id":"u1","report":"fixed login, \"quoted\"\nsecond line","task_id":"t1","version":1}`, entries[0].Content)
+	assert.Equal(t, "Update report", entries[1].Status)
+	assert.Equal(t, entries[0].Hash, entries[1].Prev)
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+)
+
+// csvColumns is the header of the csv sink
+var csvColumns = []string{"action", "logged_at", "id", "user_id", "task_id", "report", "version", "created_at", "updated_at", "actor_id", "before_task_id", "before_report", "before_version"}
+
+// NewReportToJSONL writes one JSON object per line to "YYYY-MM-DD.jsonl".
+func NewReportToJSONL(cfg config.PathConfig) ReportSink {
+	return &dailyFileSink{
+		dir:        cfg.StaticPath,
+		ext:        ".jsonl",
+		terminator: "\n",
+		fsync:      cfg.ReportFsync,
+		format: func(record reportRecord) (string, error) {
+			line, err := json.Marshal(record)
+			if err != nil {
+				return "", err
+			}
+			return string(line) + "\n", nil
+		},
+	}
+}
+
+// NewReportToCSV writes one row per entry to "YYYY-MM-DD.csv", with a header row on top.
+// The before columns are only filled for updates.
+func NewReportToCSV(cfg config.PathConfig) ReportSink {
+	return &dailyFileSink{
+		dir:        cfg.StaticPath,
+		ext:        ".csv",
+		terminator: "\n",
+		fsync:      cfg.ReportFsync,
+		header: func(time.Time) string {
+			return strings.Join(csvColumns, ",") + "\n"
+		},
+		format: func(record reportRecord) (string, error) {
+			before := []string{"", "", ""}
+			if record.Before != nil {
+				before = []string{record.Before.TaskId, record.Before.Report, strconv.Itoa(record.Before.Version)}
+			}
+
+			var row bytes.Buffer
+			writer := csv.NewWriter(&row)
+			writer.Write(append([]string{
+				record.Action,
+				record.LoggedAt.Format(time.RFC3339),
+				record.Id,
+				record.UserId,
+				record.TaskId,
+				record.Report,
+				strconv.Itoa(record.Version),
+				record.CreatedAt.Format(time.RFC3339),
+				record.UpdatedAt.Format(time.RFC3339),
+				record.ActorId,
+			}, before...))
+			writer.Flush()
+			return row.String(), writer.Error()
+		},
+	}
+}
+
+// NewReportToMarkdown writes a readable "YYYY-MM-DD.md" with one section per entry.
+func NewReportToMarkdown(cfg config.PathConfig) ReportSink {
+	return &dailyFileSink{
+		dir:        cfg.StaticPath,
+		ext:        ".md",
+		terminator: "\n\n",
+		fsync:      cfg.ReportFsync,
+		header: func(day time.Time) string {
+			return "# Reports " + day.Format("2006-01-02") + "\n\n"
+		},
+		format: func(record reportRecord) (string, error) {
+			var entry strings.Builder
+			fmt.Fprintf(&entry, "## %s report %s (%s)\n\n", actionTitle(record.Action), record.Id, record.LoggedAt.Format("15:04:05"))
+			fmt.Fprintf(&entry, "- User: %s\n- Task: %s\n- Version: %d\n- Actor: %s\n\n", record.UserId, record.TaskId, record.Version, record.ActorId)
+			for _, line := range strings.Split(record.Report, "\n") {
+				fmt.Fprintf(&entry, "> %s\n", line)
+			}
+			if record.Before != nil {
+				fmt.Fprintf(&entry, "\nBefore (version %d, task %s):\n\n", record.Before.Version, record.Before.TaskId)
+				for _, line := range strings.Split(record.Before.Report, "\n") {
+					fmt.Fprintf(&entry, "> %s\n", line)
+				}
+			}
+			entry.WriteString("\n")
+			return entry.String(), nil
+		},
+	}
+}
+
+// actionTitle capitalizes the action for headings
+func actionTitle(action string) string {
+	if action == "" {
+		return action
+	}
+	return strings.ToUpper(action[:1]) + action[1:]
+}
@@ -1,117 +1,132 @@
 package report
 
 import (
-	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"enigma.com/projectmanagementhub/config"
 	"enigma.com/projectmanagementhub/model"
 )
 
-// ReportToTXT adalah interface untuk menulis laporan ke file teks.
-type ReportToTXT interface {
-	WriteReport(report model.ShowReport, status string) error
+// txtStatusMessages adalah judul setiap entri di file teks.
+var txtStatusMessages = map[string]string{
+	"create":  "Create report",
+	"update":  "Update report",
+	"delete":  "Delete report",
+	"restore": "Restore report",
+	"purge":   "Purge report",
 }
 
-type reportToTXT struct {
-	cfg config.PathConfig
-}
-
-func NewReportToTXT(cfg config.PathConfig) ReportToTXT {
-	return &reportToTXT{cfg: cfg}
+// NewReportToTXT membuat ReportSink yang menulis laporan ke file "YYYY-MM-DD.txt".
+// Setiap entri ditambahkan di akhir file, dipisahkan oleh baris kosong, dan dirantai dengan hash
+// entri sebelumnya (lihat hashChain).
+func NewReportToTXT(cfg config.PathConfig) ReportSink {
+	return &dailyFileSink{
+		dir:        cfg.StaticPath,
+		ext:        ".txt",
+		terminator: "\n\n",
+		fsync:      cfg.ReportFsync,
+		seal:       (&hashChain{dir: cfg.StaticPath, key: cfg.ReportHmacKey}).seal,
+		format:     formatTXTEntry,
+	}
 }
 
-// WriteReport menulis laporan ke file teks.
-func (r *reportToTXT) WriteReport(report model.ShowReport, status string) error {
-	// Membuat nama file dengan format "YYYY-MM-DD.txt" di dalam folder
-	folderPath := r.cfg.StaticPath //"D:/final_project_enigma2/project-management-hub/report/report-result"
-	fileName := filepath.Join(folderPath, time.Now().Format("2006-01-02")+".txt")
-
-	// Membuat folder jika belum ada
-	if err := os.MkdirAll(folderPath, os.ModePerm); err != nil {
-		return fmt.Errorf("gagal membuat folder: %v", err)
+// formatTXTEntry menulis judul status, tanggal, waktu, pelaku dan laporan dalam bentuk JSON.
+// Pada update, versi sebelumnya ditulis di baris "Before:".
+func formatTXTEntry(record reportRecord) (string, error) {
+	content, err := txtReportJSON(record.content)
+	if err != nil {
+		return "", err
 	}
 
-	// Mengecek apakah file untuk hari tersebut sudah ada atau belum
-	_, err := os.Stat(fileName)
-	if os.IsNotExist(err) {
-		// Jika file belum ada, buat file baru
-		file, err := os.Create(fileName)
-		if err != nil {
-			return fmt.Errorf("gagal membuat file: %v", err)
-		}
-		defer file.Close()
-
-		writer := bufio.NewWriter(file)
-
-		// Menggunakan encoding/json untuk mengubah struct Report menjadi JSON
-		jsonOldData, err := json.Marshal(report.Content)
+	var entry strings.Builder
+	fmt.Fprintf(&entry, "%s\nDate: %s\nTime: %s\n", txtStatusMessages[record.Action], record.LoggedAt.Format("2006-01-02"), record.LoggedAt.Format(time.RFC3339))
+	if record.ActorId != "" {
+		fmt.Fprintf(&entry, "Actor: %s\n", record.ActorId)
+	}
+	if record.before != nil {
+		before, err := txtReportJSON(*record.before)
 		if err != nil {
-			return fmt.Errorf("gagal mengonversi ke JSON: %v", err)
+			return "", err
 		}
+		fmt.Fprintf(&entry, "Before: %s\n", before)
+	}
+	fmt.Fprintf(&entry, "%s\n\n", content)
 
-		// Check the status and set the appropriate message
-		var statusMessage string
-		if status == "create" {
-			statusMessage = "Create report"
-		} else if status == "update" {
-			statusMessage = "Update report"
-		} else if status == "delete" {
-			statusMessage = "Delete report"
-		}
+	return entry.String(), nil
+}
 
-		writer.WriteString(fmt.Sprintf("%s\nDate: %s\n%s\n", statusMessage, time.Now().Format("2006-01-02"), jsonOldData))
+// txtReportJSON mengonversi laporan ke JSON satu baris.
+func txtReportJSON(report model.Report) ([]byte, error) {
+	// jurnal mencatat isi laporan anggota, bukan review dari manager
+	report.ReviewStatus, report.ReviewComment, report.ReviewedBy, report.ReviewedAt = "", "", "", nil
 
-		writer.WriteString("\n")
-		writer.Flush()
+	content, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengonversi ke JSON: %v", err)
+	}
+	return content, nil
+}
 
-		fmt.Printf("File '%s' telah dibuat.\n", fileName)
-	} else if err == nil {
-		// Jika file sudah ada, baca kontennya, tambahkan data baru, dan tulis kembali
-		file, err := os.OpenFile(fileName, os.O_RDWR, 0666)
-		if err != nil {
-			return fmt.Errorf("gagal membuka file: %v", err)
-		}
-		defer file.Close()
+// txtEntry adalah satu entri file teks. Body adalah teks yang di-hash: semua baris kecuali trailer.
+// Time, Actor dan Before kosong pada entri lama.
+type txtEntry struct {
+	Status  string
+	Date    string
+	Time    string
+	Actor   string
+	Before  string
+	Content string
+	Prev    string
+	Hash    string
+	Hmac    string
+	Body    string
+}
 
-		// Baca konten lama
-		scanner := bufio.NewScanner(file)
-		var oldContent string
-		for scanner.Scan() {
-			oldContent += scanner.Text() + "\n"
+// parseTXTJournal memecah isi file teks menjadi entri. rest berisi sisa yang tidak diakhiri baris kosong.
+func parseTXTJournal(content []byte) (entries []txtEntry, rest []byte) {
+	for {
+		block, after, found := bytes.Cut(content, []byte("\n\n"))
+		if !found {
+			return entries, content
 		}
-
-		// Tambahkan data baru
-		jsonOldData, err := json.Marshal(report.Content)
-		if err != nil {
-			return fmt.Errorf("gagal mengonversi ke JSON: %v", err)
+		content = after
+		if len(bytes.TrimSpace(block)) == 0 {
+			continue
 		}
 
-		// Check the status and set the appropriate message
-		var statusMessage string
-		if status == "create" {
-			statusMessage = "Create report"
-		} else if status == "update" {
-			statusMessage = "Update report"
-		} else if status == "delete" {
-			statusMessage = "Delete report"
+		var entry txtEntry
+		var body strings.Builder
+		for i, line := range strings.Split(string(block), "\n") {
+			switch {
+			case strings.HasPrefix(line, "Prev: "):
+				entry.Prev = strings.TrimPrefix(line, "Prev: ")
+				continue
+			case strings.HasPrefix(line, "Hash: "):
+				entry.Hash = strings.TrimPrefix(line, "Hash: ")
+				continue
+			case strings.HasPrefix(line, "Hmac: "):
+				entry.Hmac = strings.TrimPrefix(line, "Hmac: ")
+				continue
+			case i == 0:
+				entry.Status = line
+			case strings.HasPrefix(line, "Date: "):
+				entry.Date = strings.TrimPrefix(line, "Date: ")
+			case strings.HasPrefix(line, "Time: "):
+				entry.Time = strings.TrimPrefix(line, "Time: ")
+			case strings.HasPrefix(line, "Actor: "):
+				entry.Actor = strings.TrimPrefix(line, "Actor: ")
+			case strings.HasPrefix(line, "Before: "):
+				entry.Before = strings.TrimPrefix(line, "Before: ")
+			default:
+				entry.Content = line
+			}
+			body.WriteString(line + "\n")
 		}
-
-		newContent := fmt.Sprintf("%s\nDate: %s\n%s\n\n%s", statusMessage, time.Now().Format("2006-01-02"), jsonOldData, oldContent)
-
-		// Pindahkan kursor ke awal file dan tulis konten baru
-		file.Seek(0, 0)
-		file.Truncate(0)
-		file.WriteString(newContent)
-
-		fmt.Printf("File '%s' telah diperbarui.\n", fileName)
-	} else {
-		return fmt.Errorf("gagal mendapatkan info file: %v", err)
+		entry.Body = body.String()
+		entries = append(entries, entry)
 	}
-
-	return nil
 }
@@ -0,0 +1,152 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+// ReportSink receives every report that is created, updated, deleted, restored or purged.
+// status is "create", "update", "delete", "restore" or "purge".
+type ReportSink interface {
+	WriteReport(report model.ShowReport, status string) error
+}
+
+// reportRecord is the flat form of a report change used by the structured sinks.
+// The report fields hold the values after the change; Before is only set on updates.
+type reportRecord struct {
+	Action    string        `json:"action"`
+	LoggedAt  time.Time     `json:"logged_at"`
+	ActorId   string        `json:"actor_id"`
+	Id        string        `json:"id"`
+	UserId    string        `json:"user_id"`
+	TaskId    string        `json:"task_id"`
+	Report    string        `json:"report"`
+	Version   int           `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Before    *reportValues `json:"before,omitempty"`
+	content   model.Report
+	before    *model.Report
+}
+
+// reportValues are the fields of a report an update can change
+type reportValues struct {
+	UserId    string    `json:"user_id"`
+	TaskId    string    `json:"task_id"`
+	Report    string    `json:"report"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newReportRecord(report model.ShowReport, status string) reportRecord {
+	loggedAt := report.Date
+	if loggedAt.IsZero() {
+		loggedAt = time.Now()
+	}
+
+	var before *reportValues
+	if report.Before != nil {
+		before = &reportValues{
+			UserId:    report.Before.User_id,
+			TaskId:    report.Before.Task_id,
+			Report:    report.Before.Report,
+			Version:   report.Before.Version,
+			UpdatedAt: report.Before.Updated_at,
+		}
+	}
+
+	return reportRecord{
+		Action:    status,
+		LoggedAt:  loggedAt,
+		ActorId:   report.ActorId,
+		Id:        report.Content.Id,
+		UserId:    report.Content.User_id,
+		TaskId:    report.Content.Task_id,
+		Report:    report.Content.Report,
+		Version:   report.Content.Version,
+		CreatedAt: report.Content.Created_at,
+		UpdatedAt: report.Content.Updated_at,
+		Before:    before,
+		content:   report.Content,
+		before:    report.Before,
+	}
+}
+
+// dailyFileSink appends entries to one file per day, named after the entry date and ext.
+// header, when set, is written once at the top of every new file, and every entry ends with
+// terminator so a torn write can be recognized.
+type dailyFileSink struct {
+	dir        string
+	ext        string
+	terminator string
+	fsync      bool
+	seal       sealFunc
+	header     func(day time.Time) string
+	format     func(record reportRecord) (string, error)
+}
+
+// WriteReport implements ReportSink.
+func (d *dailyFileSink) WriteReport(report model.ShowReport, status string) error {
+	record := newReportRecord(report, status)
+	entry, err := d.format(record)
+	if err != nil {
+		return fmt.Errorf("failed to format report entry: %v", err)
+	}
+
+	var header string
+	if d.header != nil {
+		header = d.header(record.LoggedAt)
+	}
+
+	fileName := filepath.Join(d.dir, record.LoggedAt.Format("2006-01-02")+d.ext)
+	return appendEntry(fileName, header, entry, d.terminator, d.fsync, d.seal)
+}
+
+// multiSink writes to every sink, so one failing sink does not stop the others
+type multiSink []ReportSink
+
+// WriteReport implements ReportSink.
+func (m multiSink) WriteReport(report model.ShowReport, status string) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.WriteReport(report, status); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewReportSink builds the sinks named in cfg.ReportSinks: txt, jsonl, csv and md.
+// Several sinks write side by side into cfg.StaticPath.
+func NewReportSink(cfg config.PathConfig) (ReportSink, error) {
+	var sinks multiSink
+	for _, name := range cfg.ReportSinks {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "txt":
+			sinks = append(sinks, NewReportToTXT(cfg))
+		case "jsonl":
+			sinks = append(sinks, NewReportToJSONL(cfg))
+		case "csv":
+			sinks = append(sinks, NewReportToCSV(cfg))
+		case "md", "markdown":
+			sinks = append(sinks, NewReportToMarkdown(cfg))
+		default:
+			return nil, fmt.Errorf("unknown report sink %q", name)
+		}
+	}
+
+	switch len(sinks) {
+	case 0:
+		return NewReportToTXT(cfg), nil
+	case 1:
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
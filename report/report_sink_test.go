@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var sinkReport = model.ShowReport{
+	Date:    time.Date(2024, 3, 1, 9, 30, 0, 0, time.Local),
+	Content: model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "fixed login, \"quoted\"\nsecond line", Version: 1},
+}
+
+func TestNewReportSink_CombinesSinks(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewReportSink(config.PathConfig{StaticPath: dir, ReportSinks: []string{"jsonl", "csv", "md"}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, sink.WriteReport(sinkReport, "create"))
+	assert.NoError(t, sink.WriteReport(sinkReport, "update"))
+
+	jsonl, _ := os.ReadFile(filepath.Join(dir, "2024-03-01.jsonl"))
+	lines := strings.Split(strings.TrimSpace(string(jsonl)), "\n")
+	assert.Len(t, lines, 2)
+	var record reportRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "update", record.Action)
+	assert.Equal(t, sinkReport.Content.Report, record.Report)
+
+	file, _ := os.Open(filepath.Join(dir, "2024-03-01.csv"))
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, csvColumns, rows[0])
+	assert.Equal(t, sinkReport.Content.Report, rows[1][5])
+
+	markdown, _ := os.ReadFile(filepath.Join(dir, "2024-03-01.md"))
+	assert.True(t, strings.HasPrefix(string(markdown), "# Reports 2024-03-01\n"))
+	assert.Contains(t, string(markdown), "## Update report r1 (09:30:00)")
+	assert.Contains(t, string(markdown), "> second line\n")
+}
+
+func TestNewReportSink_UnknownSink(t *testing.T) {
+	_, err := NewReportSink(config.PathConfig{StaticPath: t.TempDir(), ReportSinks: []string{"txt", "xml"}})
+
+	assert.Error(t, err)
+}
+
+func TestWriteReport_RecordsActorAndPreviousVersion(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.PathConfig{StaticPath: dir, ReportSinks: []string{"txt", "jsonl", "csv"}}
+	sink, err := NewReportSink(cfg)
+	assert.NoError(t, err)
+	change := sinkReport
+	change.ActorId = "u1"
+	change.Before = &model.Report{Id: "r1", User_id: "u1", Task_id: "t0", Report: "draft", Version: 1, ReviewStatus: model.ReportAcknowledged}
+	change.Content.Version = 2
+
+	assert.NoError(t, sink.WriteReport(change, "update"))
+	assert.NoError(t, sink.WriteReport(model.ShowReport{Date: sinkReport.Date, ActorId: "a1", Content: change.Content}, "delete"))
+
+	entries, err := NewJournalReader(cfg).Read(time.Time{}, sinkReport.Date)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "u1", entries[0].Actor)
+		assert.Equal(t, sinkReport.Date.Format(time.RFC3339), entries[0].Time)
+		assert.Equal(t, "draft", entries[0].Before.Report)
+		assert.Empty(t, entries[0].Before.ReviewStatus)
+		assert.Equal(t, 2, entries[0].Report.Version)
+		assert.Equal(t, "delete", entries[1].Action)
+		assert.Equal(t, "a1", entries[1].Actor)
+		assert.Nil(t, entries[1].Before)
+		assert.Equal(t, sinkReport.Content.Report, entries[1].Report.Report)
+	}
+	verification, err := NewJournalVerifier(cfg).Verify(sinkReport.Date, sinkReport.Date)
+	assert.NoError(t, err)
+	assert.True(t, verification.Valid)
+
+	jsonl, _ := os.ReadFile(filepath.Join(dir, "2024-03-01.jsonl"))
+	var record reportRecord
+	assert.NoError(t, json.Unmarshal([]byte(strings.Split(string(jsonl), "\n")[0]), &record))
+	assert.Equal(t, "u1", record.ActorId)
+	assert.Equal(t, "t0", record.Before.TaskId)
+
+	file, _ := os.Open(filepath.Join(dir, "2024-03-01.csv"))
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1", "t0", "draft", "1"}, rows[1][9:])
+	assert.Equal(t, []string{"a1", "", "", ""}, rows[2][9:])
+}
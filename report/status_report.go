@@ -0,0 +1,160 @@
+package report
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+)
+
+// statusView is the template data of a status report, with the member reports grouped by author
+type statusView struct {
+	model.StatusReportData
+	Members []memberSection
+}
+
+type memberSection struct {
+	Name    string
+	Reports []model.MemberReport
+}
+
+var statusFuncs = map[string]any{
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"datetime": func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	"indent":   func(s string) string { return strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n  ") },
+	"orDash": func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	},
+}
+
+var statusMarkdown = template.Must(template.New("markdown").Funcs(statusFuncs).Parse(`# Status report: {{.Project.Name}}{{if .Project.Key}} ({{.Project.Key}}){{end}}
+
+Period: {{.StartDate}} to {{.EndDate}}, generated {{datetime .GeneratedAt}}
+
+## Summary
+
+- Status changes: {{len .StatusChanges}}
+- Completed tasks: {{len .Completed}}
+- Overdue tasks: {{len .Overdue}}
+- Member reports: {{len .Reports}}
+
+## Completed tasks
+{{range .Completed}}
+- {{.Key}} {{.Name}} (accepted {{date .ChangedAt}}){{else}}
+_None._{{end}}
+
+## Overdue tasks
+{{range .Overdue}}
+- {{.Key}} {{.Name}}: {{.Status}}, due {{.Deadline}}, {{orDash .PersonInCharge}}{{else}}
+_None._{{end}}
+
+## Status changes
+{{range .StatusChanges}}
+- {{datetime .ChangedAt}} {{.Key}} {{.Name}}: {{orDash .PreviousStatus}} -> {{.Status}}{{else}}
+_None._{{end}}
+
+## Member reports
+{{range .Members}}
+### {{.Name}}
+{{range .Reports}}
+- {{datetime .CreatedAt}} {{.TaskKey}}: {{indent .Report}}{{end}}
+{{else}}
+_None._
+{{end}}`))
+
+var statusHTML = htmltemplate.Must(htmltemplate.New("html").Funcs(statusFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Status report: {{.Project.Name}} {{.StartDate}} to {{.EndDate}}</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+td.report { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Status report: {{.Project.Name}}{{if .Project.Key}} ({{.Project.Key}}){{end}}</h1>
+<p>Period: {{.StartDate}} to {{.EndDate}}, generated {{datetime .GeneratedAt}}</p>
+
+<h2>Summary</h2>
+<ul>
+<li>Status changes: {{len .StatusChanges}}</li>
+<li>Completed tasks: {{len .Completed}}</li>
+<li>Overdue tasks: {{len .Overdue}}</li>
+<li>Member reports: {{len .Reports}}</li>
+</ul>
+
+<h2>Completed tasks</h2>
+{{if .Completed}}<table>
+<tr><th>Task</th><th>Name</th><th>Accepted</th></tr>
+{{range .Completed}}<tr><td>{{.Key}}</td><td>{{.Name}}</td><td>{{date .ChangedAt}}</td></tr>
+{{end}}</table>{{else}}<p><em>None.</em></p>{{end}}
+
+<h2>Overdue tasks</h2>
+{{if .Overdue}}<table>
+<tr><th>Task</th><th>Name</th><th>Status</th><th>Deadline</th><th>Person in charge</th></tr>
+{{range .Overdue}}<tr><td>{{.Key}}</td><td>{{.Name}}</td><td>{{.Status}}</td><td>{{.Deadline}}</td><td>{{orDash .PersonInCharge}}</td></tr>
+{{end}}</table>{{else}}<p><em>None.</em></p>{{end}}
+
+<h2>Status changes</h2>
+{{if .StatusChanges}}<table>
+<tr><th>When</th><th>Task</th><th>Name</th><th>From</th><th>To</th></tr>
+{{range .StatusChanges}}<tr><td>{{datetime .ChangedAt}}</td><td>{{.Key}}</td><td>{{.Name}}</td><td>{{orDash .PreviousStatus}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>{{else}}<p><em>None.</em></p>{{end}}
+
+<h2>Member reports</h2>
+{{range .Members}}<h3>{{.Name}}</h3>
+<table>
+<tr><th>When</th><th>Task</th><th>Report</th></tr>
+{{range .Reports}}<tr><td>{{datetime .CreatedAt}}</td><td>{{.TaskKey}}</td><td class="report">{{.Report}}</td></tr>
+{{end}}</table>
+{{else}}<p><em>None.</em></p>
+{{end}}</body>
+</html>
+`))
+
+// RenderStatusMarkdown renders a project status report as Markdown
+func RenderStatusMarkdown(data model.StatusReportData) (string, error) {
+	var buf bytes.Buffer
+	if err := statusMarkdown.Execute(&buf, newStatusView(data)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderStatusHTML renders a project status report as a standalone HTML page
+func RenderStatusHTML(data model.StatusReportData) (string, error) {
+	var buf bytes.Buffer
+	if err := statusHTML.Execute(&buf, newStatusView(data)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// newStatusView groups the member reports by author, keeping the order of their first report
+func newStatusView(data model.StatusReportData) statusView {
+	view := statusView{StatusReportData: data}
+	index := map[string]int{}
+	for _, report := range data.Reports {
+		i, ok := index[report.UserId]
+		if !ok {
+			name := report.UserName
+			if name == "" {
+				name = report.UserId
+			}
+			i = len(view.Members)
+			index[report.UserId] = i
+			view.Members = append(view.Members, memberSection{Name: name})
+		}
+		view.Members[i].Reports = append(view.Members[i].Reports, report)
+	}
+	return view
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+// rankGap is the distance between neighbouring tasks after a column is renumbered
+const rankGap = 1024
+
+// minRankGap is the smallest gap split before the column is renumbered
+const minRankGap = 1e-6
+
+type BoardRepository interface {
+	GetTasks(projectId string) ([]model.Task, error)
+	GetWipLimits(projectId string) (map[string]int, error)
+	SetWipLimits(projectId string, limits map[string]int) error
+	MoveTask(move model.BoardMove) (model.Task, error)
+}
+
+type boardRepository struct {
+	db *sql.DB
+}
+
+// GetTasks implements BoardRepository.
+func (b *boardRepository) GetTasks(projectId string) ([]model.Task, error) {
+	var tasks []model.Task
+
+	rows, err := b.db.Query(config.GetBoardTasks, projectId)
+	if err != nil {
+		log.Println("board_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task model.Task
+		err := rows.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
+		if err != nil {
+			log.Println("boardRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// GetWipLimits implements BoardRepository.
+func (b *boardRepository) GetWipLimits(projectId string) (map[string]int, error) {
+	limits := map[string]int{}
+
+	rows, err := b.db.Query(config.GetWipLimits, projectId)
+	if err != nil {
+		log.Println("board_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var limit int
+		if err := rows.Scan(&status, &limit); err != nil {
+			log.Println("boardRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+		limits[status] = limit
+	}
+
+	return limits, nil
+}
+
+// SetWipLimits implements BoardRepository.
+// A limit of 0 removes the limit of that column.
+func (b *boardRepository) SetWipLimits(projectId string, limits map[string]int) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		log.Println("board_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	for status, limit := range limits {
+		if limit == 0 {
+			_, err = tx.Exec(config.DeleteWipLimit, projectId, status)
+		} else {
+			_, err = tx.Exec(config.UpsertWipLimit, projectId, status, limit)
+		}
+		if err != nil {
+			log.Println("board_repository.Exec", err.Error())
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// MoveTask implements BoardRepository.
+// Moves into the target column are serialized by checkWipLimit, so the WIP limit and the
+// new rank are decided on a column no one else is changing; status and rank are written together.
+// Moving a task into "Accepted" approves it the same way a manager update does.
+func (b *boardRepository) MoveTask(move model.BoardMove) (model.Task, error) {
+	tx, err := b.db.Begin()
+	if err != nil {
+		log.Println("board_repository.Begin", err.Error())
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkWipLimit(tx, move.TaskId, move.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	rows, err := tx.Query(config.GetColumnRanks, move.ProjectId, move.Status, move.TaskId)
+	if err != nil {
+		log.Println("board_repository.Query", err.Error())
+		return model.Task{}, err
+	}
+	var ids []string
+	var ranks []float64
+	for rows.Next() {
+		var id string
+		var rank float64
+		if err := rows.Scan(&id, &rank); err != nil {
+			rows.Close()
+			log.Println("boardRepository.Rows.Next", err.Error())
+			return model.Task{}, err
+		}
+		ids = append(ids, id)
+		ranks = append(ranks, rank)
+	}
+	rows.Close()
+
+	position := min(max(move.Position, 0), len(ids))
+	rank, ok := rankAt(ranks, position)
+	if !ok {
+		// no room left between the neighbours, spread the column out again
+		for i, id := range ids {
+			slot := i
+			if i >= position {
+				slot++
+			}
+			if _, err := tx.Exec(config.SetTaskRank, id, float64((slot+1)*rankGap)); err != nil {
+				log.Println("board_repository.Exec", err.Error())
+				return model.Task{}, err
+			}
+		}
+		rank = float64((position + 1) * rankGap)
+	}
+
+	var task model.Task
+	err = tx.QueryRow(config.MoveTask, move.TaskId, move.Status, rank, move.Version).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
+	if err != nil {
+		log.Println("board_repository.QueryRow", err.Error())
+		if errors.Is(err, sql.ErrNoRows) && move.Version != 0 {
+			return model.Task{}, versionConflict(tx.QueryRow(config.GetTaskVersion, move.TaskId))
+		}
+		return model.Task{}, err
+	}
+
+	if err := recordTaskStatus(tx, task.Id, task.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+// rankAt returns a rank that sorts at position among the ordered ranks,
+// or false when the neighbours are too close to split.
+func rankAt(ranks []float64, position int) (float64, bool) {
+	switch {
+	case len(ranks) == 0:
+		return rankGap, true
+	case position == 0:
+		return ranks[0] - rankGap, true
+	case position == len(ranks):
+		return ranks[len(ranks)-1] + rankGap, true
+	}
+
+	before, after := ranks[position-1], ranks[position]
+	if after-before < minRankGap {
+		return 0, false
+	}
+	return before + (after-before)/2, true
+}
+
+func NewBoardRepository(db *sql.DB) BoardRepository {
+	return &boardRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BoardRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    BoardRepository
+}
+
+func (t *BoardRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewBoardRepository(t.mockDB)
+}
+
+func TestBoardRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BoardRepositoryTestSuite))
+}
+
+var boardTaskColumns = []string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}
+
+func (t *BoardRepositoryTestSuite) TestMoveTask_BetweenNeighbours() {
+	move := model.BoardMove{TaskId: "t3", ProjectId: "p1", Status: "Blocked", Position: 1}
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, "t3", "Blocked", "In Progress", 3, 2)
+	t.mockSql.ExpectQuery(`SELECT id, rank FROM tasks WHERE project_id = \$1 AND status = \$2 AND id <> \$3 AND deleted_at IS NULL ORDER BY rank, created_at FOR UPDATE`).
+		WithArgs("p1", "Blocked", "t3").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rank"}).AddRow("t1", 1024.0).AddRow("t2", 2048.0))
+	t.mockSql.ExpectQuery(`UPDATE tasks SET status = \$2, rank = \$3`).
+		WithArgs("t3", "Blocked", 1536.0, 0).
+		WillReturnRows(sqlmock.NewRows(boardTaskColumns).AddRow("t3", "task3", "Blocked", false, "u1", "2024-03-01", "p1", nil, "-", originalTask.CreatedAt, originalTask.UpdatedAt, 2, "", 3, "", nil, "WEB-3"))
+	t.mockSql.ExpectExec(`INSERT INTO task_status_history`).WithArgs("t3", "Blocked").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
+
+	task, err := t.repo.MoveTask(move)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "Blocked", task.Status)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *BoardRepositoryTestSuite) TestMoveTask_RenumbersWhenNoRoom() {
+	move := model.BoardMove{TaskId: "t3", ProjectId: "p1", Status: "Blocked", Position: 1}
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, "t3", "Blocked", "In Progress", 3, 2)
+	t.mockSql.ExpectQuery(`SELECT id, rank FROM tasks`).
+		WithArgs("p1", "Blocked", "t3").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rank"}).AddRow("t1", 1.0).AddRow("t2", 1.0000000001))
+	t.mockSql.ExpectExec(`UPDATE tasks SET rank = \$2 WHERE id = \$1`).WithArgs("t1", 1024.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE tasks SET rank = \$2 WHERE id = \$1`).WithArgs("t2", 3072.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectQuery(`UPDATE tasks SET status = \$2, rank = \$3`).
+		WithArgs("t3", "Blocked", 2048.0, 0).
+		WillReturnRows(sqlmock.NewRows(boardTaskColumns).AddRow("t3", "task3", "Blocked", false, "u1", "2024-03-01", "p1", nil, "-", originalTask.CreatedAt, originalTask.UpdatedAt, 2, "", 3, "", nil, "WEB-3"))
+	t.mockSql.ExpectExec(`INSERT INTO task_status_history`).WithArgs("t3", "Blocked").WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectCommit()
+
+	_, err := t.repo.MoveTask(move)
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *BoardRepositoryTestSuite) TestMoveTask_WipLimitReached() {
+	move := model.BoardMove{TaskId: "t3", ProjectId: "p1", Status: "In Progress"}
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, "t3", "In Progress", "Blocked", 2, 2)
+	t.mockSql.ExpectRollback()
+
+	_, err := t.repo.MoveTask(move)
+
+	assert.ErrorIs(t.T(), err, shared_model.ErrWipLimit)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *BoardRepositoryTestSuite) TestMoveTask_ReorderIgnoresFullColumn() {
+	move := model.BoardMove{TaskId: "t3", ProjectId: "p1", Status: "In Progress", Position: 0}
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, "t3", "In Progress", "In Progress", 2, 2)
+	t.mockSql.ExpectQuery(`SELECT id, rank FROM tasks`).
+		WithArgs("p1", "In Progress", "t3").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rank"}).AddRow("t1", 1024.0).AddRow("t2", 2048.0))
+	t.mockSql.ExpectQuery(`UPDATE tasks SET status = \$2, rank = \$3`).
+		WithArgs("t3", "In Progress", 0.0, 0).
+		WillReturnRows(sqlmock.NewRows(boardTaskColumns).AddRow("t3", "task3", "In Progress", false, "u1", "2024-03-01", "p1", nil, "-", originalTask.CreatedAt, originalTask.UpdatedAt, 2, "", 3, "", nil, "WEB-3"))
+	t.mockSql.ExpectExec(`INSERT INTO task_status_history`).WithArgs("t3", "In Progress").WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectCommit()
+
+	_, err := t.repo.MoveTask(move)
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *BoardRepositoryTestSuite) TestGetWipLimits_Success() {
+	t.mockSql.ExpectQuery(`SELECT status, wip_limit FROM board_wip_limits WHERE project_id = \$1`).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "wip_limit"}).AddRow("In Progress", 3))
+
+	limits, err := t.repo.GetWipLimits("p1")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), map[string]int{"In Progress": 3}, limits)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+type ChartRepository interface {
+	GetProjectTasks(projectId string) ([]model.ChartTask, error)
+	GetSprintTasks(sprintId string) ([]model.ChartTask, error)
+}
+
+type chartRepository struct {
+	db *sql.DB
+}
+
+// GetProjectTasks implements ChartRepository.
+func (c *chartRepository) GetProjectTasks(projectId string) ([]model.ChartTask, error) {
+	return c.getTasks(config.GetChartTasksByProjectId, projectId)
+}
+
+// GetSprintTasks implements ChartRepository.
+func (c *chartRepository) GetSprintTasks(sprintId string) ([]model.ChartTask, error) {
+	return c.getTasks(config.GetChartTasksBySprintId, sprintId)
+}
+
+// getTasks folds one row per history entry back into one ChartTask per task.
+func (c *chartRepository) getTasks(query string, id string) ([]model.ChartTask, error) {
+	var tasks []model.ChartTask
+
+	rows, err := c.db.Query(query, id)
+	if err != nil {
+		log.Println("chart_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskId      string
+			storyPoints int
+			createdAt   time.Time
+			status      sql.NullString
+			changedAt   sql.NullTime
+		)
+		if err := rows.Scan(&taskId, &storyPoints, &createdAt, &status, &changedAt); err != nil {
+			log.Println("chartRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		if len(tasks) == 0 || tasks[len(tasks)-1].Id != taskId {
+			tasks = append(tasks, model.ChartTask{Id: taskId, StoryPoints: storyPoints, CreatedAt: createdAt})
+		}
+		if status.Valid {
+			task := &tasks[len(tasks)-1]
+			task.History = append(task.History, model.StatusChange{Status: status.String, ChangedAt: changedAt.Time})
+		}
+	}
+
+	return tasks, nil
+}
+
+func NewChartRepository(db *sql.DB) ChartRepository {
+	return &chartRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChartRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    ChartRepository
+}
+
+func (t *ChartRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewChartRepository(t.mockDB)
+}
+
+func TestChartRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ChartRepositoryTestSuite))
+}
+
+func (t *ChartRepositoryTestSuite) TestGetProjectTasks_GroupsHistory() {
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	accepted := time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)
+	t.mockSql.ExpectQuery(`SELECT t.id, t.story_points, t.created_at, h.status, h.changed_at FROM tasks t LEFT JOIN task_status_history h ON h.task_id = t.id WHERE t.project_id = \$1`).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "story_points", "created_at", "status", "changed_at"}).
+			AddRow("t1", 3, created, "In Progress", created).
+			AddRow("t1", 3, created, "Accepted", accepted).
+			AddRow("t2", 5, created, nil, nil))
+
+	tasks, err := t.repo.GetProjectTasks("p1")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.ChartTask{
+		{Id: "t1", StoryPoints: 3, CreatedAt: created, History: []model.StatusChange{{Status: "In Progress", ChangedAt: created}, {Status: "Accepted", ChangedAt: accepted}}},
+		{Id: "t2", StoryPoints: 5, CreatedAt: created},
+	}, tasks)
+}
+
+func (t *ChartRepositoryTestSuite) TestGetSprintTasks_QueryError() {
+	t.mockSql.ExpectQuery(`WHERE t.sprint_id = \$1`).WithArgs("s1").WillReturnError(sql.ErrConnDone)
+
+	_, err := t.repo.GetSprintTasks("s1")
+
+	assert.Error(t.T(), err)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/lib/pq"
+)
+
+type CustomFieldRepository interface {
+	GetByProjectId(projectId string) ([]model.CustomField, error)
+	GetById(id string) (model.CustomField, error)
+	Create(payload model.CustomField) (model.CustomField, error)
+	Update(payload model.CustomField) (model.CustomField, error)
+	Delete(id string) error
+}
+
+type customFieldRepository struct {
+	db *sql.DB
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// GetByProjectId implements CustomFieldRepository.
+func (c *customFieldRepository) GetByProjectId(projectId string) ([]model.CustomField, error) {
+	var fields []model.CustomField
+
+	rows, err := c.db.Query(config.GetCustomFieldsByProjectId, projectId)
+	if err != nil {
+		log.Println("custom_field_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			log.Println("customFieldRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// GetById implements CustomFieldRepository.
+func (c *customFieldRepository) GetById(id string) (model.CustomField, error) {
+	field, err := scanCustomField(c.db.QueryRow(config.GetCustomFieldById, id))
+	if err != nil {
+		log.Println("custom_field_repository.QueryRow", err.Error())
+		return model.CustomField{}, err
+	}
+
+	return field, nil
+}
+
+// Create implements CustomFieldRepository.
+func (c *customFieldRepository) Create(payload model.CustomField) (model.CustomField, error) {
+	field, err := scanCustomField(c.db.QueryRow(config.CreateCustomField, payload.ProjectId, payload.Name, payload.Type, pq.Array(payload.Options), payload.Required))
+	if err != nil {
+		log.Println("custom_field_repository.QueryRow", err.Error())
+		return model.CustomField{}, err
+	}
+
+	return field, nil
+}
+
+// Update implements CustomFieldRepository.
+// Only options and required can change; the name keys the stored values and the type shapes them.
+func (c *customFieldRepository) Update(payload model.CustomField) (model.CustomField, error) {
+	field, err := scanCustomField(c.db.QueryRow(config.UpdateCustomField, payload.Id, pq.Array(payload.Options), payload.Required))
+	if err != nil {
+		log.Println("custom_field_repository.QueryRow", err.Error())
+		return model.CustomField{}, err
+	}
+
+	return field, nil
+}
+
+// Delete implements CustomFieldRepository.
+// The field's values are removed from every task of the project.
+func (c *customFieldRepository) Delete(id string) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		log.Println("custom_field_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	var projectId, name string
+	if err := tx.QueryRow(config.DeleteCustomField, id).Scan(&projectId, &name); err != nil {
+		log.Println("custom_field_repository.QueryRow", err.Error())
+		return err
+	}
+
+	if _, err := tx.Exec(config.RemoveCustomFieldValues, projectId, name); err != nil {
+		log.Println("custom_field_repository.Exec", err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanCustomField(row rowScanner) (model.CustomField, error) {
+	var field model.CustomField
+
+	err := row.Scan(&field.Id, &field.ProjectId, &field.Name, &field.Type, pq.Array(&field.Options), &field.Required, &field.CreatedAt, &field.UpdatedAt)
+	return field, err
+}
+
+func NewCustomFieldRepository(db *sql.DB) CustomFieldRepository {
+	return &customFieldRepository{
+		db: db,
+	}
+}
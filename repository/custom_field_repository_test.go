@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CustomFieldRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    CustomFieldRepository
+}
+
+func (t *CustomFieldRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewCustomFieldRepository(t.mockDB)
+}
+
+func TestCustomFieldRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CustomFieldRepositoryTestSuite))
+}
+
+var customFieldTest = model.CustomField{
+	Id:        "f1",
+	ProjectId: "p1",
+	Name:      "env",
+	Type:      "single_select",
+	Options:   []string{"dev", "prod"},
+	Required:  true,
+	CreatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	UpdatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+}
+
+var customFieldColumns = []string{"id", "project_id", "name", "field_type", "options", "required", "created_at", "updated_at"}
+
+func customFieldRow(f model.CustomField) *sqlmock.Rows {
+	return sqlmock.NewRows(customFieldColumns).AddRow(f.Id, f.ProjectId, f.Name, f.Type, "{dev,prod}", f.Required, f.CreatedAt, f.UpdatedAt)
+}
+
+func (t *CustomFieldRepositoryTestSuite) TestGetByProjectId_Success() {
+	t.mockSql.ExpectQuery(`SELECT id, project_id, name, field_type, options, required, created_at, updated_at FROM custom_field_definitions WHERE project_id = \$1`).
+		WithArgs("p1").
+		WillReturnRows(customFieldRow(customFieldTest))
+
+	actual, err := t.repo.GetByProjectId("p1")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.CustomField{customFieldTest}, actual)
+}
+
+func (t *CustomFieldRepositoryTestSuite) TestGetById_NotFound() {
+	t.mockSql.ExpectQuery(`FROM custom_field_definitions WHERE id = \$1`).WithArgs("f1").WillReturnError(sql.ErrNoRows)
+
+	_, err := t.repo.GetById("f1")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+}
+
+func (t *CustomFieldRepositoryTestSuite) TestCreate_Success() {
+	t.mockSql.ExpectQuery(`INSERT INTO custom_field_definitions\(project_id, name, field_type, options, required, updated_at\)`).
+		WithArgs(customFieldTest.ProjectId, customFieldTest.Name, customFieldTest.Type, pq.Array(customFieldTest.Options), customFieldTest.Required).
+		WillReturnRows(customFieldRow(customFieldTest))
+
+	actual, err := t.repo.Create(customFieldTest)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), customFieldTest, actual)
+}
+
+func (t *CustomFieldRepositoryTestSuite) TestDelete_RemovesValues() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE custom_field_definitions SET deleted_at = CURRENT_TIMESTAMP`).
+		WithArgs("f1").
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "name"}).AddRow("p1", "env"))
+	t.mockSql.ExpectExec(`UPDATE tasks SET custom_fields = custom_fields - \$2::text`).WithArgs("p1", "env").WillReturnResult(sqlmock.NewResult(0, 3))
+	t.mockSql.ExpectCommit()
+
+	err := t.repo.Delete("f1")
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *CustomFieldRepositoryTestSuite) TestDelete_NotFound() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE custom_field_definitions SET deleted_at = CURRENT_TIMESTAMP`).WithArgs("f1").WillReturnError(sql.ErrNoRows)
+	t.mockSql.ExpectRollback()
+
+	err := t.repo.Delete("f1")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
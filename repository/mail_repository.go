@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/lib/pq"
+)
+
+type MailRepository interface {
+	GetMode(userId string) (string, error)
+	SaveMode(userId string, mode string) error
+	Enqueue(payload model.QueuedMail) error
+	GetPending(digest bool) ([]model.QueuedMail, error)
+	MarkSent(ids []int64) error
+}
+
+type mailRepository struct {
+	db *sql.DB
+}
+
+// GetMode implements MailRepository.
+// Returns sql.ErrNoRows when the user never chose a mode.
+func (m *mailRepository) GetMode(userId string) (string, error) {
+	var mode string
+	if err := m.db.QueryRow(config.GetMailMode, userId).Scan(&mode); err != nil {
+		log.Println("mail_repository.QueryRow", err.Error())
+		return "", err
+	}
+
+	return mode, nil
+}
+
+// SaveMode implements MailRepository.
+func (m *mailRepository) SaveMode(userId string, mode string) error {
+	if _, err := m.db.Exec(config.UpsertMailMode, userId, mode); err != nil {
+		log.Println("mail_repository.Exec", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Enqueue implements MailRepository.
+func (m *mailRepository) Enqueue(payload model.QueuedMail) error {
+	if _, err := m.db.Exec(config.EnqueueMail, payload.UserId, payload.Type, payload.Message, payload.Digest); err != nil {
+		log.Println("mail_repository.Exec", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetPending implements MailRepository.
+// Unsent mails come grouped by user, oldest first.
+func (m *mailRepository) GetPending(digest bool) ([]model.QueuedMail, error) {
+	var mails []model.QueuedMail
+
+	rows, err := m.db.Query(config.GetPendingMails, digest)
+	if err != nil {
+		log.Println("mail_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mail model.QueuedMail
+		if err := rows.Scan(&mail.Id, &mail.UserId, &mail.Type, &mail.Message, &mail.Digest, &mail.CreatedAt); err != nil {
+			log.Println("mailRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		mails = append(mails, mail)
+	}
+
+	return mails, nil
+}
+
+// MarkSent implements MailRepository.
+func (m *mailRepository) MarkSent(ids []int64) error {
+	if _, err := m.db.Exec(config.MarkMailsSent, pq.Array(ids)); err != nil {
+		log.Println("mail_repository.Exec", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewMailRepository(db *sql.DB) MailRepository {
+	return &mailRepository{
+		db: db,
+	}
+}
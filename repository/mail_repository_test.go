@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MailRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    MailRepository
+}
+
+func (t *MailRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewMailRepository(t.mockDB)
+}
+
+func TestMailRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MailRepositoryTestSuite))
+}
+
+func (t *MailRepositoryTestSuite) TestGetMode_Unset() {
+	t.mockSql.ExpectQuery(`SELECT mode FROM mail_settings WHERE user_id = \$1`).
+		WithArgs("u1").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := t.repo.GetMode("u1")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+}
+
+func (t *MailRepositoryTestSuite) TestEnqueue_Success() {
+	t.mockSql.ExpectExec(`INSERT INTO mail_queue\(user_id, type, message, digest\)`).
+		WithArgs("u1", model.EventTaskAssigned, "You were assigned to task WEB-1", true).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := t.repo.Enqueue(model.QueuedMail{UserId: "u1", Type: model.EventTaskAssigned, Message: "You were assigned to task WEB-1", Digest: true})
+
+	assert.NoError(t.T(), err)
+}
+
+func (t *MailRepositoryTestSuite) TestGetPending_Success() {
+	createdAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	t.mockSql.ExpectQuery(`SELECT id, user_id, type, message, digest, created_at FROM mail_queue WHERE sent_at IS NULL AND digest = \$1`).
+		WithArgs(false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "message", "digest", "created_at"}).
+			AddRow(1, "u1", model.EventTaskAssigned, "You were assigned to task WEB-1", false, createdAt))
+
+	mails, err := t.repo.GetPending(false)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.QueuedMail{{Id: 1, UserId: "u1", Type: model.EventTaskAssigned, Message: "You were assigned to task WEB-1", CreatedAt: createdAt}}, mails)
+}
+
+func (t *MailRepositoryTestSuite) TestMarkSent_Success() {
+	t.mockSql.ExpectExec(`UPDATE mail_queue SET sent_at = CURRENT_TIMESTAMP WHERE id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]int64{1, 2})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := t.repo.MarkSent([]int64{1, 2})
+
+	assert.NoError(t.T(), err)
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+type MilestoneRepository interface {
+	GetByProjectId(projectId string) ([]model.Milestone, error)
+	GetById(id string) (model.Milestone, error)
+	Create(payload model.Milestone) (model.Milestone, error)
+	Update(payload model.Milestone) (model.Milestone, error)
+	Delete(id string) error
+	AttachTasks(id string, taskIds []string) error
+}
+
+type milestoneRepository struct {
+	db *sql.DB
+}
+
+// GetByProjectId implements MilestoneRepository.
+func (m *milestoneRepository) GetByProjectId(projectId string) ([]model.Milestone, error) {
+	var milestones []model.Milestone
+
+	rows, err := m.db.Query(config.GetMilestonesByProjectId, projectId)
+	if err != nil {
+		log.Println("milestone_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		milestone := model.Milestone{}
+		err := rows.Scan(&milestone.Id, &milestone.ProjectId, &milestone.Name, &milestone.Description, &milestone.DueDate, &milestone.CreatedAt, &milestone.UpdatedAt, &milestone.TotalTasks, &milestone.CompletedTasks)
+		if err != nil {
+			log.Println("milestoneRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		milestones = append(milestones, milestone)
+	}
+
+	return milestones, nil
+}
+
+// GetById implements MilestoneRepository.
+func (m *milestoneRepository) GetById(id string) (model.Milestone, error) {
+	var milestone model.Milestone
+
+	err := m.db.QueryRow(config.GetMilestoneById, id).Scan(&milestone.Id, &milestone.ProjectId, &milestone.Name, &milestone.Description, &milestone.DueDate, &milestone.CreatedAt, &milestone.UpdatedAt, &milestone.TotalTasks, &milestone.CompletedTasks)
+	if err != nil {
+		log.Println("milestone_repository.QueryRow", err.Error())
+		return model.Milestone{}, err
+	}
+
+	return milestone, nil
+}
+
+// Create implements MilestoneRepository.
+func (m *milestoneRepository) Create(payload model.Milestone) (model.Milestone, error) {
+	var milestone model.Milestone
+
+	err := m.db.QueryRow(config.CreateMilestone, payload.ProjectId, payload.Name, payload.Description, payload.DueDate).Scan(&milestone.Id, &milestone.ProjectId, &milestone.Name, &milestone.Description, &milestone.DueDate, &milestone.CreatedAt, &milestone.UpdatedAt)
+	if err != nil {
+		log.Println("milestone_repository.QueryRow", err.Error())
+		return model.Milestone{}, err
+	}
+
+	return milestone, nil
+}
+
+// Update implements MilestoneRepository.
+func (m *milestoneRepository) Update(payload model.Milestone) (model.Milestone, error) {
+	var milestone model.Milestone
+
+	err := m.db.QueryRow(config.UpdateMilestone, payload.Id, payload.Name, payload.Description, payload.DueDate).Scan(&milestone.Id, &milestone.ProjectId, &milestone.Name, &milestone.Description, &milestone.DueDate, &milestone.CreatedAt, &milestone.UpdatedAt)
+	if err != nil {
+		log.Println("milestone_repository.QueryRow", err.Error())
+		return model.Milestone{}, err
+	}
+
+	return milestone, nil
+}
+
+// Delete implements MilestoneRepository.
+// Tasks attached to the milestone are detached, not deleted.
+func (m *milestoneRepository) Delete(id string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		log.Println("milestone_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(config.DetachMilestoneTasks, id); err != nil {
+		log.Println("milestone_repository.Exec", err.Error())
+		return err
+	}
+
+	result, err := tx.Exec(config.DeleteMilestone, id)
+	if err != nil {
+		log.Println("milestone_repository.Exec", err.Error())
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// AttachTasks implements MilestoneRepository.
+// Every task must belong to the milestone's project, otherwise nothing is attached.
+func (m *milestoneRepository) AttachTasks(id string, taskIds []string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		log.Println("milestone_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, taskId := range taskIds {
+		result, err := tx.Exec(config.AttachMilestoneTask, id, taskId)
+		if err != nil {
+			log.Println("milestone_repository.Exec", err.Error())
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return fmt.Errorf("task %s not found in the milestone's project", taskId)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func NewMilestoneRepository(db *sql.DB) MilestoneRepository {
+	return &milestoneRepository{
+		db: db,
+	}
+}
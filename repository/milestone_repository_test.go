@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MilestoneRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    MilestoneRepository
+}
+
+func (t *MilestoneRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewMilestoneRepository(t.mockDB)
+}
+
+func TestMilestoneRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MilestoneRepositoryTestSuite))
+}
+
+var milestoneTest = model.Milestone{
+	Id:          "m1",
+	ProjectId:   "p1",
+	Name:        "Beta",
+	Description: "feature complete",
+	DueDate:     "2024-03-01",
+	CreatedAt:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	UpdatedAt:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+}
+
+var milestoneColumns = []string{"id", "project_id", "name", "description", "due_date", "created_at", "updated_at", "count", "count"}
+
+func (t *MilestoneRepositoryTestSuite) TestGetByProjectId_Success() {
+	t.mockSql.ExpectQuery(`SELECT m.id, m.project_id, m.name, m.description, m.due_date, m.created_at, m.updated_at, COUNT\(t.id\), COUNT\(t.id\) FILTER \(WHERE t.status = 'Accepted'\) FROM milestones m LEFT JOIN tasks t`).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows(milestoneColumns).AddRow(milestoneTest.Id, milestoneTest.ProjectId, milestoneTest.Name, milestoneTest.Description, milestoneTest.DueDate, milestoneTest.CreatedAt, milestoneTest.UpdatedAt, 4, 1))
+
+	actual, err := t.repo.GetByProjectId("p1")
+
+	expected := milestoneTest
+	expected.TotalTasks, expected.CompletedTasks = 4, 1
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.Milestone{expected}, actual)
+}
+
+func (t *MilestoneRepositoryTestSuite) TestGetById_NotFound() {
+	t.mockSql.ExpectQuery(`FROM milestones m`).WithArgs("m1").WillReturnError(sql.ErrNoRows)
+
+	_, err := t.repo.GetById("m1")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+}
+
+func (t *MilestoneRepositoryTestSuite) TestCreate_Success() {
+	t.mockSql.ExpectQuery(`INSERT INTO milestones\(project_id, name, description, due_date, updated_at\)`).
+		WithArgs(milestoneTest.ProjectId, milestoneTest.Name, milestoneTest.Description, milestoneTest.DueDate).
+		WillReturnRows(sqlmock.NewRows(milestoneColumns[:7]).AddRow(milestoneTest.Id, milestoneTest.ProjectId, milestoneTest.Name, milestoneTest.Description, milestoneTest.DueDate, milestoneTest.CreatedAt, milestoneTest.UpdatedAt))
+
+	actual, err := t.repo.Create(milestoneTest)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), milestoneTest, actual)
+}
+
+func (t *MilestoneRepositoryTestSuite) TestDelete_DetachesTasks() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectExec(`UPDATE tasks SET milestone_id = NULL`).WithArgs("m1").WillReturnResult(sqlmock.NewResult(0, 2))
+	t.mockSql.ExpectExec(`UPDATE milestones SET deleted_at = CURRENT_TIMESTAMP`).WithArgs("m1").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
+
+	err := t.repo.Delete("m1")
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *MilestoneRepositoryTestSuite) TestAttachTasks_TaskOutsideProject() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectExec(`UPDATE tasks SET milestone_id = \$1`).WithArgs("m1", "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE tasks SET milestone_id = \$1`).WithArgs("m1", "t2").WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectRollback()
+
+	err := t.repo.AttachTasks("m1", []string{"t1", "t2"})
+
+	assert.Error(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
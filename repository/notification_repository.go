@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"math"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+type NotificationRepository interface {
+	Create(payload model.Notification) error
+	GetByUserId(userId string, unreadOnly bool, page int, size int) ([]model.Notification, shared_model.Paging, error)
+	CountUnread(userId string) (int, error)
+	MarkRead(userId string, id string) error
+	MarkAllRead(userId string) (int64, error)
+	GetPreferences(userId string) (map[string]bool, error)
+	SetPreferences(userId string, preferences map[string]bool) error
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+// Create implements NotificationRepository.
+func (n *notificationRepository) Create(payload model.Notification) error {
+	_, err := n.db.Exec(config.CreateNotification, payload.UserId, payload.Type, payload.ProjectId, payload.TaskId, payload.Message, payload.Data)
+	if err != nil {
+		log.Println("notification_repository.Exec", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetByUserId implements NotificationRepository.
+// Newest first.
+func (n *notificationRepository) GetByUserId(userId string, unreadOnly bool, page int, size int) ([]model.Notification, shared_model.Paging, error) {
+	var notifications []model.Notification
+
+	offset := (page - 1) * size
+	rows, err := n.db.Query(config.GetNotifications, userId, unreadOnly, size, offset)
+	if err != nil {
+		log.Println("notification_repository.Query", err.Error())
+		return nil, shared_model.Paging{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notification := model.Notification{UserId: userId}
+		if err := rows.Scan(&notification.Id, &notification.Type, &notification.ProjectId, &notification.TaskId, &notification.Message, &notification.Data, &notification.ReadAt, &notification.CreatedAt); err != nil {
+			log.Println("notificationRepository.Rows.Next", err.Error())
+			return nil, shared_model.Paging{}, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	totalRows := 0
+	if err := n.db.QueryRow(config.CountNotifications, userId, unreadOnly).Scan(&totalRows); err != nil {
+		log.Println("notification_repository.QueryRow", err.Error())
+		return nil, shared_model.Paging{}, err
+	}
+
+	paging := shared_model.Paging{
+		Page:        page,
+		RowsPerPage: size,
+		TotalRows:   totalRows,
+		TotalPages:  int(math.Ceil(float64(totalRows) / float64(size))),
+	}
+
+	return notifications, paging, nil
+}
+
+// CountUnread implements NotificationRepository.
+func (n *notificationRepository) CountUnread(userId string) (int, error) {
+	var count int
+	if err := n.db.QueryRow(config.CountNotifications, userId, true).Scan(&count); err != nil {
+		log.Println("notification_repository.QueryRow", err.Error())
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkRead implements NotificationRepository.
+// Returns sql.ErrNoRows when the notification does not belong to the user.
+func (n *notificationRepository) MarkRead(userId string, id string) error {
+	if err := n.db.QueryRow(config.MarkNotificationRead, id, userId).Scan(&id); err != nil {
+		log.Println("notification_repository.QueryRow", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// MarkAllRead implements NotificationRepository.
+func (n *notificationRepository) MarkAllRead(userId string) (int64, error) {
+	result, err := n.db.Exec(config.MarkAllNotificationsRead, userId)
+	if err != nil {
+		log.Println("notification_repository.Exec", err.Error())
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetPreferences implements NotificationRepository.
+// Only event types the user has set are returned.
+func (n *notificationRepository) GetPreferences(userId string) (map[string]bool, error) {
+	preferences := map[string]bool{}
+
+	rows, err := n.db.Query(config.GetNotificationPreferences, userId)
+	if err != nil {
+		log.Println("notification_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventType string
+		var enabled bool
+		if err := rows.Scan(&eventType, &enabled); err != nil {
+			log.Println("notificationRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+		preferences[eventType] = enabled
+	}
+
+	return preferences, nil
+}
+
+// SetPreferences implements NotificationRepository.
+func (n *notificationRepository) SetPreferences(userId string, preferences map[string]bool) error {
+	tx, err := n.db.Begin()
+	if err != nil {
+		log.Println("notification_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	for eventType, enabled := range preferences {
+		if _, err := tx.Exec(config.UpsertNotificationPreference, userId, eventType, enabled); err != nil {
+			log.Println("notification_repository.Exec", err.Error())
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
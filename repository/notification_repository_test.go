@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    NotificationRepository
+}
+
+func (t *NotificationRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewNotificationRepository(t.mockDB)
+}
+
+func TestNotificationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationRepositoryTestSuite))
+}
+
+func (t *NotificationRepositoryTestSuite) TestCreate_Success() {
+	payload := model.Notification{UserId: "u1", Type: model.EventTaskAssigned, ProjectId: "p1", TaskId: "t1", Message: "You were assigned WEB-1", Data: model.EventData{"key": "WEB-1"}}
+	t.mockSql.ExpectExec(`INSERT INTO notifications`).
+		WithArgs("u1", model.EventTaskAssigned, "p1", "t1", "You were assigned WEB-1", `{"key":"WEB-1"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := t.repo.Create(payload)
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *NotificationRepositoryTestSuite) TestGetByUserId_UnreadOnly() {
+	createdAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	t.mockSql.ExpectQuery(`SELECT id, type, COALESCE\(project_id::text, ''\), COALESCE\(task_id::text, ''\), message, data, read_at, created_at FROM notifications`).
+		WithArgs("u1", true, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "project_id", "task_id", "message", "data", "read_at", "created_at"}).
+			AddRow("n1", model.EventMemberAdded, "p1", "", "You were added to a project", []byte(`{"project_id":"p1"}`), nil, createdAt))
+	t.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM notifications`).
+		WithArgs("u1", true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	notifications, paging, err := t.repo.GetByUserId("u1", true, 1, 10)
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), notifications, 1)
+	assert.Equal(t.T(), "p1", notifications[0].Data["project_id"])
+	assert.Nil(t.T(), notifications[0].ReadAt)
+	assert.Equal(t.T(), 1, paging.TotalPages)
+}
+
+func (t *NotificationRepositoryTestSuite) TestMarkRead_NotOwned() {
+	t.mockSql.ExpectQuery(`UPDATE notifications SET read_at`).
+		WithArgs("n1", "u2").
+		WillReturnError(sql.ErrNoRows)
+
+	err := t.repo.MarkRead("u2", "n1")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+}
+
+func (t *NotificationRepositoryTestSuite) TestMarkAllRead_Success() {
+	t.mockSql.ExpectExec(`UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = \$1 AND read_at IS NULL`).
+		WithArgs("u1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	count, err := t.repo.MarkAllRead("u1")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), int64(3), count)
+}
+
+func (t *NotificationRepositoryTestSuite) TestSetPreferences_Success() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectExec(`INSERT INTO notification_preferences`).
+		WithArgs("u1", model.EventTaskOverdue, false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
+
+	err := t.repo.SetPreferences("u1", map[string]bool{model.EventTaskOverdue: false})
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
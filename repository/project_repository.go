@@ -2,12 +2,15 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"math"
 
 	"enigma.com/projectmanagementhub/config"
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/report"
 	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/lib/pq"
 )
 
 type ProjectRepository interface {
@@ -21,11 +24,13 @@ type ProjectRepository interface {
 	DeleteProjectMember(id string, members []string) error
 	GetAllProjectMember(id string) ([]model.User, error)
 	Update(payload model.Project) (model.Project, error)
-	Delete(id string) error
+	Delete(id string, actorId string) error
+	GetDeleteImpact(id string) (model.DeleteImpact, error)
 }
 
 type projectRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	report report.ReportSink
 }
 
 // DeleteProjectMember implements ProjectRepository.
@@ -45,26 +50,69 @@ func (p *projectRepository) DeleteProjectMember(id string, members []string) err
 }
 
 // CreateProject implements ProjectRepository.
+// A key that is already taken is reported as shared_model.ErrKeyInUse.
 func (p *projectRepository) CreateProject(payload model.Project) (model.Project, error) {
 	var project model.Project
 
-	err := p.db.QueryRow(config.CreateProject, payload.Name, payload.ManagerId, payload.Deadline).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+	err := p.db.QueryRow(config.CreateProject, payload.Name, payload.ManagerId, payload.Deadline, payload.Key).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 	if err != nil {
 		log.Println("project_repository.QueryRow", err.Error())
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "projects_key_key" {
+			return model.Project{}, shared_model.ErrKeyInUse
+		}
 		return model.Project{}, err
 	}
 	return project, nil
 }
 
 // Delete implements ProjectRepository.
-func (p *projectRepository) Delete(id string) error {
-	_, err := p.db.Query(config.DeleteProject, id)
+// Reports, tasks, memberships, milestones and sprints of the project are soft-deleted in the same transaction,
+// so they all share the project's deleted_at and can be restored together. Every deleted report is journaled.
+func (p *projectRepository) Delete(id string, actorId string) error {
+	tx, err := p.db.Begin()
 	if err != nil {
-		log.Println("project_repository.Query", err.Error())
+		log.Println("project_repository.Begin", err.Error())
 		return err
 	}
+	defer tx.Rollback()
 
-	return nil
+	reports, err := queryReports(tx, config.DeleteProjectReports, id)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{config.DeleteProjectTasks, config.DeleteProjectMembers, config.DeleteProjectMilestones, config.DeleteProjectSprints, config.DeleteProjectFields} {
+		if _, err := tx.Exec(query, id); err != nil {
+			log.Println("project_repository.Exec", err.Error())
+			return err
+		}
+	}
+
+	result, err := tx.Exec(config.DeleteProject, id)
+	if err != nil {
+		log.Println("project_repository.Exec", err.Error())
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return commitJournaledReports(tx, p.report, actorId, reports, "delete")
+}
+
+// GetDeleteImpact implements ProjectRepository.
+func (p *projectRepository) GetDeleteImpact(id string) (model.DeleteImpact, error) {
+	var impact model.DeleteImpact
+
+	err := p.db.QueryRow(config.GetProjectDeleteImpact, id).Scan(&impact.Tasks, &impact.Reports, &impact.Members)
+	if err != nil {
+		log.Println("project_repository.QueryRow", err.Error())
+		return model.DeleteImpact{}, err
+	}
+	impact.Projects = 1
+
+	return impact, nil
 }
 
 // AddProjectMember implements ProjectRepository.
@@ -95,7 +143,7 @@ func (p *projectRepository) GetAll(page int, size int) ([]model.Project, shared_
 	for row.Next() {
 		project := model.Project{}
 		//updated_at cannot be nil
-		err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+		err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 		if err != nil {
 			log.Println("projectRepository.Rows.Next", err.Error())
 			return nil, shared_model.Paging{}, err
@@ -140,7 +188,7 @@ func (p *projectRepository) GetAllProjectMember(id string) ([]model.User, error)
 			return []model.User{}, err1
 		}
 
-		err := p.db.QueryRow(config.GetUserByID, id).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+		err := p.db.QueryRow(config.GetUserByID, id).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 		if err != nil {
 			log.Println("user not found", err.Error())
 			return []model.User{}, err
@@ -164,7 +212,7 @@ func (p *projectRepository) GetByDeadline(date string) ([]model.Project, error)
 	for row.Next() {
 		project := model.Project{}
 		//updated_at cannot be nil
-		err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+		err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 		if err != nil {
 			log.Println("projectRepository.Rows.Next", err.Error())
 			return nil, err
@@ -180,7 +228,7 @@ func (p *projectRepository) GetByDeadline(date string) ([]model.Project, error)
 func (p *projectRepository) GetById(id string) (model.Project, error) {
 	var project model.Project
 
-	err := p.db.QueryRow(config.GetProjectByID, id).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+	err := p.db.QueryRow(config.GetProjectByID, id).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 	if err != nil {
 		log.Println("project_repository.QueryRow", err.Error())
 		return model.Project{}, err
@@ -199,7 +247,7 @@ func (p *projectRepository) GetById(id string) (model.Project, error) {
 
 	for row.Next() {
 		task := model.Task{}
-		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 		if err != nil {
 			log.Println("taskRepository.Rows.Next", err.Error())
 		}
@@ -224,7 +272,7 @@ func (p *projectRepository) GetByManagerId(id string) ([]model.Project, error) {
 	for row.Next() {
 		project := model.Project{}
 		//updated_at cannot be nil
-		err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+		err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 		if err != nil {
 			log.Println("projectRepository.Rows.Next", err.Error())
 			return nil, err
@@ -254,7 +302,7 @@ func (p *projectRepository) GetByMemberId(id string) ([]model.Project, error) {
 			log.Println("projectRepository.Rows.Next", err.Error())
 			return nil, err
 		}
-		err = p.db.QueryRow(config.GetProjectByID, id).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+		err = p.db.QueryRow(config.GetProjectByID, id).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 		if err != nil {
 			log.Println("projectRepository.Rows.Next", err.Error())
 			return nil, err
@@ -270,16 +318,20 @@ func (p *projectRepository) GetByMemberId(id string) ([]model.Project, error) {
 func (p *projectRepository) Update(payload model.Project) (model.Project, error) {
 	var project model.Project
 
-	err := p.db.QueryRow(config.UpdateProject, payload.Id, payload.Name, payload.ManagerId, payload.Deadline).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+	err := p.db.QueryRow(config.UpdateProject, payload.Id, payload.Name, payload.ManagerId, payload.Deadline, payload.Version).Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 	if err != nil {
 		log.Println("user_repository.QueryRow", err.Error())
+		if errors.Is(err, sql.ErrNoRows) && payload.Version != 0 {
+			return model.Project{}, versionConflict(p.db.QueryRow(config.GetProjectVersion, payload.Id))
+		}
 		return model.Project{}, err
 	}
 	return project, nil
 }
 
-func NewProjectRepository(db *sql.DB) ProjectRepository {
+func NewProjectRepository(db *sql.DB, report report.ReportSink) ProjectRepository {
 	return &projectRepository{
-		db: db,
+		db:     db,
+		report: report,
 	}
 }
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -16,13 +18,15 @@ type ProjectRepositoryTestSuite struct {
 	suite.Suite
 	mockDB  *sql.DB
 	mockSql sqlmock.Sqlmock
+	sink    *recordingSink
 	repo    ProjectRepository
 }
 
 func (t *ProjectRepositoryTestSuite) SetupTest() {
 	db, mock, _ := sqlmock.New()
 	t.mockDB, t.mockSql = db, mock
-	t.repo = NewProjectRepository(t.mockDB)
+	t.sink = &recordingSink{}
+	t.repo = NewProjectRepository(t.mockDB, t.sink)
 }
 
 var projectTest = model.Project{
@@ -30,6 +34,7 @@ var projectTest = model.Project{
 	Name:      "project1",
 	ManagerId: "1",
 	Deadline:  "2024-01-01",
+	Key:       "WEB",
 	CreatedAt: time.Now(),
 	UpdatedAt: time.Now(),
 	DeletedAt: nil,
@@ -40,6 +45,7 @@ var updatedProjectTest = model.Project{
 	Name:      "project1",
 	ManagerId: "1",
 	Deadline:  "2024-01-01",
+	Key:       "WEB",
 	CreatedAt: projectTest.CreatedAt,
 	UpdatedAt: time.Now(),
 	DeletedAt: nil,
@@ -47,10 +53,10 @@ var updatedProjectTest = model.Project{
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_CreateProject_Success() {
 	// Mock the SQL query expectations for CreateProject with a success outcome.
-	t.mockSql.ExpectQuery(`INSERT INTO projects\(name, manager_id, deadline, updated_at\) VALUES \(\$1, \$2, \$3, CURRENT_TIMESTAMP\) RETURNING id, name, manager_id, deadline, created_at, updated_at`).
-		WithArgs(projectTest.Name, projectTest.ManagerId, projectTest.Deadline).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at"}).
-			AddRow(projectTest.Id, projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.CreatedAt, projectTest.UpdatedAt))
+	t.mockSql.ExpectQuery(`INSERT INTO projects\(name, manager_id, deadline, key, updated_at\) VALUES \(\$1, \$2, \$3, \$4, CURRENT_TIMESTAMP\) RETURNING id, name, manager_id, deadline, created_at, updated_at, version, key`).
+		WithArgs(projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.Key).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at", "version", "key"}).
+			AddRow(projectTest.Id, projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.CreatedAt, projectTest.UpdatedAt, projectTest.Version, projectTest.Key))
 
 	// Call the CreateProject method.
 	resultProject, err := t.repo.CreateProject(projectTest)
@@ -62,8 +68,8 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_CreateProject_Success
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_CreateProject_ErrorOnQuery() {
 	// Mock the SQL query expectations for CreateProject with an error.
-	t.mockSql.ExpectQuery(`INSERT INTO projects\(name, manager_id, deadline, updated_at\) VALUES \(\$1, \$2, \$3, CURRENT_TIMESTAMP\) RETURNING id, name, manager_id, deadline, created_at, updated_at`).
-		WithArgs(projectTest.Name, projectTest.ManagerId, projectTest.Deadline).
+	t.mockSql.ExpectQuery(`INSERT INTO projects\(name, manager_id, deadline, key, updated_at\) VALUES \(\$1, \$2, \$3, \$4, CURRENT_TIMESTAMP\) RETURNING id, name, manager_id, deadline, created_at, updated_at, version, key`).
+		WithArgs(projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.Key).
 		WillReturnError(sql.ErrConnDone)
 
 	// Call the CreateProject method.
@@ -75,13 +81,26 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_CreateProject_ErrorOn
 	assert.Equal(t.T(), model.Project{}, resultProject)
 }
 
+func (t *ProjectRepositoryTestSuite) TestProjectRepository_CreateProject_KeyInUse() {
+	// Mock the SQL query expectations for CreateProject with a duplicate key.
+	t.mockSql.ExpectQuery(`INSERT INTO projects`).
+		WithArgs(projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.Key).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "projects_key_key"})
+
+	// Call the CreateProject method.
+	_, err := t.repo.CreateProject(projectTest)
+
+	// Assertions
+	assert.True(t.T(), errors.Is(err, shared_model.ErrKeyInUse))
+}
+
 // UpdateProject method testing methods
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_UpdateProject_Success() {
 	// Mock the SQL query expectations for UpdateProject with a success outcome.
-	t.mockSql.ExpectQuery(`UPDATE projects SET name = \$2, manager_id = \$3, deadline = \$4, updated_at = CURRENT_TIMESTAMP WHERE id = \$1 AND deleted_at IS NULL RETURNING id, name, manager_id, deadline, created_at, updated_at`).
-		WithArgs(updatedProjectTest.Id, updatedProjectTest.Name, updatedProjectTest.ManagerId, updatedProjectTest.Deadline).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at"}).
-			AddRow(updatedProjectTest.Id, updatedProjectTest.Name, updatedProjectTest.ManagerId, updatedProjectTest.Deadline, updatedProjectTest.CreatedAt, updatedProjectTest.UpdatedAt))
+	t.mockSql.ExpectQuery(`UPDATE projects SET name = \$2, manager_id = \$3, deadline = \$4, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$1 AND \(\$5 = 0 OR version = \$5\) AND deleted_at IS NULL RETURNING id, name, manager_id, deadline, created_at, updated_at, version, key`).
+		WithArgs(updatedProjectTest.Id, updatedProjectTest.Name, updatedProjectTest.ManagerId, updatedProjectTest.Deadline, updatedProjectTest.Version).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at", "version", "key"}).
+			AddRow(updatedProjectTest.Id, updatedProjectTest.Name, updatedProjectTest.ManagerId, updatedProjectTest.Deadline, updatedProjectTest.CreatedAt, updatedProjectTest.UpdatedAt, updatedProjectTest.Version, updatedProjectTest.Key))
 
 	// Call the UpdateProject method.
 	resultProject, err := t.repo.Update(updatedProjectTest)
@@ -93,8 +112,8 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_UpdateProject_Success
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_UpdateProject_ErrorOnQuery() {
 	// Mock the SQL query expectations for UpdateProject with an error.
-	t.mockSql.ExpectQuery(`UPDATE projects SET name = \$2, manager_id = \$3, deadline = \$4, updated_at = CURRENT_TIMESTAMP WHERE id = \$1 AND deleted_at IS NULL RETURNING id, name, manager_id, deadline, created_at, updated_at`).
-		WithArgs(updatedProjectTest.Id, updatedProjectTest.Name, updatedProjectTest.ManagerId, updatedProjectTest.Deadline).
+	t.mockSql.ExpectQuery(`UPDATE projects SET name = \$2, manager_id = \$3, deadline = \$4, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$1 AND \(\$5 = 0 OR version = \$5\) AND deleted_at IS NULL RETURNING id, name, manager_id, deadline, created_at, updated_at, version, key`).
+		WithArgs(updatedProjectTest.Id, updatedProjectTest.Name, updatedProjectTest.ManagerId, updatedProjectTest.Deadline, updatedProjectTest.Version).
 		WillReturnError(sql.ErrConnDone)
 
 	// Call the UpdateProject method.
@@ -109,25 +128,85 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_UpdateProject_ErrorOn
 // DeleteProject method testing methods
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_DeleteProject_Success() {
 	// Mock the SQL query expectations for DeleteProject with a success outcome.
-	t.mockSql.ExpectQuery(`UPDATE projects SET deleted_at = CURRENT_TIMESTAMP WHERE id = \$1 AND deleted_at IS NULL`).
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE reports SET deleted_at = CURRENT_TIMESTAMP WHERE deleted_at IS NULL AND task_id IN`).
 		WithArgs(projectTest.Id).
-		WillReturnRows(sqlmock.NewRows([]string{}))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow("1", "2", "report one", "3", nil, "pending", "", "", nil, time.Now(), time.Now(), 1).
+			AddRow("4", "5", "report two", "3", nil, "pending", "", "", nil, time.Now(), time.Now(), 1))
+	t.mockSql.ExpectExec(`UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(projectTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE project_members SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(projectTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE milestones SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(projectTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE sprints SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(projectTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE custom_field_definitions SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(projectTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE projects SET deleted_at = CURRENT_TIMESTAMP WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(projectTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
 
 	// Call the DeleteProject method.
-	err := t.repo.Delete(projectTest.Id)
+	err := t.repo.Delete(projectTest.Id, "2")
 
 	// Assertions
 	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+	assert.Equal(t.T(), []string{"delete", "delete"}, t.sink.statuses)
+	assert.Equal(t.T(), "1", t.sink.changes[0].Content.Id)
+	assert.Equal(t.T(), "4", t.sink.changes[1].Content.Id)
+	assert.Equal(t.T(), "2", t.sink.changes[0].ActorId)
+}
+
+func (t *ProjectRepositoryTestSuite) TestProjectRepository_DeleteProject_NotFound() {
+	// Nothing cascaded, and the project itself is missing, so the transaction is rolled back.
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE reports`).WithArgs(projectTest.Id).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}))
+	t.mockSql.ExpectExec(`UPDATE tasks`).WithArgs(projectTest.Id).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectExec(`UPDATE project_members`).WithArgs(projectTest.Id).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectExec(`UPDATE milestones`).WithArgs(projectTest.Id).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectExec(`UPDATE sprints`).WithArgs(projectTest.Id).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectExec(`UPDATE custom_field_definitions`).WithArgs(projectTest.Id).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectExec(`UPDATE projects`).WithArgs(projectTest.Id).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectRollback()
+
+	// Call the DeleteProject method.
+	err := t.repo.Delete(projectTest.Id, "2")
+
+	// Assertions
+	assert.True(t.T(), errors.Is(err, sql.ErrNoRows))
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetDeleteImpact() {
+	t.mockSql.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM tasks WHERE project_id = \$1`).
+		WithArgs(projectTest.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"tasks", "reports", "members"}).AddRow(3, 5, 2))
+
+	impact, err := t.repo.GetDeleteImpact(projectTest.Id)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), model.DeleteImpact{Projects: 1, Tasks: 3, Reports: 5, Members: 2}, impact)
 }
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_DeleteProject_ErrorOnQuery() {
 	// Mock the SQL query expectations for DeleteProject with an error.
-	t.mockSql.ExpectQuery(`UPDATE projects SET deleted_at = CURRENT_TIMESTAMP WHERE id = \$1 AND deleted_at IS NULL`).
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE reports SET deleted_at = CURRENT_TIMESTAMP`).
 		WithArgs(projectTest.Id).
 		WillReturnError(sql.ErrConnDone)
+	t.mockSql.ExpectRollback()
 
 	// Call the DeleteProject method.
-	err := t.repo.Delete(projectTest.Id)
+	err := t.repo.Delete(projectTest.Id, "2")
 
 	// Assertions
 	assert.Error(t.T(), err)
@@ -136,7 +215,7 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_DeleteProject_ErrorOn
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetAll_ErrorOnQuery() {
 	// Mock the SQL query expectations for GetAll with an error.
-	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
+	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
 		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnError(sql.ErrConnDone)
 
@@ -151,7 +230,7 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetAll_ErrorOnQuery()
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetById_ErrorOnQuery() {
 	// Mock the SQL query expectations for GetById with an error.
-	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND id = \$1`).
+	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND id = \$1`).
 		WithArgs(projectTest.Id).
 		WillReturnError(sql.ErrConnDone)
 
@@ -167,10 +246,10 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetById_ErrorOnQuery(
 // GetByManagerId method testing methods
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetByManagerId_Success() {
 	// Mock the SQL query expectations for GetByManagerId with a success outcome.
-	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND manager_id = \$1`).
+	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND manager_id = \$1`).
 		WithArgs(projectTest.ManagerId).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at"}).
-			AddRow(projectTest.Id, projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.CreatedAt, projectTest.UpdatedAt))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at", "version", "key"}).
+			AddRow(projectTest.Id, projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.CreatedAt, projectTest.UpdatedAt, projectTest.Version, projectTest.Key))
 
 	// Call the GetByManagerId method.
 	projects, err := t.repo.GetByManagerId(projectTest.ManagerId)
@@ -183,7 +262,7 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetByManagerId_Succes
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetByManagerId_ErrorOnQuery() {
 	// Mock the SQL query expectations for GetByManagerId with an error.
-	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND manager_id = \$1`).
+	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND manager_id = \$1`).
 		WithArgs(projectTest.ManagerId).
 		WillReturnError(sql.ErrConnDone)
 
@@ -199,10 +278,10 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetByManagerId_ErrorO
 // GetByDeadline method testing methods
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetByDeadline_Success() {
 	// Mock the SQL query expectations for GetByDeadline with a success outcome.
-	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND deadline = \$1`).
+	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND deadline = \$1`).
 		WithArgs(projectTest.Deadline).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at"}).
-			AddRow(projectTest.Id, projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.CreatedAt, projectTest.UpdatedAt))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "manager_id", "deadline", "created_at", "updated_at", "version", "key"}).
+			AddRow(projectTest.Id, projectTest.Name, projectTest.ManagerId, projectTest.Deadline, projectTest.CreatedAt, projectTest.UpdatedAt, projectTest.Version, projectTest.Key))
 
 	// Call the GetByDeadline method.
 	projects, err := t.repo.GetByDeadline(projectTest.Deadline)
@@ -215,7 +294,7 @@ func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetByDeadline_Success
 
 func (t *ProjectRepositoryTestSuite) TestProjectRepository_GetByDeadline_ErrorOnQuery() {
 	// Mock the SQL query expectations for GetByDeadline with an error.
-	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at FROM projects WHERE deleted_at IS NULL AND deadline = \$1`).
+	t.mockSql.ExpectQuery(`SELECT id, name, manager_id, deadline, created_at, updated_at, version, key FROM projects WHERE deleted_at IS NULL AND deadline = \$1`).
 		WithArgs(projectTest.Deadline).
 		WillReturnError(sql.ErrConnDone)
 
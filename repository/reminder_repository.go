@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+type ReminderRepository interface {
+	GetSetting(projectId string) (model.ReminderSetting, error)
+	SaveSetting(setting model.ReminderSetting) (model.ReminderSetting, error)
+	GetDueTasks(today time.Time, daysBefore int, escalateAfterDays int) ([]model.DueItem, error)
+	GetDueProjects(today time.Time, daysBefore int) ([]model.DueItem, error)
+	MarkSent(reminder model.Reminder) (bool, error)
+}
+
+type reminderRepository struct {
+	db *sql.DB
+}
+
+// GetSetting implements ReminderRepository.
+// Returns sql.ErrNoRows when the project uses the default setting.
+func (r *reminderRepository) GetSetting(projectId string) (model.ReminderSetting, error) {
+	var setting model.ReminderSetting
+
+	err := r.db.QueryRow(config.GetReminderSetting, projectId).Scan(&setting.ProjectId, &setting.Enabled, &setting.DaysBefore, &setting.EscalateAfterDays)
+	if err != nil {
+		log.Println("reminder_repository.QueryRow", err.Error())
+		return model.ReminderSetting{}, err
+	}
+
+	return setting, nil
+}
+
+// SaveSetting implements ReminderRepository.
+func (r *reminderRepository) SaveSetting(setting model.ReminderSetting) (model.ReminderSetting, error) {
+	var saved model.ReminderSetting
+
+	err := r.db.QueryRow(config.UpsertReminderSetting, setting.ProjectId, setting.Enabled, setting.DaysBefore, setting.EscalateAfterDays).Scan(&saved.ProjectId, &saved.Enabled, &saved.DaysBefore, &saved.EscalateAfterDays)
+	if err != nil {
+		log.Println("reminder_repository.QueryRow", err.Error())
+		return model.ReminderSetting{}, err
+	}
+
+	return saved, nil
+}
+
+// GetDueTasks implements ReminderRepository.
+// Returns the unaccepted tasks of enabled projects whose deadline is within their project's reminder window or past.
+// Projects without a setting use daysBefore and escalateAfterDays.
+func (r *reminderRepository) GetDueTasks(today time.Time, daysBefore int, escalateAfterDays int) ([]model.DueItem, error) {
+	var items []model.DueItem
+
+	rows, err := r.db.Query(config.GetDueTasks, today, daysBefore, escalateAfterDays)
+	if err != nil {
+		log.Println("reminder_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.DueItem
+		if err := rows.Scan(&item.TaskId, &item.ProjectId, &item.Key, &item.Name, &item.Deadline, &item.PersonInCharge, &item.ManagerId, &item.DaysBefore, &item.EscalateAfterDays); err != nil {
+			log.Println("reminderRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetDueProjects implements ReminderRepository.
+// A project counts as open while it still has unaccepted tasks.
+func (r *reminderRepository) GetDueProjects(today time.Time, daysBefore int) ([]model.DueItem, error) {
+	var items []model.DueItem
+
+	rows, err := r.db.Query(config.GetDueProjects, today, daysBefore)
+	if err != nil {
+		log.Println("reminder_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.DueItem
+		if err := rows.Scan(&item.ProjectId, &item.Key, &item.Name, &item.Deadline, &item.ManagerId, &item.DaysBefore); err != nil {
+			log.Println("reminderRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// MarkSent implements ReminderRepository.
+// Reports false when the reminder was already sent for the same deadline.
+func (r *reminderRepository) MarkSent(reminder model.Reminder) (bool, error) {
+	query, subjectId := config.InsertTaskReminder, reminder.TaskId
+	if reminder.TaskId == "" {
+		query, subjectId = config.InsertProjectReminder, reminder.ProjectId
+	}
+
+	result, err := r.db.Exec(query, subjectId, reminder.Kind, reminder.Deadline)
+	if err != nil {
+		log.Println("reminder_repository.Exec", err.Error())
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("reminder_repository.RowsAffected", err.Error())
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func NewReminderRepository(db *sql.DB) ReminderRepository {
+	return &reminderRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReminderRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    ReminderRepository
+}
+
+func (t *ReminderRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewReminderRepository(t.mockDB)
+}
+
+func TestReminderRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderRepositoryTestSuite))
+}
+
+var reminderToday = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func (t *ReminderRepositoryTestSuite) TestGetSetting_Success() {
+	t.mockSql.ExpectQuery(`SELECT project_id, enabled, days_before, escalate_after_days FROM reminder_settings WHERE project_id = \$1`).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "enabled", "days_before", "escalate_after_days"}).AddRow("p1", true, 3, 2))
+
+	setting, err := t.repo.GetSetting("p1")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), model.ReminderSetting{ProjectId: "p1", Enabled: true, DaysBefore: 3, EscalateAfterDays: 2}, setting)
+}
+
+func (t *ReminderRepositoryTestSuite) TestSaveSetting_Success() {
+	payload := model.ReminderSetting{ProjectId: "p1", Enabled: false, DaysBefore: 2, EscalateAfterDays: 1}
+	t.mockSql.ExpectQuery(`INSERT INTO reminder_settings`).
+		WithArgs("p1", false, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "enabled", "days_before", "escalate_after_days"}).AddRow("p1", false, 2, 1))
+
+	setting, err := t.repo.SaveSetting(payload)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), payload, setting)
+}
+
+func (t *ReminderRepositoryTestSuite) TestGetDueTasks_Success() {
+	deadline := reminderToday.AddDate(0, 0, 1)
+	t.mockSql.ExpectQuery(`SELECT t.id, t.project_id, t.task_key, t.name, t.deadline, t.person_in_charge, p.manager_id`).
+		WithArgs(reminderToday, 1, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "task_key", "name", "deadline", "person_in_charge", "manager_id", "days_before", "escalate_after_days"}).
+			AddRow("t1", "p1", "WEB-1", "task1", deadline, "u1", "m1", 1, 0))
+
+	items, err := t.repo.GetDueTasks(reminderToday, 1, 0)
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), items, 1)
+	assert.Equal(t.T(), "WEB-1", items[0].Key)
+	assert.Equal(t.T(), "m1", items[0].ManagerId)
+	assert.Equal(t.T(), deadline, items[0].Deadline)
+}
+
+func (t *ReminderRepositoryTestSuite) TestGetDueTasks_Fail() {
+	t.mockSql.ExpectQuery(`SELECT t.id, t.project_id`).WillReturnError(fmt.Errorf("error"))
+
+	_, err := t.repo.GetDueTasks(reminderToday, 1, 0)
+
+	assert.Error(t.T(), err)
+}
+
+func (t *ReminderRepositoryTestSuite) TestGetDueProjects_Success() {
+	t.mockSql.ExpectQuery(`SELECT p.id, p.key, p.name, p.deadline, p.manager_id`).
+		WithArgs(reminderToday, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key", "name", "deadline", "manager_id", "days_before"}).
+			AddRow("p1", "WEB", "website", reminderToday, "m1", 1))
+
+	items, err := t.repo.GetDueProjects(reminderToday, 1)
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), items, 1)
+	assert.Empty(t.T(), items[0].TaskId)
+}
+
+func (t *ReminderRepositoryTestSuite) TestMarkSent_FirstTime() {
+	reminder := model.Reminder{TaskId: "t1", ProjectId: "p1", Kind: model.ReminderDueSoon, Deadline: reminderToday}
+	t.mockSql.ExpectExec(`INSERT INTO task_reminders`).
+		WithArgs("t1", model.ReminderDueSoon, reminderToday).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	sent, err := t.repo.MarkSent(reminder)
+
+	assert.NoError(t.T(), err)
+	assert.True(t.T(), sent)
+}
+
+func (t *ReminderRepositoryTestSuite) TestMarkSent_AlreadySent() {
+	reminder := model.Reminder{ProjectId: "p1", Kind: model.ReminderOverdue, Deadline: reminderToday}
+	t.mockSql.ExpectExec(`INSERT INTO project_reminders`).
+		WithArgs("p1", model.ReminderOverdue, reminderToday).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	sent, err := t.repo.MarkSent(reminder)
+
+	assert.NoError(t.T(), err)
+	assert.False(t.T(), sent)
+}
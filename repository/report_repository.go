@@ -2,41 +2,54 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"time"
 
 	"enigma.com/projectmanagementhub/config"
 	"enigma.com/projectmanagementhub/model"
 	"enigma.com/projectmanagementhub/report"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/lib/pq"
 )
 
 type ReportRepository interface {
 	CreateReport(payload model.Report) (model.Report, error)
 	UpdateReport(payload model.Report) (model.Report, error)
-	DeleteReportById(id string) error
+	DeleteReportById(id string, actorId string) error
 	GetReportByTaskId(taskId string) ([]model.Report, error)
 	GetReportByUserId(userId string) ([]model.Report, error)
+	GetReportById(id string) (model.Report, error)
+	ReviewReport(id string, reviewerId string, review model.ReportReview) (model.Report, error)
+	GetMissingReports(projectId string, date string) ([]model.MissingReport, error)
+	GetReportsByIds(ids []string) (map[string]model.Report, error)
+	ImportReport(payload model.Report, readVersion int) error
+	SearchReports(filter model.ReportFilter, page int, size int) ([]model.Report, shared_model.Paging, error)
 }
 
 type reportRepository struct {
 	db     *sql.DB
-	report report.ReportToTXT
+	report report.ReportSink
 }
 
 // CreateReport implements Report.
 func (r *reportRepository) CreateReport(payload model.Report) (model.Report, error) {
-	var report model.Report
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("report_repository.Begin", err.Error())
+		return model.Report{}, err
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRow(config.CreateReport, payload.User_id, payload.Report, payload.Task_id).Scan(&report.Id, &report.User_id, &report.Report, &report.Task_id, &report.Created_at, &report.Updated_at)
+	report, err := scanReport(tx.QueryRow(config.CreateReport, payload.User_id, payload.Report, payload.Task_id, payload.Standup))
 	if err != nil {
 		log.Println("report_repository.QueryRow", err.Error())
 		return model.Report{}, err
 	}
 
-	//report to txt
-	reportToTXT := model.ShowReport{Content: report}
-	err = r.report.WriteReport(reportToTXT, "create")
-	if err != nil {
+	if err := r.commitJournaled(tx, model.ShowReport{ActorId: payload.User_id, Content: report}, "create"); err != nil {
 		return model.Report{}, err
 	}
 	return report, nil
@@ -44,15 +57,24 @@ func (r *reportRepository) CreateReport(payload model.Report) (model.Report, err
 }
 
 // DeleteReportById implements Report.
-func (r *reportRepository) DeleteReportById(id string) error {
+func (r *reportRepository) DeleteReportById(id string, actorId string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("report_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err := r.db.Query(config.DeleteReportById, id)
+	report, err := scanReport(tx.QueryRow(config.DeleteReportById, id))
 	if err != nil {
-		log.Println("report_repository.Query", err.Error())
+		log.Println("report_repository.QueryRow", err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("report not found")
+		}
 		return err
 	}
 
-	return nil
+	return r.commitJournaled(tx, model.ShowReport{ActorId: actorId, Content: report}, "delete")
 }
 
 // GetReportByProjectId implements Report.
@@ -66,9 +88,8 @@ func (r *reportRepository) GetReportByUserId(userId string) ([]model.Report, err
 	}
 
 	for rows.Next() {
-		report := model.Report{}
 		//updated_at cannot be nil
-		err := rows.Scan(&report.Id, &report.User_id, &report.Report, &report.Task_id, &report.Created_at, &report.Updated_at)
+		report, err := scanReport(rows)
 		if err != nil {
 			log.Println("report_Repository.Rows.Next", err.Error())
 			return nil, err
@@ -91,9 +112,8 @@ func (r *reportRepository) GetReportByTaskId(taskId string) ([]model.Report, err
 	}
 
 	for rows.Next() {
-		report := model.Report{}
 		//updated_at cannot be nil
-		err := rows.Scan(&report.Id, &report.User_id, &report.Report, &report.Task_id, &report.Created_at, &report.Updated_at)
+		report, err := scanReport(rows)
 		fmt.Println("ini report :", report)
 		if err != nil {
 			log.Println("reportRepository.Rows.Next", err.Error())
@@ -108,24 +128,234 @@ func (r *reportRepository) GetReportByTaskId(taskId string) ([]model.Report, err
 }
 
 // UpdateReport implements Report.
+// The row is locked while it is read, so the journal gets the version this update replaced.
 func (r *reportRepository) UpdateReport(payload model.Report) (model.Report, error) {
-	var report model.Report
-	err := r.db.QueryRow(config.UpdateReport, payload.Id, payload.User_id, payload.Report, payload.Task_id).Scan(&report.Id, &report.User_id, &report.Report, &report.Task_id, &report.Created_at, &report.Updated_at)
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("report_repository.Begin", err.Error())
+		return model.Report{}, err
+	}
+	defer tx.Rollback()
+
+	// the locked row tells a stale version from a missing report
+	before, err := scanReport(tx.QueryRow(config.GetReportForUpdate, payload.Id, payload.User_id))
+	if err != nil {
+		log.Println("report_repository.QueryRow", err.Error())
+		return model.Report{}, err
+	}
+	if payload.Version != 0 && payload.Version != before.Version {
+		return model.Report{}, shared_model.ErrVersionConflict
+	}
+	report, err := scanReport(tx.QueryRow(config.UpdateReport, payload.Id, payload.User_id, payload.Report, payload.Task_id, payload.Version, payload.Standup))
+	if err != nil {
+		log.Println("report_repository.QueryRow", err.Error())
+		return model.Report{}, err
+	}
+
+	if err := r.commitJournaled(tx, model.ShowReport{ActorId: payload.User_id, Before: &before, Content: report}, "update"); err != nil {
+		return model.Report{}, err
+	}
+	return report, nil
+}
+
+// SearchReports implements ReportRepository.
+// filter.Sort and filter.Order must already be one of created_at/updated_at and asc/desc.
+func (r *reportRepository) SearchReports(filter model.ReportFilter, page int, size int) ([]model.Report, shared_model.Paging, error) {
+	reports := []model.Report{}
+
+	offset := (page - 1) * size
+	rows, err := r.db.Query(config.SearchReports, filter.UserId, filter.TaskId, filter.ProjectId, filter.From, filter.To, filter.Query, filter.ManagerId, filter.Sort, filter.Order, size, offset)
+	if err != nil {
+		log.Println("report_repository.Query", err.Error())
+		return nil, shared_model.Paging{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			log.Println("reportRepository.Rows.Next", err.Error())
+			return nil, shared_model.Paging{}, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	totalRows := 0
+	if err := r.db.QueryRow(config.CountSearchReports, filter.UserId, filter.TaskId, filter.ProjectId, filter.From, filter.To, filter.Query, filter.ManagerId).Scan(&totalRows); err != nil {
+		log.Println("report_repository.QueryRow", err.Error())
+		return nil, shared_model.Paging{}, err
+	}
+
+	paging := shared_model.Paging{
+		Page:        page,
+		RowsPerPage: size,
+		TotalRows:   totalRows,
+		TotalPages:  int(math.Ceil(float64(totalRows) / float64(size))),
+	}
+
+	return reports, paging, nil
+}
+
+// GetReportById implements ReportRepository.
+func (r *reportRepository) GetReportById(id string) (model.Report, error) {
+	report, err := scanReport(r.db.QueryRow(config.GetReportById, id))
 	if err != nil {
 		log.Println("report_repository.QueryRow", err.Error())
 		return model.Report{}, err
 	}
 
-	// report to txt
-	reportToTXT := model.ShowReport{Content: report}
-	err = r.report.WriteReport(reportToTXT, "update")
+	return report, nil
+}
+
+// ReviewReport implements ReportRepository.
+// Reviews are not written to the report journal, which records what members reported.
+func (r *reportRepository) ReviewReport(id string, reviewerId string, review model.ReportReview) (model.Report, error) {
+	report, err := scanReport(r.db.QueryRow(config.ReviewReport, id, review.Status, review.Comment, reviewerId))
 	if err != nil {
+		log.Println("report_repository.QueryRow", err.Error())
 		return model.Report{}, err
 	}
+
 	return report, nil
 }
 
-func NewReportRepository(db *sql.DB, report report.ReportToTXT) ReportRepository {
+// GetMissingReports implements ReportRepository.
+// Active tasks are those In Progress, Blocked or Rejected that existed on date (YYYY-MM-DD).
+func (r *reportRepository) GetMissingReports(projectId string, date string) ([]model.MissingReport, error) {
+	missing := []model.MissingReport{}
+
+	rows, err := r.db.Query(config.GetMissingReports, projectId, date)
+	if err != nil {
+		log.Println("report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member model.MissingReport
+		if err := rows.Scan(&member.UserId, &member.Name, &member.Email, pq.Array(&member.TaskKeys)); err != nil {
+			log.Println("reportRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		missing = append(missing, member)
+	}
+
+	return missing, nil
+}
+
+// GetReportsByIds implements ReportRepository.
+// Deleted reports are included with DeletedAt set; ids that are not in the table are left out.
+func (r *reportRepository) GetReportsByIds(ids []string) (map[string]model.Report, error) {
+	reports := map[string]model.Report{}
+
+	rows, err := r.db.Query(config.GetReportsByIds, pq.Array(ids))
+	if err != nil {
+		log.Println("report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report model.Report
+		if err := rows.Scan(&report.Id, &report.User_id, &report.Report, &report.Task_id, &report.Standup, &report.Created_at, &report.Updated_at, &report.DeletedAt, &report.Version); err != nil {
+			log.Println("reportRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		reports[report.Id] = report
+	}
+
+	return reports, nil
+}
+
+// ImportReport implements ReportRepository.
+// Writes a report exactly as given, inserting it or overwriting the row with its id. Unlike
+// CreateReport and UpdateReport it is not journaled, since it restores what the journal already holds.
+// An existing row is only overwritten while it still has readVersion, the version the import compared
+// against; a readVersion of 0 means the row was missing. Otherwise ErrVersionConflict is returned.
+func (r *reportRepository) ImportReport(payload model.Report, readVersion int) error {
+	result, err := r.db.Exec(config.ImportReport, payload.Id, payload.User_id, payload.Report, payload.Task_id, payload.Standup, payload.Created_at, payload.Updated_at, payload.DeletedAt, payload.Version, readVersion)
+	if err != nil {
+		log.Println("report_repository.Exec", err.Error())
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("report_repository.RowsAffected", err.Error())
+		return err
+	}
+	if affected == 0 {
+		return shared_model.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// commitJournaled writes the change to the report journal and then commits tx. A change that cannot
+// be journaled is rolled back, so every report change in the table has its journal entry. An entry whose
+// commit fails stays in the journal; cmd/import-journal reports it.
+func (r *reportRepository) commitJournaled(tx *sql.Tx, change model.ShowReport, status string) error {
+	return commitJournaledChanges(tx, r.report, status, change)
+}
+
+// commitJournaledReports is commitJournaled for statements that change many reports at once,
+// such as deleting a project: each report returned by the statement gets its own entry.
+func commitJournaledReports(tx *sql.Tx, sink report.ReportSink, actorId string, reports []model.Report, status string) error {
+	changes := make([]model.ShowReport, 0, len(reports))
+	for _, content := range reports {
+		changes = append(changes, model.ShowReport{ActorId: actorId, Content: content})
+	}
+	return commitJournaledChanges(tx, sink, status, changes...)
+}
+
+func commitJournaledChanges(tx *sql.Tx, sink report.ReportSink, status string, changes ...model.ShowReport) error {
+	now := time.Now()
+	for _, change := range changes {
+		change.Date = now
+		if err := sink.WriteReport(change, status); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("report_repository.Commit", err.Error())
+		return err
+	}
+	return nil
+}
+
+// queryReports runs a statement that returns report rows, such as the bulk updates with RETURNING
+func queryReports(tx *sql.Tx, query string, args ...any) ([]model.Report, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		log.Println("report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []model.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			log.Println("reportRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+func scanReport(row rowScanner) (model.Report, error) {
+	var report model.Report
+	err := row.Scan(&report.Id, &report.User_id, &report.Report, &report.Task_id, &report.Standup, &report.ReviewStatus, &report.ReviewComment, &report.ReviewedBy, &report.ReviewedAt, &report.Created_at, &report.Updated_at, &report.Version)
+	return report, err
+}
+
+func NewReportRepository(db *sql.DB, report report.ReportSink) ReportRepository {
 	return &reportRepository{
 		db:     db,
 		report: report,
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
 	"enigma.com/projectmanagementhub/config"
 	"enigma.com/projectmanagementhub/model"
 	"enigma.com/projectmanagementhub/report"
+	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/suite"
 )
@@ -17,7 +19,7 @@ type ReportRepositoryTestSuite struct {
 	mockDB  *sql.DB
 	mockSql sqlmock.Sqlmock
 	repo    ReportRepository
-	reports report.ReportToTXT
+	reports report.ReportSink
 }
 
 func (r *ReportRepositoryTestSuite) SetupTest() {
@@ -29,13 +31,14 @@ func (r *ReportRepositoryTestSuite) SetupTest() {
 }
 
 var expectedReport = model.Report{
-	Id:         "1",
-	User_id:    "1",
-	Report:     "This is report",
-	Task_id:    "1",
-	Created_at: time.Now(),
-	Updated_at: time.Now(),
-	DeletedAt:  nil,
+	Id:           "1",
+	User_id:      "1",
+	Report:       "This is report",
+	Task_id:      "1",
+	ReviewStatus: model.ReportSubmitted,
+	Created_at:   time.Now(),
+	Updated_at:   time.Now(),
+	DeletedAt:    nil,
 }
 
 func TestReportRepositoryTestSuite(t *testing.T) {
@@ -48,10 +51,12 @@ func (r *ReportRepositoryTestSuite) TearDownTest() {
 
 func (r *ReportRepositoryTestSuite) TestCreateReport_Success() {
 	// Ekspektasi bahwa panggilan QueryRowContext akan terjadi
+	r.mockSql.ExpectBegin()
 	r.mockSql.ExpectQuery("INSERT INTO reports").
-		WithArgs(expectedReport.User_id, expectedReport.Report, expectedReport.Task_id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "created_at", "updated_at"}).
-			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, expectedReport.Created_at, expectedReport.Updated_at))
+		WithArgs(expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
+	r.mockSql.ExpectCommit()
 
 	// Melakukan pemanggilan metode yang diuji
 	reportCreated, err := r.repo.CreateReport(expectedReport)
@@ -69,9 +74,11 @@ func (r *ReportRepositoryTestSuite) TestCreateReport_Success() {
 
 func (r *ReportRepositoryTestSuite) TestCreateReport_Failure() {
 	// Ekspektasi bahwa panggilan QueryRowContext akan terjadi
+	r.mockSql.ExpectBegin()
 	r.mockSql.ExpectQuery("INSERT INTO reports").
-		WithArgs(expectedReport.User_id, expectedReport.Report, expectedReport.Task_id).
+		WithArgs(expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil).
 		WillReturnError(sql.ErrNoRows)
+	r.mockSql.ExpectRollback()
 
 	// Melakukan pemanggilan metode yang diuji
 	_, err := r.repo.CreateReport(expectedReport)
@@ -86,10 +93,16 @@ func (r *ReportRepositoryTestSuite) TestCreateReport_Failure() {
 
 func (r *ReportRepositoryTestSuite) TestUpdateReport_Success() {
 
+	r.mockSql.ExpectBegin()
+	r.mockSql.ExpectQuery(`SELECT (.+) FROM reports WHERE id = \$1 AND user_id = \$2 AND deleted_at IS null FOR UPDATE`).
+		WithArgs(expectedReport.Id, expectedReport.User_id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
 	r.mockSql.ExpectQuery("UPDATE reports").
-		WithArgs(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "created_at", "updated_at"}).
-			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, expectedReport.Created_at, expectedReport.Updated_at))
+		WithArgs(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, expectedReport.Version, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
+	r.mockSql.ExpectCommit()
 
 	reportUpdated, err := r.repo.UpdateReport(expectedReport)
 
@@ -102,9 +115,11 @@ func (r *ReportRepositoryTestSuite) TestUpdateReport_Success() {
 
 func (r *ReportRepositoryTestSuite) TestUpdateReport_Failure() {
 
-	r.mockSql.ExpectQuery("UPDATE reports").
-		WithArgs(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id).
+	r.mockSql.ExpectBegin()
+	r.mockSql.ExpectQuery(`SELECT (.+) FROM reports WHERE id = \$1 AND user_id = \$2 AND deleted_at IS null FOR UPDATE`).
+		WithArgs(expectedReport.Id, expectedReport.User_id).
 		WillReturnError(sql.ErrNoRows)
+	r.mockSql.ExpectRollback()
 
 	_, err := r.repo.UpdateReport(expectedReport)
 
@@ -116,12 +131,14 @@ func (r *ReportRepositoryTestSuite) TestUpdateReport_Failure() {
 
 func (r *ReportRepositoryTestSuite) TestDeleteReportById_Success() {
 
+	r.mockSql.ExpectBegin()
 	r.mockSql.ExpectQuery("UPDATE reports SET deleted_at").
 		WithArgs(expectedReport.Id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "created_at", "updated_at"}).
-			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, expectedReport.Created_at, expectedReport.Updated_at))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
+	r.mockSql.ExpectCommit()
 
-	err := r.repo.DeleteReportById(expectedReport.Id)
+	err := r.repo.DeleteReportById(expectedReport.Id, "a1")
 
 	r.NoError(err, "DeleteReportById should not return an error")
 
@@ -131,11 +148,13 @@ func (r *ReportRepositoryTestSuite) TestDeleteReportById_Success() {
 
 func (r *ReportRepositoryTestSuite) TestDeleteReportById_Failure() {
 
+	r.mockSql.ExpectBegin()
 	r.mockSql.ExpectQuery("UPDATE reports SET deleted_at").
 		WithArgs(expectedReport.Id).
 		WillReturnError(sql.ErrNoRows)
+	r.mockSql.ExpectRollback()
 
-	err := r.repo.DeleteReportById(expectedReport.Id)
+	err := r.repo.DeleteReportById(expectedReport.Id, "a1")
 
 	r.Error(err, "DeleteReportById should return an error")
 
@@ -145,10 +164,10 @@ func (r *ReportRepositoryTestSuite) TestDeleteReportById_Failure() {
 
 func (r *ReportRepositoryTestSuite) TestGetReportByTaskId_Success() {
 
-	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, created_at, updated_at FROM reports").
+	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, standup, review_status, review_comment, .+, reviewed_at, created_at, updated_at, version FROM reports").
 		WithArgs(expectedReport.Task_id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "created_at", "updated_at"}).
-			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, expectedReport.Created_at, expectedReport.Updated_at))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
 
 	reports, err := r.repo.GetReportByTaskId(expectedReport.Task_id)
 
@@ -161,7 +180,7 @@ func (r *ReportRepositoryTestSuite) TestGetReportByTaskId_Success() {
 
 func (r *ReportRepositoryTestSuite) TestGetReportByTaskId_Failure() {
 
-	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, created_at, updated_at FROM reports").
+	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, standup, review_status, review_comment, .+, reviewed_at, created_at, updated_at, version FROM reports").
 		WithArgs(expectedReport.Task_id).
 		WillReturnError(sql.ErrNoRows)
 
@@ -177,10 +196,10 @@ func (r *ReportRepositoryTestSuite) TestGetReportByTaskId_Failure() {
 
 func (r *ReportRepositoryTestSuite) TestGetReportByUserId_Success() {
 
-	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, created_at, updated_at FROM reports").
+	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, standup, review_status, review_comment, .+, reviewed_at, created_at, updated_at, version FROM reports").
 		WithArgs(expectedReport.User_id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "created_at", "updated_at"}).
-			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, expectedReport.Created_at, expectedReport.Updated_at))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
 
 	reports, err := r.repo.GetReportByUserId(expectedReport.User_id)
 
@@ -193,7 +212,7 @@ func (r *ReportRepositoryTestSuite) TestGetReportByUserId_Success() {
 
 func (r *ReportRepositoryTestSuite) TestGetReportByUserId_Failure() {
 
-	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, created_at, updated_at FROM reports").
+	r.mockSql.ExpectQuery("SELECT id, user_id, report, task_id, standup, review_status, review_comment, .+, reviewed_at, created_at, updated_at, version FROM reports").
 		WithArgs(expectedReport.User_id).
 		WillReturnError(sql.ErrNoRows)
 
@@ -205,3 +224,165 @@ func (r *ReportRepositoryTestSuite) TestGetReportByUserId_Failure() {
 	err = r.mockSql.ExpectationsWereMet()
 	r.NoError(err, "Database expectations were not met")
 }
+
+func (r *ReportRepositoryTestSuite) TestSearchReports_Success() {
+	filter := model.ReportFilter{ProjectId: "p1", From: "2024-03-04", To: "2024-03-10", Query: "login", ManagerId: "m1", Sort: "created_at", Order: "desc"}
+
+	r.mockSql.ExpectQuery(`SELECT r.id, r.user_id, r.report, r.task_id, r.standup, r.review_status, r.review_comment, .+, r.reviewed_at, r.created_at, r.updated_at, r.version FROM reports r JOIN tasks t ON t.id = r.task_id WHERE .+ ORDER BY .+ LIMIT \$10 OFFSET \$11`).
+		WithArgs("", "", "p1", "2024-03-04", "2024-03-10", "login", "m1", "created_at", "desc", 5, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, `{"today":"login page"}`, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
+	r.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM reports r JOIN tasks t ON t.id = r.task_id WHERE`).
+		WithArgs("", "", "p1", "2024-03-04", "2024-03-10", "login", "m1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+
+	reports, paging, err := r.repo.SearchReports(filter, 2, 5)
+
+	r.NoError(err)
+	r.Len(reports, 1)
+	r.Equal("login page", reports[0].Standup.Today)
+	r.Equal(shared_model.Paging{Page: 2, RowsPerPage: 5, TotalRows: 6, TotalPages: 2}, paging)
+	r.NoError(r.mockSql.ExpectationsWereMet())
+}
+
+func (r *ReportRepositoryTestSuite) TestReviewReport_Success() {
+	reviewedAt := time.Now()
+	review := model.ReportReview{Status: model.ReportNeedsMoreInfo, Comment: "which endpoint?"}
+
+	r.mockSql.ExpectQuery(`UPDATE reports SET review_status = \$2, review_comment = \$3, reviewed_by = \$4, reviewed_at = CURRENT_TIMESTAMP WHERE id = \$1`).
+		WithArgs(expectedReport.Id, review.Status, review.Comment, "m1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, review.Status, review.Comment, "m1", reviewedAt, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
+
+	report, err := r.repo.ReviewReport(expectedReport.Id, "m1", review)
+
+	r.NoError(err)
+	r.Equal(model.ReportNeedsMoreInfo, report.ReviewStatus)
+	r.Equal("which endpoint?", report.ReviewComment)
+	r.Equal("m1", report.ReviewedBy)
+	r.NotNil(report.ReviewedAt)
+}
+
+func (r *ReportRepositoryTestSuite) TestGetMissingReports_Success() {
+	r.mockSql.ExpectQuery(`SELECT u.id, u.name, u.email, array_agg\(t.task_key ORDER BY t.task_key\) FROM tasks t JOIN users u`).
+		WithArgs("p1", "2024-03-04").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "task_keys"}).AddRow("u1", "Budi", "budi@example.com", "{WEB-1,WEB-3}"))
+
+	missing, err := r.repo.GetMissingReports("p1", "2024-03-04")
+
+	r.NoError(err)
+	r.Equal([]model.MissingReport{{UserId: "u1", Name: "Budi", Email: "budi@example.com", TaskKeys: []string{"WEB-1", "WEB-3"}}}, missing)
+}
+
+func (r *ReportRepositoryTestSuite) TestGetReportsByIds_Success() {
+	deletedAt := time.Now()
+
+	r.mockSql.ExpectQuery(`SELECT id, user_id, report, task_id, standup, created_at, updated_at, deleted_at, version FROM reports WHERE id::text = ANY\(\$1\)`).
+		WithArgs("{\"1\",\"2\",\"3\"}").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "created_at", "updated_at", "deleted_at", "version"}).
+			AddRow("1", "1", "This is report", "1", nil, expectedReport.Created_at, expectedReport.Updated_at, nil, 2).
+			AddRow("2", "1", "Old report", "1", nil, expectedReport.Created_at, expectedReport.Updated_at, deletedAt, 1))
+
+	reports, err := r.repo.GetReportsByIds([]string{"1", "2", "3"})
+
+	r.NoError(err)
+	r.Len(reports, 2)
+	r.Nil(reports["1"].DeletedAt)
+	r.Equal(2, reports["1"].Version)
+	r.NotNil(reports["2"].DeletedAt)
+}
+
+func (r *ReportRepositoryTestSuite) TestImportReport_Success() {
+	r.mockSql.ExpectExec(`INSERT INTO reports\(id, user_id, report, task_id, standup, created_at, updated_at, deleted_at, version\)`).
+		WithArgs(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.Created_at, expectedReport.Updated_at, nil, expectedReport.Version, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := r.repo.ImportReport(expectedReport, 0)
+
+	r.NoError(err)
+	r.NoError(r.mockSql.ExpectationsWereMet())
+}
+
+func (r *ReportRepositoryTestSuite) TestImportReport_ChangedConcurrently() {
+	r.mockSql.ExpectExec(`ON CONFLICT \(id\) DO UPDATE SET .* WHERE reports.version = \$10`).
+		WithArgs(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.Created_at, expectedReport.Updated_at, nil, expectedReport.Version, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := r.repo.ImportReport(expectedReport, 1)
+
+	r.ErrorIs(err, shared_model.ErrVersionConflict)
+	r.NoError(r.mockSql.ExpectationsWereMet())
+}
+
+// recordingSink keeps the changes written to the journal
+type recordingSink struct {
+	changes  []model.ShowReport
+	statuses []string
+	err      error
+}
+
+func (s *recordingSink) WriteReport(report model.ShowReport, status string) error {
+	s.changes = append(s.changes, report)
+	s.statuses = append(s.statuses, status)
+	return s.err
+}
+
+func (r *ReportRepositoryTestSuite) TestUpdateReport_JournalsPreviousVersion() {
+	sink := &recordingSink{}
+	r.repo = NewReportRepository(r.mockDB, sink)
+	r.mockSql.ExpectBegin()
+	r.mockSql.ExpectQuery(`SELECT (.+) FOR UPDATE`).
+		WithArgs(expectedReport.Id, expectedReport.User_id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, "Old report", expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, 1))
+	r.mockSql.ExpectQuery("UPDATE reports").
+		WithArgs(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, expectedReport.Version, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, 2))
+	r.mockSql.ExpectCommit()
+
+	_, err := r.repo.UpdateReport(expectedReport)
+
+	r.NoError(err)
+	r.Equal([]string{"update"}, sink.statuses)
+	r.Equal(expectedReport.User_id, sink.changes[0].ActorId)
+	r.Equal("Old report", sink.changes[0].Before.Report)
+	r.Equal(2, sink.changes[0].Content.Version)
+	r.False(sink.changes[0].Date.IsZero())
+	r.NoError(r.mockSql.ExpectationsWereMet())
+}
+
+func (r *ReportRepositoryTestSuite) TestDeleteReportById_JournalsDeletion() {
+	sink := &recordingSink{}
+	r.repo = NewReportRepository(r.mockDB, sink)
+	r.mockSql.ExpectBegin()
+	r.mockSql.ExpectQuery("UPDATE reports SET deleted_at").
+		WithArgs(expectedReport.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
+	r.mockSql.ExpectCommit()
+
+	err := r.repo.DeleteReportById(expectedReport.Id, "a1")
+
+	r.NoError(err)
+	r.Equal([]string{"delete"}, sink.statuses)
+	r.Equal("a1", sink.changes[0].ActorId)
+	r.Nil(sink.changes[0].Before)
+	r.Equal(expectedReport.Report, sink.changes[0].Content.Report)
+	r.NoError(r.mockSql.ExpectationsWereMet())
+}
+
+func (r *ReportRepositoryTestSuite) TestDeleteReportById_RollsBackWhenJournalFails() {
+	r.repo = NewReportRepository(r.mockDB, &recordingSink{err: fmt.Errorf("disk full")})
+	r.mockSql.ExpectBegin()
+	r.mockSql.ExpectQuery("UPDATE reports SET deleted_at").
+		WithArgs(expectedReport.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow(expectedReport.Id, expectedReport.User_id, expectedReport.Report, expectedReport.Task_id, nil, expectedReport.ReviewStatus, "", "", nil, expectedReport.Created_at, expectedReport.Updated_at, expectedReport.Version))
+	r.mockSql.ExpectRollback()
+
+	err := r.repo.DeleteReportById(expectedReport.Id, "a1")
+
+	r.Error(err)
+	r.NoError(r.mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+type SprintRepository interface {
+	GetByProjectId(projectId string) ([]model.Sprint, error)
+	GetById(id string) (model.Sprint, error)
+	Create(payload model.Sprint) (model.Sprint, error)
+	Update(payload model.Sprint) (model.Sprint, error)
+	Start(id string) error
+	Close(id string, rolloverTo string) (int64, error)
+	AddTasks(id string, taskIds []string) error
+	RemoveTasks(id string, taskIds []string) error
+}
+
+type sprintRepository struct {
+	db *sql.DB
+}
+
+func scanSprint(row rowScanner) (model.Sprint, error) {
+	var sprint model.Sprint
+
+	err := row.Scan(&sprint.Id, &sprint.ProjectId, &sprint.Name, &sprint.Goal, &sprint.StartDate, &sprint.EndDate, &sprint.State,
+		&sprint.Snapshot.CommittedTasks, &sprint.Snapshot.CommittedPoints, &sprint.Snapshot.CompletedTasks, &sprint.Snapshot.CompletedPoints,
+		&sprint.CreatedAt, &sprint.UpdatedAt,
+		&sprint.Current.CommittedTasks, &sprint.Current.CommittedPoints, &sprint.Current.CompletedTasks, &sprint.Current.CompletedPoints)
+
+	return sprint, err
+}
+
+// GetByProjectId implements SprintRepository.
+func (s *sprintRepository) GetByProjectId(projectId string) ([]model.Sprint, error) {
+	var sprints []model.Sprint
+
+	rows, err := s.db.Query(config.GetSprintsByProjectId, projectId)
+	if err != nil {
+		log.Println("sprint_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sprint, err := scanSprint(rows)
+		if err != nil {
+			log.Println("sprintRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		sprints = append(sprints, sprint)
+	}
+
+	return sprints, nil
+}
+
+// GetById implements SprintRepository.
+func (s *sprintRepository) GetById(id string) (model.Sprint, error) {
+	sprint, err := scanSprint(s.db.QueryRow(config.GetSprintById, id))
+	if err != nil {
+		log.Println("sprint_repository.QueryRow", err.Error())
+		return model.Sprint{}, err
+	}
+
+	return sprint, nil
+}
+
+// Create implements SprintRepository.
+func (s *sprintRepository) Create(payload model.Sprint) (model.Sprint, error) {
+	var sprint model.Sprint
+
+	err := s.db.QueryRow(config.CreateSprint, payload.ProjectId, payload.Name, payload.Goal, payload.StartDate, payload.EndDate).Scan(&sprint.Id, &sprint.ProjectId, &sprint.Name, &sprint.Goal, &sprint.StartDate, &sprint.EndDate, &sprint.State, &sprint.CreatedAt, &sprint.UpdatedAt)
+	if err != nil {
+		log.Println("sprint_repository.QueryRow", err.Error())
+		return model.Sprint{}, err
+	}
+
+	return sprint, nil
+}
+
+// Update implements SprintRepository.
+func (s *sprintRepository) Update(payload model.Sprint) (model.Sprint, error) {
+	var sprint model.Sprint
+
+	err := s.db.QueryRow(config.UpdateSprint, payload.Id, payload.Name, payload.Goal, payload.StartDate, payload.EndDate).Scan(&sprint.Id, &sprint.ProjectId, &sprint.Name, &sprint.Goal, &sprint.StartDate, &sprint.EndDate, &sprint.State, &sprint.CreatedAt, &sprint.UpdatedAt)
+	if err != nil {
+		log.Println("sprint_repository.QueryRow", err.Error())
+		return model.Sprint{}, err
+	}
+
+	return sprint, nil
+}
+
+// Start implements SprintRepository.
+// The tasks in the sprint at this moment are recorded as committed.
+func (s *sprintRepository) Start(id string) error {
+	result, err := s.db.Exec(config.StartSprint, id)
+	if err != nil {
+		log.Println("sprint_repository.Exec", err.Error())
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("sprint is not planned or the project already has an active sprint")
+	}
+
+	return nil
+}
+
+// Close implements SprintRepository.
+// Unfinished tasks are moved to rolloverTo, or back to the backlog when it is empty.
+func (s *sprintRepository) Close(id string, rolloverTo string) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Println("sprint_repository.Begin", err.Error())
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(config.CloseSprint, id)
+	if err != nil {
+		log.Println("sprint_repository.Exec", err.Error())
+		return 0, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return 0, fmt.Errorf("sprint is not active")
+	}
+
+	result, err = tx.Exec(config.RolloverSprintTasks, id, sql.NullString{String: rolloverTo, Valid: rolloverTo != ""})
+	if err != nil {
+		log.Println("sprint_repository.Exec", err.Error())
+		return 0, err
+	}
+	rolled, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return rolled, nil
+}
+
+// AddTasks implements SprintRepository.
+// Every task must belong to the sprint's project, otherwise nothing is moved.
+func (s *sprintRepository) AddTasks(id string, taskIds []string) error {
+	return s.moveTasks(config.AddSprintTask, id, taskIds, "task %s not found in the sprint's project")
+}
+
+// RemoveTasks implements SprintRepository.
+func (s *sprintRepository) RemoveTasks(id string, taskIds []string) error {
+	return s.moveTasks(config.RemoveSprintTask, id, taskIds, "task %s is not in the sprint")
+}
+
+func (s *sprintRepository) moveTasks(query string, id string, taskIds []string, notFound string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Println("sprint_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, taskId := range taskIds {
+		result, err := tx.Exec(query, id, taskId)
+		if err != nil {
+			log.Println("sprint_repository.Exec", err.Error())
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return fmt.Errorf(notFound, taskId)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func NewSprintRepository(db *sql.DB) SprintRepository {
+	return &sprintRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SprintRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    SprintRepository
+}
+
+func (t *SprintRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewSprintRepository(t.mockDB)
+}
+
+func TestSprintRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SprintRepositoryTestSuite))
+}
+
+var sprintTest = model.Sprint{
+	Id:        "s1",
+	ProjectId: "p1",
+	Name:      "Sprint 1",
+	Goal:      "checkout flow",
+	StartDate: "2024-03-04",
+	EndDate:   "2024-03-15",
+	State:     "active",
+	CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	UpdatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func (t *SprintRepositoryTestSuite) TestGetById_Success() {
+	t.mockSql.ExpectQuery(`SELECT s.id, s.project_id, s.name, s.goal, s.start_date, s.end_date, s.state, s.committed_tasks, s.committed_points, s.completed_tasks, s.completed_points, s.created_at, s.updated_at, COUNT\(t.id\)`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "name", "goal", "start_date", "end_date", "state", "committed_tasks", "committed_points", "completed_tasks", "completed_points", "created_at", "updated_at", "count", "sum", "count", "sum"}).
+			AddRow(sprintTest.Id, sprintTest.ProjectId, sprintTest.Name, sprintTest.Goal, sprintTest.StartDate, sprintTest.EndDate, sprintTest.State, 5, 13, 0, 0, sprintTest.CreatedAt, sprintTest.UpdatedAt, 6, 16, 2, 5))
+
+	actual, err := t.repo.GetById("s1")
+
+	expected := sprintTest
+	expected.Snapshot = model.SprintSummary{CommittedTasks: 5, CommittedPoints: 13}
+	expected.Current = model.SprintSummary{CommittedTasks: 6, CommittedPoints: 16, CompletedTasks: 2, CompletedPoints: 5}
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), expected, actual)
+}
+
+func (t *SprintRepositoryTestSuite) TestStart_AlreadyActive() {
+	t.mockSql.ExpectExec(`UPDATE sprints SET state = 'active'`).WithArgs("s1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := t.repo.Start("s1")
+
+	assert.Error(t.T(), err)
+}
+
+func (t *SprintRepositoryTestSuite) TestClose_RollsOverToNextSprint() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectExec(`UPDATE sprints SET state = 'closed'`).WithArgs("s1").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE tasks SET sprint_id = \$2`).
+		WithArgs("s1", sql.NullString{String: "s2", Valid: true}).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	t.mockSql.ExpectCommit()
+
+	rolled, err := t.repo.Close("s1", "s2")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), int64(3), rolled)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *SprintRepositoryTestSuite) TestClose_RollsOverToBacklog() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectExec(`UPDATE sprints SET state = 'closed'`).WithArgs("s1").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE tasks SET sprint_id = \$2`).
+		WithArgs("s1", sql.NullString{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
+
+	rolled, err := t.repo.Close("s1", "")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), int64(1), rolled)
+}
+
+func (t *SprintRepositoryTestSuite) TestAddTasks_TaskOutsideProject() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectExec(`UPDATE tasks SET sprint_id = \$1`).WithArgs("s1", "t1").WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectRollback()
+
+	err := t.repo.AddTasks("s1", []string{"t1"})
+
+	assert.Error(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+type StatusReportRepository interface {
+	GetStatusChanges(projectId string, start time.Time, end time.Time) ([]model.TaskStatusChange, error)
+	GetOverdueTasks(projectId string, asOf time.Time) ([]model.OverdueTask, error)
+	GetMemberReports(projectId string, start time.Time, end time.Time) ([]model.MemberReport, error)
+	Save(payload model.StatusReport) (model.StatusReport, error)
+	GetById(id string) (model.StatusReport, error)
+	GetByProjectId(projectId string) ([]model.StatusReport, error)
+	GetProjectsWithoutReport(startDate string, endDate string) ([]string, error)
+}
+
+type statusReportRepository struct {
+	db *sql.DB
+}
+
+// GetStatusChanges implements StatusReportRepository.
+// Returns the status changes of the project's tasks in [start, end), oldest first.
+func (s *statusReportRepository) GetStatusChanges(projectId string, start time.Time, end time.Time) ([]model.TaskStatusChange, error) {
+	var changes []model.TaskStatusChange
+
+	rows, err := s.db.Query(config.GetStatusReportChanges, projectId, start, end)
+	if err != nil {
+		log.Println("status_report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change model.TaskStatusChange
+		if err := rows.Scan(&change.TaskId, &change.Key, &change.Name, &change.Status, &change.PreviousStatus, &change.ChangedAt); err != nil {
+			log.Println("status_report_repository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// GetOverdueTasks implements StatusReportRepository.
+// Returns the unaccepted tasks whose deadline is before asOf.
+func (s *statusReportRepository) GetOverdueTasks(projectId string, asOf time.Time) ([]model.OverdueTask, error) {
+	var tasks []model.OverdueTask
+
+	rows, err := s.db.Query(config.GetStatusReportOverdue, projectId, asOf)
+	if err != nil {
+		log.Println("status_report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task model.OverdueTask
+		if err := rows.Scan(&task.TaskId, &task.Key, &task.Name, &task.Status, &task.Deadline, &task.PersonInCharge); err != nil {
+			log.Println("status_report_repository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// GetMemberReports implements StatusReportRepository.
+// Returns the reports written on the project's tasks in [start, end), grouped by author.
+func (s *statusReportRepository) GetMemberReports(projectId string, start time.Time, end time.Time) ([]model.MemberReport, error) {
+	var reports []model.MemberReport
+
+	rows, err := s.db.Query(config.GetStatusReportReports, projectId, start, end)
+	if err != nil {
+		log.Println("status_report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report model.MemberReport
+		if err := rows.Scan(&report.UserId, &report.UserName, &report.TaskKey, &report.TaskName, &report.Report, &report.CreatedAt); err != nil {
+			log.Println("status_report_repository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// Save implements StatusReportRepository.
+// A report for the same project and period is replaced.
+func (s *statusReportRepository) Save(payload model.StatusReport) (model.StatusReport, error) {
+	var report model.StatusReport
+
+	err := s.db.QueryRow(config.UpsertStatusReport, payload.ProjectId, payload.StartDate, payload.EndDate, payload.Markdown, payload.Html, payload.CreatedBy).Scan(&report.Id, &report.ProjectId, &report.StartDate, &report.EndDate, &report.Markdown, &report.Html, &report.CreatedBy, &report.CreatedAt)
+	if err != nil {
+		log.Println("status_report_repository.QueryRow", err.Error())
+		return model.StatusReport{}, err
+	}
+
+	return report, nil
+}
+
+// GetById implements StatusReportRepository.
+func (s *statusReportRepository) GetById(id string) (model.StatusReport, error) {
+	var report model.StatusReport
+
+	err := s.db.QueryRow(config.GetStatusReportById, id).Scan(&report.Id, &report.ProjectId, &report.StartDate, &report.EndDate, &report.Markdown, &report.Html, &report.CreatedBy, &report.CreatedAt)
+	if err != nil {
+		log.Println("status_report_repository.QueryRow", err.Error())
+		return model.StatusReport{}, err
+	}
+
+	return report, nil
+}
+
+// GetByProjectId implements StatusReportRepository.
+// The rendered documents are left out, newest period first.
+func (s *statusReportRepository) GetByProjectId(projectId string) ([]model.StatusReport, error) {
+	reports := []model.StatusReport{}
+
+	rows, err := s.db.Query(config.GetStatusReportsByProject, projectId)
+	if err != nil {
+		log.Println("status_report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report model.StatusReport
+		if err := rows.Scan(&report.Id, &report.ProjectId, &report.StartDate, &report.EndDate, &report.CreatedBy, &report.CreatedAt); err != nil {
+			log.Println("status_report_repository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// GetProjectsWithoutReport implements StatusReportRepository.
+// Returns the ids of live projects that have no report for exactly this period.
+func (s *statusReportRepository) GetProjectsWithoutReport(startDate string, endDate string) ([]string, error) {
+	var ids []string
+
+	rows, err := s.db.Query(config.GetProjectsWithoutStatus, startDate, endDate)
+	if err != nil {
+		log.Println("status_report_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			log.Println("status_report_repository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func NewStatusReportRepository(db *sql.DB) StatusReportRepository {
+	return &statusReportRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatusReportRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    StatusReportRepository
+}
+
+func (t *StatusReportRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewStatusReportRepository(t.mockDB)
+}
+
+func TestStatusReportRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusReportRepositoryTestSuite))
+}
+
+var (
+	statusStart = time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	statusEnd   = time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+)
+
+func (t *StatusReportRepositoryTestSuite) TestGetStatusChanges_Success() {
+	changedAt := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	t.mockSql.ExpectQuery(`SELECT t.id, t.task_key, t.name, h.status, COALESCE\(h.previous::text, ''\), h.changed_at FROM \(SELECT .+ LAG\(status\) OVER \(PARTITION BY task_id ORDER BY id\)`).
+		WithArgs("p1", statusStart, statusEnd).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_key", "name", "status", "previous", "changed_at"}).
+			AddRow("t1", "WEB-1", "Login", "In Progress", "", changedAt).
+			AddRow("t1", "WEB-1", "Login", "Accepted", "In Progress", changedAt.Add(time.Hour)))
+
+	changes, err := t.repo.GetStatusChanges("p1", statusStart, statusEnd)
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), changes, 2)
+	assert.Equal(t.T(), model.TaskStatusChange{TaskId: "t1", Key: "WEB-1", Name: "Login", Status: "Accepted", PreviousStatus: "In Progress", ChangedAt: changedAt.Add(time.Hour)}, changes[1])
+}
+
+func (t *StatusReportRepositoryTestSuite) TestGetOverdueTasks_Success() {
+	t.mockSql.ExpectQuery(`SELECT t.id, t.task_key, t.name, t.status, t.deadline::text, COALESCE\(u.name, ''\) FROM tasks t .+ t.status <> 'Accepted' AND t.deadline < \$2`).
+		WithArgs("p1", statusEnd).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_key", "name", "status", "deadline", "name"}).AddRow("t2", "WEB-2", "Signup", "Blocked", "2024-03-08", "Budi"))
+
+	tasks, err := t.repo.GetOverdueTasks("p1", statusEnd)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.OverdueTask{{TaskId: "t2", Key: "WEB-2", Name: "Signup", Status: "Blocked", Deadline: "2024-03-08", PersonInCharge: "Budi"}}, tasks)
+}
+
+func (t *StatusReportRepositoryTestSuite) TestGetMemberReports_Success() {
+	createdAt := time.Date(2024, 3, 6, 9, 0, 0, 0, time.UTC)
+	t.mockSql.ExpectQuery(`SELECT r.user_id, COALESCE\(u.name, ''\), t.task_key, t.name, r.report, r.created_at FROM reports r`).
+		WithArgs("p1", statusStart, statusEnd).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "name", "task_key", "task_name", "report", "created_at"}).AddRow("u1", "Budi", "WEB-2", "Signup", "done the form", createdAt))
+
+	reports, err := t.repo.GetMemberReports("p1", statusStart, statusEnd)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.MemberReport{{UserId: "u1", UserName: "Budi", TaskKey: "WEB-2", TaskName: "Signup", Report: "done the form", CreatedAt: createdAt}}, reports)
+}
+
+func (t *StatusReportRepositoryTestSuite) TestSave_Success() {
+	payload := model.StatusReport{ProjectId: "p1", StartDate: "2024-03-04", EndDate: "2024-03-10", Markdown: "# md", Html: "<h1>html</h1>"}
+	t.mockSql.ExpectQuery(`INSERT INTO status_reports\(project_id, start_date, end_date, markdown, html, created_by\) .+ ON CONFLICT \(project_id, start_date, end_date\) DO UPDATE`).
+		WithArgs("p1", "2024-03-04", "2024-03-10", "# md", "<h1>html</h1>", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "start_date", "end_date", "markdown", "html", "created_by", "created_at"}).
+			AddRow("s1", "p1", "2024-03-04", "2024-03-10", "# md", "<h1>html</h1>", "", statusStart))
+
+	report, err := t.repo.Save(payload)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "s1", report.Id)
+	assert.Equal(t.T(), "# md", report.Markdown)
+}
+
+func (t *StatusReportRepositoryTestSuite) TestGetById_NotFound() {
+	t.mockSql.ExpectQuery(`SELECT id, project_id, start_date::text, end_date::text, markdown, html, COALESCE\(created_by::text, ''\), created_at FROM status_reports WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := t.repo.GetById("missing")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+}
+
+func (t *StatusReportRepositoryTestSuite) TestGetByProjectId_Success() {
+	t.mockSql.ExpectQuery(`SELECT id, project_id, start_date::text, end_date::text, COALESCE\(created_by::text, ''\), created_at FROM status_reports WHERE project_id = \$1`).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "start_date", "end_date", "created_by", "created_at"}).AddRow("s1", "p1", "2024-03-04", "2024-03-10", "m1", statusStart))
+
+	reports, err := t.repo.GetByProjectId("p1")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.StatusReport{{Id: "s1", ProjectId: "p1", StartDate: "2024-03-04", EndDate: "2024-03-10", CreatedBy: "m1", CreatedAt: statusStart}}, reports)
+}
+
+func (t *StatusReportRepositoryTestSuite) TestGetProjectsWithoutReport_Success() {
+	t.mockSql.ExpectQuery(`SELECT p.id FROM projects p WHERE p.deleted_at IS NULL AND NOT EXISTS`).
+		WithArgs("2024-03-04", "2024-03-10").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("p1").AddRow("p2"))
+
+	ids, err := t.repo.GetProjectsWithoutReport("2024-03-04", "2024-03-10")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []string{"p1", "p2"}, ids)
+}
@@ -2,11 +2,13 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"math"
 
 	"enigma.com/projectmanagementhub/config"
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/report"
 	"enigma.com/projectmanagementhub/shared/shared_model"
 )
 
@@ -30,25 +32,52 @@ type Task struct {
 type TaskRepository interface {
 	GetAll(page int, size int) ([]model.Task, shared_model.Paging, error)
 	GetById(Id string) (model.Task, error)
+	GetByKey(key string) (model.Task, error)
 	GetByPersonInCharge(Id string) ([]model.Task, error)
 	GetByProjectId(Id string) ([]model.Task, error)
+	GetByProjectIdAndFields(Id string, fields model.CustomFieldValues) ([]model.Task, error)
 	CreateTask(payload model.Task) (model.Task, error)
 	UpdateTaskByManager(payload model.Task) (model.Task, error)
 	UpdateTaskByMember(payload model.Task) (model.Task, error)
+	MoveToProject(id string, projectId string) (model.Task, error)
+	Clone(id string, payload model.TaskClone, actorId string) (model.Task, error)
 	Delete(id string) error
 }
 
 type taskRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	report report.ReportSink
 }
 
 // UpdateTaskByManager implements TaskRepository.
 func (t *taskRepository) UpdateTaskByManager(payload model.Task) (model.Task, error) {
 
 	var task model.Task
-	err := t.db.QueryRow(config.UpdateTaskByManager, payload.Id, payload.Name, payload.Status, payload.Approval, payload.PersonInCharge, payload.Deadline, payload.Feedback).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("task_repository.Begin", err.Error())
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkWipLimit(tx, payload.Id, payload.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	err = tx.QueryRow(config.UpdateTaskByManager, payload.Id, payload.Name, payload.Status, payload.Approval, payload.PersonInCharge, payload.Deadline, payload.Feedback, payload.Version, payload.StoryPoints, payload.CustomFields).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 	if err != nil {
 		log.Println("task_repository.QueryRow", err.Error())
+		if errors.Is(err, sql.ErrNoRows) && payload.Version != 0 {
+			return model.Task{}, versionConflict(tx.QueryRow(config.GetTaskVersion, payload.Id))
+		}
+		return model.Task{}, err
+	}
+
+	if err := recordTaskStatus(tx, task.Id, task.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return model.Task{}, err
 	}
 
@@ -61,9 +90,31 @@ func (t *taskRepository) UpdateTaskByMember(payload model.Task) (model.Task, err
 
 	var task model.Task
 
-	err := t.db.QueryRow(config.UpdateTaskByMember, payload.Id, payload.PersonInCharge, payload.Status).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("task_repository.Begin", err.Error())
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkWipLimit(tx, payload.Id, payload.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	err = tx.QueryRow(config.UpdateTaskByMember, payload.Id, payload.PersonInCharge, payload.Status, payload.Version).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 	if err != nil {
 		log.Println("task_repository.QueryRow", err.Error())
+		if errors.Is(err, sql.ErrNoRows) && payload.Version != 0 {
+			return model.Task{}, versionConflict(tx.QueryRow(config.GetTaskVersion, payload.Id))
+		}
+		return model.Task{}, err
+	}
+
+	if err := recordTaskStatus(tx, task.Id, task.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return model.Task{}, err
 	}
 
@@ -75,15 +126,144 @@ func (t *taskRepository) CreateTask(payload model.Task) (model.Task, error) {
 
 	var task model.Task
 
-	err := t.db.QueryRow(config.CreateTask, payload.Name, payload.PersonInCharge, payload.Deadline, payload.ProjectId).Scan(&task.Id, &task.Name, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.CreatedAt)
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("task_repository.Begin", err.Error())
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	// the project row stays locked until commit, so concurrent creates get distinct numbers
+	var key string
+	if err := tx.QueryRow(config.AllocateTaskKey, payload.ProjectId).Scan(&key); err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return model.Task{}, err
+	}
+
+	err = tx.QueryRow(config.CreateTask, payload.Name, payload.PersonInCharge, payload.Deadline, payload.ProjectId, payload.StoryPoints, payload.CustomFields, key).Scan(&task.Id, &task.Name, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.CreatedAt, &task.Version, &task.StoryPoints, &task.CustomFields, &task.Key)
 	if err != nil {
 		log.Println("task_repository.QueryRow", err.Error())
 		return model.Task{}, err
 	}
+
+	if _, err := tx.Exec(config.InsertTaskKey, task.Key, task.Id); err != nil {
+		log.Println("task_repository.Exec", err.Error())
+		return model.Task{}, err
+	}
 	task.Status = "In Progress"
 	task.Approval = false
 	task.UpdatedAt = task.CreatedAt
 
+	if err := recordTaskStatus(tx, task.Id, task.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+// MoveToProject implements TaskRepository.
+// The task gets a key in the target project while its old keys keep resolving.
+// Reports follow the task; milestone, sprint and custom fields unknown to the target are dropped.
+// The task keeps its status, so the target project's column for it must have room.
+func (t *taskRepository) MoveToProject(id string, projectId string) (model.Task, error) {
+
+	var task model.Task
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("task_repository.Begin", err.Error())
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.QueryRow(config.GetTaskStatus, id).Scan(&status); err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return model.Task{}, err
+	}
+	if err := checkColumnWipLimit(tx, projectId, status); err != nil {
+		return model.Task{}, err
+	}
+
+	var key string
+	if err := tx.QueryRow(config.AllocateTaskKey, projectId).Scan(&key); err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return model.Task{}, err
+	}
+
+	err = tx.QueryRow(config.MoveTaskToProject, id, projectId, key).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
+	if err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return model.Task{}, err
+	}
+
+	if _, err := tx.Exec(config.InsertTaskKey, task.Key, task.Id); err != nil {
+		log.Println("task_repository.Exec", err.Error())
+		return model.Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+// Clone implements TaskRepository.
+// The clone starts over as a new task in progress in payload.ProjectId. Copied reports are journaled
+// as created by actorId.
+func (t *taskRepository) Clone(id string, payload model.TaskClone, actorId string) (model.Task, error) {
+
+	var task model.Task
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("task_repository.Begin", err.Error())
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	// clones start in progress
+	if err := checkColumnWipLimit(tx, payload.ProjectId, "In Progress"); err != nil {
+		return model.Task{}, err
+	}
+
+	var key string
+	if err := tx.QueryRow(config.AllocateTaskKey, payload.ProjectId).Scan(&key); err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return model.Task{}, err
+	}
+
+	err = tx.QueryRow(config.CloneTask, id, payload.ProjectId, payload.Name, payload.CopyStoryPoints, payload.CopyCustomFields, key).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
+	if err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return model.Task{}, err
+	}
+
+	if _, err := tx.Exec(config.InsertTaskKey, task.Key, task.Id); err != nil {
+		log.Println("task_repository.Exec", err.Error())
+		return model.Task{}, err
+	}
+
+	if err := recordTaskStatus(tx, task.Id, task.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	var reports []model.Report
+	if payload.CopyReports {
+		if reports, err = queryReports(tx, config.CloneTaskReports, id, task.Id); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	if err := commitJournaledReports(tx, t.report, actorId, reports, "create"); err != nil {
+		return model.Task{}, err
+	}
+
 	return task, nil
 }
 
@@ -112,7 +292,7 @@ func (t *taskRepository) GetAll(page int, size int) ([]model.Task, shared_model.
 	for row.Next() {
 		task := model.Task{}
 		//updated_at cannot be nil
-		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 		if err != nil {
 			log.Println("taskRepository.Rows.Next", err.Error())
 			return nil, shared_model.Paging{}, err
@@ -141,7 +321,21 @@ func (t *taskRepository) GetAll(page int, size int) ([]model.Task, shared_model.
 func (t *taskRepository) GetById(Id string) (model.Task, error) {
 
 	var task model.Task
-	err := t.db.QueryRow(config.GetTaskById, Id).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+	err := t.db.QueryRow(config.GetTaskById, Id).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
+	if err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return model.Task{}, err
+	}
+
+	return task, nil
+}
+
+// GetByKey implements TaskRepository.
+// Keys a task had in earlier projects still resolve to it.
+func (t *taskRepository) GetByKey(key string) (model.Task, error) {
+	var task model.Task
+
+	err := t.db.QueryRow(config.GetTaskByKey, key).Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 	if err != nil {
 		log.Println("task_repository.QueryRow", err.Error())
 		return model.Task{}, err
@@ -162,7 +356,7 @@ func (t *taskRepository) GetByPersonInCharge(Id string) ([]model.Task, error) {
 	}
 	for row.Next() {
 		task := model.Task{}
-		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 		if err != nil {
 			log.Println("taskRepository.Rows.Next", err.Error())
 			return nil, err
@@ -186,7 +380,33 @@ func (t *taskRepository) GetByProjectId(Id string) ([]model.Task, error) {
 
 	for row.Next() {
 		task := model.Task{}
-		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
+		if err != nil {
+			log.Println("taskRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// GetByProjectIdAndFields implements TaskRepository.
+// Only tasks whose custom fields contain every given value are returned.
+func (t *taskRepository) GetByProjectIdAndFields(Id string, fields model.CustomFieldValues) ([]model.Task, error) {
+
+	var tasks []model.Task
+
+	row, err := t.db.Query(config.GetTaskByProjectIdAndFields, Id, fields)
+	if err != nil {
+		log.Println("task_repository.Query", err.Error())
+		return nil, err
+	}
+
+	for row.Next() {
+		task := model.Task{}
+		err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 		if err != nil {
 			log.Println("taskRepository.Rows.Next", err.Error())
 			return nil, err
@@ -198,8 +418,79 @@ func (t *taskRepository) GetByProjectId(Id string) ([]model.Task, error) {
 	return tasks, nil
 }
 
-func NewTaskRepository(db *sql.DB) TaskRepository {
+// recordTaskStatus appends to the status history that burndown charts are rebuilt from.
+// Nothing is written when the status did not change.
+func recordTaskStatus(tx *sql.Tx, taskId string, status string) error {
+	if _, err := tx.Exec(config.InsertTaskStatusHistory, taskId, status); err != nil {
+		log.Println("task_repository.Exec", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// versionConflict tells why an update guarded by a version matched no row: row reads the row's current
+// version, so a row that is still there is a version conflict and a missing one is sql.ErrNoRows.
+func versionConflict(row *sql.Row) error {
+	var version int
+	if err := row.Scan(&version); err != nil {
+		return err
+	}
+	return shared_model.ErrVersionConflict
+}
+
+// checkWipLimit serializes status changes into the task's board column for the rest of the
+// transaction and rejects the change when that column is already full.
+// The lock has to come first: row locks only cover tasks that are already in the column.
+func checkWipLimit(tx *sql.Tx, taskId string, status string) error {
+	if _, err := tx.Exec(config.LockBoardColumn, taskId, status); err != nil {
+		log.Println("task_repository.Exec", err.Error())
+		return err
+	}
+
+	var current string
+	var limit, count int
+	err := tx.QueryRow(config.GetWipColumn, taskId, status).Scan(&current, &limit, &count)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// the update itself reports the missing task
+			return nil
+		}
+		log.Println("task_repository.QueryRow", err.Error())
+		return err
+	}
+
+	if current != status && limit > 0 && count >= limit {
+		return shared_model.ErrWipLimit
+	}
+
+	return nil
+}
+
+// checkColumnWipLimit is checkWipLimit for a task entering projectId from outside it, as moved
+// and cloned tasks do. It takes the same lock, so it serializes with status changes in that column.
+func checkColumnWipLimit(tx *sql.Tx, projectId string, status string) error {
+	if _, err := tx.Exec(config.LockProjectColumn, projectId, status); err != nil {
+		log.Println("task_repository.Exec", err.Error())
+		return err
+	}
+
+	var limit, count int
+	if err := tx.QueryRow(config.GetProjectWipColumn, projectId, status).Scan(&limit, &count); err != nil {
+		log.Println("task_repository.QueryRow", err.Error())
+		return err
+	}
+
+	if limit > 0 && count >= limit {
+		return shared_model.ErrWipLimit
+	}
+
+	return nil
+}
+
+func NewTaskRepository(db *sql.DB, report report.ReportSink) TaskRepository {
 	return &taskRepository{
-		db: db,
+		db:     db,
+		report: report,
 	}
 }
@@ -17,17 +17,20 @@ type TaskRepositoryTestSuite struct {
 	suite.Suite
 	mockDB  *sql.DB
 	mockSql sqlmock.Sqlmock
+	sink    *recordingSink
 	repo    TaskRepository
 }
 
 func (t *TaskRepositoryTestSuite) SetupTest() {
 	db, mock, _ := sqlmock.New()
 	t.mockDB, t.mockSql = db, mock
-	t.repo = NewTaskRepository(t.mockDB)
+	t.sink = &recordingSink{}
+	t.repo = NewTaskRepository(t.mockDB, t.sink)
 }
 
 var originalTask = model.Task{
 	Id:             "1",
+	Key:            "WEB-42",
 	Name:           "task1",
 	ProjectId:      "1",
 	Status:         "In Progress",
@@ -58,9 +61,9 @@ var updatedTask = model.Task{
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetAll_Success() {
 	// Mock the SQL query expectations for GetAll.
-	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at"}).
-		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt)
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, originalTask.Version, originalTask.MilestoneId, originalTask.StoryPoints, originalTask.SprintId, nil, originalTask.Key)
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
 		WithArgs(10, 0).
 		WillReturnRows(rows)
 	t.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks WHERE deleted_at IS NULL`).
@@ -78,7 +81,7 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetAll_Success() {
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetAll_ErrorOnQuery() {
 	// Mock the SQL query expectations for GetAll with an error.
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
 		WithArgs(10, 0).
 		WillReturnError(sql.ErrConnDone)
 
@@ -94,9 +97,9 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetAll_ErrorOnQuery() {
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetAll_ErrorOnRowScan() {
 	// Mock the SQL query expectations for GetAll with an error on row scan.
-	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at"}).
-		AddRow("invalid_id", originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt)
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow("invalid_id", originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, originalTask.Version, originalTask.MilestoneId, originalTask.StoryPoints, originalTask.SprintId, nil, originalTask.Key)
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE deleted_at IS NULL ORDER BY deadline DESC LIMIT \$1 OFFSET \$2`).
 		WithArgs(10, 0).
 		WillReturnRows(rows)
 
@@ -112,9 +115,9 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetAll_ErrorOnRowScan() {
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetById_Success() {
 	// Mock the SQL query expectations for GetById.
-	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at"}).
-		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt)
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE id = \$1 AND deleted_at IS NULL`).
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, originalTask.Version, originalTask.MilestoneId, originalTask.StoryPoints, originalTask.SprintId, nil, originalTask.Key)
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE id = \$1 AND deleted_at IS NULL`).
 		WithArgs(originalTask.Id).
 		WillReturnRows(rows)
 
@@ -128,7 +131,7 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetById_Success() {
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetById_NotFound() {
 	// Mock the SQL query expectations for GetById with no result.
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE id = \$1 AND deleted_at IS NULL`).
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE id = \$1 AND deleted_at IS NULL`).
 		WithArgs(originalTask.Id).
 		WillReturnRows(sqlmock.NewRows([]string{}))
 
@@ -143,9 +146,9 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetById_NotFound() {
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByPersonInCharge_Success() {
 	// Mock the SQL query expectations for GetByPersonInCharge.
-	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at"}).
-		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt)
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE person_in_charge=\$1 AND deleted_at IS NULL`).
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, originalTask.Version, originalTask.MilestoneId, originalTask.StoryPoints, originalTask.SprintId, nil, originalTask.Key)
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE person_in_charge=\$1 AND deleted_at IS NULL`).
 		WithArgs(originalTask.PersonInCharge).
 		WillReturnRows(rows)
 
@@ -160,7 +163,7 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByPersonInCharge_Success
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByPersonInCharge_EmptyResult() {
 	// Mock the SQL query expectations for GetByPersonInCharge with no result.
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE person_in_charge=\$1 AND deleted_at IS NULL`).
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE person_in_charge=\$1 AND deleted_at IS NULL`).
 		WithArgs(originalTask.PersonInCharge).
 		WillReturnRows(sqlmock.NewRows([]string{}))
 
@@ -175,9 +178,9 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByPersonInCharge_EmptyRe
 // Similar tests can be created for GetByProjectId, CreateTask, UpdateTaskByManager, UpdateTaskByMember, and Delete methods.
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByProjectId_Success() {
 	// Mock the SQL query expectations for GetByProjectId.
-	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at"}).
-		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt)
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE project_id=\$1 AND deleted_at IS NULL`).
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, originalTask.Version, originalTask.MilestoneId, originalTask.StoryPoints, originalTask.SprintId, nil, originalTask.Key)
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE project_id=\$1 AND deleted_at IS NULL ORDER BY rank, created_at`).
 		WithArgs(originalTask.ProjectId).
 		WillReturnRows(rows)
 
@@ -192,7 +195,7 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByProjectId_Success() {
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByProjectId_EmptyResult() {
 	// Mock the SQL query expectations for GetByProjectId with no result.
-	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at FROM tasks WHERE project_id=\$1 AND deleted_at IS NULL`).
+	t.mockSql.ExpectQuery(`SELECT id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields, task_key FROM tasks WHERE project_id=\$1 AND deleted_at IS NULL ORDER BY rank, created_at`).
 		WithArgs(originalTask.ProjectId).
 		WillReturnRows(sqlmock.NewRows([]string{}))
 
@@ -204,13 +207,38 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByProjectId_EmptyResult(
 	assert.Empty(t.T(), resultTasks)
 }
 
+func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByProjectIdAndFields_Success() {
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, originalTask.Version, originalTask.MilestoneId, originalTask.StoryPoints, originalTask.SprintId, []byte(`{"env":"prod"}`), originalTask.Key)
+	t.mockSql.ExpectQuery(`FROM tasks WHERE project_id=\$1 AND custom_fields @> \$2::jsonb AND deleted_at IS NULL`).
+		WithArgs(originalTask.ProjectId, `{"env":"prod"}`).
+		WillReturnRows(rows)
+
+	resultTasks, err := t.repo.GetByProjectIdAndFields(originalTask.ProjectId, model.CustomFieldValues{"env": "prod"})
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), resultTasks, 1)
+	assert.Equal(t.T(), model.CustomFieldValues{"env": "prod"}, resultTasks[0].CustomFields)
+}
+
 func (t *TaskRepositoryTestSuite) TestTaskRepository_CreateTask_Success() {
 	// Mock the SQL query expectations for CreateTask.
-	rows := sqlmock.NewRows([]string{"id", "name", "person_in_charge", "deadline", "project_id", "created_at"}).
-		AddRow(originalTask.Id, originalTask.Name, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.CreatedAt)
-	t.mockSql.ExpectQuery(`INSERT INTO tasks\(name, status, approval, person_in_charge, deadline, project_id, updated_at\) VALUES \(\$1, 'In Progress', false, \$2, \$3, \$4, CURRENT_TIMESTAMP\) RETURNING id, name, person_in_charge, deadline, project_id, created_at`).
-		WithArgs(originalTask.Name, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId).
+	rows := sqlmock.NewRows([]string{"id", "name", "person_in_charge", "deadline", "project_id", "created_at", "version", "story_points", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.CreatedAt, originalTask.Version, originalTask.StoryPoints, []byte(`{"env":"prod"}`), "WEB-42")
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE projects SET next_task_number = next_task_number \+ 1 WHERE id = \$1`).
+		WithArgs(originalTask.ProjectId).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("WEB-42"))
+	t.mockSql.ExpectQuery(`INSERT INTO tasks\(name, status, approval, person_in_charge, deadline, project_id, story_points, rank, custom_fields, task_key, updated_at\) VALUES \(\$1, 'In Progress', false, \$2, \$3, \$4, COALESCE\(\$5, 0\), \(SELECT COALESCE\(MAX\(rank\), 0\) \+ 1024 FROM tasks WHERE project_id = \$4\), COALESCE\(\$6::jsonb, '{}'\), \$7, CURRENT_TIMESTAMP\) RETURNING id, name, person_in_charge, deadline, project_id, created_at, version, story_points, custom_fields, task_key`).
+		WithArgs(originalTask.Name, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.StoryPoints, originalTask.CustomFields, "WEB-42").
 		WillReturnRows(rows)
+	t.mockSql.ExpectExec(`INSERT INTO task_keys\(key, task_id\) VALUES \(\$1, \$2\)`).
+		WithArgs("WEB-42", originalTask.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`INSERT INTO task_status_history\(task_id, status\) SELECT \$1, \$2 WHERE \$2::task_status IS DISTINCT FROM`).
+		WithArgs(originalTask.Id, "In Progress").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
 
 	// Call the CreateTask method.
 	resultTask, err := t.repo.CreateTask(originalTask)
@@ -219,15 +247,134 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_CreateTask_Success() {
 	assert.NoError(t.T(), err)
 	assert.Equal(t.T(), "In Progress", resultTask.Status)
 	assert.False(t.T(), resultTask.Approval)
+	assert.Equal(t.T(), model.CustomFieldValues{"env": "prod"}, resultTask.CustomFields)
+	assert.Equal(t.T(), "WEB-42", resultTask.Key)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_CreateTask_ProjectNotFound() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE projects SET next_task_number`).WithArgs(originalTask.ProjectId).WillReturnError(sql.ErrNoRows)
+	t.mockSql.ExpectRollback()
+
+	_, err := t.repo.CreateTask(originalTask)
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_GetByKey_Success() {
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, originalTask.ProjectId, originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, originalTask.Version, originalTask.MilestoneId, originalTask.StoryPoints, originalTask.SprintId, nil, originalTask.Key)
+	t.mockSql.ExpectQuery(`FROM tasks WHERE id = \(SELECT task_id FROM task_keys WHERE key = \$1\) AND deleted_at IS NULL`).
+		WithArgs("WEB-42").
+		WillReturnRows(rows)
+
+	task, err := t.repo.GetByKey("WEB-42")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), originalTask, task)
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_MoveToProject_Success() {
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(originalTask.Id, originalTask.Name, originalTask.Status, originalTask.Approval, originalTask.PersonInCharge, originalTask.Deadline, "2", originalTask.ApprovalDate, originalTask.Feedback, originalTask.CreatedAt, originalTask.UpdatedAt, 2, "", 0, "", nil, "APP-7")
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`SELECT status FROM tasks`).WithArgs(originalTask.Id).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(originalTask.Status))
+	expectColumnWipCheck(t.mockSql, "2", originalTask.Status, 3, 2)
+	t.mockSql.ExpectQuery(`UPDATE projects SET next_task_number`).WithArgs("2").WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("APP-7"))
+	t.mockSql.ExpectQuery(`UPDATE tasks SET project_id = \$2, task_key = \$3, milestone_id = NULL, sprint_id = NULL`).
+		WithArgs(originalTask.Id, "2", "APP-7").
+		WillReturnRows(rows)
+	t.mockSql.ExpectExec(`INSERT INTO task_keys`).WithArgs("APP-7", originalTask.Id).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
+
+	task, err := t.repo.MoveToProject(originalTask.Id, "2")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "2", task.ProjectId)
+	assert.Equal(t.T(), "APP-7", task.Key)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_Clone_WithReports() {
+	payload := model.TaskClone{ProjectId: "2", CopyCustomFields: true, CopyReports: true}
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow("9", originalTask.Name, "In Progress", false, originalTask.PersonInCharge, originalTask.Deadline, "2", nil, "-", originalTask.CreatedAt, originalTask.UpdatedAt, 1, "", 0, "", nil, "APP-8")
+	t.mockSql.ExpectBegin()
+	expectColumnWipCheck(t.mockSql, "2", "In Progress", 0, 5)
+	t.mockSql.ExpectQuery(`UPDATE projects SET next_task_number`).WithArgs("2").WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("APP-8"))
+	t.mockSql.ExpectQuery(`INSERT INTO tasks\(name, status, approval, person_in_charge, deadline, project_id, story_points, rank, custom_fields, task_key, updated_at\) SELECT`).
+		WithArgs(originalTask.Id, "2", "", false, true, "APP-8").
+		WillReturnRows(rows)
+	t.mockSql.ExpectExec(`INSERT INTO task_keys`).WithArgs("APP-8", "9").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`INSERT INTO task_status_history`).WithArgs("9", "In Progress").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectQuery(`INSERT INTO reports\(user_id, report, task_id, standup, created_at, updated_at\) SELECT`).
+		WithArgs(originalTask.Id, "9").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow("11", "2", "report one", "9", nil, "pending", "", "", nil, originalTask.CreatedAt, originalTask.UpdatedAt, 1).
+			AddRow("12", "3", "report two", "9", nil, "pending", "", "", nil, originalTask.CreatedAt, originalTask.UpdatedAt, 1))
+	t.mockSql.ExpectCommit()
+
+	task, err := t.repo.Clone(originalTask.Id, payload, "5")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "9", task.Id)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+	assert.Equal(t.T(), []string{"create", "create"}, t.sink.statuses)
+	assert.Equal(t.T(), "11", t.sink.changes[0].Content.Id)
+	assert.Equal(t.T(), "5", t.sink.changes[0].ActorId)
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_MoveToProject_WipLimit() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`SELECT status FROM tasks`).WithArgs(originalTask.Id).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(originalTask.Status))
+	expectColumnWipCheck(t.mockSql, "2", originalTask.Status, 3, 3)
+	t.mockSql.ExpectRollback()
+
+	_, err := t.repo.MoveToProject(originalTask.Id, "2")
+
+	assert.ErrorIs(t.T(), err, shared_model.ErrWipLimit)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_Clone_WipLimit() {
+	t.mockSql.ExpectBegin()
+	expectColumnWipCheck(t.mockSql, "2", "In Progress", 1, 1)
+	t.mockSql.ExpectRollback()
+
+	_, err := t.repo.Clone(originalTask.Id, model.TaskClone{ProjectId: "2"}, "5")
+
+	assert.ErrorIs(t.T(), err, shared_model.ErrWipLimit)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_Clone_TaskNotFound() {
+	t.mockSql.ExpectBegin()
+	expectColumnWipCheck(t.mockSql, "2", "In Progress", 0, 0)
+	t.mockSql.ExpectQuery(`UPDATE projects SET next_task_number`).WithArgs("2").WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("APP-8"))
+	t.mockSql.ExpectQuery(`INSERT INTO tasks`).WillReturnError(sql.ErrNoRows)
+	t.mockSql.ExpectRollback()
+
+	_, err := t.repo.Clone(originalTask.Id, model.TaskClone{ProjectId: "2"}, "5")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
 }
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_UpdateTaskByManager_Success() {
 	// Mock the SQL query expectations for UpdateTaskByManager.
-	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at"}).
-		AddRow(updatedTask.Id, updatedTask.Name, updatedTask.Status, updatedTask.Approval, updatedTask.PersonInCharge, updatedTask.Deadline, updatedTask.ProjectId, updatedTask.ApprovalDate, updatedTask.Feedback, updatedTask.CreatedAt, updatedTask.UpdatedAt)
-	t.mockSql.ExpectQuery(`UPDATE tasks SET name = \$2, status = \$3, approval = \$4, person_in_charge = \$5, deadline = \$6, approval_date = CURRENT_TIMESTAMP, feedback = \$7, updated_at = CURRENT_TIMESTAMP WHERE id = \$1 AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at`).
-		WithArgs(updatedTask.Id, updatedTask.Name, updatedTask.Status, updatedTask.Approval, updatedTask.PersonInCharge, updatedTask.Deadline, updatedTask.Feedback).
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(updatedTask.Id, updatedTask.Name, updatedTask.Status, updatedTask.Approval, updatedTask.PersonInCharge, updatedTask.Deadline, updatedTask.ProjectId, updatedTask.ApprovalDate, updatedTask.Feedback, updatedTask.CreatedAt, updatedTask.UpdatedAt, updatedTask.Version, updatedTask.MilestoneId, updatedTask.StoryPoints, updatedTask.SprintId, nil, updatedTask.Key)
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, updatedTask.Id, updatedTask.Status, originalTask.Status, 0, 0)
+	t.mockSql.ExpectQuery(`UPDATE tasks SET name = \$2, status = \$3, approval = \$4, person_in_charge = \$5, deadline = \$6, approval_date = CURRENT_TIMESTAMP, feedback = \$7, story_points = COALESCE\(\$9, story_points\), custom_fields = COALESCE\(\$10::jsonb, custom_fields\), updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$1 AND \(\$8 = 0 OR version = \$8\) AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields`).
+		WithArgs(updatedTask.Id, updatedTask.Name, updatedTask.Status, updatedTask.Approval, updatedTask.PersonInCharge, updatedTask.Deadline, updatedTask.Feedback, updatedTask.Version, updatedTask.StoryPoints, updatedTask.CustomFields).
 		WillReturnRows(rows)
+	t.mockSql.ExpectExec(`INSERT INTO task_status_history`).
+		WithArgs(updatedTask.Id, updatedTask.Status).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
 
 	// Call the UpdateTaskByManager method.
 	resultTask, err := t.repo.UpdateTaskByManager(updatedTask)
@@ -239,11 +386,17 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_UpdateTaskByManager_Success
 
 func (t *TaskRepositoryTestSuite) TestTaskRepository_UpdateTaskByMember_Success() {
 	// Mock the SQL query expectations for UpdateTaskByMember.
-	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at"}).
-		AddRow(updatedTask.Id, updatedTask.Name, updatedTask.Status, updatedTask.Approval, updatedTask.PersonInCharge, updatedTask.Deadline, updatedTask.ProjectId, updatedTask.ApprovalDate, updatedTask.Feedback, updatedTask.CreatedAt, updatedTask.UpdatedAt)
-	t.mockSql.ExpectQuery(`UPDATE tasks SET status = \$3, updated_at = CURRENT_TIMESTAMP WHERE id = \$1 AND person_in_charge = \$2 AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at`).
-		WithArgs(updatedTask.Id, updatedTask.PersonInCharge, updatedTask.Status).
+	rows := sqlmock.NewRows([]string{"id", "name", "status", "approval", "person_in_charge", "deadline", "project_id", "approval_date", "feedback", "created_at", "updated_at", "version", "milestone_id", "story_points", "sprint_id", "custom_fields", "task_key"}).
+		AddRow(updatedTask.Id, updatedTask.Name, updatedTask.Status, updatedTask.Approval, updatedTask.PersonInCharge, updatedTask.Deadline, updatedTask.ProjectId, updatedTask.ApprovalDate, updatedTask.Feedback, updatedTask.CreatedAt, updatedTask.UpdatedAt, updatedTask.Version, updatedTask.MilestoneId, updatedTask.StoryPoints, updatedTask.SprintId, nil, updatedTask.Key)
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, updatedTask.Id, updatedTask.Status, originalTask.Status, 0, 0)
+	t.mockSql.ExpectQuery(`UPDATE tasks SET status = \$3, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$1 AND person_in_charge = \$2 AND \(\$4 = 0 OR version = \$4\) AND deleted_at IS NULL RETURNING id, name, status, approval, person_in_charge, deadline, project_id, approval_date, CASE WHEN feedback IS NULL THEN '-' ELSE feedback END, created_at, updated_at, version, COALESCE\(milestone_id::text, ''\), story_points, COALESCE\(sprint_id::text, ''\), custom_fields`).
+		WithArgs(updatedTask.Id, updatedTask.PersonInCharge, updatedTask.Status, updatedTask.Version).
 		WillReturnRows(rows)
+	t.mockSql.ExpectExec(`INSERT INTO task_status_history`).
+		WithArgs(updatedTask.Id, updatedTask.Status).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectCommit()
 
 	// Call the UpdateTaskByMember method.
 	resultTask, err := t.repo.UpdateTaskByMember(updatedTask)
@@ -253,6 +406,78 @@ func (t *TaskRepositoryTestSuite) TestTaskRepository_UpdateTaskByMember_Success(
 	assert.Equal(t.T(), updatedTask, resultTask)
 }
 
+func (t *TaskRepositoryTestSuite) TestTaskRepository_UpdateTaskByManager_VersionConflict() {
+	// A stale version matches no row, which is reported as a conflict.
+	staleTask := updatedTask
+	staleTask.Version = 1
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, staleTask.Id, staleTask.Status, originalTask.Status, 0, 0)
+	t.mockSql.ExpectQuery(`UPDATE tasks SET name = \$2`).
+		WithArgs(staleTask.Id, staleTask.Name, staleTask.Status, staleTask.Approval, staleTask.PersonInCharge, staleTask.Deadline, staleTask.Feedback, staleTask.Version, staleTask.StoryPoints, staleTask.CustomFields).
+		WillReturnError(sql.ErrNoRows)
+	t.mockSql.ExpectQuery(`SELECT version FROM tasks WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(staleTask.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	t.mockSql.ExpectRollback()
+
+	// Call the UpdateTaskByManager method.
+	_, err := t.repo.UpdateTaskByManager(staleTask)
+
+	// Assertions
+	assert.True(t.T(), errors.Is(err, shared_model.ErrVersionConflict))
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_UpdateTaskByManager_Missing() {
+	// A versioned update of a task that is gone is not a conflict.
+	staleTask := updatedTask
+	staleTask.Version = 1
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, staleTask.Id, staleTask.Status, originalTask.Status, 0, 0)
+	t.mockSql.ExpectQuery(`UPDATE tasks SET name = \$2`).WillReturnError(sql.ErrNoRows)
+	t.mockSql.ExpectQuery(`SELECT version FROM tasks`).WithArgs(staleTask.Id).WillReturnError(sql.ErrNoRows)
+	t.mockSql.ExpectRollback()
+
+	_, err := t.repo.UpdateTaskByManager(staleTask)
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *TaskRepositoryTestSuite) TestTaskRepository_UpdateTaskByMember_WipLimitReached() {
+	// The target column is full, so the status change is refused before the update runs.
+	blocked := updatedTask
+	blocked.Status = "Blocked"
+	t.mockSql.ExpectBegin()
+	expectWipCheck(t.mockSql, blocked.Id, blocked.Status, "In Progress", 2, 2)
+	t.mockSql.ExpectRollback()
+
+	// Call the UpdateTaskByMember method.
+	_, err := t.repo.UpdateTaskByMember(blocked)
+
+	// Assertions
+	assert.ErrorIs(t.T(), err, shared_model.ErrWipLimit)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+// expectWipCheck mocks the column lock and WIP count that precede every status change.
+func expectWipCheck(mock sqlmock.Sqlmock, taskId string, status string, current string, limit int, count int) {
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs(taskId, status).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT t.status, COALESCE\(l.wip_limit, 0\)`).
+		WithArgs(taskId, status).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "wip_limit", "count"}).AddRow(current, limit, count))
+}
+
+func expectColumnWipCheck(mock sqlmock.Sqlmock, projectId string, status string, limit int, count int) {
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs(projectId, status).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COALESCE\(\(SELECT wip_limit FROM board_wip_limits`).
+		WithArgs(projectId, status).
+		WillReturnRows(sqlmock.NewRows([]string{"wip_limit", "count"}).AddRow(limit, count))
+}
+
 func (t *TaskRepositoryTestSuite) TestTaskRepository_DeleteTask_Success() {
 	// Mock the SQL query expectations for DeleteTask.
 	t.mockSql.ExpectQuery(`UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = \$1 AND deleted_at IS NULL`).
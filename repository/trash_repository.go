@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/report"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+type TrashRepository interface {
+	GetDeleted(entity string, page int, size int) ([]model.TrashItem, shared_model.Paging, error)
+	RestoreUser(id string) error
+	RestoreProject(id string, actorId string) error
+	RestoreTask(id string) error
+	RestoreReport(id string, actorId string) error
+	Purge(before time.Time) (model.PurgeResult, error)
+}
+
+type trashRepository struct {
+	db     *sql.DB
+	report report.ReportSink
+}
+
+var trashQueries = map[string][2]string{
+	"users":    {config.GetDeletedUsers, config.CountDeletedUsers},
+	"projects": {config.GetDeletedProjects, config.CountDeletedProjects},
+	"tasks":    {config.GetDeletedTasks, config.CountDeletedTasks},
+	"reports":  {config.GetDeletedReports, config.CountDeletedReports},
+}
+
+// GetDeleted implements TrashRepository.
+func (t *trashRepository) GetDeleted(entity string, page int, size int) ([]model.TrashItem, shared_model.Paging, error) {
+	queries, ok := trashQueries[entity]
+	if !ok {
+		return nil, shared_model.Paging{}, fmt.Errorf("unknown trash type %s", entity)
+	}
+
+	var items []model.TrashItem
+	offset := (page - 1) * size
+	rows, err := t.db.Query(queries[0], size, offset)
+	if err != nil {
+		log.Println("trash_repository.Query", err.Error())
+		return nil, shared_model.Paging{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := model.TrashItem{Type: entity}
+		err := rows.Scan(&item.Id, &item.Name, &item.DeletedAt)
+		if err != nil {
+			log.Println("trashRepository.Rows.Next", err.Error())
+			return nil, shared_model.Paging{}, err
+		}
+
+		items = append(items, item)
+	}
+
+	totalRows := 0
+
+	if err := t.db.QueryRow(queries[1]).Scan(&totalRows); err != nil {
+		return nil, shared_model.Paging{}, err
+	}
+
+	paging := shared_model.Paging{
+		Page:        page,
+		RowsPerPage: size,
+		TotalRows:   totalRows,
+		TotalPages:  int(math.Ceil(float64(totalRows) / float64(size))),
+	}
+
+	return items, paging, nil
+}
+
+// RestoreUser implements TrashRepository.
+func (t *trashRepository) RestoreUser(id string) error {
+	return t.restoreOne(config.RestoreUser, id)
+}
+
+// RestoreTask implements TrashRepository.
+func (t *trashRepository) RestoreTask(id string) error {
+	return t.restoreOne(config.RestoreTask, id)
+}
+
+// RestoreReport implements TrashRepository.
+func (t *trashRepository) RestoreReport(id string, actorId string) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("trash_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	reports, err := queryReports(tx, config.RestoreReport, id)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return sql.ErrNoRows
+	}
+
+	return commitJournaledReports(tx, t.report, actorId, reports, "restore")
+}
+
+// RestoreProject implements TrashRepository.
+// Tasks, their reports, memberships, milestones and sprints deleted together with (or after) the project are restored with it.
+// Every restored report is journaled.
+func (t *trashRepository) RestoreProject(id string, actorId string) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("trash_repository.Begin", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	if err := tx.QueryRow(config.GetProjectDeletedAt, id).Scan(&deletedAt); err != nil {
+		log.Println("trash_repository.QueryRow", err.Error())
+		return err
+	}
+
+	result, err := tx.Exec(config.RestoreProject, id)
+	if err != nil {
+		log.Println("trash_repository.Exec", err.Error())
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("project manager is deleted, restore the manager first")
+	}
+
+	if _, err := tx.Exec(config.RestoreProjectTasks, id, deletedAt); err != nil {
+		log.Println("trash_repository.Exec", err.Error())
+		return err
+	}
+
+	reports, err := queryReports(tx, config.RestoreProjectTaskReports, id, deletedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{config.RestoreProjectMemberships, config.RestoreProjectMilestones, config.RestoreProjectSprints, config.RestoreProjectFields} {
+		if _, err := tx.Exec(query, id, deletedAt); err != nil {
+			log.Println("trash_repository.Exec", err.Error())
+			return err
+		}
+	}
+
+	return commitJournaledReports(tx, t.report, actorId, reports, "restore")
+}
+
+// Purge implements TrashRepository.
+// Rows are removed children first and are skipped while anything still references them.
+// Purged reports are journaled without an actor.
+func (t *trashRepository) Purge(before time.Time) (model.PurgeResult, error) {
+	var result model.PurgeResult
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Println("trash_repository.Begin", err.Error())
+		return model.PurgeResult{}, err
+	}
+	defer tx.Rollback()
+
+	reports, err := queryReports(tx, config.PurgeReports, before)
+	if err != nil {
+		return model.PurgeResult{}, err
+	}
+	result.Reports = int64(len(reports))
+
+	steps := []struct {
+		query string
+		count *int64
+	}{
+		{config.PurgeTasks, &result.Tasks},
+		{config.PurgeProjectMembers, &result.ProjectMembers},
+		{config.PurgeMilestones, &result.Milestones},
+		{config.PurgeSprints, &result.Sprints},
+		{config.PurgeCustomFields, &result.CustomFields},
+		{config.PurgeProjects, &result.Projects},
+		{config.PurgeUsers, &result.Users},
+	}
+
+	for _, step := range steps {
+		res, err := tx.Exec(step.query, before)
+		if err != nil {
+			log.Println("trash_repository.Exec", err.Error())
+			return model.PurgeResult{}, err
+		}
+		*step.count, _ = res.RowsAffected()
+	}
+
+	if err := commitJournaledReports(tx, t.report, "", reports, "purge"); err != nil {
+		return model.PurgeResult{}, err
+	}
+
+	return result, nil
+}
+
+func (t *trashRepository) restoreOne(query string, id string) error {
+	result, err := t.db.Exec(query, id)
+	if err != nil {
+		log.Println("trash_repository.Exec", err.Error())
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func NewTrashRepository(db *sql.DB, report report.ReportSink) TrashRepository {
+	return &trashRepository{
+		db:     db,
+		report: report,
+	}
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	sink    *recordingSink
+	repo    TrashRepository
+}
+
+func (t *TrashRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.sink = &recordingSink{}
+	t.repo = NewTrashRepository(t.mockDB, t.sink)
+}
+
+func TestTrashRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashRepositoryTestSuite))
+}
+
+var deletedAt = time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+
+func (t *TrashRepositoryTestSuite) TestGetDeleted_Success() {
+	t.mockSql.ExpectQuery(`SELECT id, name, deleted_at FROM tasks WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT \$1 OFFSET \$2`).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow("1", "task1", deletedAt))
+	t.mockSql.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks WHERE deleted_at IS NOT NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	items, paging, err := t.repo.GetDeleted("tasks", 1, 10)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []model.TrashItem{{Id: "1", Type: "tasks", Name: "task1", DeletedAt: deletedAt}}, items)
+	assert.Equal(t.T(), 1, paging.TotalPages)
+}
+
+func (t *TrashRepositoryTestSuite) TestGetDeleted_UnknownType() {
+	_, _, err := t.repo.GetDeleted("comments", 1, 10)
+
+	assert.Error(t.T(), err)
+}
+
+func (t *TrashRepositoryTestSuite) TestRestoreTask_NotInTrash() {
+	t.mockSql.ExpectExec(`UPDATE tasks SET deleted_at = NULL`).
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := t.repo.RestoreTask("1")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+}
+
+func (t *TrashRepositoryTestSuite) TestRestoreReport_Journaled() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE reports SET deleted_at = NULL`).
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow("7", "2", "report", "3", nil, "pending", "", "", nil, deletedAt, deletedAt, 1))
+	t.mockSql.ExpectCommit()
+
+	err := t.repo.RestoreReport("7", "2")
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+	assert.Equal(t.T(), []string{"restore"}, t.sink.statuses)
+	assert.Equal(t.T(), "7", t.sink.changes[0].Content.Id)
+}
+
+func (t *TrashRepositoryTestSuite) TestRestoreReport_NotInTrash() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`UPDATE reports SET deleted_at = NULL`).
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}))
+	t.mockSql.ExpectRollback()
+
+	err := t.repo.RestoreReport("7", "2")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+	assert.Empty(t.T(), t.sink.statuses)
+}
+
+func (t *TrashRepositoryTestSuite) TestRestoreProject_Cascades() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`SELECT deleted_at FROM projects WHERE id = \$1 AND deleted_at IS NOT NULL`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+	t.mockSql.ExpectExec(`UPDATE projects SET deleted_at = NULL`).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE tasks SET deleted_at = NULL`).WithArgs("1", deletedAt).WillReturnResult(sqlmock.NewResult(0, 2))
+	t.mockSql.ExpectQuery(`UPDATE reports SET deleted_at = NULL`).
+		WithArgs("1", deletedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow("7", "2", "report", "3", nil, "pending", "", "", nil, deletedAt, deletedAt, 1))
+	t.mockSql.ExpectExec(`UPDATE project_members SET deleted_at = NULL`).WithArgs("1", deletedAt).WillReturnResult(sqlmock.NewResult(0, 2))
+	t.mockSql.ExpectExec(`UPDATE milestones SET deleted_at = NULL`).WithArgs("1", deletedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE sprints SET deleted_at = NULL`).WithArgs("1", deletedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`UPDATE custom_field_definitions SET deleted_at = NULL`).WithArgs("1", deletedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectCommit()
+
+	err := t.repo.RestoreProject("1", "2")
+
+	assert.NoError(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+	assert.Equal(t.T(), []string{"restore"}, t.sink.statuses)
+	assert.Equal(t.T(), "7", t.sink.changes[0].Content.Id)
+	assert.Equal(t.T(), "2", t.sink.changes[0].ActorId)
+}
+
+func (t *TrashRepositoryTestSuite) TestRestoreProject_ManagerDeleted() {
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`SELECT deleted_at FROM projects`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt))
+	t.mockSql.ExpectExec(`UPDATE projects SET deleted_at = NULL`).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectRollback()
+
+	err := t.repo.RestoreProject("1", "2")
+
+	assert.Error(t.T(), err)
+	assert.Empty(t.T(), t.sink.statuses)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
+
+func (t *TrashRepositoryTestSuite) TestPurge_Success() {
+	before := time.Now()
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`DELETE FROM reports`).
+		WithArgs(before).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "report", "task_id", "standup", "review_status", "review_comment", "reviewed_by", "reviewed_at", "created_at", "updated_at", "version"}).
+			AddRow("1", "2", "report", "3", nil, "pending", "", "", nil, deletedAt, deletedAt, 1).
+			AddRow("4", "2", "report", "3", nil, "pending", "", "", nil, deletedAt, deletedAt, 1).
+			AddRow("5", "2", "report", "3", nil, "pending", "", "", nil, deletedAt, deletedAt, 1).
+			AddRow("6", "2", "report", "3", nil, "pending", "", "", nil, deletedAt, deletedAt, 1))
+	t.mockSql.ExpectExec(`DELETE FROM tasks`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+	t.mockSql.ExpectExec(`DELETE FROM project_members`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+	t.mockSql.ExpectExec(`DELETE FROM milestones`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`DELETE FROM sprints`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectExec(`DELETE FROM custom_field_definitions`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectExec(`DELETE FROM projects`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 1))
+	t.mockSql.ExpectExec(`DELETE FROM users`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 0))
+	t.mockSql.ExpectCommit()
+
+	result, err := t.repo.Purge(before)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), model.PurgeResult{Reports: 4, Tasks: 3, ProjectMembers: 2, Milestones: 1, Projects: 1}, result)
+	assert.Equal(t.T(), []string{"purge", "purge", "purge", "purge"}, t.sink.statuses)
+}
+
+func (t *TrashRepositoryTestSuite) TestPurge_ErrorRollsBack() {
+	before := time.Now()
+	t.mockSql.ExpectBegin()
+	t.mockSql.ExpectQuery(`DELETE FROM reports`).WithArgs(before).WillReturnError(sql.ErrConnDone)
+	t.mockSql.ExpectRollback()
+
+	_, err := t.repo.Purge(before)
+
+	assert.Error(t.T(), err)
+	assert.NoError(t.T(), t.mockSql.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"math"
 
@@ -16,7 +17,8 @@ type UserRepository interface {
 	GetByEmail(email string) (model.User, error)
 	CreateUser(payload model.User) (model.User, error)
 	Update(payload model.User) (model.User, error)
-	Delete(id string) error
+	Delete(id string, reassign model.UserReassignment) error
+	GetDeleteImpact(id string) (model.DeleteImpact, error)
 }
 
 type userRepository struct {
@@ -24,15 +26,58 @@ type userRepository struct {
 }
 
 // Delete implements User.
-func (u *userRepository) Delete(id string) error {
-	//to do make query to delete user
-	_, err := u.db.Query(config.DeleteUserById, id)
+// Tasks and managed projects are handed over to the reassignment targets and the user's
+// memberships are removed in the same transaction as the user itself.
+func (u *userRepository) Delete(id string, reassign model.UserReassignment) error {
+	tx, err := u.db.Begin()
 	if err != nil {
-		log.Println("user_repository.Query", err.Error())
+		log.Println("user_repository.Begin", err.Error())
 		return err
 	}
+	defer tx.Rollback()
 
-	return nil
+	if reassign.TasksTo != "" {
+		if _, err := tx.Exec(config.EnsureReassignedMemberships, id, reassign.TasksTo); err != nil {
+			log.Println("user_repository.Exec", err.Error())
+			return err
+		}
+		if _, err := tx.Exec(config.ReassignUserTasks, id, reassign.TasksTo); err != nil {
+			log.Println("user_repository.Exec", err.Error())
+			return err
+		}
+	}
+
+	if reassign.ProjectsTo != "" {
+		if _, err := tx.Exec(config.ReassignUserProjects, id, reassign.ProjectsTo); err != nil {
+			log.Println("user_repository.Exec", err.Error())
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(config.DeleteUserMemberships, id); err != nil {
+		log.Println("user_repository.Exec", err.Error())
+		return err
+	}
+
+	if _, err := tx.Exec(config.DeleteUserById, id); err != nil {
+		log.Println("user_repository.Exec", err.Error())
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetDeleteImpact implements User.
+func (u *userRepository) GetDeleteImpact(id string) (model.DeleteImpact, error) {
+	var impact model.DeleteImpact
+
+	err := u.db.QueryRow(config.GetUserDeleteImpact, id).Scan(&impact.Projects, &impact.Tasks, &impact.Members)
+	if err != nil {
+		log.Println("user_repository.QueryRow", err.Error())
+		return model.DeleteImpact{}, err
+	}
+
+	return impact, nil
 }
 
 // GetAll implements User.
@@ -48,7 +93,7 @@ func (u *userRepository) GetAll(page int, size int) ([]model.User, shared_model.
 	for row.Next() {
 		user := model.User{}
 		//updated_at cannot be nil
-		err := row.Scan(&user.Id, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+		err := row.Scan(&user.Id, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 		if err != nil {
 			log.Println("userRepository.Rows.Next", err.Error())
 			return nil, shared_model.Paging{}, err
@@ -77,7 +122,7 @@ func (u *userRepository) GetAll(page int, size int) ([]model.User, shared_model.
 func (u *userRepository) GetByEmail(email string) (model.User, error) {
 	var user model.User
 
-	err := u.db.QueryRow(config.GetUserByEmail, email).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRow(config.GetUserByEmail, email).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 	if err != nil {
 		log.Println("user not found", err.Error())
 		return model.User{}, err
@@ -90,7 +135,7 @@ func (u *userRepository) GetByEmail(email string) (model.User, error) {
 func (u *userRepository) GetById(id string) (model.User, error) {
 	var user model.User
 
-	err := u.db.QueryRow(config.GetUserByID, id).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRow(config.GetUserByID, id).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 	if err != nil {
 		log.Println("user not found", err.Error())
 		return model.User{}, err
@@ -107,7 +152,7 @@ func (u *userRepository) GetById(id string) (model.User, error) {
 		for row.Next() {
 			project := model.Project{}
 			//updated_at cannot be nil
-			err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+			err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 			if err != nil {
 				log.Println("projectRepository.Rows.Next", err.Error())
 			}
@@ -125,7 +170,7 @@ func (u *userRepository) GetById(id string) (model.User, error) {
 		}
 		for row.Next() {
 			task := model.Task{}
-			err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt)
+			err := row.Scan(&task.Id, &task.Name, &task.Status, &task.Approval, &task.PersonInCharge, &task.Deadline, &task.ProjectId, &task.ApprovalDate, &task.Feedback, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.MilestoneId, &task.StoryPoints, &task.SprintId, &task.CustomFields, &task.Key)
 			if err != nil {
 				log.Println("taskRepository.Rows.Next", err.Error())
 			}
@@ -142,7 +187,7 @@ func (u *userRepository) GetById(id string) (model.User, error) {
 		for row.Next() {
 			project := model.Project{}
 			//updated_at cannot be nil
-			err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt)
+			err := row.Scan(&project.Id, &project.Name, &project.ManagerId, &project.Deadline, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.Key)
 			if err != nil {
 				log.Println("projectRepository.Rows.Next", err.Error())
 			}
@@ -160,7 +205,7 @@ func (u *userRepository) GetById(id string) (model.User, error) {
 func (u *userRepository) CreateUser(payload model.User) (model.User, error) {
 	var user model.User
 
-	err := u.db.QueryRow(config.CreateUser, payload.Name, payload.Email, payload.Password, payload.Role).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRow(config.CreateUser, payload.Name, payload.Email, payload.Password, payload.Role).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 	if err != nil {
 		log.Println("user_repository.QueryRow", err.Error())
 		return model.User{}, err
@@ -171,9 +216,12 @@ func (u *userRepository) CreateUser(payload model.User) (model.User, error) {
 // Update implements User.
 func (u *userRepository) Update(payload model.User) (model.User, error) {
 	var user model.User
-	err := u.db.QueryRow(config.UpdateUser, payload.Id, payload.Name, payload.Email, payload.Password, payload.Role).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := u.db.QueryRow(config.UpdateUser, payload.Id, payload.Name, payload.Email, payload.Password, payload.Role, payload.Version).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 	if err != nil {
 		log.Println("user_repository.QueryRow", err.Error())
+		if errors.Is(err, sql.ErrNoRows) && payload.Version != 0 {
+			return model.User{}, versionConflict(u.db.QueryRow(config.GetUserVersion, payload.Id))
+		}
 		return model.User{}, err
 	}
 	return user, nil
@@ -56,9 +56,9 @@ var userTestUpdate = model.User{
 // Test Get All User Success
 func (a *UserRepositoryTestSuite) TestUserRepository_GetAll_Success() {
 	// Mock the SQL query expectations for GetAll.
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "role", "created_at", "updated_at"}).
-		AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt)
-	a.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, role, created_at, updated_at FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`)).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "role", "created_at", "updated_at", "version"}).
+		AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt, userTest.Version)
+	a.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, role, created_at, updated_at, version FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`)).
 		WithArgs(10, 0).
 		WillReturnRows(rows)
 	a.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users WHERE deleted_at IS NULL`)).
@@ -76,7 +76,7 @@ func (a *UserRepositoryTestSuite) TestUserRepository_GetAll_Success() {
 
 // Test Get All User Failed
 func (a *UserRepositoryTestSuite) TestUserRepository_GetAll_Failed() {
-	a.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, role, created_at, updated_at FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`)).
+	a.mockSql.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, role, created_at, updated_at, version FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`)).
 		WithArgs(10, 0).
 		WillReturnError(sql.ErrConnDone)
 
@@ -92,9 +92,9 @@ func (a *UserRepositoryTestSuite) TestUserRepository_GetAll_Failed() {
 // Test Get All User Error Row Scan
 func (a *UserRepositoryTestSuite) TestUserRepository_GetAll_ErrorOnRowScan() {
 	// Mock the SQL query expectations for GetAll with an error on row scan.
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "role", "created_at", "updated_at"}).
-		AddRow("invalid_id", userTest.Name, userTest.Email, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt)
-	a.mockSql.ExpectQuery(`ELECT id, name, email, role, created_at, updated_at FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "role", "created_at", "updated_at", "version"}).
+		AddRow("invalid_id", userTest.Name, userTest.Email, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt, userTest.Version)
+	a.mockSql.ExpectQuery(`ELECT id, name, email, role, created_at, updated_at, version FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`).
 		WithArgs(10, 0).
 		WillReturnRows(rows)
 
@@ -110,8 +110,8 @@ func (a *UserRepositoryTestSuite) TestUserRepository_GetAll_ErrorOnRowScan() {
 
 // Test Get By ID Success
 func (a *UserRepositoryTestSuite) TestGeUsertById_Success() {
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Password, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt)
-	a.mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE id = $1 AND deleted_at IS NULL")).WithArgs(userTest.Id).WillReturnRows(rows)
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at", "version"}).AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Password, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt, userTest.Version)
+	a.mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, created_at, updated_at, version FROM users WHERE id = $1 AND deleted_at IS NULL")).WithArgs(userTest.Id).WillReturnRows(rows)
 	actual, err := a.repo.GetById(userTest.Id)
 	a.NoError(err)
 	a.Nil(err)
@@ -128,8 +128,8 @@ func (a *UserRepositoryTestSuite) TestGetUserById_Failed() {
 
 // Test Get By Email Success
 func (a *UserRepositoryTestSuite) TestGetUserByEmail_Success() {
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Password, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt)
-	a.mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL")).WithArgs(userTest.Email).WillReturnRows(rows)
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at", "version"}).AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Password, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt, userTest.Version)
+	a.mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, created_at, updated_at, version FROM users WHERE email = $1 AND deleted_at IS NULL")).WithArgs(userTest.Email).WillReturnRows(rows)
 	actual, err := a.repo.GetByEmail(userTest.Email)
 	a.NoError(err)
 	a.Nil(err)
@@ -139,7 +139,7 @@ func (a *UserRepositoryTestSuite) TestGetUserByEmail_Success() {
 // Test Get By Email Not Found
 func (a *UserRepositoryTestSuite) TestGetUserByEmail_UserNotFound() {
 	rows := sqlmock.NewRows([]string{})
-	a.mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL")).
+	a.mockSql.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, password, role, created_at, updated_at, version FROM users WHERE email = $1 AND deleted_at IS NULL")).
 		WithArgs(userTest.Email).
 		WillReturnRows(rows)
 
@@ -163,7 +163,7 @@ func (a *UserRepositoryTestSuite) TestGetUserByEmail_Failed() {
 // Test Create Success
 func (a *UserRepositoryTestSuite) TestCreateUser_Success() {
 
-	a.mockSql.ExpectQuery(regexp.QuoteMeta("INSERT INTO users(name, email, password, role, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, email, password, role, created_at, updated_at")).WithArgs(userTest.Name, userTest.Email, userTest.Password, userTest.Role).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Password, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt))
+	a.mockSql.ExpectQuery(regexp.QuoteMeta("INSERT INTO users(name, email, password, role, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, email, password, role, created_at, updated_at, version")).WithArgs(userTest.Name, userTest.Email, userTest.Password, userTest.Role).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at", "version"}).AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Password, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt, userTest.Version))
 	actual, err := a.repo.CreateUser(userTest)
 	a.NoError(err)
 	a.Equal(userTest.Name, actual.Name)
@@ -174,7 +174,7 @@ func (a *UserRepositoryTestSuite) TestCreateUser_Failed() {
 
 	expectedError := errors.New("insert user failed")
 
-	a.mockSql.ExpectQuery(regexp.QuoteMeta("INSERT INTO users(name, email, password, role, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, email, password, role, created_at, updated_at")).
+	a.mockSql.ExpectQuery(regexp.QuoteMeta("INSERT INTO users(name, email, password, role, updated_at) VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id, name, email, password, role, created_at, updated_at, version")).
 		WithArgs(userTest.Name, userTest.Email, userTest.Password, userTest.Role).
 		WillReturnError(expectedError)
 
@@ -188,10 +188,10 @@ func (a *UserRepositoryTestSuite) TestCreateUser_Failed() {
 // Test Update User Success
 func (a *UserRepositoryTestSuite) TestUpdateUser_Success() {
 	// Mock the SQL query expectations for UpdateTaskByManager.
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).
-		AddRow(userTestUpdate.Id, userTestUpdate.Name, userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role, userTestUpdate.CreatedAt, userTestUpdate.UpdatedAt)
-	a.mockSql.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET name = $2, email = $3, password = $4, role = $5, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING id, name, email, password, role, created_at, updated_at`)).
-		WithArgs(userTestUpdate.Id, userTestUpdate.Name, userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at", "version"}).
+		AddRow(userTestUpdate.Id, userTestUpdate.Name, userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role, userTestUpdate.CreatedAt, userTestUpdate.UpdatedAt, userTestUpdate.Version)
+	a.mockSql.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET name = $2, email = $3, password = $4, role = $5, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND ($6 = 0 OR version = $6) AND deleted_at IS NULL RETURNING id, name, email, password, role, created_at, updated_at, version`)).
+		WithArgs(userTestUpdate.Id, userTestUpdate.Name, userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role, userTestUpdate.Version).
 		WillReturnRows(rows)
 
 	updatedUser, err := a.repo.Update(userTestUpdate)
@@ -201,10 +201,10 @@ func (a *UserRepositoryTestSuite) TestUpdateUser_Success() {
 
 // Test Update Failed
 func (a *UserRepositoryTestSuite) TestUpdateUser_Failed() {
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).
-		AddRow(userTestUpdate.Id, "differentName", userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role, userTestUpdate.CreatedAt, userTestUpdate.UpdatedAt)
-	a.mockSql.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET name = $2, email = $3, password = $4, role = $5, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL RETURNING id, name, email, password, role, created_at, updated_at`)).
-		WithArgs(userTestUpdate.Id, userTestUpdate.Name, userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at", "version"}).
+		AddRow(userTestUpdate.Id, "differentName", userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role, userTestUpdate.CreatedAt, userTestUpdate.UpdatedAt, userTestUpdate.Version)
+	a.mockSql.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET name = $2, email = $3, password = $4, role = $5, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND ($6 = 0 OR version = $6) AND deleted_at IS NULL RETURNING id, name, email, password, role, created_at, updated_at, version`)).
+		WithArgs(userTestUpdate.Id, userTestUpdate.Name, userTestUpdate.Email, userTestUpdate.Password, userTestUpdate.Role, userTestUpdate.Version).
 		WillReturnRows(rows)
 
 	updatedUser, err := a.repo.Update(userTestUpdate)
@@ -215,12 +215,17 @@ func (a *UserRepositoryTestSuite) TestUpdateUser_Failed() {
 
 // Test Delete Success
 func (a *UserRepositoryTestSuite) TestDeleteUser_Success() {
-	a.mockSql.ExpectQuery(regexp.QuoteMeta("UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL")).
+	a.mockSql.ExpectBegin()
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE project_members SET deleted_at = CURRENT_TIMESTAMP WHERE member_id = $1 AND deleted_at IS NULL")).
 		WithArgs(userTest.Id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).AddRow(userTest.Id, userTest.Name, userTest.Email, userTest.Password, userTest.Role, userTest.CreatedAt, userTest.UpdatedAt)) // 1 row affected
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL")).
+		WithArgs(userTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1)) // 1 row affected
+	a.mockSql.ExpectCommit()
 
 	// Memanggil metode DeleteUser
-	err := a.repo.Delete(userTest.Id)
+	err := a.repo.Delete(userTest.Id, model.UserReassignment{})
 
 	// Verifikasi bahwa tidak ada error
 	a.NoError(err)
@@ -235,11 +240,16 @@ func (a *UserRepositoryTestSuite) TestDeleteUser_Failed() {
 	// Expected error message
 	expectedError := errors.New("delete user failed")
 
-	a.mockSql.ExpectQuery(regexp.QuoteMeta("UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL")).
+	a.mockSql.ExpectBegin()
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE project_members SET deleted_at = CURRENT_TIMESTAMP WHERE member_id = $1 AND deleted_at IS NULL")).
+		WithArgs(userTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL")).
 		WithArgs(userTest.Id).
 		WillReturnError(expectedError)
+	a.mockSql.ExpectRollback()
 
-	err := a.repo.Delete(userTest.Id)
+	err := a.repo.Delete(userTest.Id, model.UserReassignment{})
 
 	a.Error(err)
 	a.Equal(expectedError, err)
@@ -248,6 +258,45 @@ func (a *UserRepositoryTestSuite) TestDeleteUser_Failed() {
 	a.NoError(err)
 }
 
+// Test Delete with reassignment
+func (a *UserRepositoryTestSuite) TestDeleteUser_Reassign() {
+	reassign := model.UserReassignment{TasksTo: "2", ProjectsTo: "3"}
+	a.mockSql.ExpectBegin()
+	a.mockSql.ExpectExec(regexp.QuoteMeta("INSERT INTO project_members(member_id, project_id) SELECT DISTINCT $2::uuid, project_id FROM tasks")).
+		WithArgs(userTest.Id, reassign.TasksTo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET person_in_charge = $2")).
+		WithArgs(userTest.Id, reassign.TasksTo).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE projects SET manager_id = $2")).
+		WithArgs(userTest.Id, reassign.ProjectsTo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE project_members SET deleted_at = CURRENT_TIMESTAMP WHERE member_id = $1")).
+		WithArgs(userTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	a.mockSql.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1")).
+		WithArgs(userTest.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	a.mockSql.ExpectCommit()
+
+	err := a.repo.Delete(userTest.Id, reassign)
+
+	a.NoError(err)
+	a.NoError(a.mockSql.ExpectationsWereMet())
+}
+
+// Test Delete impact
+func (a *UserRepositoryTestSuite) TestGetDeleteImpact() {
+	a.mockSql.ExpectQuery(regexp.QuoteMeta("SELECT (SELECT COUNT(*) FROM projects WHERE manager_id = $1")).
+		WithArgs(userTest.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"projects", "tasks", "members"}).AddRow(1, 2, 3))
+
+	impact, err := a.repo.GetDeleteImpact(userTest.Id)
+
+	a.NoError(err)
+	a.Equal(model.DeleteImpact{Projects: 1, Tasks: 2, Members: 3}, impact)
+}
+
 // Test Suite
 func TestUserRepository(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"math"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository interface {
+	Create(payload model.Webhook) (model.Webhook, error)
+	GetById(id string) (model.Webhook, error)
+	GetByProjectId(projectId string) ([]model.Webhook, error)
+	Update(payload model.Webhook) (model.Webhook, error)
+	Delete(id string) error
+	Enqueue(event model.Event, payload []byte) (int64, error)
+	GetDueDeliveries(now time.Time, claimUntil time.Time, limit int) ([]model.WebhookDelivery, error)
+	SaveAttempt(delivery model.WebhookDelivery) error
+	GetDeliveries(webhookId string, page int, size int) ([]model.WebhookDelivery, shared_model.Paging, error)
+	GetDeliveryById(id string) (model.WebhookDelivery, error)
+	Redeliver(id string) (model.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+// Create implements WebhookRepository.
+func (w *webhookRepository) Create(payload model.Webhook) (model.Webhook, error) {
+	webhook, err := scanWebhook(w.db.QueryRow(config.CreateWebhook, payload.ProjectId, payload.Url, payload.Secret, pq.Array(payload.EventTypes), payload.Active, payload.CreatedBy))
+	if err != nil {
+		log.Println("webhook_repository.QueryRow", err.Error())
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// GetById implements WebhookRepository.
+func (w *webhookRepository) GetById(id string) (model.Webhook, error) {
+	webhook, err := scanWebhook(w.db.QueryRow(config.GetWebhookById, id))
+	if err != nil {
+		log.Println("webhook_repository.QueryRow", err.Error())
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// GetByProjectId implements WebhookRepository.
+// An empty projectId lists the global webhooks.
+func (w *webhookRepository) GetByProjectId(projectId string) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+
+	query, args := config.GetWebhooksByProjectId, []any{projectId}
+	if projectId == "" {
+		query, args = config.GetGlobalWebhooks, nil
+	}
+
+	rows, err := w.db.Query(query, args...)
+	if err != nil {
+		log.Println("webhook_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			log.Println("webhookRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+// Update implements WebhookRepository.
+// The project and the secret of a webhook never change.
+func (w *webhookRepository) Update(payload model.Webhook) (model.Webhook, error) {
+	webhook, err := scanWebhook(w.db.QueryRow(config.UpdateWebhook, payload.Id, payload.Url, pq.Array(payload.EventTypes), payload.Active))
+	if err != nil {
+		log.Println("webhook_repository.QueryRow", err.Error())
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// Delete implements WebhookRepository.
+// The delivery log of the webhook goes with it.
+func (w *webhookRepository) Delete(id string) error {
+	result, err := w.db.Exec(config.DeleteWebhook, id)
+	if err != nil {
+		log.Println("webhook_repository.Exec", err.Error())
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Enqueue implements WebhookRepository.
+// One delivery is queued for every active webhook subscribed to the event, and their number returned.
+func (w *webhookRepository) Enqueue(event model.Event, payload []byte) (int64, error) {
+	result, err := w.db.Exec(config.EnqueueWebhookEvent, event.ProjectId, event.Type, string(payload), event.FromProjectId)
+	if err != nil {
+		log.Println("webhook_repository.Exec", err.Error())
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetDueDeliveries implements WebhookRepository.
+// The returned deliveries are claimed by pushing their next attempt to claimUntil; rows another
+// instance is claiming at the same time are skipped, so each delivery is sent by one instance only.
+func (w *webhookRepository) GetDueDeliveries(now time.Time, claimUntil time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+
+	rows, err := w.db.Query(config.GetDueWebhookDeliveries, now, limit, claimUntil)
+	if err != nil {
+		log.Println("webhook_repository.Query", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		if err := rows.Scan(&delivery.Id, &delivery.WebhookId, &delivery.EventType, &delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt, &delivery.Url, &delivery.Secret); err != nil {
+			log.Println("webhookRepository.Rows.Next", err.Error())
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// SaveAttempt implements WebhookRepository.
+func (w *webhookRepository) SaveAttempt(delivery model.WebhookDelivery) error {
+	_, err := w.db.Exec(config.SaveWebhookAttempt, delivery.Id, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt)
+	if err != nil {
+		log.Println("webhook_repository.Exec", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveries implements WebhookRepository.
+// Newest first.
+func (w *webhookRepository) GetDeliveries(webhookId string, page int, size int) ([]model.WebhookDelivery, shared_model.Paging, error) {
+	var deliveries []model.WebhookDelivery
+
+	offset := (page - 1) * size
+	rows, err := w.db.Query(config.GetWebhookDeliveries, webhookId, size, offset)
+	if err != nil {
+		log.Println("webhook_repository.Query", err.Error())
+		return nil, shared_model.Paging{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			log.Println("webhookRepository.Rows.Next", err.Error())
+			return nil, shared_model.Paging{}, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	totalRows := 0
+	if err := w.db.QueryRow(config.CountWebhookDeliveries, webhookId).Scan(&totalRows); err != nil {
+		log.Println("webhook_repository.QueryRow", err.Error())
+		return nil, shared_model.Paging{}, err
+	}
+
+	paging := shared_model.Paging{
+		Page:        page,
+		RowsPerPage: size,
+		TotalRows:   totalRows,
+		TotalPages:  int(math.Ceil(float64(totalRows) / float64(size))),
+	}
+
+	return deliveries, paging, nil
+}
+
+// GetDeliveryById implements WebhookRepository.
+func (w *webhookRepository) GetDeliveryById(id string) (model.WebhookDelivery, error) {
+	delivery, err := scanWebhookDelivery(w.db.QueryRow(config.GetWebhookDeliveryById, id))
+	if err != nil {
+		log.Println("webhook_repository.QueryRow", err.Error())
+		return model.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// Redeliver implements WebhookRepository.
+// The payload is queued again as a new delivery so the log keeps the earlier attempts.
+func (w *webhookRepository) Redeliver(id string) (model.WebhookDelivery, error) {
+	delivery, err := scanWebhookDelivery(w.db.QueryRow(config.RedeliverWebhook, id))
+	if err != nil {
+		log.Println("webhook_repository.QueryRow", err.Error())
+		return model.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+func scanWebhook(row rowScanner) (model.Webhook, error) {
+	var webhook model.Webhook
+
+	err := row.Scan(&webhook.Id, &webhook.ProjectId, &webhook.Url, &webhook.Secret, pq.Array(&webhook.EventTypes), &webhook.Active, &webhook.CreatedBy, &webhook.CreatedAt, &webhook.UpdatedAt)
+	return webhook, err
+}
+
+func scanWebhookDelivery(row rowScanner) (model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+
+	err := row.Scan(&delivery.Id, &delivery.WebhookId, &delivery.EventType, &delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt)
+	return delivery, err
+}
+
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
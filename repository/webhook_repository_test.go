@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    WebhookRepository
+}
+
+func (t *WebhookRepositoryTestSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+	t.mockDB, t.mockSql = db, mock
+	t.repo = NewWebhookRepository(t.mockDB)
+}
+
+func TestWebhookRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
+
+var webhookColumns = []string{"id", "project_id", "url", "secret", "event_types", "active", "created_by", "created_at", "updated_at"}
+var deliveryColumns = []string{"id", "webhook_id", "event_type", "payload", "status", "attempts", "next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at"}
+var webhookCreatedAt = time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func (t *WebhookRepositoryTestSuite) TestCreate_Success() {
+	payload := model.Webhook{Url: "https://ci.example.com/hook", Secret: "s3cret", EventTypes: []string{model.EventTaskCreated}, Active: true, CreatedBy: "a1"}
+	t.mockSql.ExpectQuery(`INSERT INTO webhooks\(project_id, url, secret, event_types, active, created_by\)`).
+		WithArgs("", payload.Url, "s3cret", pq.Array(payload.EventTypes), true, "a1").
+		WillReturnRows(sqlmock.NewRows(webhookColumns).AddRow("w1", "", payload.Url, "s3cret", "{task.created}", true, "a1", webhookCreatedAt, webhookCreatedAt))
+
+	webhook, err := t.repo.Create(payload)
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "w1", webhook.Id)
+	assert.Equal(t.T(), []string{model.EventTaskCreated}, webhook.EventTypes)
+}
+
+func (t *WebhookRepositoryTestSuite) TestGetByProjectId_Global() {
+	t.mockSql.ExpectQuery(`SELECT .* FROM webhooks WHERE project_id IS NULL`).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).AddRow("w1", "", "https://chat.example.com", "s", "{}", true, "a1", webhookCreatedAt, webhookCreatedAt))
+
+	webhooks, err := t.repo.GetByProjectId("")
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), webhooks, 1)
+	assert.Empty(t.T(), webhooks[0].EventTypes)
+}
+
+func (t *WebhookRepositoryTestSuite) TestDelete_NotFound() {
+	t.mockSql.ExpectExec(`DELETE FROM webhooks WHERE id = \$1`).WithArgs("w1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := t.repo.Delete("w1")
+
+	assert.ErrorIs(t.T(), err, sql.ErrNoRows)
+}
+
+func (t *WebhookRepositoryTestSuite) TestEnqueue_MatchesSubscriptions() {
+	event := model.Event{Type: model.EventTaskCreated, ProjectId: "p1"}
+	t.mockSql.ExpectExec(`INSERT INTO webhook_deliveries\(webhook_id, event_type, payload\) SELECT id, \$2, \$3 FROM webhooks WHERE active`).
+		WithArgs("p1", model.EventTaskCreated, `{"type":"task.created"}`, "").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	count, err := t.repo.Enqueue(event, []byte(`{"type":"task.created"}`))
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), int64(2), count)
+}
+
+func (t *WebhookRepositoryTestSuite) TestGetDueDeliveries_Success() {
+	now := webhookCreatedAt.Add(time.Minute)
+	claimUntil := now.Add(30 * time.Minute)
+	t.mockSql.ExpectQuery(`FOR UPDATE SKIP LOCKED\) UPDATE webhook_deliveries d SET next_attempt_at = \$3 .* RETURNING d.id, d.webhook_id, .* w.url, w.secret`).
+		WithArgs(now, 50, claimUntil).
+		WillReturnRows(sqlmock.NewRows(append(deliveryColumns, "url", "secret")).
+			AddRow("d1", "w1", model.EventTaskCreated, []byte(`{"type":"task.created"}`), model.DeliveryPending, 0, webhookCreatedAt, 0, "", webhookCreatedAt, nil, "https://ci.example.com/hook", "s3cret"))
+
+	deliveries, err := t.repo.GetDueDeliveries(now, claimUntil, 50)
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), deliveries, 1)
+	assert.JSONEq(t.T(), `{"type":"task.created"}`, string(deliveries[0].Payload))
+	assert.Equal(t.T(), "s3cret", deliveries[0].Secret)
+}
+
+func (t *WebhookRepositoryTestSuite) TestRedeliver_Success() {
+	t.mockSql.ExpectQuery(`INSERT INTO webhook_deliveries\(webhook_id, event_type, payload\) SELECT webhook_id, event_type, payload FROM webhook_deliveries WHERE id = \$1`).
+		WithArgs("d1").
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).AddRow("d2", "w1", model.EventTaskCreated, []byte(`{}`), model.DeliveryPending, 0, webhookCreatedAt, 0, "", webhookCreatedAt, nil))
+
+	delivery, err := t.repo.Redeliver("d1")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "d2", delivery.Id)
+	assert.Equal(t.T(), model.DeliveryPending, delivery.Status)
+}
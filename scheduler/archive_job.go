@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/usecase"
+)
+
+// ArchiveJob moves old report files into monthly archives and deletes those past the maximum age.
+type ArchiveJob struct {
+	reportUC usecase.ReportUsecase
+	interval time.Duration
+}
+
+func NewArchiveJob(reportUC usecase.ReportUsecase, interval time.Duration) *ArchiveJob {
+	return &ArchiveJob{
+		reportUC: reportUC,
+		interval: interval,
+	}
+}
+
+// Start runs the job once, then in the background on every tick until ctx is cancelled.
+func (a *ArchiveJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+
+		a.run()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.run()
+			}
+		}
+	}()
+}
+
+func (a *ArchiveJob) run() {
+	if _, err := a.reportUC.ArchiveJournal(); err != nil {
+		log.Println("ArchiveJob.ArchiveJournal", err.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/usecase"
+)
+
+// MailJob flushes queued immediate emails on a short interval and sends each digest once it has waited the long one.
+// Digests are checked on the short tick against the age of the queued rows, so restarting the process does not postpone them.
+type MailJob struct {
+	mailUC         usecase.MailUsecase
+	sendInterval   time.Duration
+	digestInterval time.Duration
+}
+
+func NewMailJob(mailUC usecase.MailUsecase, sendInterval time.Duration, digestInterval time.Duration) *MailJob {
+	return &MailJob{
+		mailUC:         mailUC,
+		sendInterval:   sendInterval,
+		digestInterval: digestInterval,
+	}
+}
+
+// Start runs the job once, then in the background on every tick until ctx is cancelled.
+func (m *MailJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.sendInterval)
+		defer ticker.Stop()
+
+		m.run(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				m.run(now)
+			}
+		}
+	}()
+}
+
+func (m *MailJob) run(now time.Time) {
+	if _, err := m.mailUC.SendPending(); err != nil {
+		log.Println("MailJob.SendPending", err.Error())
+	}
+	if _, err := m.mailUC.SendDigests(now.Add(-m.digestInterval)); err != nil {
+		log.Println("MailJob.SendDigests", err.Error())
+	}
+}
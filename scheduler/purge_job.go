@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/usecase"
+)
+
+// PurgeJob hard-deletes trash rows older than the retention period on a fixed interval.
+type PurgeJob struct {
+	trashUC  usecase.TrashUsecase
+	interval time.Duration
+}
+
+func NewPurgeJob(trashUC usecase.TrashUsecase, interval time.Duration) *PurgeJob {
+	return &PurgeJob{
+		trashUC:  trashUC,
+		interval: interval,
+	}
+}
+
+// Start runs the job once, then in the background on every tick until ctx is cancelled.
+func (p *PurgeJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		p.run()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.run()
+			}
+		}
+	}()
+}
+
+func (p *PurgeJob) run() {
+	if _, err := p.trashUC.PurgeExpired(); err != nil {
+		log.Println("PurgeJob.PurgeExpired", err.Error())
+	}
+}
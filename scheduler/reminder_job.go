@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/usecase"
+)
+
+// ReminderJob sends due-date reminders and overdue escalations on a fixed interval.
+// Sent reminders are recorded, so running it more often than daily only catches new deadlines sooner.
+type ReminderJob struct {
+	reminderUC usecase.ReminderUsecase
+	interval   time.Duration
+}
+
+func NewReminderJob(reminderUC usecase.ReminderUsecase, interval time.Duration) *ReminderJob {
+	return &ReminderJob{
+		reminderUC: reminderUC,
+		interval:   interval,
+	}
+}
+
+// Start runs the job once, then in the background on every tick until ctx is cancelled.
+func (r *ReminderJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.run(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				r.run(now)
+			}
+		}
+	}()
+}
+
+func (r *ReminderJob) run(now time.Time) {
+	if _, err := r.reminderUC.SendDue(now); err != nil {
+		log.Println("ReminderJob.SendDue", err.Error())
+	}
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/usecase"
+)
+
+// StatusReportJob generates last week's status report of every project.
+// Projects that already have one are skipped, so the interval only decides how soon after Monday it runs.
+type StatusReportJob struct {
+	statusReportUC usecase.StatusReportUsecase
+	interval       time.Duration
+}
+
+func NewStatusReportJob(statusReportUC usecase.StatusReportUsecase, interval time.Duration) *StatusReportJob {
+	return &StatusReportJob{
+		statusReportUC: statusReportUC,
+		interval:       interval,
+	}
+}
+
+// Start runs the job in the background until ctx is cancelled.
+func (s *StatusReportJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := s.statusReportUC.GenerateWeekly(now); err != nil {
+					log.Println("StatusReportJob.GenerateWeekly", err.Error())
+				}
+			}
+		}
+	}()
+}
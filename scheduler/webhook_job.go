@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/usecase"
+)
+
+// WebhookJob posts queued webhook deliveries whose next attempt is due.
+type WebhookJob struct {
+	webhookUC usecase.WebhookUsecase
+	interval  time.Duration
+}
+
+func NewWebhookJob(webhookUC usecase.WebhookUsecase, interval time.Duration) *WebhookJob {
+	return &WebhookJob{
+		webhookUC: webhookUC,
+		interval:  interval,
+	}
+}
+
+// Start runs the job in the background until ctx is cancelled.
+func (w *WebhookJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := w.webhookUC.SendDue(now); err != nil {
+					log.Println("WebhookJob.SendDue", err.Error())
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/gin-gonic/gin"
+)
+
+// SetETag writes the resource version as a strong ETag header.
+func SetETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", fmt.Sprintf("%q", strconv.Itoa(version)))
+}
+
+// ParseIfMatch reads the If-Match header and returns the version it names. Without the header the
+// version sent in the body is used, and without either the update is refused with
+// shared_model.ErrPreconditionRequired. "*" returns 0, which disables the version check.
+func ParseIfMatch(ctx *gin.Context, bodyVersion int) (int, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		if bodyVersion < 1 {
+			return 0, shared_model.ErrPreconditionRequired
+		}
+		return bodyVersion, nil
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match header: %s", header)
+	}
+
+	return version, nil
+}
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 
 	"enigma.com/projectmanagementhub/shared/shared_model"
@@ -14,6 +16,26 @@ func SendErrorResponse(ctx *gin.Context, code int, message string) {
 	})
 }
 
+// ErrorStatus returns the HTTP status for a usecase error: 403 for shared_model.ErrForbidden,
+// 404 for sql.ErrNoRows, 412 for shared_model.ErrVersionConflict, 428 for shared_model.ErrPreconditionRequired,
+// 409 for shared_model.ErrWipLimit and shared_model.ErrKeyInUse, and fallback for anything else.
+func ErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, shared_model.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, shared_model.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, shared_model.ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, shared_model.ErrWipLimit), errors.Is(err, shared_model.ErrKeyInUse):
+		return http.StatusConflict
+	default:
+		return fallback
+	}
+}
+
 func SendSingleResponse(ctx *gin.Context, data interface{}, message string) {
 	ctx.JSON(http.StatusOK, shared_model.SingleResponse{
 		Status: shared_model.Status{
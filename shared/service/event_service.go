@@ -0,0 +1,44 @@
+package service
+
+import (
+	"sync"
+
+	"enigma.com/projectmanagementhub/model"
+)
+
+type EventHandler func(event model.Event)
+
+// EventService is the in-process notification channel; handlers receive every published event.
+type EventService interface {
+	Publish(event model.Event)
+	Subscribe(handler EventHandler)
+}
+
+type eventService struct {
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+// Publish implements EventService.
+// Handlers run in subscription order on the caller's goroutine and must not block.
+func (e *eventService) Publish(event model.Event) {
+	e.mu.RLock()
+	handlers := e.handlers
+	e.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Subscribe implements EventService.
+func (e *eventService) Subscribe(handler EventHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers = append(e.handlers, handler)
+}
+
+func NewEventService() EventService {
+	return &eventService{}
+}
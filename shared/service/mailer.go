@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"enigma.com/projectmanagementhub/config"
+	"enigma.com/projectmanagementhub/model"
+)
+
+// Mailer delivers rendered emails
+type Mailer interface {
+	Send(mail model.Mail) error
+}
+
+type smtpMailer struct {
+	cfg config.MailConfig
+}
+
+// Send implements Mailer.
+func (s *smtpMailer) Send(mail model.Mail) error {
+	message, err := buildMessage(s.cfg.MailFrom, mail)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.cfg.SmtpUser != "" {
+		auth = smtp.PlainAuth("", s.cfg.SmtpUser, s.cfg.SmtpPassword, s.cfg.SmtpHost)
+	}
+
+	if err := smtp.SendMail(s.cfg.SmtpHost+":"+s.cfg.SmtpPort, auth, s.cfg.MailFrom, []string{mail.To}, message); err != nil {
+		return fmt.Errorf("failed to send mail to %s: %v", mail.To, err)
+	}
+
+	return nil
+}
+
+// fileMailer writes every email as an .eml file into a local outbox directory instead of sending it
+type fileMailer struct {
+	cfg config.MailConfig
+}
+
+// Send implements Mailer.
+func (f *fileMailer) Send(mail model.Mail) error {
+	message, err := buildMessage(f.cfg.MailFrom, mail)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.cfg.MailOutboxDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create outbox: %v", err)
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(mail.To)
+	fileName := filepath.Join(f.cfg.MailOutboxDir, time.Now().Format("20060102T150405.000000000")+"-"+recipient+".eml")
+	if err := os.WriteFile(fileName, message, 0o644); err != nil {
+		return fmt.Errorf("failed to write mail to outbox: %v", err)
+	}
+
+	return nil
+}
+
+// buildMessage encodes mail as a multipart/alternative message with a text and an html part
+func buildMessage(from string, mail model.Mail) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", mail.TextBody},
+		{"text/html; charset=utf-8", mail.HtmlBody},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// NewMailer picks the implementation named by MAIL_DRIVER: "smtp", or the file outbox otherwise
+func NewMailer(cfg config.MailConfig) Mailer {
+	if cfg.MailDriver == "smtp" {
+		return &smtpMailer{cfg: cfg}
+	}
+	return &fileMailer{cfg: cfg}
+}
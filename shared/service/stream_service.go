@@ -0,0 +1,111 @@
+package service
+
+import (
+	"sync"
+
+	"enigma.com/projectmanagementhub/model"
+)
+
+// StreamFilter decides whether a subscription receives an event
+type StreamFilter func(event model.Event) bool
+
+// StreamSubscription is one connected real-time client.
+// Replay holds the buffered events the client missed since its last event id. Reset is set when
+// some of them are no longer buffered, in which case the client should reload its state.
+// Events is closed when the subscription ends, including when the client falls too far behind.
+type StreamSubscription struct {
+	Replay []model.StreamEvent
+	Reset  bool
+	Events <-chan model.StreamEvent
+}
+
+// StreamService fans published events out to real-time subscribers and keeps a
+// bounded history so reconnecting clients can resume.
+type StreamService interface {
+	Publish(event model.Event)
+	Subscribe(filter StreamFilter, lastEventId uint64) *StreamSubscription
+	Unsubscribe(subscription *StreamSubscription)
+}
+
+type streamSubscriber struct {
+	filter StreamFilter
+	events chan model.StreamEvent
+}
+
+type streamService struct {
+	mu          sync.Mutex
+	lastId      uint64
+	history     []model.StreamEvent
+	historySize int
+	subscribers map[*StreamSubscription]*streamSubscriber
+}
+
+// streamBufferSize is the number of events a subscriber may lag behind before it is dropped
+const streamBufferSize = 64
+
+// Publish implements StreamService.
+// It is subscribed to the event service, so it never blocks: a subscriber whose buffer is
+// full is disconnected and can resume from its last event id.
+func (s *streamService) Publish(event model.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastId++
+	streamEvent := model.StreamEvent{Id: s.lastId, Event: event}
+	s.history = append(s.history, streamEvent)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+
+	for subscription, subscriber := range s.subscribers {
+		if !subscriber.filter(event) {
+			continue
+		}
+		select {
+		case subscriber.events <- streamEvent:
+		default:
+			close(subscriber.events)
+			delete(s.subscribers, subscription)
+		}
+	}
+}
+
+// Subscribe implements StreamService.
+// A lastEventId of 0 starts with live events only.
+func (s *streamService) Subscribe(filter StreamFilter, lastEventId uint64) *StreamSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make(chan model.StreamEvent, streamBufferSize)
+	subscription := &StreamSubscription{Events: events}
+	if lastEventId > 0 {
+		// ids restart with the server, so an id from the future also means events were lost
+		subscription.Reset = lastEventId > s.lastId || (len(s.history) > 0 && s.history[0].Id > lastEventId+1)
+		for _, streamEvent := range s.history {
+			if streamEvent.Id > lastEventId && filter(streamEvent.Event) {
+				subscription.Replay = append(subscription.Replay, streamEvent)
+			}
+		}
+	}
+
+	s.subscribers[subscription] = &streamSubscriber{filter: filter, events: events}
+	return subscription
+}
+
+// Unsubscribe implements StreamService.
+func (s *streamService) Unsubscribe(subscription *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if subscriber, ok := s.subscribers[subscription]; ok {
+		close(subscriber.events)
+		delete(s.subscribers, subscription)
+	}
+}
+
+func NewStreamService(historySize int) StreamService {
+	return &streamService{
+		historySize: historySize,
+		subscribers: make(map[*StreamSubscription]*streamSubscriber),
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// WebhookClient posts signed event payloads to webhook endpoints
+type WebhookClient interface {
+	Post(url string, secret string, eventType string, deliveryId string, body []byte) (int, error)
+}
+
+type webhookClient struct {
+	client *http.Client
+}
+
+// Post implements WebhookClient.
+// The body is signed with the webhook secret so receivers can verify the X-PMH-Signature header.
+func (w *webhookClient) Post(url string, secret string, eventType string, deliveryId string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook url: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PMH-Webhook/1.0")
+	req.Header.Set("X-PMH-Event", eventType)
+	req.Header.Set("X-PMH-Delivery", deliveryId)
+	req.Header.Set("X-PMH-Signature", "sha256="+SignPayload(secret, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// SignPayload returns the hex encoded HMAC-SHA256 of body keyed with secret
+func SignPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BlockedWebhookIP reports whether ip is loopback, private, link-local or otherwise
+// not a public address, which webhooks must never reach.
+func BlockedWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// checkWebhookDial runs on every connection after the host name is resolved, so a webhook
+// whose name later resolves to an internal address is still refused, redirects included.
+func checkWebhookDial(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || BlockedWebhookIP(ip) {
+		return fmt.Errorf("webhook address %s is not allowed", host)
+	}
+	return nil
+}
+
+// NewWebhookClient returns a client that only connects to public addresses.
+// Proxies are not used, the dial check has to see the webhook host itself.
+func NewWebhookClient(timeout time.Duration) WebhookClient {
+	dialer := &net.Dialer{Timeout: timeout, Control: checkWebhookDial}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &webhookClient{client: &http.Client{Timeout: timeout, Transport: transport}}
+}
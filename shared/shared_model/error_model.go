@@ -0,0 +1,18 @@
+package shared_model
+
+import "errors"
+
+// ErrVersionConflict is returned when an update carries a version that no longer matches the stored row.
+var ErrVersionConflict = errors.New("resource has been modified by another request, reload and try again")
+
+// ErrPreconditionRequired is returned when an update names no version, neither in If-Match nor in the body.
+var ErrPreconditionRequired = errors.New("If-Match header or version is required")
+
+// ErrForbidden is returned when the caller is authenticated but not allowed to act on the resource.
+var ErrForbidden = errors.New("you are not allowed to access this resource")
+
+// ErrWipLimit is returned when a task is moved into a board column that is already full.
+var ErrWipLimit = errors.New("the column has reached its WIP limit")
+
+// ErrKeyInUse is returned when a project is created with a key another project already has.
+var ErrKeyInUse = errors.New("project key already in use")
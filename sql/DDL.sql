@@ -6,6 +6,10 @@ CREATE TYPE role_type AS ENUM ('ADMIN','MANAGER', 'TEAM MEMBER');
 
 CREATE TYPE task_status AS ENUM('In Progress', 'Blocked', 'Waiting Approval', 'Accepted', 'Rejected', 'On Hold');
 
+CREATE TYPE sprint_state AS ENUM('planned', 'active', 'closed');
+
+CREATE TYPE custom_field_type AS ENUM('text', 'number', 'date', 'single_select', 'multi_select', 'user');
+
 CREATE TABLE users (
     id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
@@ -14,7 +18,8 @@ CREATE TABLE users (
     role role_type NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL,
-    deleted_at TIMESTAMP
+    deleted_at TIMESTAMP,
+    version INT NOT NULL DEFAULT 1
 );
 
 CREATE TABLE projects (
@@ -22,9 +27,12 @@ CREATE TABLE projects (
     name VARCHAR(255) NOT NULL,
     manager_id UUID NOT NULL,
     deadline DATE NOT NULL,
+    key VARCHAR(10) NOT NULL UNIQUE,
+    next_task_number INT NOT NULL DEFAULT 1,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL,
     deleted_at TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
     FOREIGN KEY (manager_id) REFERENCES users(id)
 );
 
@@ -40,6 +48,55 @@ CREATE TABLE project_members (
 );
 
 
+CREATE TABLE milestones (
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    project_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    due_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+
+
+CREATE TABLE sprints (
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    project_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    state sprint_state NOT NULL DEFAULT 'planned',
+    committed_tasks INT NOT NULL DEFAULT 0,
+    committed_points INT NOT NULL DEFAULT 0,
+    completed_tasks INT NOT NULL DEFAULT 0,
+    completed_points INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+
+
+CREATE TABLE custom_field_definitions (
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    project_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    field_type custom_field_type NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+
+-- values are keyed by name, so names only need to be unique among live definitions
+CREATE UNIQUE INDEX custom_field_definitions_project_name ON custom_field_definitions(project_id, name) WHERE deleted_at IS NULL;
+
+
 CREATE TABLE tasks (
     id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
@@ -53,8 +110,76 @@ CREATE TABLE tasks (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL,
     deleted_at TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    milestone_id UUID,
+    story_points INT NOT NULL DEFAULT 0,
+    sprint_id UUID,
+    rank DOUBLE PRECISION NOT NULL DEFAULT 0,
+    custom_fields JSONB NOT NULL DEFAULT '{}',
+    task_key VARCHAR(32) NOT NULL UNIQUE,
     FOREIGN KEY (person_in_charge) REFERENCES users(id),
-    FOREIGN KEY (project_id) REFERENCES projects(id)
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (milestone_id) REFERENCES milestones(id),
+    FOREIGN KEY (sprint_id) REFERENCES sprints(id)
+);
+
+
+-- every key a task ever had, so keys keep resolving after a task moves to another project
+CREATE TABLE task_keys (
+    key VARCHAR(32) PRIMARY KEY,
+    task_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+
+CREATE TABLE task_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    task_id UUID NOT NULL,
+    status task_status NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+-- seed the history of tasks created before it was recorded
+INSERT INTO task_status_history(task_id, status, changed_at) SELECT id, status, updated_at FROM tasks;
+
+
+CREATE TABLE board_wip_limits (
+    project_id UUID NOT NULL,
+    status task_status NOT NULL,
+    wip_limit INT NOT NULL,
+    PRIMARY KEY (project_id, status),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+
+CREATE TABLE reminder_settings (
+    project_id UUID PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    days_before INT NOT NULL,
+    escalate_after_days INT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+-- one row per reminder sent, so each fires once for a given deadline
+CREATE TABLE task_reminders (
+    task_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    deadline DATE NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, kind, deadline),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE TABLE project_reminders (
+    project_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    deadline DATE NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, kind, deadline),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
 );
 
 
@@ -63,9 +188,115 @@ CREATE TABLE reports (
     user_id UUID NOT NULL,
     report TEXT NOT NULL,
     task_id UUID NOT NULL,
+    standup JSONB,
+    review_status VARCHAR(20) NOT NULL DEFAULT 'submitted',
+    review_comment TEXT NOT NULL DEFAULT '',
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL,
     deleted_at TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
     FOREIGN KEY (user_id) REFERENCES users(id),
-    FOREIGN KEY (task_id) REFERENCES tasks(id)
-);
\ No newline at end of file
+    FOREIGN KEY (task_id) REFERENCES tasks(id),
+    FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX reports_task ON reports(task_id, created_at) WHERE deleted_at IS NULL;
+CREATE INDEX reports_user ON reports(user_id, created_at) WHERE deleted_at IS NULL;
+CREATE INDEX reports_created_at ON reports(created_at) WHERE deleted_at IS NULL;
+
+CREATE TABLE notifications (
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    user_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    project_id UUID,
+    task_id UUID,
+    message TEXT NOT NULL,
+    data JSONB,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE SET NULL
+);
+
+CREATE INDEX notifications_user_created ON notifications(user_id, created_at DESC);
+
+-- event types a user opted out of, or back into; types without a row are enabled
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event_type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- how each user wants email: 'off', 'immediate' or 'digest'; users without a row get the server default
+CREATE TABLE mail_settings (
+    user_id UUID PRIMARY KEY,
+    mode VARCHAR(20) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE mail_queue (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    digest BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX mail_queue_pending ON mail_queue(digest, created_at) WHERE sent_at IS NULL;
+
+
+-- project_id NULL subscribes to every project, an empty event_types to every event type
+CREATE TABLE webhooks (
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    project_id UUID,
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE webhook_deliveries (
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    webhook_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+
+-- rendered project status reports, one per project and period; regenerating a period replaces it
+CREATE TABLE status_reports (
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    project_id UUID NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    markdown TEXT NOT NULL,
+    html TEXT NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, start_date, end_date),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
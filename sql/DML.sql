@@ -9,13 +9,13 @@ VALUES
   ('User4', 'user4@example.com', 'user4_password', 'TEAM MEMBER', CURRENT_TIMESTAMP);
 
 --Input Project
-INSERT INTO projects (name, manager_id, deadline, updated_at)
+INSERT INTO projects (name, manager_id, deadline, key, updated_at)
 VALUES 
-    ('Project Web', 'f3bb0a14-b760-45c6-a0bb-e9b2e3e3f178', '2024-02-05', CURRENT_TIMESTAMP),
-    ('Mobile App Project', 'f3bb0a14-b760-45c6-a0bb-e9b2e3e3f178', '2024-03-15', CURRENT_TIMESTAMP),
-    ('Data Analytics Web', 'e2c7508e-edb6-408f-968f-5a67748b18c3', '2024-04-30', CURRENT_TIMESTAMP),
-    ('Infrastructure Upgrade', 'e2c7508e-edb6-408f-968f-5a67748b18c3', '2024-05-10', CURRENT_TIMESTAMP),
-    ('E-commerce Platform', 'e2c7508e-edb6-408f-968f-5a67748b18c3', '2024-06-20', CURRENT_TIMESTAMP);
+    ('Project Web', 'f3bb0a14-b760-45c6-a0bb-e9b2e3e3f178', '2024-02-05', 'WEB', CURRENT_TIMESTAMP),
+    ('Mobile App Project', 'f3bb0a14-b760-45c6-a0bb-e9b2e3e3f178', '2024-03-15', 'MAP', CURRENT_TIMESTAMP),
+    ('Data Analytics Web', 'e2c7508e-edb6-408f-968f-5a67748b18c3', '2024-04-30', 'DAW', CURRENT_TIMESTAMP),
+    ('Infrastructure Upgrade', 'e2c7508e-edb6-408f-968f-5a67748b18c3', '2024-05-10', 'IU', CURRENT_TIMESTAMP),
+    ('E-commerce Platform', 'e2c7508e-edb6-408f-968f-5a67748b18c3', '2024-06-20', 'ECP', CURRENT_TIMESTAMP);
 
 --Input Project Member
 INSERT INTO project_members (member_id, project_id)
@@ -25,10 +25,14 @@ VALUES
 ('70913634-660b-4139-91fd-596d8e00c896', '7bdf1090-033f-497b-b161-bb4ce62c422d');
 
 --Input Task
-INSERT INTO tasks (name, status, approval, person_in_charge, deadline, project_Id, updated_at)
+INSERT INTO tasks (name, status, approval, person_in_charge, deadline, project_Id, task_key, updated_at)
 VALUES
-('fitur-login', 1, false, 'c3086749-8326-49bf-bb13-803694731708', '2024-01-31', '7bdf1090-033f-497b-b161-bb4ce62c422d', CURRENT_TIMESTAMP),
-('fitur-search-page', 1, false, 'c3086749-8326-49bf-bb13-803694731708','2024-02-01', '7bdf1090-033f-497b-b161-bb4ce62c422d', CURRENT_TIMESTAMP),
-('fitur-create-task', 1, false, 'c3086749-8326-49bf-bb13-803694731708', '2024-02-01', '7bdf1090-033f-497b-b161-bb4ce62c422d', CURRENT_TIMESTAMP),
-('fitur-update-task', 1, false, '70913634-660b-4139-91fd-596d8e00c896', '2024-02-02', '7bdf1090-033f-497b-b161-bb4ce62c422d', CURRENT_TIMESTAMP),
-('fitur-delete-task', 1, false, '70913634-660b-4139-91fd-596d8e00c896', '2024-02-02', '7bdf1090-033f-497b-b161-bb4ce62c422d', CURRENT_TIMESTAMP);
\ No newline at end of file
+('fitur-login', 1, false, 'c3086749-8326-49bf-bb13-803694731708', '2024-01-31', '7bdf1090-033f-497b-b161-bb4ce62c422d', 'WEB-1', CURRENT_TIMESTAMP),
+('fitur-search-page', 1, false, 'c3086749-8326-49bf-bb13-803694731708','2024-02-01', '7bdf1090-033f-497b-b161-bb4ce62c422d', 'WEB-2', CURRENT_TIMESTAMP),
+('fitur-create-task', 1, false, 'c3086749-8326-49bf-bb13-803694731708', '2024-02-01', '7bdf1090-033f-497b-b161-bb4ce62c422d', 'WEB-3', CURRENT_TIMESTAMP),
+('fitur-update-task', 1, false, '70913634-660b-4139-91fd-596d8e00c896', '2024-02-02', '7bdf1090-033f-497b-b161-bb4ce62c422d', 'WEB-4', CURRENT_TIMESTAMP),
+('fitur-delete-task', 1, false, '70913634-660b-4139-91fd-596d8e00c896', '2024-02-02', '7bdf1090-033f-497b-b161-bb4ce62c422d', 'WEB-5', CURRENT_TIMESTAMP);
+
+--Task keys
+UPDATE projects SET next_task_number = 6 WHERE key = 'WEB';
+INSERT INTO task_keys (key, task_id) SELECT task_key, id FROM tasks;
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"fmt"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
+)
+
+type BoardUsecase interface {
+	GetBoard(projectId string) (model.Board, error)
+	MoveTask(userId string, move model.BoardMove) (model.Task, error)
+	SetWipLimits(userId string, projectId string, limits map[string]int) error
+}
+
+type boardUsecase struct {
+	boardRepository   repository.BoardRepository
+	taskRepository    repository.TaskRepository
+	userRepository    repository.UserRepository
+	projectRepository repository.ProjectRepository
+	eventService      service.EventService
+}
+
+// GetBoard implements BoardUsecase.
+func (b *boardUsecase) GetBoard(projectId string) (model.Board, error) {
+	if _, err := b.projectRepository.GetById(projectId); err != nil {
+		return model.Board{}, fmt.Errorf("failed to get board. project id invalid")
+	}
+
+	tasks, err := b.boardRepository.GetTasks(projectId)
+	if err != nil {
+		return model.Board{}, fmt.Errorf("failed to get board: %s", err.Error())
+	}
+
+	limits, err := b.boardRepository.GetWipLimits(projectId)
+	if err != nil {
+		return model.Board{}, fmt.Errorf("failed to get board: %s", err.Error())
+	}
+
+	board := model.Board{ProjectId: projectId}
+	for _, status := range taskStatuses {
+		column := model.BoardColumn{Status: status, WipLimit: limits[status], Tasks: []model.Task{}}
+		for _, task := range tasks {
+			if task.Status == status {
+				column.Tasks = append(column.Tasks, task)
+			}
+		}
+		column.Count = len(column.Tasks)
+		board.Columns = append(board.Columns, column)
+	}
+
+	return board, nil
+}
+
+// MoveTask implements BoardUsecase.
+// Managers may only move tasks of the projects they manage; members only the tasks they are in charge of.
+func (b *boardUsecase) MoveTask(userId string, move model.BoardMove) (model.Task, error) {
+	if !validTaskStatus(move.Status) {
+		return model.Task{}, fmt.Errorf("invalid status type. status type: ('In Progress', 'Blocked', 'Waiting Approval', 'Accepted', 'Rejected', 'On Hold')")
+	}
+
+	user, err := b.userRepository.GetById(userId)
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to move task. user id invalid")
+	}
+
+	task, err := b.taskRepository.GetById(move.TaskId)
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to move task. task id invalid")
+	}
+	if user.Role == "MANAGER" {
+		if err := checkProjectManager(b.projectRepository, task.ProjectId, userId); err != nil {
+			return model.Task{}, fmt.Errorf("failed to move task: %w", err)
+		}
+	} else if task.PersonInCharge != userId {
+		return model.Task{}, fmt.Errorf("only person in charge and project manager can move task")
+	}
+	// moving into "Accepted" approves the task
+	if user.Role != "MANAGER" && move.Status == "Accepted" && task.Status != "Accepted" {
+		return model.Task{}, fmt.Errorf("only project manager can accept task")
+	}
+	move.ProjectId = task.ProjectId
+
+	moved, err := b.boardRepository.MoveTask(move)
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to move task: %w", err)
+	}
+
+	publishTaskChanges(b.eventService, userId, task, moved)
+	return moved, nil
+}
+
+// SetWipLimits implements BoardUsecase.
+func (b *boardUsecase) SetWipLimits(userId string, projectId string, limits map[string]int) error {
+	if len(limits) == 0 {
+		return fmt.Errorf("failed to set wip limits. field 'limits' cannot be empty")
+	}
+	for status, limit := range limits {
+		if !validTaskStatus(status) {
+			return fmt.Errorf("failed to set wip limits. invalid status %s", status)
+		}
+		if limit < 0 {
+			return fmt.Errorf("failed to set wip limits. limit cannot be negative")
+		}
+	}
+
+	if err := checkProjectManager(b.projectRepository, projectId, userId); err != nil {
+		return fmt.Errorf("failed to set wip limits: %w", err)
+	}
+
+	if err := b.boardRepository.SetWipLimits(projectId, limits); err != nil {
+		return fmt.Errorf("failed to set wip limits: %s", err.Error())
+	}
+
+	return nil
+}
+
+func NewBoardUsecase(boardRepository repository.BoardRepository, taskRepository repository.TaskRepository, userRepository repository.UserRepository, projectRepository repository.ProjectRepository, eventService service.EventService) BoardUsecase {
+	return &boardUsecase{
+		boardRepository:   boardRepository,
+		taskRepository:    taskRepository,
+		userRepository:    userRepository,
+		projectRepository: projectRepository,
+		eventService:      eventService,
+	}
+}
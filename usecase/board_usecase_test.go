@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BoardUsecaseTest struct {
+	suite.Suite
+	brm *repository_mock.BoardRepositoryMock
+	trm *repository_mock.TaskRepositoryMock
+	urm *repository_mock.UserRepositoryMock
+	prm *repository_mock.ProjectRepositoryMock
+	buc BoardUsecase
+}
+
+func (b *BoardUsecaseTest) SetupTest() {
+	b.brm = new(repository_mock.BoardRepositoryMock)
+	b.trm = new(repository_mock.TaskRepositoryMock)
+	b.urm = new(repository_mock.UserRepositoryMock)
+	b.prm = new(repository_mock.ProjectRepositoryMock)
+	b.buc = NewBoardUsecase(b.brm, b.trm, b.urm, b.prm, service.NewEventService())
+}
+
+func TestBoardUsecase(t *testing.T) {
+	suite.Run(t, new(BoardUsecaseTest))
+}
+
+var boardTask = model.Task{Id: "t1", Status: "In Progress", PersonInCharge: "member1", ProjectId: "p1"}
+
+func (b *BoardUsecaseTest) TestGetBoard_GroupsByStatus() {
+	b.prm.On("GetById", "p1").Return(model.Project{Id: "p1"}, nil)
+	b.brm.On("GetTasks", "p1").Return([]model.Task{boardTask, {Id: "t2", Status: "Blocked"}, {Id: "t3", Status: "In Progress"}}, nil)
+	b.brm.On("GetWipLimits", "p1").Return(map[string]int{"In Progress": 3}, nil)
+
+	board, err := b.buc.GetBoard("p1")
+
+	assert.NoError(b.T(), err)
+	assert.Len(b.T(), board.Columns, 6)
+	assert.Equal(b.T(), "In Progress", board.Columns[0].Status)
+	assert.Equal(b.T(), 3, board.Columns[0].WipLimit)
+	assert.Equal(b.T(), []string{"t1", "t3"}, []string{board.Columns[0].Tasks[0].Id, board.Columns[0].Tasks[1].Id})
+	assert.Equal(b.T(), 1, board.Columns[1].Count)
+}
+
+func (b *BoardUsecaseTest) TestMoveTask_WipLimitReached() {
+	b.urm.On("GetById", "member1").Return(model.User{Id: "member1", Role: "TEAM MEMBER"}, nil)
+	b.trm.On("GetById", "t1").Return(boardTask, nil)
+	b.brm.On("MoveTask", model.BoardMove{TaskId: "t1", ProjectId: "p1", Status: "Waiting Approval", Position: 0}).Return(model.Task{}, shared_model.ErrWipLimit)
+
+	_, err := b.buc.MoveTask("member1", model.BoardMove{TaskId: "t1", Status: "Waiting Approval"})
+
+	assert.True(b.T(), errors.Is(err, shared_model.ErrWipLimit))
+}
+
+func (b *BoardUsecaseTest) TestMoveTask_MemberCannotAccept() {
+	b.urm.On("GetById", "member1").Return(model.User{Id: "member1", Role: "TEAM MEMBER"}, nil)
+	b.trm.On("GetById", "t1").Return(boardTask, nil)
+
+	_, err := b.buc.MoveTask("member1", model.BoardMove{TaskId: "t1", Status: "Accepted"})
+
+	assert.Error(b.T(), err)
+	b.brm.AssertNotCalled(b.T(), "MoveTask", mock.Anything)
+}
+
+func (b *BoardUsecaseTest) TestMoveTask_NotPersonInCharge() {
+	b.urm.On("GetById", "member2").Return(model.User{Id: "member2", Role: "TEAM MEMBER"}, nil)
+	b.trm.On("GetById", "t1").Return(boardTask, nil)
+
+	_, err := b.buc.MoveTask("member2", model.BoardMove{TaskId: "t1", Status: "Blocked"})
+
+	assert.Error(b.T(), err)
+	b.brm.AssertNotCalled(b.T(), "MoveTask", mock.Anything)
+}
+
+func (b *BoardUsecaseTest) TestMoveTask_ManagerOfAnotherProject() {
+	b.urm.On("GetById", "manager2").Return(model.User{Id: "manager2", Role: "MANAGER"}, nil)
+	b.trm.On("GetById", "t1").Return(boardTask, nil)
+	b.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "manager1"}, nil)
+
+	_, err := b.buc.MoveTask("manager2", model.BoardMove{TaskId: "t1", Status: "Accepted"})
+
+	assert.ErrorIs(b.T(), err, shared_model.ErrForbidden)
+	b.brm.AssertNotCalled(b.T(), "MoveTask", mock.Anything)
+}
+
+func (b *BoardUsecaseTest) TestSetWipLimits_InvalidStatus() {
+	err := b.buc.SetWipLimits("manager1", "p1", map[string]int{"Done": 3})
+
+	assert.Error(b.T(), err)
+	b.brm.AssertNotCalled(b.T(), "SetWipLimits", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+)
+
+// maxBurnChartDays bounds the days a burn chart covers, so a far-off deadline cannot blow up its series
+const maxBurnChartDays = 3660
+
+type ChartUsecase interface {
+	ProjectBurn(projectId string) (model.BurnChart, error)
+	SprintBurn(sprintId string) (model.BurnChart, error)
+}
+
+type chartUsecase struct {
+	chartRepository   repository.ChartRepository
+	projectRepository repository.ProjectRepository
+	sprintRepository  repository.SprintRepository
+	now               func() time.Time
+}
+
+// ProjectBurn implements ChartUsecase.
+// The series runs from the project's creation to today, the ideal line up to the deadline.
+func (c *chartUsecase) ProjectBurn(projectId string) (model.BurnChart, error) {
+	project, err := c.projectRepository.GetById(projectId)
+	if err != nil {
+		return model.BurnChart{}, fmt.Errorf("failed to get burn chart. project id invalid")
+	}
+
+	end, err := parseDate(project.Deadline)
+	if err != nil {
+		return model.BurnChart{}, fmt.Errorf("failed to get burn chart. invalid project deadline")
+	}
+	start := truncateDay(project.CreatedAt)
+	today := truncateDay(c.now())
+	if err := checkBurnPeriod(start, end, today); err != nil {
+		return model.BurnChart{}, err
+	}
+
+	tasks, err := c.chartRepository.GetProjectTasks(projectId)
+	if err != nil {
+		return model.BurnChart{}, fmt.Errorf("failed to get burn chart: %s", err.Error())
+	}
+
+	return buildBurnChart("project", projectId, tasks, start, end, today), nil
+}
+
+// SprintBurn implements ChartUsecase.
+// The series is limited to the sprint's time-box and the tasks currently in it.
+func (c *chartUsecase) SprintBurn(sprintId string) (model.BurnChart, error) {
+	sprint, err := c.sprintRepository.GetById(sprintId)
+	if err != nil {
+		return model.BurnChart{}, fmt.Errorf("failed to get burn chart. sprint id invalid")
+	}
+
+	start, err := parseDate(sprint.StartDate)
+	if err != nil {
+		return model.BurnChart{}, fmt.Errorf("failed to get burn chart. invalid sprint start date")
+	}
+	end, err := parseDate(sprint.EndDate)
+	if err != nil {
+		return model.BurnChart{}, fmt.Errorf("failed to get burn chart. invalid sprint end date")
+	}
+	if err := checkBurnPeriod(start, end, end); err != nil {
+		return model.BurnChart{}, err
+	}
+
+	tasks, err := c.chartRepository.GetSprintTasks(sprintId)
+	if err != nil {
+		return model.BurnChart{}, fmt.Errorf("failed to get burn chart: %s", err.Error())
+	}
+
+	today := truncateDay(c.now())
+	if today.After(end) {
+		today = end
+	}
+
+	return buildBurnChart("sprint", sprintId, tasks, start, end, today), nil
+}
+
+// buildBurnChart replays the status history day by day. A task counts as completed on a day
+// when its last status change before the end of that day is 'Accepted'.
+func buildBurnChart(scope string, id string, tasks []model.ChartTask, start time.Time, end time.Time, until time.Time) model.BurnChart {
+	chart := model.BurnChart{
+		Scope:     scope,
+		ScopeId:   id,
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+		Actual:    []model.BurnPoint{},
+		Ideal:     []model.IdealPoint{},
+	}
+
+	for day := start; !day.After(until); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		point := model.BurnPoint{Date: day.Format("2006-01-02")}
+
+		for _, task := range tasks {
+			if !task.CreatedAt.Before(dayEnd) {
+				continue
+			}
+			point.TotalTasks++
+			point.TotalPoints += task.StoryPoints
+
+			status := ""
+			for _, change := range task.History {
+				if change.ChangedAt.Before(dayEnd) {
+					status = change.Status
+				}
+			}
+			if status == "Accepted" {
+				point.CompletedTasks++
+				point.CompletedPoints += task.StoryPoints
+			}
+		}
+
+		point.RemainingTasks = point.TotalTasks - point.CompletedTasks
+		point.RemainingPoints = point.TotalPoints - point.CompletedPoints
+		chart.Actual = append(chart.Actual, point)
+	}
+
+	// the ideal line burns the current scope down to zero on the end date
+	var scopeTasks, scopePoints float64
+	if len(chart.Actual) > 0 {
+		last := chart.Actual[len(chart.Actual)-1]
+		scopeTasks, scopePoints = float64(last.TotalTasks), float64(last.TotalPoints)
+	}
+
+	days := int(end.Sub(start).Hours() / 24)
+	for i := 0; i <= days; i++ {
+		left := 1.0
+		if days > 0 {
+			left = float64(days-i) / float64(days)
+		}
+		chart.Ideal = append(chart.Ideal, model.IdealPoint{
+			Date:            start.AddDate(0, 0, i).Format("2006-01-02"),
+			RemainingTasks:  scopeTasks * left,
+			RemainingPoints: scopePoints * left,
+		})
+	}
+
+	return chart
+}
+
+// checkBurnPeriod refuses charts whose ideal line to end or actual series to until is longer than maxBurnChartDays
+func checkBurnPeriod(start time.Time, end time.Time, until time.Time) error {
+	limit := start.AddDate(0, 0, maxBurnChartDays)
+	if end.After(limit) || until.After(limit) {
+		return fmt.Errorf("failed to get burn chart. period cannot exceed %d days", maxBurnChartDays)
+	}
+	return nil
+}
+
+// parseDate accepts both plain dates and the timestamps DATE columns are scanned as
+func parseDate(value string) (time.Time, error) {
+	if len(value) > 10 {
+		value = value[:10]
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func NewChartUsecase(chartRepository repository.ChartRepository, projectRepository repository.ProjectRepository, sprintRepository repository.SprintRepository) ChartUsecase {
+	return &chartUsecase{
+		chartRepository:   chartRepository,
+		projectRepository: projectRepository,
+		sprintRepository:  sprintRepository,
+		now:               time.Now,
+	}
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChartUsecaseTest struct {
+	suite.Suite
+	crm *repository_mock.ChartRepositoryMock
+	prm *repository_mock.ProjectRepositoryMock
+	srm *repository_mock.SprintRepositoryMock
+	cuc ChartUsecase
+}
+
+func (c *ChartUsecaseTest) SetupTest() {
+	c.crm = new(repository_mock.ChartRepositoryMock)
+	c.prm = new(repository_mock.ProjectRepositoryMock)
+	c.srm = new(repository_mock.SprintRepositoryMock)
+	c.cuc = NewChartUsecase(c.crm, c.prm, c.srm)
+	c.cuc.(*chartUsecase).now = func() time.Time { return time.Date(2024, 3, 3, 15, 0, 0, 0, time.UTC) }
+}
+
+func TestChartUsecase(t *testing.T) {
+	suite.Run(t, new(ChartUsecaseTest))
+}
+
+var chartTasks = []model.ChartTask{
+	{Id: "t1", StoryPoints: 3, CreatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), History: []model.StatusChange{
+		{Status: "In Progress", ChangedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{Status: "Accepted", ChangedAt: time.Date(2024, 3, 2, 16, 0, 0, 0, time.UTC)},
+	}},
+	{Id: "t2", StoryPoints: 5, CreatedAt: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC), History: []model.StatusChange{
+		{Status: "In Progress", ChangedAt: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)},
+	}},
+}
+
+func (c *ChartUsecaseTest) TestSprintBurn_ReplaysHistory() {
+	c.srm.On("GetById", "s1").Return(model.Sprint{Id: "s1", StartDate: "2024-03-01", EndDate: "2024-03-05T00:00:00Z"}, nil)
+	c.crm.On("GetSprintTasks", "s1").Return(chartTasks, nil)
+
+	chart, err := c.cuc.SprintBurn("s1")
+
+	assert.NoError(c.T(), err)
+	assert.Equal(c.T(), []model.BurnPoint{
+		{Date: "2024-03-01", TotalTasks: 1, RemainingTasks: 1, TotalPoints: 3, RemainingPoints: 3},
+		{Date: "2024-03-02", TotalTasks: 2, CompletedTasks: 1, RemainingTasks: 1, TotalPoints: 8, CompletedPoints: 3, RemainingPoints: 5},
+		{Date: "2024-03-03", TotalTasks: 2, CompletedTasks: 1, RemainingTasks: 1, TotalPoints: 8, CompletedPoints: 3, RemainingPoints: 5},
+	}, chart.Actual)
+	assert.Len(c.T(), chart.Ideal, 5)
+	assert.Equal(c.T(), model.IdealPoint{Date: "2024-03-01", RemainingTasks: 2, RemainingPoints: 8}, chart.Ideal[0])
+	assert.Equal(c.T(), model.IdealPoint{Date: "2024-03-05"}, chart.Ideal[4])
+}
+
+func (c *ChartUsecaseTest) TestProjectBurn_IdealUpToDeadline() {
+	c.prm.On("GetById", "p1").Return(model.Project{Id: "p1", Deadline: "2024-03-11", CreatedAt: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)}, nil)
+	c.crm.On("GetProjectTasks", "p1").Return(chartTasks, nil)
+
+	chart, err := c.cuc.ProjectBurn("p1")
+
+	assert.NoError(c.T(), err)
+	assert.Len(c.T(), chart.Actual, 3)
+	assert.Len(c.T(), chart.Ideal, 11)
+	assert.Equal(c.T(), "2024-03-11", chart.EndDate)
+}
+
+func (c *ChartUsecaseTest) TestProjectBurn_InvalidProject() {
+	c.prm.On("GetById", "p1").Return(model.Project{}, fmt.Errorf("not found"))
+
+	_, err := c.cuc.ProjectBurn("p1")
+
+	assert.Error(c.T(), err)
+	c.crm.AssertNotCalled(c.T(), "GetProjectTasks", "p1")
+}
+
+func (c *ChartUsecaseTest) TestProjectBurn_DeadlineTooFar() {
+	c.prm.On("GetById", "p1").Return(model.Project{Id: "p1", Deadline: "9999-12-31", CreatedAt: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)}, nil)
+
+	_, err := c.cuc.ProjectBurn("p1")
+
+	assert.EqualError(c.T(), err, "failed to get burn chart. period cannot exceed 3660 days")
+	c.crm.AssertNotCalled(c.T(), "GetProjectTasks", "p1")
+}
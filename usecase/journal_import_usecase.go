@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/report"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+type JournalImportUsecase interface {
+	Import(from string, to string, dryRun bool) (model.JournalImport, error)
+}
+
+type journalImportUsecase struct {
+	reportRepository repository.ReportRepository
+	journalReader    report.JournalReader
+}
+
+// journalState is what the journal says about one report after replaying its entries
+type journalState struct {
+	report  model.Report
+	content bool
+	deleted bool
+	purged  bool
+	first   model.JournalEntry
+	last    model.JournalEntry
+	created bool
+	seen    map[string]model.JournalEntry
+}
+
+// Import implements JournalImportUsecase.
+// Replays the journal entries between from and to (YYYY-MM-DD; an empty from reads every file, to defaults
+// to today) and compares the last state of each report with its row, deleted rows included.
+//   - a report without a row is inserted as the journal last saw it, deleted if its last entry is a deletion
+//   - a deletion that never reached the table is applied
+//   - a purged report is expected to have no row
+//   - different content is applied only when the journal holds a newer version than the table
+//
+// Rows deleted without a journal entry and entries repeating an earlier one are reported but left alone.
+// With dryRun nothing is written.
+func (j *journalImportUsecase) Import(from string, to string, dryRun bool) (model.JournalImport, error) {
+	var fromDate time.Time
+	var err error
+	if from != "" {
+		if fromDate, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			return model.JournalImport{}, fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+	}
+	if to == "" {
+		to = time.Now().Format("2006-01-02")
+	}
+	toDate, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return model.JournalImport{}, fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+	}
+	if from != "" && toDate.Before(fromDate) {
+		return model.JournalImport{}, fmt.Errorf("to date cannot be before from date")
+	}
+
+	entries, err := j.journalReader.Read(fromDate, toDate)
+	if err != nil {
+		return model.JournalImport{}, fmt.Errorf("failed to read report journal: %s", err.Error())
+	}
+
+	result := model.JournalImport{From: from, To: to, DryRun: dryRun, Missing: []model.JournalDiff{}, Divergent: []model.JournalDiff{}, Duplicates: []model.JournalDiff{}, Unreadable: []model.JournalBreak{}, Failed: []model.JournalDiff{}}
+	states := map[string]*journalState{}
+	var ids []string
+	files := map[string]bool{}
+
+	for _, entry := range entries {
+		files[entry.File] = true
+		if entry.Error != "" {
+			result.Unreadable = append(result.Unreadable, model.JournalBreak{File: entry.File, Entry: entry.Entry, Reason: entry.Error})
+			continue
+		}
+		result.Entries++
+
+		state, ok := states[entry.Report.Id]
+		if !ok {
+			state = &journalState{first: entry, seen: map[string]model.JournalEntry{}}
+			states[entry.Report.Id] = state
+			ids = append(ids, entry.Report.Id)
+		}
+
+		content, _ := json.Marshal(entry.Report)
+		key := entry.Action + "\n" + entry.Date + "\n" + string(content)
+		if earlier, ok := state.seen[key]; ok {
+			result.Duplicates = append(result.Duplicates, journalDiff(entry, fmt.Sprintf("repeats entry %d of %s", earlier.Entry, earlier.File)))
+			continue
+		}
+		if entry.Action == "create" && state.created {
+			result.Duplicates = append(result.Duplicates, journalDiff(entry, "report was already created"))
+			continue
+		}
+		state.seen[key] = entry
+		state.apply(entry)
+	}
+	result.Files = len(files)
+	result.Reports = len(ids)
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+	rows, err := j.reportRepository.GetReportsByIds(ids)
+	if err != nil {
+		return model.JournalImport{}, fmt.Errorf("failed to read reports: %s", err.Error())
+	}
+
+	for _, id := range ids {
+		state := states[id]
+		journal := state.report
+
+		row, ok := rows[id]
+		if !ok && state.purged {
+			continue
+		}
+		if !ok {
+			diff := journalDiff(state.last, "report is not in the database")
+			diff.Journal = &journal
+			diff.Apply = state.content
+			if !state.content {
+				diff.Reason = "report is not in the database and the journal only holds its deletion"
+			}
+			result.Missing = append(result.Missing, diff)
+			if diff.Apply {
+				j.apply(&result, diff, journal, 0)
+			}
+			continue
+		}
+
+		target := row
+		var reasons []string
+		apply := false
+		if state.deleted && row.DeletedAt == nil {
+			reasons = append(reasons, "deleted in the journal but not in the database")
+			target.DeletedAt = journal.DeletedAt
+			apply = true
+		}
+		if !state.deleted && row.DeletedAt != nil {
+			reasons = append(reasons, "deleted in the database without a journal entry")
+		}
+		if state.content && (row.Report != journal.Report || row.Task_id != journal.Task_id || row.User_id != journal.User_id) {
+			if journal.Version > row.Version {
+				reasons = append(reasons, "journal holds a newer version")
+				target.User_id, target.Report, target.Task_id, target.Standup = journal.User_id, journal.Report, journal.Task_id, journal.Standup
+				target.Updated_at, target.Version = journal.Updated_at, journal.Version
+				apply = true
+			} else {
+				reasons = append(reasons, "content differs and the database version is not older")
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		diff := journalDiff(state.last, joinReasons(reasons))
+		diff.Apply = apply
+		diff.Journal, diff.Database = &journal, &row
+		result.Divergent = append(result.Divergent, diff)
+		if apply {
+			j.apply(&result, diff, target, row.Version)
+		}
+	}
+
+	return result, nil
+}
+
+// apply writes one reconciled report unless this is a dry run. A report that changed in the
+// database since it was read, readVersion being 0 for a missing one, is left alone and reported as failed.
+func (j *journalImportUsecase) apply(result *model.JournalImport, diff model.JournalDiff, payload model.Report, readVersion int) {
+	if result.DryRun {
+		return
+	}
+	if err := j.reportRepository.ImportReport(payload, readVersion); err != nil {
+		diff.Reason = err.Error()
+		if errors.Is(err, shared_model.ErrVersionConflict) {
+			diff.Reason = "report changed in the database during the import"
+		}
+		result.Failed = append(result.Failed, diff)
+		return
+	}
+	result.Applied++
+}
+
+// apply replays one journal entry. Timestamps only have the day the entry was written.
+func (s *journalState) apply(entry model.JournalEntry) {
+	day, _ := time.ParseInLocation("2006-01-02", entry.Date, time.Local)
+	s.last = entry
+
+	switch entry.Action {
+	case "create", "update":
+		created := s.report.Created_at
+		s.report = entry.Report
+		s.report.Created_at, s.report.Updated_at = created, day
+		if entry.Action == "create" || !s.content {
+			s.report.Created_at = day
+		}
+		s.content, s.deleted, s.purged = true, false, false
+		s.report.DeletedAt = nil
+		s.created = s.created || entry.Action == "create"
+	case "delete", "restore", "purge":
+		// these carry the whole report; when it is all the journal has, its creation day is unknown
+		if !s.content && entry.Report.Report != "" {
+			s.report = entry.Report
+			s.report.Created_at, s.report.Updated_at = day, day
+			s.content = true
+		}
+		s.report.Id = entry.Report.Id
+		s.deleted, s.purged = entry.Action != "restore", entry.Action == "purge"
+		s.report.DeletedAt = &day
+		if !s.deleted {
+			s.report.DeletedAt = nil
+		}
+	}
+}
+
+func journalDiff(entry model.JournalEntry, reason string) model.JournalDiff {
+	return model.JournalDiff{ReportId: entry.Report.Id, File: entry.File, Entry: entry.Entry, Reason: reason}
+}
+
+func joinReasons(reasons []string) string {
+	joined := reasons[0]
+	for _, reason := range reasons[1:] {
+		joined += "; " + reason
+	}
+	return joined
+}
+
+func NewJournalImportUsecase(reportRepository repository.ReportRepository, journalReader report.JournalReader) JournalImportUsecase {
+	return &journalImportUsecase{
+		reportRepository: reportRepository,
+		journalReader:    journalReader,
+	}
+}
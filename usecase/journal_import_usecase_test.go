@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/report_mock"
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type JournalImportUsecaseTest struct {
+	suite.Suite
+	rrm *repository_mock.ReportRepositoryMock
+	jrm *report_mock.JournalReaderMock
+	juc JournalImportUsecase
+}
+
+func (s *JournalImportUsecaseTest) SetupTest() {
+	s.rrm = new(repository_mock.ReportRepositoryMock)
+	s.jrm = new(report_mock.JournalReaderMock)
+	s.juc = NewJournalImportUsecase(s.rrm, s.jrm)
+}
+
+func TestJournalImportUsecase(t *testing.T) {
+	suite.Run(t, new(JournalImportUsecaseTest))
+}
+
+var (
+	importFrom = time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	importTo   = time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local)
+)
+
+func journalEntry(entry int, action string, date string, report model.Report) model.JournalEntry {
+	return model.JournalEntry{File: date + ".txt", Entry: entry, Action: action, Date: date, Report: report}
+}
+
+func (s *JournalImportUsecaseTest) TestImport_InsertsMissingReport() {
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		journalEntry(1, "create", "2024-03-01", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "draft", Version: 1}),
+		journalEntry(1, "update", "2024-03-02", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{}, nil)
+	s.rrm.On("ImportReport", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", false)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, result.Files)
+	assert.Equal(s.T(), 2, result.Entries)
+	assert.Len(s.T(), result.Missing, 1)
+	assert.Equal(s.T(), 1, result.Applied)
+	imported := s.rrm.Calls[1].Arguments.Get(0).(model.Report)
+	assert.Equal(s.T(), "final", imported.Report)
+	assert.Equal(s.T(), 2, imported.Version)
+	assert.Equal(s.T(), importFrom, imported.Created_at)
+	assert.Equal(s.T(), importTo, imported.Updated_at)
+	assert.Nil(s.T(), imported.DeletedAt)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_AppliesDeletion() {
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		journalEntry(1, "delete", "2024-03-02", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{"r1": {Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}}, nil)
+	s.rrm.On("ImportReport", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", false)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Divergent, 1)
+	assert.True(s.T(), result.Divergent[0].Apply)
+	imported := s.rrm.Calls[1].Arguments.Get(0).(model.Report)
+	assert.Equal(s.T(), importTo, *imported.DeletedAt)
+	assert.Equal(s.T(), "final", imported.Report)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_ReportChangedDuringImport() {
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		journalEntry(1, "update", "2024-03-02", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 3}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{"r1": {Id: "r1", User_id: "u1", Task_id: "t1", Report: "draft", Version: 2}}, nil)
+	s.rrm.On("ImportReport", mock.Anything, 2).Return(shared_model.ErrVersionConflict)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", false)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, result.Applied)
+	assert.Len(s.T(), result.Failed, 1)
+	assert.Equal(s.T(), "report changed in the database during the import", result.Failed[0].Reason)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_KeepsNewerDatabaseVersion() {
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		journalEntry(1, "update", "2024-03-01", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "older", Version: 2}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{"r1": {Id: "r1", User_id: "u1", Task_id: "t1", Report: "newer", Version: 3}}, nil)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", false)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Divergent, 1)
+	assert.False(s.T(), result.Divergent[0].Apply)
+	assert.Equal(s.T(), 0, result.Applied)
+	s.rrm.AssertNotCalled(s.T(), "ImportReport", mock.Anything, mock.Anything)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_DryRunSkipsDuplicates() {
+	created := journalEntry(1, "create", "2024-03-01", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "draft", Version: 1})
+	repeated := created
+	repeated.Entry = 2
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		created,
+		repeated,
+		{File: "2024-03-02.txt", Entry: 1, Error: "incomplete entry at the end of the file"},
+		journalEntry(2, "update", "2024-03-02", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{"r1": {Id: "r1", User_id: "u1", Task_id: "t1", Report: "draft", Version: 1}}, nil)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", true)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Duplicates, 1)
+	assert.Len(s.T(), result.Unreadable, 1)
+	assert.Len(s.T(), result.Divergent, 1)
+	assert.True(s.T(), result.Divergent[0].Apply)
+	assert.Equal(s.T(), "final", result.Divergent[0].Journal.Report)
+	assert.Equal(s.T(), 0, result.Applied)
+	s.rrm.AssertNotCalled(s.T(), "ImportReport", mock.Anything, mock.Anything)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_InvalidDate() {
+	_, err := s.juc.Import("01-03-2024", "", false)
+
+	assert.Error(s.T(), err)
+	s.jrm.AssertNotCalled(s.T(), "Read", mock.Anything, mock.Anything)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_RestoresDeletedReport() {
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		journalEntry(1, "delete", "2024-03-02", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{}, nil)
+	s.rrm.On("ImportReport", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", false)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Missing, 1)
+	assert.Equal(s.T(), 1, result.Applied)
+	imported := s.rrm.Calls[1].Arguments.Get(0).(model.Report)
+	assert.Equal(s.T(), "final", imported.Report)
+	assert.Equal(s.T(), importTo, *imported.DeletedAt)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_RestoreUndoesDeletion() {
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		journalEntry(1, "delete", "2024-03-01", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+		journalEntry(1, "restore", "2024-03-02", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{"r1": {Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}}, nil)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", false)
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result.Divergent)
+	assert.Equal(s.T(), 0, result.Applied)
+	s.rrm.AssertNotCalled(s.T(), "ImportReport", mock.Anything, mock.Anything)
+}
+
+func (s *JournalImportUsecaseTest) TestImport_PurgedReportHasNoRow() {
+	s.jrm.On("Read", importFrom, importTo).Return([]model.JournalEntry{
+		journalEntry(1, "delete", "2024-03-01", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+		journalEntry(1, "purge", "2024-03-02", model.Report{Id: "r1", User_id: "u1", Task_id: "t1", Report: "final", Version: 2}),
+	}, nil)
+	s.rrm.On("GetReportsByIds", []string{"r1"}).Return(map[string]model.Report{}, nil)
+
+	result, err := s.juc.Import("2024-03-01", "2024-03-02", false)
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result.Missing)
+	s.rrm.AssertNotCalled(s.T(), "ImportReport", mock.Anything, mock.Anything)
+}
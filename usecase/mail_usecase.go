@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"text/template"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
+)
+
+type MailUsecase interface {
+	Notify(event model.Event)
+	SendPending() (int, error)
+	SendDigests(dueBefore time.Time) (int, error)
+	GetMode(userId string) (string, error)
+	UpdateMode(userId string, mode string) error
+}
+
+type mailUsecase struct {
+	mailRepository         repository.MailRepository
+	userRepository         repository.UserRepository
+	notificationRepository repository.NotificationRepository
+	mailer                 service.Mailer
+	defaultMode            string
+}
+
+type mailView struct {
+	Name  string
+	Items []model.QueuedMail
+}
+
+var (
+	mailText = template.Must(template.New("mail").Parse(`Hi {{.Name}},
+
+{{range .Items}}{{.Message}}
+{{end}}
+Project Management Hub
+`))
+	mailHtml = htmltemplate.Must(htmltemplate.New("mail").Parse(`<p>Hi {{.Name}},</p>
+{{range .Items}}<p>{{.Message}}</p>
+{{end}}<p>Project Management Hub</p>
+`))
+	digestText = template.Must(template.New("digest").Parse(`Hi {{.Name}},
+
+Here is what happened since your last digest:
+{{range .Items}}
+- {{.CreatedAt.Format "2006-01-02 15:04"}} {{.Message}}{{end}}
+
+Project Management Hub
+`))
+	digestHtml = htmltemplate.Must(htmltemplate.New("digest").Parse(`<p>Hi {{.Name}},</p>
+<p>Here is what happened since your last digest:</p>
+<ul>
+{{range .Items}}<li>{{.CreatedAt.Format "2006-01-02 15:04"}} {{.Message}}</li>
+{{end}}</ul>
+<p>Project Management Hub</p>
+`))
+)
+
+// Notify implements MailUsecase.
+// It is subscribed to the event service and queues an email for every recipient who wants one,
+// either to go out on its own or in the recipient's next digest.
+func (m *mailUsecase) Notify(event model.Event) {
+	if !validNotificationType(event.Type) {
+		return
+	}
+
+	message := notificationMessage(event)
+	for _, userId := range notificationRecipients(m.notificationRepository, event) {
+		mode, err := m.GetMode(userId)
+		if err != nil {
+			log.Println("mailUsecase.Notify", err.Error())
+			continue
+		}
+		if mode == model.MailOff {
+			continue
+		}
+
+		queued := model.QueuedMail{UserId: userId, Type: event.Type, Message: message, Digest: mode == model.MailDigest}
+		if err := m.mailRepository.Enqueue(queued); err != nil {
+			log.Println("mailUsecase.Notify", err.Error())
+		}
+	}
+}
+
+// SendPending implements MailUsecase.
+// Each queued immediate mail is sent on its own; failed ones stay queued for the next run.
+func (m *mailUsecase) SendPending() (int, error) {
+	pending, err := m.mailRepository.GetPending(false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send mails: %s", err.Error())
+	}
+
+	var sent int
+	for _, queued := range pending {
+		subject := "[PMH] " + queued.Message
+		if m.send(queued.UserId, subject, mailText, mailHtml, []model.QueuedMail{queued}) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// SendDigests implements MailUsecase.
+// Everything queued for a digest user goes out in one mail, once their oldest item was queued at or before dueBefore.
+// Because the pending rows themselves say how long a user has waited, a restart does not reset the digest clock.
+func (m *mailUsecase) SendDigests(dueBefore time.Time) (int, error) {
+	pending, err := m.mailRepository.GetPending(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send digests: %s", err.Error())
+	}
+
+	var sent int
+	for start := 0; start < len(pending); {
+		end := start
+		for end < len(pending) && pending[end].UserId == pending[start].UserId {
+			end++
+		}
+
+		items := pending[start:end]
+		start = end
+		if items[0].CreatedAt.After(dueBefore) {
+			continue
+		}
+
+		subject := fmt.Sprintf("[PMH] Your digest: %d update(s)", len(items))
+		if m.send(items[0].UserId, subject, digestText, digestHtml, items) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// GetMode implements MailUsecase.
+func (m *mailUsecase) GetMode(userId string) (string, error) {
+	mode, err := m.mailRepository.GetMode(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return m.defaultMode, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get mail mode: %s", err.Error())
+	}
+
+	return mode, nil
+}
+
+// UpdateMode implements MailUsecase.
+func (m *mailUsecase) UpdateMode(userId string, mode string) error {
+	if mode != model.MailOff && mode != model.MailImmediate && mode != model.MailDigest {
+		return fmt.Errorf("invalid mail mode. mode: ('off', 'immediate', 'digest')")
+	}
+
+	if err := m.mailRepository.SaveMode(userId, mode); err != nil {
+		return fmt.Errorf("failed to update mail mode: %s", err.Error())
+	}
+
+	return nil
+}
+
+// send renders items for userId and marks them sent once the mailer accepted the mail
+func (m *mailUsecase) send(userId string, subject string, text *template.Template, html *htmltemplate.Template, items []model.QueuedMail) bool {
+	user, err := m.userRepository.GetById(userId)
+	if err != nil {
+		log.Println("mailUsecase.send", err.Error())
+		return false
+	}
+
+	view := mailView{Name: user.Name, Items: items}
+	var textBody, htmlBody bytes.Buffer
+	if err := text.Execute(&textBody, view); err != nil {
+		log.Println("mailUsecase.send", err.Error())
+		return false
+	}
+	if err := html.Execute(&htmlBody, view); err != nil {
+		log.Println("mailUsecase.send", err.Error())
+		return false
+	}
+
+	if err := m.mailer.Send(model.Mail{To: user.Email, Subject: subject, TextBody: textBody.String(), HtmlBody: htmlBody.String()}); err != nil {
+		log.Println("mailUsecase.send", err.Error())
+		return false
+	}
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+	if err := m.mailRepository.MarkSent(ids); err != nil {
+		log.Println("mailUsecase.send", err.Error())
+		return false
+	}
+
+	log.Printf("Mail %q sent to %s", subject, user.Email)
+	return true
+}
+
+func NewMailUsecase(mailRepository repository.MailRepository, userRepository repository.UserRepository, notificationRepository repository.NotificationRepository, mailer service.Mailer, defaultMode string) MailUsecase {
+	return &mailUsecase{
+		mailRepository:         mailRepository,
+		userRepository:         userRepository,
+		notificationRepository: notificationRepository,
+		mailer:                 mailer,
+		defaultMode:            defaultMode,
+	}
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/mock/service_mock"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MailUsecaseTest struct {
+	suite.Suite
+	mrm *repository_mock.MailRepositoryMock
+	urm *repository_mock.UserRepositoryMock
+	nrm *repository_mock.NotificationRepositoryMock
+	mm  *service_mock.MailerMock
+	muc MailUsecase
+}
+
+func (m *MailUsecaseTest) SetupTest() {
+	m.mrm = new(repository_mock.MailRepositoryMock)
+	m.urm = new(repository_mock.UserRepositoryMock)
+	m.nrm = new(repository_mock.NotificationRepositoryMock)
+	m.mm = new(service_mock.MailerMock)
+	m.muc = NewMailUsecase(m.mrm, m.urm, m.nrm, m.mm, model.MailImmediate)
+}
+
+func TestMailUsecase(t *testing.T) {
+	suite.Run(t, new(MailUsecaseTest))
+}
+
+func (m *MailUsecaseTest) TestNotify_QueuesByMode() {
+	event := model.Event{Type: model.EventTaskRejected, RecipientIds: []string{"u1", "u2", "u3"}, Data: model.EventData{"key": "WEB-1", "feedback": "redo"}}
+	for _, userId := range event.RecipientIds {
+		m.nrm.On("GetPreferences", userId).Return(map[string]bool{}, nil)
+	}
+	m.mrm.On("GetMode", "u1").Return("", sql.ErrNoRows)
+	m.mrm.On("GetMode", "u2").Return(model.MailDigest, nil)
+	m.mrm.On("GetMode", "u3").Return(model.MailOff, nil)
+	m.mrm.On("Enqueue", model.QueuedMail{UserId: "u1", Type: model.EventTaskRejected, Message: "Task WEB-1 was rejected: redo"}).Return(nil)
+	m.mrm.On("Enqueue", model.QueuedMail{UserId: "u2", Type: model.EventTaskRejected, Message: "Task WEB-1 was rejected: redo", Digest: true}).Return(nil)
+
+	m.muc.Notify(event)
+
+	m.mrm.AssertNumberOfCalls(m.T(), "Enqueue", 2)
+	m.mrm.AssertExpectations(m.T())
+}
+
+func (m *MailUsecaseTest) TestSendPending_KeepsFailedMailsQueued() {
+	m.mrm.On("GetPending", false).Return([]model.QueuedMail{
+		{Id: 1, UserId: "u1", Message: "You were assigned to task WEB-1"},
+		{Id: 2, UserId: "u2", Message: "Task WEB-2 was approved"},
+	}, nil)
+	m.urm.On("GetById", "u1").Return(model.User{Id: "u1", Name: "Ana", Email: "ana@mail.com"}, nil)
+	m.urm.On("GetById", "u2").Return(model.User{Id: "u2", Name: "Budi", Email: "budi@mail.com"}, nil)
+	m.mm.On("Send", mock.MatchedBy(func(mail model.Mail) bool { return mail.To == "ana@mail.com" })).Return(nil)
+	m.mm.On("Send", mock.MatchedBy(func(mail model.Mail) bool { return mail.To == "budi@mail.com" })).Return(errors.New("connection refused"))
+	m.mrm.On("MarkSent", []int64{1}).Return(nil)
+
+	sent, err := m.muc.SendPending()
+
+	assert.NoError(m.T(), err)
+	assert.Equal(m.T(), 1, sent)
+	mail := m.mm.Calls[0].Arguments.Get(0).(model.Mail)
+	assert.Equal(m.T(), "[PMH] You were assigned to task WEB-1", mail.Subject)
+	assert.Contains(m.T(), mail.TextBody, "Hi Ana,")
+	assert.Contains(m.T(), mail.HtmlBody, "<p>You were assigned to task WEB-1</p>")
+	m.mrm.AssertNotCalled(m.T(), "MarkSent", []int64{2})
+}
+
+func (m *MailUsecaseTest) TestSendDigests_OneMailPerUser() {
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	m.mrm.On("GetPending", true).Return([]model.QueuedMail{
+		{Id: 1, UserId: "u1", Message: "You were assigned to task WEB-1", Digest: true, CreatedAt: at},
+		{Id: 2, UserId: "u1", Message: "Task WEB-1 is due on 2024-03-02", Digest: true, CreatedAt: at.Add(time.Hour)},
+		{Id: 3, UserId: "u2", Message: "You were added to project WEB", Digest: true, CreatedAt: at},
+	}, nil)
+	m.urm.On("GetById", "u1").Return(model.User{Id: "u1", Name: "Ana", Email: "ana@mail.com"}, nil)
+	m.urm.On("GetById", "u2").Return(model.User{Id: "u2", Name: "Budi", Email: "budi@mail.com"}, nil)
+	m.mm.On("Send", mock.Anything).Return(nil)
+	m.mrm.On("MarkSent", []int64{1, 2}).Return(nil)
+	m.mrm.On("MarkSent", []int64{3}).Return(nil)
+
+	sent, err := m.muc.SendDigests(at.Add(time.Hour))
+
+	assert.NoError(m.T(), err)
+	assert.Equal(m.T(), 2, sent)
+	mail := m.mm.Calls[0].Arguments.Get(0).(model.Mail)
+	assert.Equal(m.T(), "[PMH] Your digest: 2 update(s)", mail.Subject)
+	assert.Contains(m.T(), mail.TextBody, "- 2024-03-01 09:00 Task WEB-1 is due on 2024-03-02")
+}
+
+func (m *MailUsecaseTest) TestSendDigests_NotDueYet() {
+	at := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	m.mrm.On("GetPending", true).Return([]model.QueuedMail{
+		{Id: 1, UserId: "u1", Message: "You were assigned to task WEB-1", Digest: true, CreatedAt: at},
+		{Id: 3, UserId: "u2", Message: "You were added to project WEB", Digest: true, CreatedAt: at.Add(2 * time.Hour)},
+	}, nil)
+	m.urm.On("GetById", "u1").Return(model.User{Id: "u1", Name: "Ana", Email: "ana@mail.com"}, nil)
+	m.mm.On("Send", mock.Anything).Return(nil)
+	m.mrm.On("MarkSent", []int64{1}).Return(nil)
+
+	sent, err := m.muc.SendDigests(at.Add(time.Hour))
+
+	assert.NoError(m.T(), err)
+	assert.Equal(m.T(), 1, sent)
+	m.urm.AssertNotCalled(m.T(), "GetById", "u2")
+	m.mrm.AssertNotCalled(m.T(), "MarkSent", []int64{3})
+}
+
+func (m *MailUsecaseTest) TestUpdateMode_Invalid() {
+	err := m.muc.UpdateMode("u1", "weekly")
+
+	assert.Error(m.T(), err)
+	m.mrm.AssertNotCalled(m.T(), "SaveMode", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+type NotificationUsecase interface {
+	Notify(event model.Event)
+	GetNotifications(userId string, unreadOnly bool, page int, size int) ([]model.Notification, shared_model.Paging, error)
+	CountUnread(userId string) (int, error)
+	MarkRead(userId string, id string) error
+	MarkAllRead(userId string) (int64, error)
+	GetPreferences(userId string) (map[string]bool, error)
+	UpdatePreferences(userId string, preferences map[string]bool) (map[string]bool, error)
+}
+
+type notificationUsecase struct {
+	notificationRepository repository.NotificationRepository
+}
+
+// Notify implements NotificationUsecase.
+// It is subscribed to the event service and stores one notification per recipient, skipping the
+// user who caused the event and users who turned the event type off.
+func (n *notificationUsecase) Notify(event model.Event) {
+	if !validNotificationType(event.Type) {
+		return
+	}
+
+	message := notificationMessage(event)
+	for _, userId := range notificationRecipients(n.notificationRepository, event) {
+		notification := model.Notification{UserId: userId, Type: event.Type, ProjectId: event.ProjectId, TaskId: event.TaskId, Message: message, Data: event.Data}
+		if err := n.notificationRepository.Create(notification); err != nil {
+			log.Println("notificationUsecase.Notify", err.Error())
+		}
+	}
+}
+
+// GetNotifications implements NotificationUsecase.
+func (n *notificationUsecase) GetNotifications(userId string, unreadOnly bool, page int, size int) ([]model.Notification, shared_model.Paging, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	notifications, paging, err := n.notificationRepository.GetByUserId(userId, unreadOnly, page, size)
+	if err != nil {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to get notifications: %s", err.Error())
+	}
+
+	return notifications, paging, nil
+}
+
+// CountUnread implements NotificationUsecase.
+func (n *notificationUsecase) CountUnread(userId string) (int, error) {
+	count, err := n.notificationRepository.CountUnread(userId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %s", err.Error())
+	}
+
+	return count, nil
+}
+
+// MarkRead implements NotificationUsecase.
+func (n *notificationUsecase) MarkRead(userId string, id string) error {
+	err := n.notificationRepository.MarkRead(userId, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to mark notification read. notification id invalid")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %s", err.Error())
+	}
+
+	return nil
+}
+
+// MarkAllRead implements NotificationUsecase.
+func (n *notificationUsecase) MarkAllRead(userId string) (int64, error) {
+	count, err := n.notificationRepository.MarkAllRead(userId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %s", err.Error())
+	}
+
+	return count, nil
+}
+
+// GetPreferences implements NotificationUsecase.
+// Every notification type is listed; types the user never set are enabled.
+func (n *notificationUsecase) GetPreferences(userId string) (map[string]bool, error) {
+	stored, err := n.notificationRepository.GetPreferences(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %s", err.Error())
+	}
+
+	preferences := make(map[string]bool, len(model.NotificationTypes))
+	for _, eventType := range model.NotificationTypes {
+		enabled, ok := stored[eventType]
+		preferences[eventType] = !ok || enabled
+	}
+
+	return preferences, nil
+}
+
+// UpdatePreferences implements NotificationUsecase.
+// Types missing from preferences keep their current setting.
+func (n *notificationUsecase) UpdatePreferences(userId string, preferences map[string]bool) (map[string]bool, error) {
+	if len(preferences) == 0 {
+		return nil, fmt.Errorf("failed to update notification preferences. field 'preferences' cannot be empty")
+	}
+	for eventType := range preferences {
+		if !validNotificationType(eventType) {
+			return nil, fmt.Errorf("failed to update notification preferences. invalid type %s", eventType)
+		}
+	}
+
+	if err := n.notificationRepository.SetPreferences(userId, preferences); err != nil {
+		return nil, fmt.Errorf("failed to update notification preferences: %s", err.Error())
+	}
+
+	return n.GetPreferences(userId)
+}
+
+func validNotificationType(eventType string) bool {
+	return slices.Contains(model.NotificationTypes, eventType)
+}
+
+// notificationRecipients lists the distinct recipients of event other than its actor
+// who have not turned its type off
+func notificationRecipients(notificationRepository repository.NotificationRepository, event model.Event) []string {
+	var recipients []string
+	for i, userId := range event.RecipientIds {
+		if userId == "" || userId == event.ActorId || slices.Contains(event.RecipientIds[:i], userId) {
+			continue
+		}
+
+		preferences, err := notificationRepository.GetPreferences(userId)
+		if err != nil {
+			log.Println("notificationRecipients", err.Error())
+			continue
+		}
+		if enabled, ok := preferences[event.Type]; ok && !enabled {
+			continue
+		}
+
+		recipients = append(recipients, userId)
+	}
+
+	return recipients
+}
+
+// notificationMessage renders the inbox line of an event
+func notificationMessage(event model.Event) string {
+	label, _ := event.Data["key"].(string)
+	if label == "" {
+		label, _ = event.Data["name"].(string)
+	}
+
+	switch event.Type {
+	case model.EventTaskAssigned:
+		return fmt.Sprintf("You were assigned to task %s", label)
+	case model.EventTaskApproved:
+		return fmt.Sprintf("Task %s was approved", label)
+	case model.EventTaskRejected:
+		return fmt.Sprintf("Task %s was rejected: %v", label, event.Data["feedback"])
+	case model.EventTaskBlocked:
+		return fmt.Sprintf("Task %s is blocked: %v", label, event.Data["blockers"])
+	case model.EventReportCreated:
+		return fmt.Sprintf("A new report was added to task %s", label)
+	case model.EventReportReviewed:
+		if event.Data["status"] == model.ReportNeedsMoreInfo {
+			return fmt.Sprintf("Your report on task %s needs more info: %v", label, event.Data["comment"])
+		}
+		return fmt.Sprintf("Your report on task %s was acknowledged", label)
+	case model.EventMemberAdded:
+		return fmt.Sprintf("You were added to project %s", label)
+	case model.EventTaskDueSoon:
+		return fmt.Sprintf("Task %s is due on %v", label, event.Data["deadline"])
+	case model.EventTaskOverdue:
+		return fmt.Sprintf("Task %s is overdue since %v", label, event.Data["deadline"])
+	case model.EventTaskEscalated:
+		return fmt.Sprintf("Task %s is %v day(s) overdue", label, event.Data["days_overdue"])
+	case model.EventProjectDueSoon:
+		return fmt.Sprintf("Project %s is due on %v", label, event.Data["deadline"])
+	case model.EventProjectOverdue:
+		return fmt.Sprintf("Project %s is overdue since %v", label, event.Data["deadline"])
+	}
+	return event.Type
+}
+
+func NewNotificationUsecase(notificationRepository repository.NotificationRepository) NotificationUsecase {
+	return &notificationUsecase{
+		notificationRepository: notificationRepository,
+	}
+}
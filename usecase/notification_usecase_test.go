@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationUsecaseTest struct {
+	suite.Suite
+	nrm *repository_mock.NotificationRepositoryMock
+	nuc NotificationUsecase
+}
+
+func (n *NotificationUsecaseTest) SetupTest() {
+	n.nrm = new(repository_mock.NotificationRepositoryMock)
+	n.nuc = NewNotificationUsecase(n.nrm)
+}
+
+func TestNotificationUsecase(t *testing.T) {
+	suite.Run(t, new(NotificationUsecaseTest))
+}
+
+func (n *NotificationUsecaseTest) TestNotify_SkipsActorAndDisabledTypes() {
+	event := model.Event{Type: model.EventMemberAdded, ProjectId: "p1", ActorId: "m1", RecipientIds: []string{"m1", "u1", "u2", "u1"}, Data: model.EventData{"key": "WEB"}}
+	n.nrm.On("GetPreferences", "u1").Return(map[string]bool{}, nil)
+	n.nrm.On("GetPreferences", "u2").Return(map[string]bool{model.EventMemberAdded: false}, nil)
+	n.nrm.On("Create", model.Notification{UserId: "u1", Type: model.EventMemberAdded, ProjectId: "p1", Message: "You were added to project WEB", Data: event.Data}).Return(nil)
+
+	n.nuc.Notify(event)
+
+	n.nrm.AssertNumberOfCalls(n.T(), "Create", 1)
+	n.nrm.AssertExpectations(n.T())
+}
+
+func (n *NotificationUsecaseTest) TestNotify_IgnoresUnknownTypes() {
+	n.nuc.Notify(model.Event{Type: "sprint.closed", RecipientIds: []string{"u1"}})
+
+	n.nrm.AssertNotCalled(n.T(), "Create", mock.Anything)
+}
+
+func (n *NotificationUsecaseTest) TestMarkRead_NotOwned() {
+	n.nrm.On("MarkRead", "u1", "n1").Return(sql.ErrNoRows)
+
+	err := n.nuc.MarkRead("u1", "n1")
+
+	assert.EqualError(n.T(), err, "failed to mark notification read. notification id invalid")
+}
+
+func (n *NotificationUsecaseTest) TestGetPreferences_DefaultsToEnabled() {
+	n.nrm.On("GetPreferences", "u1").Return(map[string]bool{model.EventTaskOverdue: false}, nil)
+
+	preferences, err := n.nuc.GetPreferences("u1")
+
+	assert.NoError(n.T(), err)
+	assert.Len(n.T(), preferences, len(model.NotificationTypes))
+	assert.False(n.T(), preferences[model.EventTaskOverdue])
+	assert.True(n.T(), preferences[model.EventTaskAssigned])
+}
+
+func (n *NotificationUsecaseTest) TestUpdatePreferences_InvalidType() {
+	_, err := n.nuc.UpdatePreferences("u1", map[string]bool{"task.deleted": false})
+
+	assert.Error(n.T(), err)
+	n.nrm.AssertNotCalled(n.T(), "SetPreferences", mock.Anything, mock.Anything)
+}
@@ -1,10 +1,17 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"enigma.com/projectmanagementhub/model"
 	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
 	"enigma.com/projectmanagementhub/shared/shared_model"
 )
 
@@ -19,18 +26,34 @@ type ProjectUseCase interface {
 	DeleteProjectMember(id string, members []string) error
 	GetAllProjectMember(id string) ([]model.User, error)
 	Update(payload model.Project) (model.Project, error)
-	Delete(id string) error
+	Delete(userId string, id string) error
+	DeletePreview(id string) (model.DeleteImpact, error)
+	GetMilestones(projectId string, userId string) ([]model.Milestone, error)
+	CreateMilestone(userId string, payload model.Milestone) (model.Milestone, error)
+	UpdateMilestone(userId string, payload model.Milestone) (model.Milestone, error)
+	DeleteMilestone(userId string, id string) error
+	AttachMilestoneTasks(userId string, id string, taskIds []string) error
+	GetCustomFields(projectId string, userId string) ([]model.CustomField, error)
+	CreateCustomField(userId string, payload model.CustomField) (model.CustomField, error)
+	UpdateCustomField(userId string, payload model.CustomField) (model.CustomField, error)
+	DeleteCustomField(userId string, id string) error
 }
 
 type projectUseCase struct {
-	projectRepo repository.ProjectRepository
-	userRepo    repository.UserRepository
+	projectRepo   repository.ProjectRepository
+	userRepo      repository.UserRepository
+	milestoneRepo repository.MilestoneRepository
+	fieldRepo     repository.CustomFieldRepository
+	eventService  service.EventService
 }
 
-func NewProjectUseCase(projectRepo repository.ProjectRepository, userRepo repository.UserRepository) ProjectUseCase {
+func NewProjectUseCase(projectRepo repository.ProjectRepository, userRepo repository.UserRepository, milestoneRepo repository.MilestoneRepository, fieldRepo repository.CustomFieldRepository, eventService service.EventService) ProjectUseCase {
 	return &projectUseCase{
-		projectRepo: projectRepo,
-		userRepo:    userRepo,
+		projectRepo:   projectRepo,
+		userRepo:      userRepo,
+		milestoneRepo: milestoneRepo,
+		fieldRepo:     fieldRepo,
+		eventService:  eventService,
 	}
 }
 
@@ -126,14 +149,31 @@ func (uc *projectUseCase) CreateNewProject(payload model.Project) (model.Project
 		return model.Project{}, errorMessage
 	}
 
-	createdProject, err := uc.projectRepo.CreateProject(payload)
-	if err != nil {
-		errorMessage := fmt.Errorf(" Failed to create project: %s", err.Error())
+	derived := payload.Key == ""
+	if derived {
+		payload.Key = defaultProjectKey(payload.Name)
+	}
+	payload.Key = strings.ToUpper(payload.Key)
+	if !projectKeyPattern.MatchString(payload.Key) {
+		errorMessage := fmt.Errorf(" Field 'key' must be 2-10 letters or digits starting with a letter")
 
 		return model.Project{}, errorMessage
 	}
 
-	return createdProject, nil
+	// a derived key that is taken gets a numeric suffix (MA, MA2, MA3, ...)
+	base := payload.Key
+	for suffix := 2; ; suffix++ {
+		createdProject, err := uc.projectRepo.CreateProject(payload)
+		if err == nil {
+			return createdProject, nil
+		}
+		if !derived || !errors.Is(err, shared_model.ErrKeyInUse) || suffix > maxProjectKeySuffix {
+			errorMessage := fmt.Errorf(" Failed to create project: %w", err)
+
+			return model.Project{}, errorMessage
+		}
+		payload.Key = base + strconv.Itoa(suffix)
+	}
 
 }
 
@@ -158,6 +198,14 @@ func (uc *projectUseCase) AddProjectMember(id string, members []string) error {
 		return errorMessage
 	}
 
+	project, _ := uc.projectRepo.GetById(id)
+	uc.eventService.Publish(model.Event{
+		Type:         model.EventMemberAdded,
+		ProjectId:    id,
+		RecipientIds: members,
+		Data:         model.EventData{"key": project.Key, "name": project.Name},
+		OccurredAt:   time.Now(),
+	})
 	return nil
 
 }
@@ -204,9 +252,9 @@ func (uc *projectUseCase) GetAllProjectMember(id string) ([]model.User, error) {
 	return users, nil
 }
 
-func (uc *projectUseCase) Delete(id string) error {
+func (uc *projectUseCase) Delete(userId string, id string) error {
 
-	err := uc.projectRepo.Delete(id)
+	err := uc.projectRepo.Delete(id, userId)
 	if err != nil {
 		errorMessage := fmt.Errorf(" Failed to delete project: %s", err.Error())
 
@@ -216,14 +264,34 @@ func (uc *projectUseCase) Delete(id string) error {
 	return nil
 }
 
+func (uc *projectUseCase) DeletePreview(id string) (model.DeleteImpact, error) {
+
+	_, err := uc.projectRepo.GetById(id)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to preview project delete: invalid id")
+		return model.DeleteImpact{}, errorMessage
+	}
+
+	impact, err := uc.projectRepo.GetDeleteImpact(id)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to preview project delete: %s", err.Error())
+		return model.DeleteImpact{}, errorMessage
+	}
+
+	return impact, nil
+}
+
 func (uc *projectUseCase) Update(payload model.Project) (model.Project, error) {
 
-	_, err := uc.projectRepo.GetById(payload.Id)
+	current, err := uc.projectRepo.GetById(payload.Id)
 	if err != nil {
 
 		errorMessage := fmt.Errorf(" Failed to update project: invalid id")
 		return model.Project{}, errorMessage
 	}
+	if payload.Version != 0 && payload.Version != current.Version {
+		return model.Project{}, fmt.Errorf(" Failed to update project: %w", shared_model.ErrVersionConflict)
+	}
 	manager, err := uc.userRepo.GetById(payload.ManagerId)
 	if err != nil {
 
@@ -244,10 +312,275 @@ func (uc *projectUseCase) Update(payload model.Project) (model.Project, error) {
 
 	updatedProject, err := uc.projectRepo.Update(payload)
 	if err != nil {
-		errorMessage := fmt.Errorf(" Failed to update project: %s", err.Error())
+		errorMessage := fmt.Errorf(" Failed to update project: %w", err)
 
 		return model.Project{}, errorMessage
 	}
 
 	return updatedProject, nil
 }
+
+func (uc *projectUseCase) GetMilestones(projectId string, userId string) ([]model.Milestone, error) {
+
+	if err := checkProjectManager(uc.projectRepo, projectId, userId); err != nil {
+		return nil, fmt.Errorf(" Failed to get milestones: %w", err)
+	}
+
+	milestones, err := uc.milestoneRepo.GetByProjectId(projectId)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to get milestones: %s", err.Error())
+		return nil, errorMessage
+	}
+
+	for i := range milestones {
+		setMilestoneProgress(&milestones[i])
+	}
+
+	return milestones, nil
+}
+
+func (uc *projectUseCase) CreateMilestone(userId string, payload model.Milestone) (model.Milestone, error) {
+
+	if payload.Name == "" || payload.DueDate == "" {
+		errorMessage := fmt.Errorf(" Fields 'name', 'due_date' cannot be empty")
+		return model.Milestone{}, errorMessage
+	}
+
+	if err := checkProjectManager(uc.projectRepo, payload.ProjectId, userId); err != nil {
+		return model.Milestone{}, fmt.Errorf(" Failed to create milestone: %w", err)
+	}
+
+	milestone, err := uc.milestoneRepo.Create(payload)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to create milestone: %s", err.Error())
+		return model.Milestone{}, errorMessage
+	}
+
+	return milestone, nil
+}
+
+func (uc *projectUseCase) UpdateMilestone(userId string, payload model.Milestone) (model.Milestone, error) {
+
+	if payload.Name == "" || payload.DueDate == "" {
+		errorMessage := fmt.Errorf(" Fields 'name', 'due_date' cannot be empty")
+		return model.Milestone{}, errorMessage
+	}
+
+	current, err := uc.milestoneRepo.GetById(payload.Id)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to update milestone: invalid id")
+		return model.Milestone{}, errorMessage
+	}
+
+	if err := checkProjectManager(uc.projectRepo, current.ProjectId, userId); err != nil {
+		return model.Milestone{}, fmt.Errorf(" Failed to update milestone: %w", err)
+	}
+
+	milestone, err := uc.milestoneRepo.Update(payload)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to update milestone: %s", err.Error())
+		return model.Milestone{}, errorMessage
+	}
+	milestone.TotalTasks, milestone.CompletedTasks = current.TotalTasks, current.CompletedTasks
+	setMilestoneProgress(&milestone)
+
+	return milestone, nil
+}
+
+func (uc *projectUseCase) DeleteMilestone(userId string, id string) error {
+
+	current, err := uc.milestoneRepo.GetById(id)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to delete milestone: invalid id")
+		return errorMessage
+	}
+
+	if err := checkProjectManager(uc.projectRepo, current.ProjectId, userId); err != nil {
+		return fmt.Errorf(" Failed to delete milestone: %w", err)
+	}
+
+	if err := uc.milestoneRepo.Delete(id); err != nil {
+		errorMessage := fmt.Errorf(" Failed to delete milestone: %s", err.Error())
+		return errorMessage
+	}
+
+	return nil
+}
+
+func (uc *projectUseCase) AttachMilestoneTasks(userId string, id string, taskIds []string) error {
+
+	if len(taskIds) == 0 {
+		errorMessage := fmt.Errorf(" Field 'task_ids' cannot be empty")
+		return errorMessage
+	}
+
+	current, err := uc.milestoneRepo.GetById(id)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to attach tasks: invalid milestone id")
+		return errorMessage
+	}
+
+	if err := checkProjectManager(uc.projectRepo, current.ProjectId, userId); err != nil {
+		return fmt.Errorf(" Failed to attach tasks: %w", err)
+	}
+
+	if err := uc.milestoneRepo.AttachTasks(id, taskIds); err != nil {
+		errorMessage := fmt.Errorf(" Failed to attach tasks: %s", err.Error())
+		return errorMessage
+	}
+
+	return nil
+}
+
+func (uc *projectUseCase) GetCustomFields(projectId string, userId string) ([]model.CustomField, error) {
+
+	if err := checkProjectManager(uc.projectRepo, projectId, userId); err != nil {
+		return nil, fmt.Errorf(" Failed to get custom fields: %w", err)
+	}
+
+	fields, err := uc.fieldRepo.GetByProjectId(projectId)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to get custom fields: %s", err.Error())
+		return nil, errorMessage
+	}
+
+	return fields, nil
+}
+
+func (uc *projectUseCase) CreateCustomField(userId string, payload model.CustomField) (model.CustomField, error) {
+
+	if payload.Name == "" || payload.Type == "" {
+		errorMessage := fmt.Errorf(" Fields 'name', 'type' cannot be empty")
+		return model.CustomField{}, errorMessage
+	}
+	if !hasOption(customFieldTypes, payload.Type) {
+		errorMessage := fmt.Errorf(" Invalid field type. field type: %v", customFieldTypes)
+		return model.CustomField{}, errorMessage
+	}
+	if err := validCustomFieldOptions(payload); err != nil {
+		return model.CustomField{}, err
+	}
+
+	if err := checkProjectManager(uc.projectRepo, payload.ProjectId, userId); err != nil {
+		return model.CustomField{}, fmt.Errorf(" Failed to create custom field: %w", err)
+	}
+
+	field, err := uc.fieldRepo.Create(payload)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to create custom field: %s", err.Error())
+		return model.CustomField{}, errorMessage
+	}
+
+	return field, nil
+}
+
+func (uc *projectUseCase) UpdateCustomField(userId string, payload model.CustomField) (model.CustomField, error) {
+
+	current, err := uc.fieldRepo.GetById(payload.Id)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to update custom field: invalid id")
+		return model.CustomField{}, errorMessage
+	}
+
+	// name and type are fixed once created, stored values depend on them
+	payload.Name, payload.Type = current.Name, current.Type
+	if err := validCustomFieldOptions(payload); err != nil {
+		return model.CustomField{}, err
+	}
+
+	if err := checkProjectManager(uc.projectRepo, current.ProjectId, userId); err != nil {
+		return model.CustomField{}, fmt.Errorf(" Failed to update custom field: %w", err)
+	}
+
+	field, err := uc.fieldRepo.Update(payload)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to update custom field: %s", err.Error())
+		return model.CustomField{}, errorMessage
+	}
+
+	return field, nil
+}
+
+func (uc *projectUseCase) DeleteCustomField(userId string, id string) error {
+
+	current, err := uc.fieldRepo.GetById(id)
+	if err != nil {
+		errorMessage := fmt.Errorf(" Failed to delete custom field: invalid id")
+		return errorMessage
+	}
+
+	if err := checkProjectManager(uc.projectRepo, current.ProjectId, userId); err != nil {
+		return fmt.Errorf(" Failed to delete custom field: %w", err)
+	}
+
+	if err := uc.fieldRepo.Delete(id); err != nil {
+		errorMessage := fmt.Errorf(" Failed to delete custom field: %s", err.Error())
+		return errorMessage
+	}
+
+	return nil
+}
+
+// checkProjectManager only lets the manager of the project manage its milestones, sprints and custom fields
+func checkProjectManager(projectRepo repository.ProjectRepository, projectId string, userId string) error {
+
+	project, err := projectRepo.GetById(projectId)
+	if err != nil {
+		return fmt.Errorf("invalid project id")
+	}
+	if project.ManagerId != userId {
+		return shared_model.ErrForbidden
+	}
+
+	return nil
+}
+
+// projectKeyPattern is the prefix of task keys such as WEB-42
+var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// maxProjectKeySuffix bounds the retries for a derived key; defaultProjectKey returns at most four
+// characters, so every suffix up to this still fits the ten character limit.
+const maxProjectKeySuffix = 99
+
+// defaultProjectKey derives a key from the project name: the initials of a
+// multi word name, otherwise its first three characters
+func defaultProjectKey(name string) string {
+	words := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+
+	var key string
+	if len(words) > 1 {
+		for _, word := range words[:min(len(words), 4)] {
+			key += word[:1]
+		}
+	} else if len(words) == 1 {
+		key = words[0][:min(len(words[0]), 3)]
+	}
+
+	if !projectKeyPattern.MatchString(key) {
+		return "PRJ"
+	}
+	return key
+}
+
+// validCustomFieldOptions requires options on select fields and rejects them elsewhere
+func validCustomFieldOptions(field model.CustomField) error {
+	selectable := field.Type == "single_select" || field.Type == "multi_select"
+	if selectable && len(field.Options) == 0 {
+		return fmt.Errorf(" Field 'options' cannot be empty for %s fields", field.Type)
+	}
+	if !selectable && len(field.Options) > 0 {
+		return fmt.Errorf(" Field 'options' is only allowed for select fields")
+	}
+	return nil
+}
+
+// setMilestoneProgress derives the progress percentage from accepted tasks
+func setMilestoneProgress(milestone *model.Milestone) {
+	if milestone.TotalTasks == 0 {
+		milestone.Progress = 0
+		return
+	}
+	milestone.Progress = float64(milestone.CompletedTasks) / float64(milestone.TotalTasks) * 100
+}
@@ -1,12 +1,14 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"enigma.com/projectmanagementhub/mock/repository_mock"
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
 	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,13 +19,17 @@ type ProjectUsecaseTest struct {
 	suite.Suite
 	arm *repository_mock.ProjectRepositoryMock
 	urm *repository_mock.UserRepositoryMock
+	mrm *repository_mock.MilestoneRepositoryMock
+	frm *repository_mock.CustomFieldRepositoryMock
 	auc ProjectUseCase
 }
 
 func (s *ProjectUsecaseTest) SetupTest() {
 	s.arm = new(repository_mock.ProjectRepositoryMock)
 	s.urm = new(repository_mock.UserRepositoryMock)
-	s.auc = NewProjectUseCase(s.arm, s.urm)
+	s.mrm = new(repository_mock.MilestoneRepositoryMock)
+	s.frm = new(repository_mock.CustomFieldRepositoryMock)
+	s.auc = NewProjectUseCase(s.arm, s.urm, s.mrm, s.frm, service.NewEventService())
 }
 
 var projectTest = model.Project{
@@ -149,6 +155,33 @@ func (s *ProjectUsecaseTest) TestCreateNewProjectFail() {
 	s.arm.AssertExpectations(s.T())
 }
 
+func (s *ProjectUsecaseTest) TestCreateNewProject_DerivedKeyTaken() {
+	s.arm.On("CreateProject", mock.MatchedBy(func(p model.Project) bool { return p.Key == "PO" })).Return(model.Project{}, shared_model.ErrKeyInUse)
+	s.arm.On("CreateProject", mock.MatchedBy(func(p model.Project) bool { return p.Key == "PO2" })).Return(model.Project{}, shared_model.ErrKeyInUse)
+	s.arm.On("CreateProject", mock.MatchedBy(func(p model.Project) bool { return p.Key == "PO3" })).Return(model.Project{Key: "PO3"}, nil)
+	payload := model.Project{
+		Name:      "Project One",
+		ManagerId: "managerID",
+		Deadline:  "2024-01-01",
+	}
+	createdProject, err := s.auc.CreateNewProject(payload)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "PO3", createdProject.Key)
+}
+
+func (s *ProjectUsecaseTest) TestCreateNewProject_GivenKeyTaken() {
+	s.arm.On("CreateProject", mock.AnythingOfType("model.Project")).Return(model.Project{}, shared_model.ErrKeyInUse)
+	payload := model.Project{
+		Name:      "Project One",
+		ManagerId: "managerID",
+		Deadline:  "2024-01-01",
+		Key:       "web",
+	}
+	_, err := s.auc.CreateNewProject(payload)
+	assert.True(s.T(), errors.Is(err, shared_model.ErrKeyInUse))
+	s.arm.AssertNumberOfCalls(s.T(), "CreateProject", 1)
+}
+
 // Test Add Project members succes
 func (suite *ProjectUsecaseTest) TestAddProjectMemberSuccess() {
 	suite.arm.On("AddProjectMember", projectTest.Id, mock.AnythingOfType("[]string")).Return(nil)
@@ -199,16 +232,16 @@ func (s *ProjectUsecaseTest) TestGetAllProjectMemberInvalidId() {
 
 // Test delete succes
 func (s *ProjectUsecaseTest) TestDeleteSuccess() {
-	s.arm.On("Delete", projectTest.Id).Return(nil)
-	err := s.auc.Delete(projectTest.Id)
+	s.arm.On("Delete", projectTest.Id, projectTest.ManagerId).Return(nil)
+	err := s.auc.Delete(projectTest.ManagerId, projectTest.Id)
 	assert.NoError(s.T(), err)
 	s.arm.AssertExpectations(s.T())
 }
 
 // Test delete fail
 func (s *ProjectUsecaseTest) TestDeleteFail() {
-	s.arm.On("Delete", projectTest.Id).Return(fmt.Errorf("Error deleting project"))
-	err := s.auc.Delete(projectTest.Id)
+	s.arm.On("Delete", projectTest.Id, projectTest.ManagerId).Return(fmt.Errorf("Error deleting project"))
+	err := s.auc.Delete(projectTest.ManagerId, projectTest.Id)
 	// Assertions
 	s.Error(err)
 }
@@ -240,6 +273,29 @@ func (s *ProjectUsecaseTest) TestUpdateProjectfail() {
 	s.urm.AssertExpectations(s.T())
 }
 
+// Test delete preview
+func (s *ProjectUsecaseTest) TestDeletePreviewSuccess() {
+	s.arm.On("GetById", projectTest.Id).Return(projectTest, nil)
+	s.arm.On("GetDeleteImpact", projectTest.Id).Return(model.DeleteImpact{Projects: 1, Tasks: 2, Reports: 3, Members: 1}, nil)
+	impact, err := s.auc.DeletePreview(projectTest.Id)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, impact.Reports)
+	s.arm.AssertNotCalled(s.T(), "Delete", projectTest.Id, mock.Anything)
+}
+
+// Test update fail on stale version
+func (s *ProjectUsecaseTest) TestUpdateProjectFailWithStaleVersion() {
+	current := projectTest
+	current.Version = 3
+	s.arm.On("GetById", projectTest.Id).Return(current, nil)
+
+	payload := projectTest
+	payload.Version = 2
+	_, err := s.auc.Update(payload)
+	assert.True(s.T(), errors.Is(err, shared_model.ErrVersionConflict))
+	s.arm.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
 // Test create new project failure with invalid deadline
 func (s *ProjectUsecaseTest) TestCreateNewProjectFailWithInvalidDeadline() {
 	// Payload with an invalid deadline
@@ -318,10 +374,10 @@ func (s *ProjectUsecaseTest) TestUpdateProjectFailWithRepositoryError() {
 // Test delete project failure with non-existing project
 func (s *ProjectUsecaseTest) TestDeleteProjectFailWithNonExistingProject() {
 	// Mocking dependencies
-	s.arm.On("Delete", projectTest.Id).Return(fmt.Errorf("Project not found"))
+	s.arm.On("Delete", projectTest.Id, projectTest.ManagerId).Return(fmt.Errorf("Project not found"))
 
 	// Call the use case method
-	err := s.auc.Delete(projectTest.Id)
+	err := s.auc.Delete(projectTest.ManagerId, projectTest.Id)
 
 	// Assertions
 	assert.Error(s.T(), err)
@@ -334,10 +390,10 @@ func (s *ProjectUsecaseTest) TestDeleteProjectFailWithNonExistingProject() {
 // Test delete project failure with repository error
 func (s *ProjectUsecaseTest) TestDeleteProjectFailWithRepositoryError() {
 	// Mocking dependencies
-	s.arm.On("Delete", projectTest.Id).Return(fmt.Errorf("Repository error"))
+	s.arm.On("Delete", projectTest.Id, projectTest.ManagerId).Return(fmt.Errorf("Repository error"))
 
 	// Call the use case method
-	err := s.auc.Delete(projectTest.Id)
+	err := s.auc.Delete(projectTest.ManagerId, projectTest.Id)
 
 	// Assertions
 	assert.Error(s.T(), err)
@@ -447,6 +503,156 @@ func (s *ProjectUsecaseTest) TestGetProjectsByDeadlineFailWithRepositoryError()
 	s.arm.AssertExpectations(s.T())
 }
 
+var milestoneTest = model.Milestone{
+	Id:        "milestone1",
+	ProjectId: projectTest.Id,
+	Name:      "Beta",
+	DueDate:   "2024-01-01",
+}
+
+// Test get milestones computes progress from accepted tasks
+func (s *ProjectUsecaseTest) TestGetMilestonesSuccess() {
+	milestone := milestoneTest
+	milestone.TotalTasks, milestone.CompletedTasks = 4, 1
+	s.arm.On("GetById", projectTest.Id).Return(projectTest, nil)
+	s.mrm.On("GetByProjectId", projectTest.Id).Return([]model.Milestone{milestone}, nil)
+
+	actual, err := s.auc.GetMilestones(projectTest.Id, projectTest.ManagerId)
+
+	s.NoError(err)
+	s.Equal(25.0, actual[0].Progress)
+	s.mrm.AssertExpectations(s.T())
+}
+
+// Test only the project's manager can manage milestones
+func (s *ProjectUsecaseTest) TestCreateMilestoneFailNotProjectManager() {
+	s.arm.On("GetById", projectTest.Id).Return(projectTest, nil)
+
+	_, err := s.auc.CreateMilestone("othermanager", milestoneTest)
+
+	s.True(errors.Is(err, shared_model.ErrForbidden))
+	s.mrm.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// Test attach tasks through the milestone's project
+func (s *ProjectUsecaseTest) TestAttachMilestoneTasksSuccess() {
+	s.mrm.On("GetById", milestoneTest.Id).Return(milestoneTest, nil)
+	s.arm.On("GetById", projectTest.Id).Return(projectTest, nil)
+	s.mrm.On("AttachTasks", milestoneTest.Id, []string{"task1"}).Return(nil)
+
+	err := s.auc.AttachMilestoneTasks(projectTest.ManagerId, milestoneTest.Id, []string{"task1"})
+
+	s.NoError(err)
+	s.mrm.AssertExpectations(s.T())
+}
+
+// Test delete milestone failure with invalid id
+func (s *ProjectUsecaseTest) TestDeleteMilestoneFailWithInvalidId() {
+	s.mrm.On("GetById", milestoneTest.Id).Return(model.Milestone{}, fmt.Errorf("not found"))
+
+	err := s.auc.DeleteMilestone(projectTest.ManagerId, milestoneTest.Id)
+
+	s.Error(err)
+	s.mrm.AssertNotCalled(s.T(), "Delete", milestoneTest.Id)
+}
+
 func TestProjectUsecase(t *testing.T) {
 	suite.Run(t, new(ProjectUsecaseTest))
 }
+
+var customFieldTest = model.CustomField{
+	Id:        "field1",
+	ProjectId: projectTest.Id,
+	Name:      "env",
+	Type:      "single_select",
+	Options:   []string{"dev", "prod"},
+}
+
+// Test create custom field by the project's manager
+func (s *ProjectUsecaseTest) TestCreateCustomFieldSuccess() {
+	s.arm.On("GetById", projectTest.Id).Return(projectTest, nil)
+	s.frm.On("Create", customFieldTest).Return(customFieldTest, nil)
+
+	actual, err := s.auc.CreateCustomField(projectTest.ManagerId, customFieldTest)
+
+	s.NoError(err)
+	s.Equal(customFieldTest, actual)
+}
+
+// Test select fields need options
+func (s *ProjectUsecaseTest) TestCreateCustomFieldFailWithoutOptions() {
+	field := customFieldTest
+	field.Options = nil
+
+	_, err := s.auc.CreateCustomField(projectTest.ManagerId, field)
+
+	s.Error(err)
+	s.frm.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// Test unknown field types are rejected
+func (s *ProjectUsecaseTest) TestCreateCustomFieldFailInvalidType() {
+	field := customFieldTest
+	field.Type = "checkbox"
+
+	_, err := s.auc.CreateCustomField(projectTest.ManagerId, field)
+
+	s.Error(err)
+	s.frm.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// Test update keeps the stored name and type
+func (s *ProjectUsecaseTest) TestUpdateCustomFieldKeepsNameAndType() {
+	payload := model.CustomField{Id: customFieldTest.Id, Name: "renamed", Type: "text", Options: []string{"dev", "prod", "staging"}, Required: true}
+	expected := customFieldTest
+	expected.Options, expected.Required = payload.Options, true
+	s.frm.On("GetById", customFieldTest.Id).Return(customFieldTest, nil)
+	s.arm.On("GetById", projectTest.Id).Return(projectTest, nil)
+	s.frm.On("Update", model.CustomField{Id: customFieldTest.Id, Name: "env", Type: "single_select", Options: payload.Options, Required: true}).Return(expected, nil)
+
+	actual, err := s.auc.UpdateCustomField(projectTest.ManagerId, payload)
+
+	s.NoError(err)
+	s.Equal(expected, actual)
+}
+
+// Test only the project's manager can delete custom fields
+func (s *ProjectUsecaseTest) TestDeleteCustomFieldFailNotProjectManager() {
+	s.frm.On("GetById", customFieldTest.Id).Return(customFieldTest, nil)
+	s.arm.On("GetById", projectTest.Id).Return(projectTest, nil)
+
+	err := s.auc.DeleteCustomField("othermanager", customFieldTest.Id)
+
+	s.True(errors.Is(err, shared_model.ErrForbidden))
+	s.frm.AssertNotCalled(s.T(), "Delete", mock.Anything)
+}
+
+// Test project key defaults to the name's initials
+func (s *ProjectUsecaseTest) TestCreateNewProjectDefaultKey() {
+	payload := model.Project{Name: "Website redesign", ManagerId: "managerID", Deadline: "2024-01-01"}
+	expected := payload
+	expected.Key = "WR"
+	s.arm.On("CreateProject", expected).Return(expected, nil)
+
+	_, err := s.auc.CreateNewProject(payload)
+
+	s.NoError(err)
+	s.arm.AssertExpectations(s.T())
+}
+
+// Test project keys must be short and start with a letter
+func (s *ProjectUsecaseTest) TestCreateNewProjectFailInvalidKey() {
+	payload := model.Project{Name: "Website", ManagerId: "managerID", Deadline: "2024-01-01", Key: "42-WEB"}
+
+	_, err := s.auc.CreateNewProject(payload)
+
+	s.Error(err)
+	s.arm.AssertNotCalled(s.T(), "CreateProject", mock.Anything)
+}
+
+func TestDefaultProjectKey(t *testing.T) {
+	assert.Equal(t, "WEB", defaultProjectKey("website"))
+	assert.Equal(t, "WR", defaultProjectKey("Website - redesign!"))
+	assert.Equal(t, "MARA", defaultProjectKey("mobile app rewrite again soon"))
+	assert.Equal(t, "PRJ", defaultProjectKey("42"))
+}
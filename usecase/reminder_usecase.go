@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
+)
+
+type ReminderUsecase interface {
+	GetSetting(userId string, projectId string) (model.ReminderSetting, error)
+	UpdateSetting(userId string, setting model.ReminderSetting) (model.ReminderSetting, error)
+	SendDue(now time.Time) (int, error)
+}
+
+type reminderUsecase struct {
+	reminderRepository repository.ReminderRepository
+	projectRepository  repository.ProjectRepository
+	eventService       service.EventService
+	defaultSetting     model.ReminderSetting
+}
+
+// GetSetting implements ReminderUsecase.
+// Projects that were never configured report the server default.
+func (r *reminderUsecase) GetSetting(userId string, projectId string) (model.ReminderSetting, error) {
+	if err := checkProjectManager(r.projectRepository, projectId, userId); err != nil {
+		return model.ReminderSetting{}, fmt.Errorf("failed to get reminder setting: %w", err)
+	}
+
+	setting, err := r.reminderRepository.GetSetting(projectId)
+	if errors.Is(err, sql.ErrNoRows) {
+		setting = r.defaultSetting
+		setting.ProjectId = projectId
+		return setting, nil
+	}
+	if err != nil {
+		return model.ReminderSetting{}, fmt.Errorf("failed to get reminder setting: %s", err.Error())
+	}
+
+	return setting, nil
+}
+
+// UpdateSetting implements ReminderUsecase.
+func (r *reminderUsecase) UpdateSetting(userId string, setting model.ReminderSetting) (model.ReminderSetting, error) {
+	if setting.DaysBefore < 0 || setting.EscalateAfterDays < 0 {
+		return model.ReminderSetting{}, fmt.Errorf("failed to update reminder setting. days cannot be negative")
+	}
+
+	if err := checkProjectManager(r.projectRepository, setting.ProjectId, userId); err != nil {
+		return model.ReminderSetting{}, fmt.Errorf("failed to update reminder setting: %w", err)
+	}
+
+	saved, err := r.reminderRepository.SaveSetting(setting)
+	if err != nil {
+		return model.ReminderSetting{}, fmt.Errorf("failed to update reminder setting: %s", err.Error())
+	}
+
+	return saved, nil
+}
+
+// SendDue implements ReminderUsecase.
+// Tasks due within the reminder window remind their person in charge, overdue tasks remind them again and,
+// once overdue for the escalation period, escalate to the project manager. Project deadlines go to the manager.
+// Every reminder is recorded before it is published so it fires once per deadline.
+func (r *reminderUsecase) SendDue(now time.Time) (int, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	tasks, err := r.reminderRepository.GetDueTasks(today, r.defaultSetting.DaysBefore, r.defaultSetting.EscalateAfterDays)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send reminders: %s", err.Error())
+	}
+	projects, err := r.reminderRepository.GetDueProjects(today, r.defaultSetting.DaysBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send reminders: %s", err.Error())
+	}
+
+	var sent int
+	var firstErr error
+	send := func(reminder model.Reminder, event model.Event) {
+		ok, err := r.reminderRepository.MarkSent(reminder)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		if !ok {
+			return
+		}
+
+		event.RecipientIds = []string{reminder.RecipientId}
+		event.OccurredAt = now
+		r.eventService.Publish(event)
+		sent++
+	}
+
+	for _, task := range tasks {
+		daysLate := int(today.Sub(task.Deadline).Hours() / 24)
+		data := map[string]any{"key": task.Key, "name": task.Name, "deadline": task.Deadline.Format("2006-01-02")}
+		reminder := model.Reminder{TaskId: task.TaskId, ProjectId: task.ProjectId, Deadline: task.Deadline}
+
+		if daysLate <= 0 {
+			reminder.Kind, reminder.RecipientId = model.ReminderDueSoon, task.PersonInCharge
+			data["days_left"] = -daysLate
+			send(reminder, model.Event{Type: model.EventTaskDueSoon, ProjectId: task.ProjectId, TaskId: task.TaskId, Data: data})
+			continue
+		}
+
+		data["days_overdue"] = daysLate
+		reminder.Kind, reminder.RecipientId = model.ReminderOverdue, task.PersonInCharge
+		send(reminder, model.Event{Type: model.EventTaskOverdue, ProjectId: task.ProjectId, TaskId: task.TaskId, Data: data})
+
+		if daysLate >= task.EscalateAfterDays {
+			data := map[string]any{"key": task.Key, "name": task.Name, "deadline": data["deadline"], "days_overdue": daysLate, "person_in_charge": task.PersonInCharge}
+			reminder.Kind, reminder.RecipientId = model.ReminderEscalated, task.ManagerId
+			send(reminder, model.Event{Type: model.EventTaskEscalated, ProjectId: task.ProjectId, TaskId: task.TaskId, Data: data})
+		}
+	}
+
+	for _, project := range projects {
+		daysLate := int(today.Sub(project.Deadline).Hours() / 24)
+		data := map[string]any{"key": project.Key, "name": project.Name, "deadline": project.Deadline.Format("2006-01-02")}
+		reminder := model.Reminder{ProjectId: project.ProjectId, Deadline: project.Deadline, RecipientId: project.ManagerId}
+		event := model.Event{ProjectId: project.ProjectId, Data: data}
+
+		if daysLate <= 0 {
+			reminder.Kind, event.Type = model.ReminderDueSoon, model.EventProjectDueSoon
+			data["days_left"] = -daysLate
+		} else {
+			reminder.Kind, event.Type = model.ReminderOverdue, model.EventProjectOverdue
+			data["days_overdue"] = daysLate
+		}
+		send(reminder, event)
+	}
+
+	if firstErr != nil {
+		return sent, fmt.Errorf("failed to send reminders: %s", firstErr.Error())
+	}
+
+	log.Printf("Send reminders for %s: %d sent", today.Format("2006-01-02"), sent)
+	return sent, nil
+}
+
+func NewReminderUsecase(reminderRepository repository.ReminderRepository, projectRepository repository.ProjectRepository, eventService service.EventService, daysBefore int, escalateAfterDays int) ReminderUsecase {
+	return &reminderUsecase{
+		reminderRepository: reminderRepository,
+		projectRepository:  projectRepository,
+		eventService:       eventService,
+		defaultSetting:     model.ReminderSetting{Enabled: true, DaysBefore: daysBefore, EscalateAfterDays: escalateAfterDays},
+	}
+}
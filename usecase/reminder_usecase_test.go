@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReminderUsecaseTest struct {
+	suite.Suite
+	rrm    *repository_mock.ReminderRepositoryMock
+	prm    *repository_mock.ProjectRepositoryMock
+	events []model.Event
+	ruc    ReminderUsecase
+}
+
+func (r *ReminderUsecaseTest) SetupTest() {
+	r.rrm = new(repository_mock.ReminderRepositoryMock)
+	r.prm = new(repository_mock.ProjectRepositoryMock)
+	r.events = nil
+	eventService := service.NewEventService()
+	eventService.Subscribe(func(event model.Event) { r.events = append(r.events, event) })
+	r.ruc = NewReminderUsecase(r.rrm, r.prm, eventService, 1, 2)
+}
+
+func TestReminderUsecase(t *testing.T) {
+	suite.Run(t, new(ReminderUsecaseTest))
+}
+
+var reminderNow = time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC)
+var reminderToday = time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func (r *ReminderUsecaseTest) TestGetSetting_DefaultWhenUnset() {
+	r.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+	r.rrm.On("GetSetting", "p1").Return(model.ReminderSetting{}, sql.ErrNoRows)
+
+	setting, err := r.ruc.GetSetting("m1", "p1")
+
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), model.ReminderSetting{ProjectId: "p1", Enabled: true, DaysBefore: 1, EscalateAfterDays: 2}, setting)
+}
+
+func (r *ReminderUsecaseTest) TestUpdateSetting_NotManager() {
+	r.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+
+	_, err := r.ruc.UpdateSetting("m2", model.ReminderSetting{ProjectId: "p1", DaysBefore: 3})
+
+	assert.True(r.T(), errors.Is(err, shared_model.ErrForbidden))
+	r.rrm.AssertNotCalled(r.T(), "SaveSetting", mock.Anything)
+}
+
+func (r *ReminderUsecaseTest) TestUpdateSetting_NegativeDays() {
+	_, err := r.ruc.UpdateSetting("m1", model.ReminderSetting{ProjectId: "p1", DaysBefore: -1})
+
+	assert.Error(r.T(), err)
+}
+
+func (r *ReminderUsecaseTest) TestSendDue_RemindsAndEscalates() {
+	tomorrow, threeDaysAgo := reminderToday.AddDate(0, 0, 1), reminderToday.AddDate(0, 0, -3)
+	r.rrm.On("GetDueTasks", reminderToday, 1, 2).Return([]model.DueItem{
+		{TaskId: "t1", ProjectId: "p1", Key: "WEB-1", Deadline: tomorrow, PersonInCharge: "u1", ManagerId: "m1", EscalateAfterDays: 2},
+		{TaskId: "t2", ProjectId: "p1", Key: "WEB-2", Deadline: threeDaysAgo, PersonInCharge: "u2", ManagerId: "m1", EscalateAfterDays: 2},
+	}, nil)
+	r.rrm.On("GetDueProjects", reminderToday, 1).Return([]model.DueItem{}, nil)
+	r.rrm.On("MarkSent", model.Reminder{TaskId: "t1", ProjectId: "p1", Kind: model.ReminderDueSoon, Deadline: tomorrow, RecipientId: "u1"}).Return(true, nil)
+	r.rrm.On("MarkSent", model.Reminder{TaskId: "t2", ProjectId: "p1", Kind: model.ReminderOverdue, Deadline: threeDaysAgo, RecipientId: "u2"}).Return(true, nil)
+	r.rrm.On("MarkSent", model.Reminder{TaskId: "t2", ProjectId: "p1", Kind: model.ReminderEscalated, Deadline: threeDaysAgo, RecipientId: "m1"}).Return(true, nil)
+
+	sent, err := r.ruc.SendDue(reminderNow)
+
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), 3, sent)
+	assert.Equal(r.T(), model.EventTaskDueSoon, r.events[0].Type)
+	assert.Equal(r.T(), []string{"u1"}, r.events[0].RecipientIds)
+	assert.Equal(r.T(), model.EventTaskOverdue, r.events[1].Type)
+	assert.Equal(r.T(), 3, r.events[1].Data["days_overdue"])
+	assert.Equal(r.T(), model.EventTaskEscalated, r.events[2].Type)
+	assert.Equal(r.T(), []string{"m1"}, r.events[2].RecipientIds)
+}
+
+func (r *ReminderUsecaseTest) TestSendDue_SkipsAlreadySentAndEarlyEscalation() {
+	yesterday := reminderToday.AddDate(0, 0, -1)
+	r.rrm.On("GetDueTasks", reminderToday, 1, 2).Return([]model.DueItem{
+		{TaskId: "t2", ProjectId: "p1", Deadline: yesterday, PersonInCharge: "u2", ManagerId: "m1", EscalateAfterDays: 2},
+	}, nil)
+	r.rrm.On("GetDueProjects", reminderToday, 1).Return([]model.DueItem{
+		{ProjectId: "p1", Deadline: yesterday, ManagerId: "m1"},
+	}, nil)
+	r.rrm.On("MarkSent", model.Reminder{TaskId: "t2", ProjectId: "p1", Kind: model.ReminderOverdue, Deadline: yesterday, RecipientId: "u2"}).Return(false, nil)
+	r.rrm.On("MarkSent", model.Reminder{ProjectId: "p1", Kind: model.ReminderOverdue, Deadline: yesterday, RecipientId: "m1"}).Return(true, nil)
+
+	sent, err := r.ruc.SendDue(reminderNow)
+
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), 1, sent)
+	assert.Len(r.T(), r.events, 1)
+	assert.Equal(r.T(), model.EventProjectOverdue, r.events[0].Type)
+}
+
+func (r *ReminderUsecaseTest) TestSendDue_QueryFails() {
+	r.rrm.On("GetDueTasks", reminderToday, 1, 2).Return([]model.DueItem(nil), errors.New("error"))
+
+	_, err := r.ruc.SendDue(reminderNow)
+
+	assert.Error(r.T(), err)
+}
@@ -3,22 +3,41 @@ package usecase
 import (
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"time"
 
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/report"
 	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
 )
 
 type ReportUsecase interface {
-	CreateReport(payload model.Report) (model.Report, error)
-	UpdateReport(payload model.Report) (model.Report, error)
-	DeleteReportById(id string) error
+	CreateReport(userId string, payload model.Report) (model.Report, error)
+	UpdateReport(userId string, payload model.Report) (model.Report, error)
+	DeleteReportById(userId string, id string) error
 	GetReportByTaskId(taskId string) ([]model.Report, error)
 	GetReportByUserId(userId string) ([]model.Report, error)
+	VerifyJournal(from string, to string) (model.JournalVerification, error)
+	ArchiveJournal() (model.JournalArchive, error)
+	DownloadArchive(from string, to string, w io.Writer) error
+	SearchReports(userId string, filter model.ReportFilter, page int, size int) ([]model.Report, shared_model.Paging, error)
+	ReviewReport(userId string, id string, review model.ReportReview) (model.Report, error)
+	GetMissingReports(userId string, projectId string, date string) ([]model.MissingReport, error)
 }
 
 type reportUsecase struct {
 	reportRepository repository.ReportRepository
 	taskRepo         repository.TaskRepository
+	projectRepo      repository.ProjectRepository
+	userRepo         repository.UserRepository
+	eventService     service.EventService
+	journalVerifier  report.JournalVerifier
+	journalArchiver  report.JournalArchiver
 }
 
 // GetReportUserId implements ReportUsecase.
@@ -39,13 +58,22 @@ func (r *reportUsecase) GetReportByUserId(userId string) ([]model.Report, error)
 }
 
 // CreateReport implements ReportUsecase.
-func (r *reportUsecase) CreateReport(payload model.Report) (model.Report, error) {
+// userId is the reporter and the journal's actor; a payload naming another user is refused.
+// A standup report needs a today section; its linked tasks must belong to the reporter.
+func (r *reportUsecase) CreateReport(userId string, payload model.Report) (model.Report, error) {
+	if err := checkReporter(userId, &payload); err != nil {
+		return model.Report{}, err
+	}
 
 	_, err := r.taskRepo.GetByPersonInCharge(payload.User_id)
 	if err != nil {
 		return model.Report{}, err
 	}
 
+	if err := r.checkStandup(&payload); err != nil {
+		return model.Report{}, err
+	}
+
 	if payload.Report == "" || payload.Task_id == "" || payload.User_id == "" {
 		return model.Report{}, errors.New("report cannot be empty")
 	}
@@ -55,15 +83,145 @@ func (r *reportUsecase) CreateReport(payload model.Report) (model.Report, error)
 		return model.Report{}, err
 	}
 
+	// the project manager and the person in charge hear about reports they did not write
+	if task, err := r.taskRepo.GetById(payload.Task_id); err == nil {
+		project, _ := r.projectRepo.GetById(task.ProjectId)
+		r.eventService.Publish(model.Event{
+			Type:         model.EventReportCreated,
+			ProjectId:    task.ProjectId,
+			TaskId:       task.Id,
+			ActorId:      payload.User_id,
+			RecipientIds: []string{project.ManagerId, task.PersonInCharge},
+			Data:         model.EventData{"key": task.Key, "name": task.Name, "report_id": report.Id, "report": report.Report},
+			OccurredAt:   time.Now(),
+		})
+	}
+
+	if payload.Standup != nil && payload.Standup.Blockers != "" {
+		r.flagBlocked(payload)
+	}
+
 	return report, nil
 }
 
+// checkReporter fills in the report's user from the token and refuses reports written as someone else
+func checkReporter(userId string, payload *model.Report) error {
+	if payload.User_id == "" {
+		payload.User_id = userId
+	}
+	if userId == "" || payload.User_id != userId {
+		return shared_model.ErrForbidden
+	}
+	return nil
+}
+
+// checkStandup trims and validates a standup, checks its linked tasks and renders
+// it as the report text when no free text was given.
+func (r *reportUsecase) checkStandup(payload *model.Report) error {
+	standup := payload.Standup
+	if standup == nil {
+		return nil
+	}
+
+	standup.Yesterday = strings.TrimSpace(standup.Yesterday)
+	standup.Today = strings.TrimSpace(standup.Today)
+	standup.Blockers = strings.TrimSpace(standup.Blockers)
+	if standup.Today == "" {
+		return errors.New("standup today section cannot be empty")
+	}
+
+	taskIds := []string{}
+	seen := map[string]bool{}
+	for _, id := range standup.TaskIds {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		task, err := r.taskRepo.GetById(id)
+		if err != nil {
+			return fmt.Errorf("linked task %s not found", id)
+		}
+		if task.PersonInCharge != payload.User_id {
+			return fmt.Errorf("linked task %s is not assigned to you", id)
+		}
+		taskIds = append(taskIds, task.Id)
+	}
+	standup.TaskIds = taskIds
+
+	if strings.TrimSpace(payload.Report) == "" {
+		payload.Report = renderStandup(*standup)
+	}
+	return nil
+}
+
+// renderStandup gives the free-text form of a standup, as written to the report journal
+func renderStandup(standup model.Standup) string {
+	blockers := standup.Blockers
+	if blockers == "" {
+		blockers = "-"
+	}
+	yesterday := standup.Yesterday
+	if yesterday == "" {
+		yesterday = "-"
+	}
+	return fmt.Sprintf("Yesterday: %s\nToday: %s\nBlockers: %s", yesterday, standup.Today, blockers)
+}
+
+// flagBlocked moves the standup's tasks to Blocked and tells their project manager.
+// Tasks that are accepted or waiting for approval keep their status; the manager is only told.
+// The report is already saved, so failures are only logged.
+func (r *reportUsecase) flagBlocked(payload model.Report) {
+	taskIds := payload.Standup.TaskIds
+	if len(taskIds) == 0 {
+		taskIds = []string{payload.Task_id}
+	}
+
+	for _, id := range taskIds {
+		previous, err := r.taskRepo.GetById(id)
+		if err != nil {
+			log.Println("report_usecase.flagBlocked", err.Error())
+			continue
+		}
+		if previous.PersonInCharge != payload.User_id {
+			continue
+		}
+
+		task := previous
+		if previous.Status != "Blocked" && previous.Status != "Accepted" && previous.Status != "Waiting Approval" {
+			task, err = r.taskRepo.UpdateTaskByMember(model.Task{Id: previous.Id, PersonInCharge: payload.User_id, Status: "Blocked"})
+			if err != nil {
+				log.Println("report_usecase.flagBlocked", err.Error())
+				continue
+			}
+			publishTaskChanges(r.eventService, payload.User_id, previous, task)
+		}
+
+		project, err := r.projectRepo.GetById(task.ProjectId)
+		if err != nil {
+			log.Println("report_usecase.flagBlocked", err.Error())
+			continue
+		}
+		r.eventService.Publish(model.Event{
+			Type:         model.EventTaskBlocked,
+			ProjectId:    task.ProjectId,
+			TaskId:       task.Id,
+			ActorId:      payload.User_id,
+			RecipientIds: []string{project.ManagerId},
+			Data:         model.EventData{"key": previous.Key, "name": previous.Name, "blockers": payload.Standup.Blockers},
+			OccurredAt:   time.Now(),
+		})
+	}
+}
+
 // DeleteReportById implements ReportUsecase.
-func (r *reportUsecase) DeleteReportById(id string) error {
+// The journal records userId as the one who deleted the report.
+func (r *reportUsecase) DeleteReportById(userId string, id string) error {
 	if id == "" {
 		return fmt.Errorf("report id cannot be empty")
 	}
-	return r.reportRepository.DeleteReportById(id)
+	return r.reportRepository.DeleteReportById(id, userId)
 }
 
 // GetReportByTaskId implements ReportUsecase.
@@ -85,26 +243,246 @@ func (r *reportUsecase) GetReportByTaskId(taskId string) ([]model.Report, error)
 }
 
 // UpdateReport implements ReportUsecase.
-func (r *reportUsecase) UpdateReport(payload model.Report) (model.Report, error) {
+// Only the reporter can update a report, and userId must be that reporter.
+func (r *reportUsecase) UpdateReport(userId string, payload model.Report) (model.Report, error) {
+	if err := checkReporter(userId, &payload); err != nil {
+		return model.Report{}, err
+	}
+
 	_, err := r.taskRepo.GetById(payload.Task_id)
 	if err != nil {
 		return model.Report{}, err
 	}
 
+	if err := r.checkStandup(&payload); err != nil {
+		return model.Report{}, err
+	}
+
 	if payload.Id == "" || payload.User_id == "" || payload.Report == "" || payload.Task_id == "" {
 		return model.Report{}, fmt.Errorf("report cannot be empty")
 	}
 
 	reports, err := r.reportRepository.UpdateReport(payload)
 	if err != nil {
-		return model.Report{}, fmt.Errorf("failed to update report : %v", err)
+		return model.Report{}, fmt.Errorf("failed to update report : %w", err)
 	}
 	return reports, nil
 }
 
-func NewReportUsecase(reportRepository repository.ReportRepository, taskRepo repository.TaskRepository) ReportUsecase {
+// VerifyJournal implements ReportUsecase.
+// Dates are YYYY-MM-DD; from defaults to today and to defaults to from.
+func (r *reportUsecase) VerifyJournal(from string, to string) (model.JournalVerification, error) {
+	if from == "" {
+		from = time.Now().Format("2006-01-02")
+	}
+	if to == "" {
+		to = from
+	}
+
+	fromDate, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return model.JournalVerification{}, fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+	}
+	toDate, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return model.JournalVerification{}, fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+	}
+	if toDate.Before(fromDate) {
+		return model.JournalVerification{}, fmt.Errorf("to date cannot be before from date")
+	}
+
+	result, err := r.journalVerifier.Verify(fromDate, toDate)
+	if err != nil {
+		return model.JournalVerification{}, fmt.Errorf("failed to verify report journal: %s", err.Error())
+	}
+
+	return result, nil
+}
+
+// ArchiveJournal implements ReportUsecase.
+func (r *reportUsecase) ArchiveJournal() (model.JournalArchive, error) {
+	result, err := r.journalArchiver.Archive(time.Now())
+	if err != nil {
+		return result, fmt.Errorf("failed to archive report files: %s", err.Error())
+	}
+
+	return result, nil
+}
+
+// DownloadArchive implements ReportUsecase.
+// Writes a zip of the report files from from to to (YYYY-MM-DD, both required) to w.
+func (r *reportUsecase) DownloadArchive(from string, to string, w io.Writer) error {
+	if from == "" || to == "" {
+		return fmt.Errorf("from and to dates are required")
+	}
+	fromDate, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+	}
+	toDate, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+	}
+	if toDate.Before(fromDate) {
+		return fmt.Errorf("to date cannot be before from date")
+	}
+
+	if err := r.journalArchiver.Export(fromDate, toDate, w); err != nil {
+		return fmt.Errorf("failed to export report files: %s", err.Error())
+	}
+
+	return nil
+}
+
+// uuidPattern matches the ids the report search filters on
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// SearchReports implements ReportUsecase.
+// Admins search every report, managers the reports of the projects they manage. Results are sorted by
+// created_at or updated_at, newest first unless order is asc.
+func (r *reportUsecase) SearchReports(userId string, filter model.ReportFilter, page int, size int) ([]model.Report, shared_model.Paging, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = "created_at"
+	}
+	if filter.Sort != "created_at" && filter.Sort != "updated_at" {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports. sort must be created_at or updated_at")
+	}
+	if filter.Order == "" {
+		filter.Order = "desc"
+	}
+	if filter.Order != "asc" && filter.Order != "desc" {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports. order must be asc or desc")
+	}
+
+	var fromDate, toDate time.Time
+	var err error
+	if filter.From != "" {
+		if fromDate, err = time.Parse("2006-01-02", filter.From); err != nil {
+			return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports. invalid from date, expected YYYY-MM-DD")
+		}
+	}
+	if filter.To != "" {
+		if toDate, err = time.Parse("2006-01-02", filter.To); err != nil {
+			return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports. invalid to date, expected YYYY-MM-DD")
+		}
+	}
+	if filter.From != "" && filter.To != "" && toDate.Before(fromDate) {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports. to date cannot be before from date")
+	}
+	// the ids are compared as uuids, so the indexes on them can be used
+	for _, id := range [][2]string{{"user_id", filter.UserId}, {"task_id", filter.TaskId}, {"project_id", filter.ProjectId}} {
+		if id[1] != "" && !uuidPattern.MatchString(id[1]) {
+			return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports. invalid %s", id[0])
+		}
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	user, err := r.userRepo.GetById(userId)
+	if err != nil {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports. invalid user id")
+	}
+	filter.ManagerId = ""
+	if user.Role != "ADMIN" {
+		if filter.ProjectId != "" {
+			if err := checkProjectManager(r.projectRepo, filter.ProjectId, userId); err != nil {
+				return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports: %w", err)
+			}
+		}
+		filter.ManagerId = userId
+	}
+
+	reports, paging, err := r.reportRepository.SearchReports(filter, page, size)
+	if err != nil {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to search reports: %s", err.Error())
+	}
+
+	return reports, paging, nil
+}
+
+// ReviewReport implements ReportUsecase.
+// Only the manager of the report's project can acknowledge it or ask for more info; the author is notified.
+func (r *reportUsecase) ReviewReport(userId string, id string, review model.ReportReview) (model.Report, error) {
+	if review.Status != model.ReportAcknowledged && review.Status != model.ReportNeedsMoreInfo {
+		return model.Report{}, fmt.Errorf("failed to review report. status must be %s or %s", model.ReportAcknowledged, model.ReportNeedsMoreInfo)
+	}
+	review.Comment = strings.TrimSpace(review.Comment)
+
+	current, err := r.reportRepository.GetReportById(id)
+	if err != nil {
+		return model.Report{}, fmt.Errorf("failed to review report. report not found")
+	}
+	task, err := r.taskRepo.GetById(current.Task_id)
+	if err != nil {
+		return model.Report{}, fmt.Errorf("failed to review report. task not found")
+	}
+	if err := checkProjectManager(r.projectRepo, task.ProjectId, userId); err != nil {
+		return model.Report{}, fmt.Errorf("failed to review report: %w", err)
+	}
+
+	reviewed, err := r.reportRepository.ReviewReport(id, userId, review)
+	if err != nil {
+		return model.Report{}, fmt.Errorf("failed to review report: %s", err.Error())
+	}
+
+	r.eventService.Publish(model.Event{
+		Type:         model.EventReportReviewed,
+		ProjectId:    task.ProjectId,
+		TaskId:       task.Id,
+		ActorId:      userId,
+		RecipientIds: []string{reviewed.User_id},
+		Data:         model.EventData{"key": task.Key, "name": task.Name, "report_id": reviewed.Id, "status": reviewed.ReviewStatus, "comment": reviewed.ReviewComment},
+		OccurredAt:   time.Now(),
+	})
+
+	return reviewed, nil
+}
+
+// GetMissingReports implements ReportUsecase.
+// Lists the project's members with active tasks who wrote no report on date (YYYY-MM-DD, default today).
+func (r *reportUsecase) GetMissingReports(userId string, projectId string, date string) ([]model.MissingReport, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("failed to get missing reports. invalid date, expected YYYY-MM-DD")
+	}
+
+	user, err := r.userRepo.GetById(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get missing reports. invalid user id")
+	}
+	if user.Role != "ADMIN" {
+		if err := checkProjectManager(r.projectRepo, projectId, userId); err != nil {
+			return nil, fmt.Errorf("failed to get missing reports: %w", err)
+		}
+	}
+
+	missing, err := r.reportRepository.GetMissingReports(projectId, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get missing reports: %s", err.Error())
+	}
+
+	return missing, nil
+}
+
+func NewReportUsecase(reportRepository repository.ReportRepository, taskRepo repository.TaskRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository, eventService service.EventService, journalVerifier report.JournalVerifier, journalArchiver report.JournalArchiver) ReportUsecase {
 	return &reportUsecase{
 		reportRepository: reportRepository,
 		taskRepo:         taskRepo,
+		projectRepo:      projectRepo,
+		userRepo:         userRepo,
+		eventService:     eventService,
+		journalVerifier:  journalVerifier,
+		journalArchiver:  journalArchiver,
 	}
 }
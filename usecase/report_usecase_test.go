@@ -1,19 +1,30 @@
 package usecase
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 	"time"
 
+	"enigma.com/projectmanagementhub/mock/report_mock"
 	"enigma.com/projectmanagementhub/mock/repository_mock"
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type ReportUsecaseSuite struct {
 	suite.Suite
-	reportRepo *repository_mock.ReportRepositoryMock
-	taskRepo   *repository_mock.TaskRepositoryMock
-	ReportUc   ReportUsecase
+	reportRepo  *repository_mock.ReportRepositoryMock
+	taskRepo    *repository_mock.TaskRepositoryMock
+	projectRepo *repository_mock.ProjectRepositoryMock
+	userRepo    *repository_mock.UserRepositoryMock
+	verifier    *report_mock.JournalVerifierMock
+	archiver    *report_mock.JournalArchiverMock
+	events      []model.Event
+	ReportUc    ReportUsecase
 }
 
 var ExpectedReport = model.Report{
@@ -41,7 +52,14 @@ var ExpectedTask = model.Task{
 func (t *ReportUsecaseSuite) SetupTest() {
 	t.reportRepo = &repository_mock.ReportRepositoryMock{}
 	t.taskRepo = &repository_mock.TaskRepositoryMock{}
-	t.ReportUc = NewReportUsecase(t.reportRepo, t.taskRepo)
+	t.projectRepo = &repository_mock.ProjectRepositoryMock{}
+	t.userRepo = &repository_mock.UserRepositoryMock{}
+	t.verifier = &report_mock.JournalVerifierMock{}
+	t.archiver = &report_mock.JournalArchiverMock{}
+	t.events = nil
+	eventService := service.NewEventService()
+	eventService.Subscribe(func(event model.Event) { t.events = append(t.events, event) })
+	t.ReportUc = NewReportUsecase(t.reportRepo, t.taskRepo, t.projectRepo, t.userRepo, eventService, t.verifier, t.archiver)
 }
 
 // func unit test to get report by user id
@@ -83,25 +101,111 @@ func (t *ReportUsecaseSuite) TestCreateReport_Success() {
 	// t.reportRepo.On("GetById", ExpectedTask.Id).Return(ExpectedTask, nil)
 	t.taskRepo.On("GetByPersonInCharge", ExpectedTask.PersonInCharge).Return([]model.Task{ExpectedTask}, nil)
 	t.reportRepo.On("CreateReport", ExpectedReport).Return(ExpectedReport, nil)
-	actual, err := t.ReportUc.CreateReport(ExpectedReport)
+	t.taskRepo.On("GetById", ExpectedTask.Id).Return(ExpectedTask, nil)
+	t.projectRepo.On("GetById", ExpectedTask.ProjectId).Return(model.Project{Id: ExpectedTask.ProjectId, ManagerId: "manager_id"}, nil)
+	actual, err := t.ReportUc.CreateReport(ExpectedReport.User_id, ExpectedReport)
 	t.NoError(err)
 	t.Nil(err)
 	t.Equal(ExpectedReport.Report, actual.Report)
+	t.Equal(model.EventReportCreated, t.events[0].Type)
+	t.Equal([]string{"manager_id", ExpectedTask.PersonInCharge}, t.events[0].RecipientIds)
 }
 
 func (t *ReportUsecaseSuite) TestCreateReport_Failed() {
 	t.taskRepo.On("GetByPersonInCharge", ExpectedTask.PersonInCharge).Return([]model.Task{ExpectedTask}, nil)
 	t.reportRepo.On("CreateReport", ExpectedReport).Return(model.Report{}, nil)
-	_, err := t.ReportUc.CreateReport(ExpectedReport)
+	t.taskRepo.On("GetById", ExpectedTask.Id).Return(model.Task{}, fmt.Errorf("task not found"))
+	_, err := t.ReportUc.CreateReport(ExpectedReport.User_id, ExpectedReport)
 	t.NoError(err)
 	t.Nil(err)
 }
 
+func (t *ReportUsecaseSuite) TestCreateReport_StandupBlocked() {
+	payload := ExpectedReport
+	payload.Report = ""
+	payload.Standup = &model.Standup{Yesterday: " wrote tests ", Today: "fix login", Blockers: "waiting for API keys", TaskIds: []string{"task_id", "task_id"}}
+	blocked := ExpectedTask
+	blocked.Status = "Blocked"
+
+	t.taskRepo.On("GetByPersonInCharge", ExpectedTask.PersonInCharge).Return([]model.Task{ExpectedTask}, nil)
+	t.taskRepo.On("GetById", ExpectedTask.Id).Return(ExpectedTask, nil)
+	t.reportRepo.On("CreateReport", mock.MatchedBy(func(r model.Report) bool {
+		return r.Report == "Yesterday: wrote tests\nToday: fix login\nBlockers: waiting for API keys" && len(r.Standup.TaskIds) == 1
+	})).Return(ExpectedReport, nil)
+	t.projectRepo.On("GetById", ExpectedTask.ProjectId).Return(model.Project{Id: ExpectedTask.ProjectId, ManagerId: "manager_id"}, nil)
+	t.taskRepo.On("UpdateTaskByMember", model.Task{Id: ExpectedTask.Id, PersonInCharge: ExpectedTask.PersonInCharge, Status: "Blocked"}).Return(blocked, nil)
+
+	_, err := t.ReportUc.CreateReport(ExpectedReport.User_id, payload)
+	t.NoError(err)
+	t.taskRepo.AssertCalled(t.T(), "UpdateTaskByMember", mock.Anything)
+
+	last := t.events[len(t.events)-1]
+	t.Equal(model.EventTaskBlocked, last.Type)
+	t.Equal([]string{"manager_id"}, last.RecipientIds)
+	t.Equal("waiting for API keys", last.Data["blockers"])
+}
+
+func (t *ReportUsecaseSuite) TestCreateReport_StandupBlockedAwaitingApproval() {
+	waiting := ExpectedTask
+	waiting.Status = "Waiting Approval"
+	payload := ExpectedReport
+	payload.Standup = &model.Standup{Today: "answer review", Blockers: "reviewer is away", TaskIds: []string{waiting.Id}}
+
+	t.taskRepo.On("GetByPersonInCharge", ExpectedTask.PersonInCharge).Return([]model.Task{waiting}, nil)
+	t.taskRepo.On("GetById", waiting.Id).Return(waiting, nil)
+	t.reportRepo.On("CreateReport", mock.Anything).Return(ExpectedReport, nil)
+	t.projectRepo.On("GetById", waiting.ProjectId).Return(model.Project{Id: waiting.ProjectId, ManagerId: "manager_id"}, nil)
+
+	_, err := t.ReportUc.CreateReport(ExpectedReport.User_id, payload)
+	t.NoError(err)
+	t.taskRepo.AssertNotCalled(t.T(), "UpdateTaskByMember", mock.Anything)
+
+	last := t.events[len(t.events)-1]
+	t.Equal(model.EventTaskBlocked, last.Type)
+	t.Equal([]string{"manager_id"}, last.RecipientIds)
+}
+
+func (t *ReportUsecaseSuite) TestCreateReport_StandupInvalid() {
+	t.taskRepo.On("GetByPersonInCharge", ExpectedTask.PersonInCharge).Return([]model.Task{ExpectedTask}, nil)
+	t.taskRepo.On("GetById", "other_task").Return(model.Task{Id: "other_task", PersonInCharge: "someone_else"}, nil)
+
+	payload := ExpectedReport
+	payload.Standup = &model.Standup{Yesterday: "wrote tests", Today: "  "}
+	_, err := t.ReportUc.CreateReport(ExpectedReport.User_id, payload)
+	t.EqualError(err, "standup today section cannot be empty")
+
+	payload.Standup = &model.Standup{Today: "fix login", TaskIds: []string{"other_task"}}
+	_, err = t.ReportUc.CreateReport(ExpectedReport.User_id, payload)
+	t.EqualError(err, "linked task other_task is not assigned to you")
+	t.reportRepo.AssertNotCalled(t.T(), "CreateReport", mock.Anything)
+}
+
+func (t *ReportUsecaseSuite) TestCreateReport_AsAnotherUser() {
+	_, err := t.ReportUc.CreateReport("someone_else", ExpectedReport)
+
+	t.ErrorIs(err, shared_model.ErrForbidden)
+	t.reportRepo.AssertNotCalled(t.T(), "CreateReport", mock.Anything)
+}
+
+func (t *ReportUsecaseSuite) TestCreateReport_UserFromToken() {
+	payload := ExpectedReport
+	payload.User_id = ""
+	t.taskRepo.On("GetByPersonInCharge", ExpectedTask.PersonInCharge).Return([]model.Task{ExpectedTask}, nil)
+	t.reportRepo.On("CreateReport", ExpectedReport).Return(ExpectedReport, nil)
+	t.taskRepo.On("GetById", ExpectedTask.Id).Return(ExpectedTask, nil)
+	t.projectRepo.On("GetById", ExpectedTask.ProjectId).Return(model.Project{Id: ExpectedTask.ProjectId, ManagerId: "manager_id"}, nil)
+
+	_, err := t.ReportUc.CreateReport(ExpectedReport.User_id, payload)
+
+	t.NoError(err)
+	t.reportRepo.AssertExpectations(t.T())
+}
+
 // func unit test to update report
 func (t *ReportUsecaseSuite) TestUpdateReport_Success() {
 	t.taskRepo.On("GetById", ExpectedTask.Id).Return(ExpectedTask, nil)
 	t.reportRepo.On("UpdateReport", ExpectedReport).Return(ExpectedReport, nil)
-	actual, err := t.ReportUc.UpdateReport(ExpectedReport)
+	actual, err := t.ReportUc.UpdateReport(ExpectedReport.User_id, ExpectedReport)
 	t.NoError(err)
 	t.Nil(err)
 	t.Equal(ExpectedReport.Report, actual.Report)
@@ -110,22 +214,29 @@ func (t *ReportUsecaseSuite) TestUpdateReport_Success() {
 func (t *ReportUsecaseSuite) TestUpdateReport_Failed() {
 	t.taskRepo.On("GetById", ExpectedTask.Id).Return(ExpectedTask, nil)
 	t.reportRepo.On("UpdateReport", ExpectedReport).Return(model.Report{}, nil)
-	_, err := t.ReportUc.UpdateReport(ExpectedReport)
+	_, err := t.ReportUc.UpdateReport(ExpectedReport.User_id, ExpectedReport)
 	t.NoError(err)
 	t.Nil(err)
 }
 
+func (t *ReportUsecaseSuite) TestUpdateReport_AsAnotherUser() {
+	_, err := t.ReportUc.UpdateReport("someone_else", ExpectedReport)
+
+	t.ErrorIs(err, shared_model.ErrForbidden)
+	t.reportRepo.AssertNotCalled(t.T(), "UpdateReport", mock.Anything)
+}
+
 // func unit test to delete report
 func (t *ReportUsecaseSuite) TestDeleteReportById_Success() {
-	t.reportRepo.On("DeleteReportById", ExpectedReport.Id).Return(nil)
-	err := t.ReportUc.DeleteReportById(ExpectedReport.Id)
+	t.reportRepo.On("DeleteReportById", ExpectedReport.Id, "a1").Return(nil)
+	err := t.ReportUc.DeleteReportById("a1", ExpectedReport.Id)
 	t.NoError(err)
 	t.Nil(err)
 }
 
 func (t *ReportUsecaseSuite) TestDeleteReportById_Failed() {
-	t.reportRepo.On("DeleteReportById", ExpectedReport.Id).Return(nil)
-	err := t.ReportUc.DeleteReportById("")
+	t.reportRepo.On("DeleteReportById", ExpectedReport.Id, "a1").Return(nil)
+	err := t.ReportUc.DeleteReportById("a1", "")
 	t.Error(err)
 	t.NotNil(err)
 }
@@ -133,3 +244,120 @@ func (t *ReportUsecaseSuite) TestDeleteReportById_Failed() {
 func TestReportUscaseSuite(t *testing.T) {
 	suite.Run(t, new(ReportUsecaseSuite))
 }
+
+func (t *ReportUsecaseSuite) TestVerifyJournal_DefaultsToOneDay() {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	t.verifier.On("Verify", day, day).Return(model.JournalVerification{From: "2024-03-01", To: "2024-03-01", Valid: true}, nil)
+
+	actual, err := t.ReportUc.VerifyJournal("2024-03-01", "")
+
+	t.NoError(err)
+	t.True(actual.Valid)
+}
+
+func (t *ReportUsecaseSuite) TestVerifyJournal_InvalidRange() {
+	_, err := t.ReportUc.VerifyJournal("2024-03-02", "2024-03-01")
+	t.Error(err)
+
+	_, err = t.ReportUc.VerifyJournal("01-03-2024", "")
+	t.Error(err)
+	t.verifier.AssertNotCalled(t.T(), "Verify")
+}
+
+func (t *ReportUsecaseSuite) TestDownloadArchive_Success() {
+	var output bytes.Buffer
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local)
+	t.archiver.On("Export", from, to, &output).Return(nil)
+
+	err := t.ReportUc.DownloadArchive("2024-03-01", "2024-03-31", &output)
+
+	t.NoError(err)
+}
+
+func (t *ReportUsecaseSuite) TestDownloadArchive_InvalidRange() {
+	var output bytes.Buffer
+
+	t.Error(t.ReportUc.DownloadArchive("2024-03-01", "", &output))
+	t.Error(t.ReportUc.DownloadArchive("2024-03-31", "2024-03-01", &output))
+	t.archiver.AssertNotCalled(t.T(), "Export")
+}
+
+func (t *ReportUsecaseSuite) TestSearchReports_ManagerScope() {
+	p1, p2 := "5b0e4a3c-1f7e-4c39-9a52-3d8f1c6b2a01", "5b0e4a3c-1f7e-4c39-9a52-3d8f1c6b2a02"
+	paging := shared_model.Paging{Page: 1, RowsPerPage: 10, TotalRows: 1, TotalPages: 1}
+	t.userRepo.On("GetById", "m1").Return(model.User{Id: "m1", Role: "MANAGER"}, nil)
+	t.userRepo.On("GetById", "a1").Return(model.User{Id: "a1", Role: "ADMIN"}, nil)
+	t.projectRepo.On("GetById", p1).Return(model.Project{Id: p1, ManagerId: "m1"}, nil)
+	t.projectRepo.On("GetById", p2).Return(model.Project{Id: p2, ManagerId: "m2"}, nil)
+	t.reportRepo.On("SearchReports", model.ReportFilter{ProjectId: p1, Query: "login", ManagerId: "m1", Sort: "created_at", Order: "desc"}, 1, 10).Return([]model.Report{ExpectedReport}, paging, nil)
+	t.reportRepo.On("SearchReports", model.ReportFilter{Sort: "updated_at", Order: "asc"}, 3, 100).Return([]model.Report{}, paging, nil)
+
+	reports, _, err := t.ReportUc.SearchReports("m1", model.ReportFilter{ProjectId: p1, Query: " login "}, 0, 0)
+	t.NoError(err)
+	t.Len(reports, 1)
+
+	_, _, err = t.ReportUc.SearchReports("m1", model.ReportFilter{ProjectId: p2}, 1, 10)
+	t.ErrorIs(err, shared_model.ErrForbidden)
+
+	// admins are not scoped, even when the caller tries to set ManagerId
+	_, _, err = t.ReportUc.SearchReports("a1", model.ReportFilter{ManagerId: "m9", Sort: "updated_at", Order: "asc"}, 3, 500)
+	t.NoError(err)
+}
+
+func (t *ReportUsecaseSuite) TestSearchReports_InvalidFilter() {
+	_, _, err := t.ReportUc.SearchReports("m1", model.ReportFilter{Sort: "report"}, 1, 10)
+	t.EqualError(err, "failed to search reports. sort must be created_at or updated_at")
+
+	_, _, err = t.ReportUc.SearchReports("m1", model.ReportFilter{From: "2024-03-10", To: "2024-03-04"}, 1, 10)
+	t.EqualError(err, "failed to search reports. to date cannot be before from date")
+
+	_, _, err = t.ReportUc.SearchReports("m1", model.ReportFilter{From: "04-03-2024"}, 1, 10)
+	t.EqualError(err, "failed to search reports. invalid from date, expected YYYY-MM-DD")
+
+	_, _, err = t.ReportUc.SearchReports("m1", model.ReportFilter{TaskId: "task1"}, 1, 10)
+	t.EqualError(err, "failed to search reports. invalid task_id")
+	t.reportRepo.AssertNotCalled(t.T(), "SearchReports", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (t *ReportUsecaseSuite) TestReviewReport_NotifiesAuthor() {
+	review := model.ReportReview{Status: model.ReportNeedsMoreInfo, Comment: " which endpoint? "}
+	saved := model.ReportReview{Status: model.ReportNeedsMoreInfo, Comment: "which endpoint?"}
+	reviewed := ExpectedReport
+	reviewed.ReviewStatus, reviewed.ReviewComment = saved.Status, saved.Comment
+	t.reportRepo.On("GetReportById", ExpectedReport.Id).Return(ExpectedReport, nil)
+	t.taskRepo.On("GetById", ExpectedTask.Id).Return(ExpectedTask, nil)
+	t.projectRepo.On("GetById", ExpectedTask.ProjectId).Return(model.Project{Id: ExpectedTask.ProjectId, ManagerId: "manager_id"}, nil)
+	t.reportRepo.On("ReviewReport", ExpectedReport.Id, "manager_id", saved).Return(reviewed, nil)
+
+	actual, err := t.ReportUc.ReviewReport("manager_id", ExpectedReport.Id, review)
+	t.NoError(err)
+	t.Equal(model.ReportNeedsMoreInfo, actual.ReviewStatus)
+	t.Equal(model.EventReportReviewed, t.events[0].Type)
+	t.Equal([]string{ExpectedReport.User_id}, t.events[0].RecipientIds)
+	t.Equal("which endpoint?", t.events[0].Data["comment"])
+
+	_, err = t.ReportUc.ReviewReport("someone_else", ExpectedReport.Id, review)
+	t.ErrorIs(err, shared_model.ErrForbidden)
+
+	_, err = t.ReportUc.ReviewReport("manager_id", ExpectedReport.Id, model.ReportReview{Status: model.ReportSubmitted})
+	t.EqualError(err, "failed to review report. status must be acknowledged or needs-more-info")
+}
+
+func (t *ReportUsecaseSuite) TestGetMissingReports() {
+	missing := []model.MissingReport{{UserId: "u1", Name: "Budi", TaskKeys: []string{"WEB-1"}}}
+	t.userRepo.On("GetById", "manager_id").Return(model.User{Id: "manager_id", Role: "MANAGER"}, nil)
+	t.userRepo.On("GetById", "other_manager").Return(model.User{Id: "other_manager", Role: "MANAGER"}, nil)
+	t.projectRepo.On("GetById", "project_id").Return(model.Project{Id: "project_id", ManagerId: "manager_id"}, nil)
+	t.reportRepo.On("GetMissingReports", "project_id", "2024-03-04").Return(missing, nil)
+
+	actual, err := t.ReportUc.GetMissingReports("manager_id", "project_id", "2024-03-04")
+	t.NoError(err)
+	t.Equal(missing, actual)
+
+	_, err = t.ReportUc.GetMissingReports("other_manager", "project_id", "2024-03-04")
+	t.ErrorIs(err, shared_model.ErrForbidden)
+
+	_, err = t.ReportUc.GetMissingReports("manager_id", "project_id", "4 March")
+	t.EqualError(err, "failed to get missing reports. invalid date, expected YYYY-MM-DD")
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+)
+
+type SprintUsecase interface {
+	GetByProjectId(projectId string) ([]model.Sprint, error)
+	GetById(id string) (model.Sprint, error)
+	CreateSprint(userId string, payload model.Sprint) (model.Sprint, error)
+	UpdateSprint(userId string, payload model.Sprint) (model.Sprint, error)
+	StartSprint(userId string, id string) (model.Sprint, error)
+	CloseSprint(userId string, id string, rolloverTo string) (model.Sprint, error)
+	AddTasks(userId string, id string, taskIds []string) error
+	RemoveTasks(userId string, id string, taskIds []string) error
+}
+
+type sprintUsecase struct {
+	sprintRepository  repository.SprintRepository
+	projectRepository repository.ProjectRepository
+}
+
+// GetByProjectId implements SprintUsecase.
+func (s *sprintUsecase) GetByProjectId(projectId string) ([]model.Sprint, error) {
+	if _, err := s.projectRepository.GetById(projectId); err != nil {
+		return nil, fmt.Errorf("failed to get sprints. project id invalid")
+	}
+
+	sprints, err := s.sprintRepository.GetByProjectId(projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprints: %s", err.Error())
+	}
+
+	for i := range sprints {
+		setSprintSummary(&sprints[i])
+	}
+
+	return sprints, nil
+}
+
+// GetById implements SprintUsecase.
+func (s *sprintUsecase) GetById(id string) (model.Sprint, error) {
+	sprint, err := s.sprintRepository.GetById(id)
+	if err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to get sprint. sprint id invalid")
+	}
+
+	setSprintSummary(&sprint)
+	return sprint, nil
+}
+
+// CreateSprint implements SprintUsecase.
+func (s *sprintUsecase) CreateSprint(userId string, payload model.Sprint) (model.Sprint, error) {
+	if err := validateSprint(payload); err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to create sprint. %s", err.Error())
+	}
+
+	if err := checkProjectManager(s.projectRepository, payload.ProjectId, userId); err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to create sprint: %w", err)
+	}
+
+	return s.sprintRepository.Create(payload)
+}
+
+// UpdateSprint implements SprintUsecase.
+func (s *sprintUsecase) UpdateSprint(userId string, payload model.Sprint) (model.Sprint, error) {
+	if err := validateSprint(payload); err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to update sprint. %s", err.Error())
+	}
+
+	current, err := s.checkSprintManager(payload.Id, userId)
+	if err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to update sprint: %w", err)
+	}
+	if current.State == "closed" {
+		return model.Sprint{}, fmt.Errorf("failed to update sprint. sprint is closed")
+	}
+
+	sprint, err := s.sprintRepository.Update(payload)
+	if err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to update sprint: %s", err.Error())
+	}
+	sprint.Snapshot, sprint.Current = current.Snapshot, current.Current
+	setSprintSummary(&sprint)
+
+	return sprint, nil
+}
+
+// StartSprint implements SprintUsecase.
+func (s *sprintUsecase) StartSprint(userId string, id string) (model.Sprint, error) {
+	current, err := s.checkSprintManager(id, userId)
+	if err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to start sprint: %w", err)
+	}
+	if current.State != "planned" {
+		return model.Sprint{}, fmt.Errorf("failed to start sprint. sprint is %s", current.State)
+	}
+
+	if err := s.sprintRepository.Start(id); err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to start sprint: %s", err.Error())
+	}
+
+	return s.GetById(id)
+}
+
+// CloseSprint implements SprintUsecase.
+// Unfinished tasks roll over into rolloverTo, or go back to the backlog when it is empty.
+func (s *sprintUsecase) CloseSprint(userId string, id string, rolloverTo string) (model.Sprint, error) {
+	current, err := s.checkSprintManager(id, userId)
+	if err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to close sprint: %w", err)
+	}
+	if current.State != "active" {
+		return model.Sprint{}, fmt.Errorf("failed to close sprint. sprint is %s", current.State)
+	}
+
+	if rolloverTo != "" {
+		next, err := s.sprintRepository.GetById(rolloverTo)
+		if err != nil {
+			return model.Sprint{}, fmt.Errorf("failed to close sprint. rollover sprint id invalid")
+		}
+		if next.Id == current.Id || next.ProjectId != current.ProjectId || next.State == "closed" {
+			return model.Sprint{}, fmt.Errorf("failed to close sprint. rollover sprint must be another open sprint of the same project")
+		}
+	}
+
+	if _, err := s.sprintRepository.Close(id, rolloverTo); err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to close sprint: %s", err.Error())
+	}
+
+	return s.GetById(id)
+}
+
+// AddTasks implements SprintUsecase.
+func (s *sprintUsecase) AddTasks(userId string, id string, taskIds []string) error {
+	if len(taskIds) == 0 {
+		return fmt.Errorf("failed to add tasks. field 'task_ids' cannot be empty")
+	}
+
+	current, err := s.checkSprintManager(id, userId)
+	if err != nil {
+		return fmt.Errorf("failed to add tasks: %w", err)
+	}
+	if current.State == "closed" {
+		return fmt.Errorf("failed to add tasks. sprint is closed")
+	}
+
+	if err := s.sprintRepository.AddTasks(id, taskIds); err != nil {
+		return fmt.Errorf("failed to add tasks: %s", err.Error())
+	}
+
+	return nil
+}
+
+// RemoveTasks implements SprintUsecase.
+func (s *sprintUsecase) RemoveTasks(userId string, id string, taskIds []string) error {
+	if len(taskIds) == 0 {
+		return fmt.Errorf("failed to remove tasks. field 'task_ids' cannot be empty")
+	}
+
+	current, err := s.checkSprintManager(id, userId)
+	if err != nil {
+		return fmt.Errorf("failed to remove tasks: %w", err)
+	}
+	if current.State == "closed" {
+		return fmt.Errorf("failed to remove tasks. sprint is closed")
+	}
+
+	if err := s.sprintRepository.RemoveTasks(id, taskIds); err != nil {
+		return fmt.Errorf("failed to remove tasks: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (s *sprintUsecase) checkSprintManager(id string, userId string) (model.Sprint, error) {
+	sprint, err := s.sprintRepository.GetById(id)
+	if err != nil {
+		return model.Sprint{}, fmt.Errorf("sprint id invalid")
+	}
+
+	if err := checkProjectManager(s.projectRepository, sprint.ProjectId, userId); err != nil {
+		return model.Sprint{}, err
+	}
+
+	return sprint, nil
+}
+
+func validateSprint(payload model.Sprint) error {
+	if payload.Name == "" || payload.StartDate == "" || payload.EndDate == "" {
+		return fmt.Errorf("fields 'name', 'start_date', 'end_date' cannot be empty")
+	}
+
+	start, err := time.Parse("2006-01-02", payload.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date, use YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", payload.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date, use YYYY-MM-DD")
+	}
+	if !end.After(start) {
+		return fmt.Errorf("end_date must be after start_date")
+	}
+
+	return nil
+}
+
+// setSprintSummary picks committed work from the start snapshot once the sprint is running
+// and completed work from the close snapshot once it is closed.
+func setSprintSummary(sprint *model.Sprint) {
+	summary := sprint.Current
+	switch sprint.State {
+	case "active":
+		summary.CommittedTasks, summary.CommittedPoints = sprint.Snapshot.CommittedTasks, sprint.Snapshot.CommittedPoints
+	case "closed":
+		summary = sprint.Snapshot
+	}
+
+	summary.RemainingTasks = max(summary.CommittedTasks-summary.CompletedTasks, 0)
+	summary.RemainingPoints = max(summary.CommittedPoints-summary.CompletedPoints, 0)
+	sprint.Summary = summary
+}
+
+func NewSprintUsecase(sprintRepository repository.SprintRepository, projectRepository repository.ProjectRepository) SprintUsecase {
+	return &sprintUsecase{
+		sprintRepository:  sprintRepository,
+		projectRepository: projectRepository,
+	}
+}
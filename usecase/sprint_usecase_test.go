@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SprintUsecaseTest struct {
+	suite.Suite
+	srm *repository_mock.SprintRepositoryMock
+	prm *repository_mock.ProjectRepositoryMock
+	suc SprintUsecase
+}
+
+func (s *SprintUsecaseTest) SetupTest() {
+	s.srm = new(repository_mock.SprintRepositoryMock)
+	s.prm = new(repository_mock.ProjectRepositoryMock)
+	s.suc = NewSprintUsecase(s.srm, s.prm)
+}
+
+func TestSprintUsecase(t *testing.T) {
+	suite.Run(t, new(SprintUsecaseTest))
+}
+
+var sprintProject = model.Project{Id: "p1", ManagerId: "manager1"}
+
+var activeSprint = model.Sprint{
+	Id:        "s1",
+	ProjectId: "p1",
+	Name:      "Sprint 1",
+	StartDate: "2024-03-04",
+	EndDate:   "2024-03-15",
+	State:     "active",
+	Snapshot:  model.SprintSummary{CommittedTasks: 5, CommittedPoints: 13},
+	Current:   model.SprintSummary{CommittedTasks: 6, CommittedPoints: 16, CompletedTasks: 2, CompletedPoints: 5},
+}
+
+func (s *SprintUsecaseTest) TestGetById_ActiveSummaryUsesCommittedSnapshot() {
+	s.srm.On("GetById", "s1").Return(activeSprint, nil)
+
+	sprint, err := s.suc.GetById("s1")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.SprintSummary{CommittedTasks: 5, CommittedPoints: 13, CompletedTasks: 2, CompletedPoints: 5, RemainingTasks: 3, RemainingPoints: 8}, sprint.Summary)
+}
+
+func (s *SprintUsecaseTest) TestCreateSprint_InvalidDates() {
+	_, err := s.suc.CreateSprint("manager1", model.Sprint{ProjectId: "p1", Name: "Sprint 1", StartDate: "2024-03-15", EndDate: "2024-03-04"})
+
+	assert.Error(s.T(), err)
+	s.srm.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *SprintUsecaseTest) TestStartSprint_NotProjectManager() {
+	s.srm.On("GetById", "s1").Return(model.Sprint{Id: "s1", ProjectId: "p1", State: "planned"}, nil)
+	s.prm.On("GetById", "p1").Return(sprintProject, nil)
+
+	_, err := s.suc.StartSprint("manager2", "s1")
+
+	assert.True(s.T(), errors.Is(err, shared_model.ErrForbidden))
+	s.srm.AssertNotCalled(s.T(), "Start", "s1")
+}
+
+func (s *SprintUsecaseTest) TestCloseSprint_RolloverToClosedSprint() {
+	s.srm.On("GetById", "s1").Return(activeSprint, nil)
+	s.srm.On("GetById", "s0").Return(model.Sprint{Id: "s0", ProjectId: "p1", State: "closed"}, nil)
+	s.prm.On("GetById", "p1").Return(sprintProject, nil)
+
+	_, err := s.suc.CloseSprint("manager1", "s1", "s0")
+
+	assert.Error(s.T(), err)
+	s.srm.AssertNotCalled(s.T(), "Close", mock.Anything, mock.Anything)
+}
+
+func (s *SprintUsecaseTest) TestCloseSprint_Success() {
+	next := model.Sprint{Id: "s2", ProjectId: "p1", State: "planned"}
+	closed := activeSprint
+	closed.State = "closed"
+	closed.Snapshot = model.SprintSummary{CommittedTasks: 5, CommittedPoints: 13, CompletedTasks: 4, CompletedPoints: 10}
+	s.srm.On("GetById", "s1").Return(activeSprint, nil).Once()
+	s.srm.On("GetById", "s2").Return(next, nil)
+	s.prm.On("GetById", "p1").Return(sprintProject, nil)
+	s.srm.On("Close", "s1", "s2").Return(int64(2), nil)
+	s.srm.On("GetById", "s1").Return(closed, nil).Once()
+
+	sprint, err := s.suc.CloseSprint("manager1", "s1", "s2")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, sprint.Summary.RemainingTasks)
+	assert.Equal(s.T(), 3, sprint.Summary.RemainingPoints)
+	s.srm.AssertExpectations(s.T())
+}
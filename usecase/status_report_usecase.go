@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/report"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+// maxStatusReportDays bounds the period of a single status report
+const maxStatusReportDays = 92
+
+type StatusReportUsecase interface {
+	Generate(userId string, projectId string, from string, to string) (model.StatusReport, error)
+	GetStatusReports(userId string, projectId string) ([]model.StatusReport, error)
+	GetStatusReport(userId string, id string) (model.StatusReport, error)
+	GenerateWeekly(now time.Time) (int, error)
+}
+
+type statusReportUsecase struct {
+	statusReportRepository repository.StatusReportRepository
+	projectRepository      repository.ProjectRepository
+	userRepository         repository.UserRepository
+}
+
+// Generate implements StatusReportUsecase.
+// Dates are YYYY-MM-DD and inclusive; without from the report covers last week, Monday to Sunday,
+// and without to it ends a week after from. Regenerating a period replaces the stored report.
+func (s *statusReportUsecase) Generate(userId string, projectId string, from string, to string) (model.StatusReport, error) {
+	if err := s.checkStatusReportAccess(userId, projectId); err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to generate status report: %w", err)
+	}
+
+	start, end := lastWeek(time.Now())
+	if from != "" {
+		var err error
+		start, err = time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return model.StatusReport{}, fmt.Errorf("failed to generate status report. invalid from date, expected YYYY-MM-DD")
+		}
+		end = start.AddDate(0, 0, 6)
+	}
+	if to != "" {
+		var err error
+		end, err = time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return model.StatusReport{}, fmt.Errorf("failed to generate status report. invalid to date, expected YYYY-MM-DD")
+		}
+	}
+	if end.Before(start) {
+		return model.StatusReport{}, fmt.Errorf("failed to generate status report. to date cannot be before from date")
+	}
+	if end.Sub(start) >= maxStatusReportDays*24*time.Hour {
+		return model.StatusReport{}, fmt.Errorf("failed to generate status report. period cannot exceed %d days", maxStatusReportDays)
+	}
+
+	project, err := s.projectRepository.GetById(projectId)
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to generate status report. invalid project id")
+	}
+
+	return s.generate(project, start, end, userId)
+}
+
+// GetStatusReports implements StatusReportUsecase.
+func (s *statusReportUsecase) GetStatusReports(userId string, projectId string) ([]model.StatusReport, error) {
+	if err := s.checkStatusReportAccess(userId, projectId); err != nil {
+		return nil, fmt.Errorf("failed to get status reports: %w", err)
+	}
+
+	reports, err := s.statusReportRepository.GetByProjectId(projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status reports: %s", err.Error())
+	}
+
+	return reports, nil
+}
+
+// GetStatusReport implements StatusReportUsecase.
+// The returned report carries its rendered documents.
+func (s *statusReportUsecase) GetStatusReport(userId string, id string) (model.StatusReport, error) {
+	statusReport, err := s.statusReportRepository.GetById(id)
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to get status report. status report not found")
+	}
+	if err := s.checkStatusReportAccess(userId, statusReport.ProjectId); err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to get status report: %w", err)
+	}
+
+	return statusReport, nil
+}
+
+// GenerateWeekly implements StatusReportUsecase.
+// Generates last week's report for every project that does not have one yet, so running it more
+// often than weekly is harmless. A failing project is logged and the others still get their report.
+func (s *statusReportUsecase) GenerateWeekly(now time.Time) (int, error) {
+	start, end := lastWeek(now)
+
+	projectIds, err := s.statusReportRepository.GetProjectsWithoutReport(start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate weekly status reports: %s", err.Error())
+	}
+
+	generated := 0
+	for _, projectId := range projectIds {
+		project, err := s.projectRepository.GetById(projectId)
+		if err != nil {
+			log.Println("statusReportUsecase.GenerateWeekly", err.Error())
+			continue
+		}
+		if _, err := s.generate(project, start, end, ""); err != nil {
+			log.Println("statusReportUsecase.GenerateWeekly", err.Error())
+			continue
+		}
+		generated++
+	}
+
+	log.Printf("Generate weekly status reports for %s to %s: %d generated", start.Format("2006-01-02"), end.Format("2006-01-02"), generated)
+	return generated, nil
+}
+
+// generate aggregates the project's activity between start and end, both whole days, and stores the rendered report.
+// Overdue tasks are those due by the end of the period that are still not accepted now.
+func (s *statusReportUsecase) generate(project model.Project, start time.Time, end time.Time, createdBy string) (model.StatusReport, error) {
+	until := end.AddDate(0, 0, 1)
+
+	changes, err := s.statusReportRepository.GetStatusChanges(project.Id, start, until)
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to generate status report: %s", err.Error())
+	}
+	overdue, err := s.statusReportRepository.GetOverdueTasks(project.Id, until)
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to generate status report: %s", err.Error())
+	}
+	reports, err := s.statusReportRepository.GetMemberReports(project.Id, start, until)
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to generate status report: %s", err.Error())
+	}
+
+	data := model.StatusReportData{
+		Project:       project,
+		StartDate:     start.Format("2006-01-02"),
+		EndDate:       end.Format("2006-01-02"),
+		GeneratedAt:   time.Now(),
+		StatusChanges: changes,
+		Overdue:       overdue,
+		Reports:       reports,
+	}
+	for _, change := range changes {
+		if change.Status == "Accepted" {
+			data.Completed = append(data.Completed, change)
+		}
+	}
+
+	markdown, err := report.RenderStatusMarkdown(data)
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to render status report: %s", err.Error())
+	}
+	html, err := report.RenderStatusHTML(data)
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to render status report: %s", err.Error())
+	}
+
+	saved, err := s.statusReportRepository.Save(model.StatusReport{
+		ProjectId: project.Id,
+		StartDate: data.StartDate,
+		EndDate:   data.EndDate,
+		Markdown:  markdown,
+		Html:      html,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return model.StatusReport{}, fmt.Errorf("failed to save status report: %s", err.Error())
+	}
+
+	return saved, nil
+}
+
+// checkStatusReportAccess lets admins and the project's manager through
+func (s *statusReportUsecase) checkStatusReportAccess(userId string, projectId string) error {
+	user, err := s.userRepository.GetById(userId)
+	if err != nil {
+		return fmt.Errorf("invalid user id")
+	}
+	if user.Role == "ADMIN" {
+		return nil
+	}
+	if projectId == "" {
+		return shared_model.ErrForbidden
+	}
+
+	return checkProjectManager(s.projectRepository, projectId, userId)
+}
+
+// lastWeek returns the Monday and Sunday of the week before the one containing now
+func lastWeek(now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	return monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -1)
+}
+
+func NewStatusReportUsecase(statusReportRepository repository.StatusReportRepository, projectRepository repository.ProjectRepository, userRepository repository.UserRepository) StatusReportUsecase {
+	return &statusReportUsecase{
+		statusReportRepository: statusReportRepository,
+		projectRepository:      projectRepository,
+		userRepository:         userRepository,
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatusReportUsecaseTest struct {
+	suite.Suite
+	srm *repository_mock.StatusReportRepositoryMock
+	prm *repository_mock.ProjectRepositoryMock
+	urm *repository_mock.UserRepositoryMock
+	suc StatusReportUsecase
+}
+
+func (s *StatusReportUsecaseTest) SetupTest() {
+	s.srm = new(repository_mock.StatusReportRepositoryMock)
+	s.prm = new(repository_mock.ProjectRepositoryMock)
+	s.urm = new(repository_mock.UserRepositoryMock)
+	s.suc = NewStatusReportUsecase(s.srm, s.prm, s.urm)
+}
+
+func TestStatusReportUsecase(t *testing.T) {
+	suite.Run(t, new(StatusReportUsecaseTest))
+}
+
+var statusProject = model.Project{Id: "p1", Name: "Website", Key: "WEB", ManagerId: "m1"}
+
+func (s *StatusReportUsecaseTest) TestGenerate_AggregatesAndRenders() {
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	until := time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)
+	s.urm.On("GetById", "m1").Return(model.User{Id: "m1", Role: "MANAGER"}, nil)
+	s.prm.On("GetById", "p1").Return(statusProject, nil)
+	s.srm.On("GetStatusChanges", "p1", start, until).Return([]model.TaskStatusChange{
+		{TaskId: "t1", Key: "WEB-1", Name: "Login", Status: "Waiting Approval", PreviousStatus: "In Progress", ChangedAt: start.Add(30 * time.Hour)},
+		{TaskId: "t1", Key: "WEB-1", Name: "Login", Status: "Accepted", PreviousStatus: "Waiting Approval", ChangedAt: start.Add(50 * time.Hour)},
+	}, nil)
+	s.srm.On("GetOverdueTasks", "p1", until).Return([]model.OverdueTask{{TaskId: "t2", Key: "WEB-2", Name: "Signup", Status: "Blocked", Deadline: "2024-03-08", PersonInCharge: "Budi"}}, nil)
+	s.srm.On("GetMemberReports", "p1", start, until).Return([]model.MemberReport{{UserId: "u1", UserName: "Budi", TaskKey: "WEB-2", TaskName: "Signup", Report: "<b>stuck</b> on captcha", CreatedAt: start.Add(60 * time.Hour)}}, nil)
+	s.srm.On("Save", mock.Anything).Return(model.StatusReport{Id: "s1"}, nil)
+
+	saved, err := s.suc.Generate("m1", "p1", "2024-03-04", "2024-03-10")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "s1", saved.Id)
+	stored := s.srm.Calls[len(s.srm.Calls)-1].Arguments.Get(0).(model.StatusReport)
+	assert.Equal(s.T(), "2024-03-04", stored.StartDate)
+	assert.Equal(s.T(), "2024-03-10", stored.EndDate)
+	assert.Equal(s.T(), "m1", stored.CreatedBy)
+	assert.Contains(s.T(), stored.Markdown, "- Completed tasks: 1")
+	assert.Contains(s.T(), stored.Markdown, "- WEB-1 Login (accepted 2024-03-06)")
+	assert.Contains(s.T(), stored.Markdown, "- WEB-2 Signup: Blocked, due 2024-03-08, Budi")
+	assert.Contains(s.T(), stored.Markdown, "### Budi")
+	assert.Contains(s.T(), stored.Html, "&lt;b&gt;stuck&lt;/b&gt; on captcha")
+}
+
+func (s *StatusReportUsecaseTest) TestGenerate_Validation() {
+	s.urm.On("GetById", "m1").Return(model.User{Id: "m1", Role: "MANAGER"}, nil)
+	s.urm.On("GetById", "m2").Return(model.User{Id: "m2", Role: "MANAGER"}, nil)
+	s.prm.On("GetById", "p1").Return(statusProject, nil)
+
+	_, err := s.suc.Generate("m2", "p1", "", "")
+	assert.ErrorIs(s.T(), err, shared_model.ErrForbidden)
+
+	_, err = s.suc.Generate("m1", "p1", "2024-03-10", "2024-03-04")
+	assert.EqualError(s.T(), err, "failed to generate status report. to date cannot be before from date")
+
+	_, err = s.suc.Generate("m1", "p1", "2024-01-01", "2024-06-01")
+	assert.EqualError(s.T(), err, "failed to generate status report. period cannot exceed 92 days")
+
+	s.srm.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *StatusReportUsecaseTest) TestGetStatusReport_ChecksProject() {
+	s.urm.On("GetById", "m2").Return(model.User{Id: "m2", Role: "MANAGER"}, nil)
+	s.prm.On("GetById", "p1").Return(statusProject, nil)
+	s.srm.On("GetById", "s1").Return(model.StatusReport{Id: "s1", ProjectId: "p1"}, nil)
+
+	_, err := s.suc.GetStatusReport("m2", "s1")
+
+	assert.ErrorIs(s.T(), err, shared_model.ErrForbidden)
+}
+
+func (s *StatusReportUsecaseTest) TestGenerateWeekly_LastWeekForMissingProjects() {
+	now := time.Date(2024, 3, 13, 9, 0, 0, 0, time.Local) // a Wednesday
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	until := time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)
+	s.srm.On("GetProjectsWithoutReport", "2024-03-04", "2024-03-10").Return([]string{"p1", "gone"}, nil)
+	s.prm.On("GetById", "p1").Return(statusProject, nil)
+	s.prm.On("GetById", "gone").Return(model.Project{}, assert.AnError)
+	s.srm.On("GetStatusChanges", "p1", start, until).Return([]model.TaskStatusChange(nil), nil)
+	s.srm.On("GetOverdueTasks", "p1", until).Return([]model.OverdueTask(nil), nil)
+	s.srm.On("GetMemberReports", "p1", start, until).Return([]model.MemberReport(nil), nil)
+	s.srm.On("Save", mock.MatchedBy(func(r model.StatusReport) bool { return r.ProjectId == "p1" && r.CreatedBy == "" })).Return(model.StatusReport{Id: "s1"}, nil)
+
+	generated, err := s.suc.GenerateWeekly(now)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, generated)
+}
+
+func (s *StatusReportUsecaseTest) TestLastWeek() {
+	for _, now := range []time.Time{
+		time.Date(2024, 3, 11, 0, 30, 0, 0, time.Local),
+		time.Date(2024, 3, 17, 23, 0, 0, 0, time.Local),
+	} {
+		start, end := lastWeek(now)
+		assert.Equal(s.T(), "2024-03-04", start.Format("2006-01-02"))
+		assert.Equal(s.T(), "2024-03-10", end.Format("2006-01-02"))
+	}
+}
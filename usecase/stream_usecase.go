@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+type StreamUsecase interface {
+	SubscribeProject(userId string, projectId string, lastEventId uint64) (*service.StreamSubscription, error)
+	SubscribeUser(userId string, lastEventId uint64) *service.StreamSubscription
+	Unsubscribe(subscription *service.StreamSubscription)
+	CheckProjectAccess(userId string, projectId string) error
+	IssueTicket(userId string) (model.StreamTicket, error)
+	RedeemTicket(ticket string) (string, error)
+	ReleaseTicket(ticket string)
+}
+
+type streamTicket struct {
+	userId    string
+	expiresAt time.Time
+	streams   int
+}
+
+type streamUsecase struct {
+	streamService     service.StreamService
+	projectRepository repository.ProjectRepository
+	userRepository    repository.UserRepository
+	mu                sync.Mutex
+	tickets           map[string]streamTicket
+}
+
+// streamTicketTTL is how long a client has to open its stream after asking for a ticket,
+// and to reconnect with it after its last stream closed
+const streamTicketTTL = 30 * time.Second
+
+// projectStreamTypes are the events that change what a project board shows
+var projectStreamTypes = []string{
+	model.EventTaskCreated,
+	model.EventTaskStatusChanged,
+	model.EventTaskAssigned,
+	model.EventTaskApproved,
+	model.EventTaskRejected,
+	model.EventTaskBlocked,
+	model.EventTaskMoved,
+	model.EventReportCreated,
+	model.EventReportReviewed,
+	model.EventMemberAdded,
+}
+
+// SubscribeProject implements StreamUsecase.
+// Admins, the project manager and project members may follow a project.
+func (s *streamUsecase) SubscribeProject(userId string, projectId string, lastEventId uint64) (*service.StreamSubscription, error) {
+	if err := s.CheckProjectAccess(userId, projectId); err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	return s.streamService.Subscribe(func(event model.Event) bool {
+		return (event.ProjectId == projectId || event.FromProjectId == projectId) && slices.Contains(projectStreamTypes, event.Type)
+	}, lastEventId), nil
+}
+
+// SubscribeUser implements StreamUsecase.
+// The user stream carries every event the user is a recipient of.
+func (s *streamUsecase) SubscribeUser(userId string, lastEventId uint64) *service.StreamSubscription {
+	return s.streamService.Subscribe(func(event model.Event) bool {
+		return slices.Contains(event.RecipientIds, userId)
+	}, lastEventId)
+}
+
+// Unsubscribe implements StreamUsecase.
+func (s *streamUsecase) Unsubscribe(subscription *service.StreamSubscription) {
+	s.streamService.Unsubscribe(subscription)
+}
+
+// IssueTicket implements StreamUsecase.
+// Tickets are kept in memory, like the stream history, so they only work on the instance that issued them.
+func (s *streamUsecase) IssueTicket(userId string) (model.StreamTicket, error) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return model.StreamTicket{}, fmt.Errorf("failed to issue stream ticket: %s", err.Error())
+	}
+	ticket := model.StreamTicket{Ticket: hex.EncodeToString(value), ExpiresAt: time.Now().Add(streamTicketTTL)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, issued := range s.tickets {
+		if issued.streams == 0 && time.Now().After(issued.expiresAt) {
+			delete(s.tickets, key)
+		}
+	}
+	s.tickets[ticket.Ticket] = streamTicket{userId: userId, expiresAt: ticket.ExpiresAt}
+	return ticket, nil
+}
+
+// RedeemTicket implements StreamUsecase.
+// It returns the user the ticket was issued to. A ticket can be redeemed again while a stream opened
+// with it is still open and for streamTicketTTL after the last one closed, so an EventSource can
+// reconnect to the same URL; a client that was away longer asks for a new ticket and passes last_event_id.
+func (s *streamUsecase) RedeemTicket(ticket string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.tickets[ticket]
+	if !ok || (issued.streams == 0 && time.Now().After(issued.expiresAt)) {
+		delete(s.tickets, ticket)
+		return "", fmt.Errorf("invalid or expired stream ticket")
+	}
+	issued.streams++
+	s.tickets[ticket] = issued
+
+	return issued.userId, nil
+}
+
+// ReleaseTicket implements StreamUsecase.
+// It is called when a stream opened with the ticket closes and starts its reconnect window.
+func (s *streamUsecase) ReleaseTicket(ticket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.tickets[ticket]
+	if !ok || issued.streams == 0 {
+		return
+	}
+	issued.streams--
+	issued.expiresAt = time.Now().Add(streamTicketTTL)
+	s.tickets[ticket] = issued
+}
+
+// CheckProjectAccess implements StreamUsecase.
+// Open project streams call it again on every heartbeat, so a user removed from the
+// project stops receiving its events within one heartbeat.
+func (s *streamUsecase) CheckProjectAccess(userId string, projectId string) error {
+	project, err := s.projectRepository.GetById(projectId)
+	if err != nil {
+		return fmt.Errorf("invalid project id")
+	}
+	if project.ManagerId == userId {
+		return nil
+	}
+
+	user, err := s.userRepository.GetById(userId)
+	if err != nil {
+		return fmt.Errorf("invalid user id")
+	}
+	if user.Role == "ADMIN" {
+		return nil
+	}
+
+	members, err := s.projectRepository.GetAllProjectMember(projectId)
+	if err != nil {
+		return fmt.Errorf("failed to get project members: %s", err.Error())
+	}
+	for _, member := range members {
+		if member.Id == userId {
+			return nil
+		}
+	}
+
+	return shared_model.ErrForbidden
+}
+
+func NewStreamUsecase(streamService service.StreamService, projectRepository repository.ProjectRepository, userRepository repository.UserRepository) StreamUsecase {
+	return &streamUsecase{
+		streamService:     streamService,
+		projectRepository: projectRepository,
+		userRepository:    userRepository,
+		tickets:           make(map[string]streamTicket),
+	}
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamUsecaseTest struct {
+	suite.Suite
+	prm           *repository_mock.ProjectRepositoryMock
+	urm           *repository_mock.UserRepositoryMock
+	streamService service.StreamService
+	suc           StreamUsecase
+}
+
+func (s *StreamUsecaseTest) SetupTest() {
+	s.prm = new(repository_mock.ProjectRepositoryMock)
+	s.urm = new(repository_mock.UserRepositoryMock)
+	s.streamService = service.NewStreamService(3)
+	s.suc = NewStreamUsecase(s.streamService, s.prm, s.urm)
+}
+
+func TestStreamUsecase(t *testing.T) {
+	suite.Run(t, new(StreamUsecaseTest))
+}
+
+func (s *StreamUsecaseTest) TestSubscribeProject_MemberReceivesProjectEvents() {
+	s.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+	s.urm.On("GetById", "u1").Return(model.User{Id: "u1", Role: "TEAM MEMBER"}, nil)
+	s.prm.On("GetAllProjectMember", "p1").Return([]model.User{{Id: "u1"}}, nil)
+
+	subscription, err := s.suc.SubscribeProject("u1", "p1", 0)
+	assert.NoError(s.T(), err)
+
+	s.streamService.Publish(model.Event{Type: model.EventTaskCreated, ProjectId: "p2"})
+	s.streamService.Publish(model.Event{Type: model.EventTaskDueSoon, ProjectId: "p1"})
+	s.streamService.Publish(model.Event{Type: model.EventTaskStatusChanged, ProjectId: "p1", TaskId: "t1"})
+
+	event := <-subscription.Events
+	assert.Equal(s.T(), uint64(3), event.Id)
+	assert.Equal(s.T(), "t1", event.TaskId)
+	assert.Empty(s.T(), subscription.Events)
+
+	s.suc.Unsubscribe(subscription)
+	_, open := <-subscription.Events
+	assert.False(s.T(), open)
+}
+
+func (s *StreamUsecaseTest) TestSubscribeProject_TaskMovedAway() {
+	s.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+	s.urm.On("GetById", "m1").Return(model.User{Id: "m1", Role: "MANAGER"}, nil)
+
+	subscription, err := s.suc.SubscribeProject("m1", "p1", 0)
+	assert.NoError(s.T(), err)
+
+	s.streamService.Publish(model.Event{Type: model.EventTaskMoved, ProjectId: "p2", FromProjectId: "p1", TaskId: "t1"})
+
+	event := <-subscription.Events
+	assert.Equal(s.T(), "t1", event.TaskId)
+	s.suc.Unsubscribe(subscription)
+}
+
+func (s *StreamUsecaseTest) TestSubscribeProject_Forbidden() {
+	s.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+	s.urm.On("GetById", "u2").Return(model.User{Id: "u2", Role: "TEAM MEMBER"}, nil)
+	s.prm.On("GetAllProjectMember", "p1").Return([]model.User{{Id: "u1"}}, nil)
+
+	_, err := s.suc.SubscribeProject("u2", "p1", 0)
+
+	assert.ErrorIs(s.T(), err, shared_model.ErrForbidden)
+}
+
+func (s *StreamUsecaseTest) TestSubscribeUser_ResumesFromLastEventId() {
+	for i := 0; i < 5; i++ {
+		s.streamService.Publish(model.Event{Type: model.EventTaskAssigned, RecipientIds: []string{"u1"}})
+	}
+
+	subscription := s.suc.SubscribeUser("u1", 2)
+	assert.False(s.T(), subscription.Reset)
+	assert.Len(s.T(), subscription.Replay, 3)
+	assert.Equal(s.T(), uint64(3), subscription.Replay[0].Id)
+
+	subscription = s.suc.SubscribeUser("u1", 1)
+	assert.True(s.T(), subscription.Reset)
+	assert.Len(s.T(), subscription.Replay, 3)
+
+	subscription = s.suc.SubscribeUser("u2", 1)
+	assert.Empty(s.T(), subscription.Replay)
+}
+
+func (s *StreamUsecaseTest) TestRedeemTicket_AllowsReconnects() {
+	ticket, err := s.suc.IssueTicket("u1")
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), ticket.Ticket, 64)
+
+	userId, err := s.suc.RedeemTicket(ticket.Ticket)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "u1", userId)
+
+	// the EventSource reconnects with the same URL after its stream dropped
+	s.suc.ReleaseTicket(ticket.Ticket)
+	userId, err = s.suc.RedeemTicket(ticket.Ticket)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "u1", userId)
+
+	_, err = s.suc.RedeemTicket("")
+	assert.Error(s.T(), err)
+}
+
+func (s *StreamUsecaseTest) TestRedeemTicket_Expired() {
+	ticket, _ := s.suc.IssueTicket("u1")
+	tickets := s.suc.(*streamUsecase).tickets
+
+	// an open stream keeps its ticket alive past the TTL
+	s.suc.RedeemTicket(ticket.Ticket)
+	issued := tickets[ticket.Ticket]
+	issued.expiresAt = time.Now().Add(-time.Minute)
+	tickets[ticket.Ticket] = issued
+	_, err := s.suc.RedeemTicket(ticket.Ticket)
+	assert.NoError(s.T(), err)
+
+	// once every stream closed, the reconnect window runs out
+	s.suc.ReleaseTicket(ticket.Ticket)
+	s.suc.ReleaseTicket(ticket.Ticket)
+	issued = tickets[ticket.Ticket]
+	issued.expiresAt = time.Now().Add(-time.Second)
+	tickets[ticket.Ticket] = issued
+	_, err = s.suc.RedeemTicket(ticket.Ticket)
+	assert.Error(s.T(), err)
+}
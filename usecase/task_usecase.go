@@ -2,9 +2,14 @@ package usecase
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"enigma.com/projectmanagementhub/model"
 	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
 	"enigma.com/projectmanagementhub/shared/shared_model"
 )
 
@@ -13,8 +18,11 @@ type TaskUsecase interface {
 	GetById(Id string) (model.Task, error)
 	GetByPersonInCharge(Id string) ([]model.Task, error)
 	GetByProjectId(Id string) ([]model.Task, error)
+	FilterByProjectId(Id string, filters map[string]string) ([]model.Task, error)
 	CreateTask(payload model.Task) (model.Task, error)
 	UpdateTask(userId string, payload model.Task) (model.Task, error)
+	MoveTask(userId string, id string, projectId string) (model.Task, error)
+	CloneTask(userId string, id string, payload model.TaskClone) (model.Task, error)
 	Delete(id string) error
 }
 
@@ -22,6 +30,8 @@ type taskUsecase struct {
 	taskRepository    repository.TaskRepository
 	userRepository    repository.UserRepository
 	projectRepository repository.ProjectRepository
+	fieldRepository   repository.CustomFieldRepository
+	eventService      service.EventService
 }
 
 // CreateTask implements TaskUsecase.
@@ -36,16 +46,29 @@ func (t *taskUsecase) CreateTask(payload model.Task) (model.Task, error) {
 	if payload.Name == "" || payload.Deadline == "" {
 		return model.Task{}, fmt.Errorf("failed to create task. empty field exist")
 	}
-	return t.taskRepository.CreateTask(payload)
+	if payload.StoryPoints != nil && *payload.StoryPoints < 0 {
+		return model.Task{}, fmt.Errorf("failed to create task. story points cannot be negative")
+	}
+	if err := t.checkCustomFields(payload.ProjectId, payload.CustomFields); err != nil {
+		return model.Task{}, fmt.Errorf("failed to create task. %v", err)
+	}
+	task, err := t.taskRepository.CreateTask(payload)
+	if err != nil {
+		return task, err
+	}
 
+	t.eventService.Publish(taskEvent(model.EventTaskCreated, "", task))
+	t.eventService.Publish(taskEvent(model.EventTaskAssigned, "", task))
+	return task, nil
 }
 
 // Delete implements TaskUsecase.
 func (t *taskUsecase) Delete(id string) error {
-	if _, err := t.taskRepository.GetById(id); err != nil {
+	task, err := t.GetById(id)
+	if err != nil {
 		return fmt.Errorf("failed to delete task. task id invalid")
 	}
-	return t.taskRepository.Delete(id)
+	return t.taskRepository.Delete(task.Id)
 }
 
 // GetAll implements TaskUsecase.
@@ -55,7 +78,11 @@ func (t *taskUsecase) GetAll(page int, size int) ([]model.Task, shared_model.Pag
 }
 
 // GetById implements TaskUsecase.
+// Id may also be a task key such as WEB-42.
 func (t *taskUsecase) GetById(Id string) (model.Task, error) {
+	if key := strings.ToUpper(Id); taskKeyPattern.MatchString(key) {
+		return t.taskRepository.GetByKey(key)
+	}
 	return t.taskRepository.GetById(Id)
 }
 
@@ -84,10 +111,46 @@ func (t *taskUsecase) GetByProjectId(Id string) ([]model.Task, error) {
 	return t.taskRepository.GetByProjectId(Id)
 }
 
+// FilterByProjectId implements TaskUsecase.
+// Filters are custom field values keyed by field name; a multi select filter matches tasks having that option.
+func (t *taskUsecase) FilterByProjectId(Id string, filters map[string]string) ([]model.Task, error) {
+	if _, err := t.projectRepository.GetById(Id); err != nil {
+		return []model.Task{}, fmt.Errorf("failed to get task by project id. project id invalid")
+	}
+
+	fields, err := t.fieldRepository.GetByProjectId(Id)
+	if err != nil {
+		return []model.Task{}, err
+	}
+
+	values := model.CustomFieldValues{}
+	for name, raw := range filters {
+		field, ok := findCustomField(fields, name)
+		if !ok {
+			return []model.Task{}, fmt.Errorf("unknown custom field %q", name)
+		}
+
+		switch field.Type {
+		case "number":
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return []model.Task{}, fmt.Errorf("custom field %q must be a number", name)
+			}
+			values[name] = n
+		case "multi_select":
+			values[name] = []string{raw}
+		default:
+			values[name] = raw
+		}
+	}
+
+	return t.taskRepository.GetByProjectIdAndFields(Id, values)
+}
+
 // UpdateTaskByManager implements TaskUsecase.
 func (t *taskUsecase) UpdateTask(userId string, payload model.Task) (model.Task, error) {
 
-	if payload.Status != "In Progress" && payload.Status != "Blocked" && payload.Status != "Waiting Approval" && payload.Status != "Accepted" && payload.Status != "Rejected" && payload.Status != "On Hold" {
+	if !validTaskStatus(payload.Status) {
 		return model.Task{}, fmt.Errorf("invalid status type. status type: ('In Progress', 'Blocked', 'Waiting Approval', 'Accepted', 'Rejected', 'On Hold')")
 	}
 
@@ -96,33 +159,294 @@ func (t *taskUsecase) UpdateTask(userId string, payload model.Task) (model.Task,
 		return model.Task{}, fmt.Errorf("failed to update task. user id invalid")
 	}
 
+	if key := strings.ToUpper(payload.Id); taskKeyPattern.MatchString(key) {
+		task, err := t.taskRepository.GetByKey(key)
+		if err != nil {
+			return model.Task{}, fmt.Errorf("failed to update task. task key invalid")
+		}
+		payload.Id = task.Id
+	}
+
 	if user.Role == "MANAGER" {
 		if payload.Name == "" || payload.Deadline == "" || payload.Feedback == "" {
 			return model.Task{}, fmt.Errorf("failed to update task. empty field exist")
 		}
+		if payload.StoryPoints != nil && *payload.StoryPoints < 0 {
+			return model.Task{}, fmt.Errorf("failed to update task. story points cannot be negative")
+		}
 
 		_, err := t.userRepository.GetById(payload.PersonInCharge)
 		if err != nil {
 			return model.Task{}, fmt.Errorf("failed to update task. person in charge id invalid")
 		}
 
-		return t.taskRepository.UpdateTaskByManager(payload)
+		// the stored task tells whether the update reassigns, approves or rejects it
+		previous, prevErr := t.taskRepository.GetById(payload.Id)
+
+		// custom fields are left untouched unless the payload carries them
+		if payload.CustomFields != nil {
+			if prevErr != nil {
+				return model.Task{}, fmt.Errorf("failed to update task. task id invalid")
+			}
+			if err := t.checkCustomFields(previous.ProjectId, payload.CustomFields); err != nil {
+				return model.Task{}, fmt.Errorf("failed to update task. %v", err)
+			}
+		}
+
+		task, err := t.taskRepository.UpdateTaskByManager(payload)
+		if err != nil {
+			return task, err
+		}
+
+		if prevErr == nil {
+			publishTaskChanges(t.eventService, userId, previous, task)
+		}
+		return task, nil
 	} else {
 		check, _ := t.taskRepository.GetById(payload.Id)
 		if check.PersonInCharge != userId {
 			return model.Task{}, fmt.Errorf("only person in charge and project manager can update task")
 		}
+		if payload.Version != 0 && payload.Version != check.Version {
+			return model.Task{}, shared_model.ErrVersionConflict
+		}
+
+		task, err := t.taskRepository.UpdateTaskByMember(payload)
+		if err != nil {
+			return task, err
+		}
 
-		return t.taskRepository.UpdateTaskByMember(payload)
+		publishTaskChanges(t.eventService, userId, check, task)
+		return task, nil
 	}
 }
 
+// MoveTask implements TaskUsecase.
+// Only a manager of both projects can move a task, and its assignee must belong to the target project.
+// The task.moved event carries both projects, so the boards of each see the task leave or arrive.
+func (t *taskUsecase) MoveTask(userId string, id string, projectId string) (model.Task, error) {
+	task, err := t.GetById(id)
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to move task. task id invalid")
+	}
+	if projectId == "" || projectId == task.ProjectId {
+		return model.Task{}, fmt.Errorf("failed to move task. target project must differ from the current one")
+	}
+	if err := t.checkTransfer(userId, task, projectId); err != nil {
+		return model.Task{}, fmt.Errorf("failed to move task. %w", err)
+	}
+
+	moved, err := t.taskRepository.MoveToProject(task.Id, projectId)
+	if err != nil {
+		return moved, err
+	}
+
+	event := taskEvent(model.EventTaskMoved, userId, moved)
+	event.FromProjectId = task.ProjectId
+	t.eventService.Publish(event)
+	return moved, nil
+}
+
+// CloneTask implements TaskUsecase.
+// The clone goes to the task's own project unless payload.ProjectId names another one.
+func (t *taskUsecase) CloneTask(userId string, id string, payload model.TaskClone) (model.Task, error) {
+	task, err := t.GetById(id)
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to clone task. task id invalid")
+	}
+	if payload.ProjectId == "" {
+		payload.ProjectId = task.ProjectId
+	}
+	if err := t.checkTransfer(userId, task, payload.ProjectId); err != nil {
+		return model.Task{}, fmt.Errorf("failed to clone task. %w", err)
+	}
+
+	clone, err := t.taskRepository.Clone(task.Id, payload, userId)
+	if err != nil {
+		return clone, err
+	}
+
+	t.eventService.Publish(taskEvent(model.EventTaskCreated, userId, clone))
+	t.eventService.Publish(taskEvent(model.EventTaskAssigned, userId, clone))
+	return clone, nil
+}
+
+// checkTransfer requires userId to manage both projects and the assignee to be on the target project
+func (t *taskUsecase) checkTransfer(userId string, task model.Task, projectId string) error {
+	if err := checkProjectManager(t.projectRepository, task.ProjectId, userId); err != nil {
+		return err
+	}
+	if err := checkProjectManager(t.projectRepository, projectId, userId); err != nil {
+		return err
+	}
+
+	// the target's manager is not listed among its members
+	if task.PersonInCharge == userId {
+		return nil
+	}
+	projects, err := t.projectRepository.GetByMemberId(task.PersonInCharge)
+	if err != nil {
+		return err
+	}
+	for _, project := range projects {
+		if project.Id == projectId {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("person in charge is not a member of the target project")
+}
+
 // UpdateTaskByMember implements TaskUsecase.
 
-func NewTaskUsecase(taskRepository repository.TaskRepository, userRepository repository.UserRepository, projectRepository repository.ProjectRepository) TaskUsecase {
+// taskKeyPattern matches keys such as WEB-42, as opposed to task UUIDs
+var taskKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}-[0-9]+$`)
+
+// taskStatuses lists the task_status values in workflow order
+var taskStatuses = []string{"In Progress", "Blocked", "Waiting Approval", "Accepted", "Rejected", "On Hold"}
+
+func validTaskStatus(status string) bool {
+	for _, s := range taskStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCustomFields validates task values against the project's field definitions
+func (t *taskUsecase) checkCustomFields(projectId string, values model.CustomFieldValues) error {
+	fields, err := t.fieldRepository.GetByProjectId(projectId)
+	if err != nil {
+		return err
+	}
+
+	for name := range values {
+		if _, ok := findCustomField(fields, name); !ok {
+			return fmt.Errorf("unknown custom field %q", name)
+		}
+	}
+
+	for _, field := range fields {
+		value, ok := values[field.Name]
+		if !ok || value == nil {
+			if field.Required {
+				return fmt.Errorf("custom field %q is required", field.Name)
+			}
+			continue
+		}
+		if err := validCustomFieldValue(field, value); err != nil {
+			return err
+		}
+		if field.Type == "user" {
+			if _, err := t.userRepository.GetById(value.(string)); err != nil {
+				return fmt.Errorf("custom field %q user id invalid", field.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// customFieldTypes lists the custom_field_type values
+var customFieldTypes = []string{"text", "number", "date", "single_select", "multi_select", "user"}
+
+func findCustomField(fields []model.CustomField, name string) (model.CustomField, bool) {
+	for _, field := range fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return model.CustomField{}, false
+}
+
+func validCustomFieldValue(field model.CustomField, value any) error {
+	invalid := fmt.Errorf("custom field %q must be a %s", field.Name, field.Type)
+
+	switch field.Type {
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return invalid
+		}
+	case "date":
+		s, ok := value.(string)
+		if !ok {
+			return invalid
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return fmt.Errorf("custom field %q must be a date (YYYY-MM-DD)", field.Name)
+		}
+	case "single_select":
+		s, ok := value.(string)
+		if !ok || !hasOption(field.Options, s) {
+			return fmt.Errorf("custom field %q must be one of %v", field.Name, field.Options)
+		}
+	case "multi_select":
+		list, ok := value.([]any)
+		if !ok {
+			return invalid
+		}
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok || !hasOption(field.Options, s) {
+				return fmt.Errorf("custom field %q values must be in %v", field.Name, field.Options)
+			}
+		}
+	default: // text and user
+		if _, ok := value.(string); !ok {
+			return invalid
+		}
+	}
+
+	return nil
+}
+
+func hasOption(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// publishTaskChanges publishes the events an update of previous into task causes
+func publishTaskChanges(eventService service.EventService, actorId string, previous model.Task, task model.Task) {
+	if task.Status != previous.Status {
+		event := taskEvent(model.EventTaskStatusChanged, actorId, task)
+		event.Data["previous_status"] = previous.Status
+		eventService.Publish(event)
+	}
+	if task.PersonInCharge != previous.PersonInCharge {
+		eventService.Publish(taskEvent(model.EventTaskAssigned, actorId, task))
+	}
+	if task.Status == "Accepted" && previous.Status != "Accepted" {
+		eventService.Publish(taskEvent(model.EventTaskApproved, actorId, task))
+	}
+	if task.Status == "Rejected" && previous.Status != "Rejected" {
+		eventService.Publish(taskEvent(model.EventTaskRejected, actorId, task))
+	}
+}
+
+// taskEvent builds an event about task addressed to its person in charge
+func taskEvent(eventType string, actorId string, task model.Task) model.Event {
+	return model.Event{
+		Type:         eventType,
+		ProjectId:    task.ProjectId,
+		TaskId:       task.Id,
+		ActorId:      actorId,
+		RecipientIds: []string{task.PersonInCharge},
+		Data:         model.EventData{"key": task.Key, "name": task.Name, "status": task.Status, "feedback": task.Feedback},
+		OccurredAt:   time.Now(),
+	}
+}
+
+func NewTaskUsecase(taskRepository repository.TaskRepository, userRepository repository.UserRepository, projectRepository repository.ProjectRepository, fieldRepository repository.CustomFieldRepository, eventService service.EventService) TaskUsecase {
 	return &taskUsecase{
 		taskRepository:    taskRepository,
 		userRepository:    userRepository,
 		projectRepository: projectRepository,
+		fieldRepository:   fieldRepository,
+		eventService:      eventService,
 	}
 }
@@ -1,12 +1,14 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"enigma.com/projectmanagementhub/mock/repository_mock"
 	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/service"
 	"enigma.com/projectmanagementhub/shared/shared_model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -14,17 +16,23 @@ import (
 
 type TaskUsecaseTest struct {
 	suite.Suite
-	trm *repository_mock.TaskRepositoryMock
-	urm *repository_mock.UserRepositoryMock
-	prm *repository_mock.ProjectRepositoryMock
-	tc  TaskUsecase
+	trm    *repository_mock.TaskRepositoryMock
+	urm    *repository_mock.UserRepositoryMock
+	prm    *repository_mock.ProjectRepositoryMock
+	frm    *repository_mock.CustomFieldRepositoryMock
+	events []model.Event
+	tc     TaskUsecase
 }
 
 func (t *TaskUsecaseTest) SetupTest() {
 	t.trm = new(repository_mock.TaskRepositoryMock)
 	t.urm = new(repository_mock.UserRepositoryMock)
 	t.prm = new(repository_mock.ProjectRepositoryMock)
-	t.tc = NewTaskUsecase(t.trm, t.urm, t.prm)
+	t.frm = new(repository_mock.CustomFieldRepositoryMock)
+	t.events = nil
+	eventService := service.NewEventService()
+	eventService.Subscribe(func(event model.Event) { t.events = append(t.events, event) })
+	t.tc = NewTaskUsecase(t.trm, t.urm, t.prm, t.frm, eventService)
 }
 
 var expectedTask = model.Task{
@@ -80,12 +88,15 @@ func TestTaskUsecase(t *testing.T) {
 func (t *TaskUsecaseTest) TestCreateTask_Success() {
 	t.urm.On("GetById", expectedTask.PersonInCharge).Return(model.User{}, nil)
 	t.prm.On("GetById", expectedTask.ProjectId).Return(model.Project{}, nil)
+	t.frm.On("GetByProjectId", expectedTask.ProjectId).Return([]model.CustomField{}, nil)
 	t.trm.On("CreateTask", expectedTask).Return(expectedTask, nil)
 
 	createdTask, err := t.tc.CreateTask(expectedTask)
 
 	assert.NoError(t.T(), err)
 	assert.Equal(t.T(), expectedTask, createdTask)
+	assert.Equal(t.T(), []string{model.EventTaskCreated, model.EventTaskAssigned}, []string{t.events[0].Type, t.events[1].Type})
+	assert.Equal(t.T(), []string{expectedTask.PersonInCharge}, t.events[1].RecipientIds)
 
 	t.urm.AssertExpectations(t.T())
 	t.prm.AssertExpectations(t.T())
@@ -429,6 +440,7 @@ func (t *TaskUsecaseTest) TestUpdateTaskByManager_Success() {
 
 	t.urm.On("GetById", managerID).Return(manager, nil)
 	t.urm.On("GetById", taskPayload.PersonInCharge).Return(user, nil)
+	t.trm.On("GetById", taskPayload.Id).Return(taskPayload, nil)
 	t.trm.On("UpdateTaskByManager", taskPayload).Return(taskPayload, nil)
 
 	updatedTask, err := t.tc.UpdateTask(managerID, taskPayload)
@@ -506,6 +518,7 @@ func (t *TaskUsecaseTest) TestUpdateTaskByManager_TaskNotFound() {
 	}
 
 	t.urm.On("GetById", managerID).Return(manager, nil)
+	t.trm.On("GetById", taskPayload.Id).Return(model.Task{}, fmt.Errorf("task not found"))
 	t.trm.On("UpdateTaskByManager", taskPayload).Return(model.Task{}, fmt.Errorf("task not found"))
 
 	_, err := t.tc.UpdateTask(managerID, taskPayload)
@@ -626,3 +639,165 @@ func (t *TaskUsecaseTest) TestUpdateTaskByTeamMember_InvalidUser() {
 
 	t.urm.AssertExpectations(t.T())
 }
+
+var taskFieldsTest = []model.CustomField{
+	{Name: "env", Type: "single_select", Options: []string{"dev", "prod"}, Required: true},
+	{Name: "estimate", Type: "number"},
+	{Name: "labels", Type: "multi_select", Options: []string{"ui", "api"}},
+	{Name: "reviewer", Type: "user"},
+}
+
+func (t *TaskUsecaseTest) TestCreateTask_CustomFieldsValid() {
+	task := expectedTask
+	task.CustomFields = model.CustomFieldValues{"env": "prod", "estimate": 3.5, "labels": []any{"ui"}, "reviewer": "2"}
+	t.urm.On("GetById", task.PersonInCharge).Return(model.User{}, nil)
+	t.urm.On("GetById", "2").Return(model.User{}, nil)
+	t.prm.On("GetById", task.ProjectId).Return(model.Project{}, nil)
+	t.frm.On("GetByProjectId", task.ProjectId).Return(taskFieldsTest, nil)
+	t.trm.On("CreateTask", task).Return(task, nil)
+
+	_, err := t.tc.CreateTask(task)
+
+	assert.NoError(t.T(), err)
+	t.trm.AssertExpectations(t.T())
+}
+
+func (t *TaskUsecaseTest) TestCreateTask_CustomFieldsInvalid() {
+	cases := map[string]model.CustomFieldValues{
+		"custom field \"env\" is required":           {"estimate": 1.0},
+		"unknown custom field \"color\"":             {"env": "dev", "color": "red"},
+		"custom field \"estimate\" must be a number": {"env": "dev", "estimate": "three"},
+		"custom field \"env\" must be one of":        {"env": "staging"},
+		"custom field \"labels\" values must be in":  {"env": "dev", "labels": []any{"db"}},
+	}
+	t.urm.On("GetById", expectedTask.PersonInCharge).Return(model.User{}, nil)
+	t.prm.On("GetById", expectedTask.ProjectId).Return(model.Project{}, nil)
+	t.frm.On("GetByProjectId", expectedTask.ProjectId).Return(taskFieldsTest, nil)
+
+	for message, values := range cases {
+		task := expectedTask
+		task.CustomFields = values
+
+		_, err := t.tc.CreateTask(task)
+
+		assert.ErrorContains(t.T(), err, message)
+	}
+	t.trm.AssertNotCalled(t.T(), "CreateTask", expectedTask)
+}
+
+func (t *TaskUsecaseTest) TestFilterByProjectId_Success() {
+	t.prm.On("GetById", "1").Return(model.Project{}, nil)
+	t.frm.On("GetByProjectId", "1").Return(taskFieldsTest, nil)
+	t.trm.On("GetByProjectIdAndFields", "1", model.CustomFieldValues{"env": "prod", "estimate": 3.0, "labels": []string{"ui"}}).Return(expectedTasks, nil)
+
+	tasks, err := t.tc.FilterByProjectId("1", map[string]string{"env": "prod", "estimate": "3", "labels": "ui"})
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), expectedTasks, tasks)
+}
+
+func (t *TaskUsecaseTest) TestFilterByProjectId_UnknownField() {
+	t.prm.On("GetById", "1").Return(model.Project{}, nil)
+	t.frm.On("GetByProjectId", "1").Return(taskFieldsTest, nil)
+
+	_, err := t.tc.FilterByProjectId("1", map[string]string{"color": "red"})
+
+	assert.EqualError(t.T(), err, "unknown custom field \"color\"")
+}
+
+func (t *TaskUsecaseTest) TestGetTaskById_ByKey() {
+	t.trm.On("GetByKey", "WEB-42").Return(expectedTask, nil)
+
+	task, err := t.tc.GetById("web-42")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), expectedTask, task)
+	t.trm.AssertNotCalled(t.T(), "GetById", "web-42")
+}
+
+func (t *TaskUsecaseTest) TestDeleteTask_ByKey() {
+	t.trm.On("GetByKey", "WEB-42").Return(expectedTask, nil)
+	t.trm.On("Delete", expectedTask.Id).Return(nil)
+
+	err := t.tc.Delete("WEB-42")
+
+	assert.NoError(t.T(), err)
+	t.trm.AssertExpectations(t.T())
+}
+
+func (t *TaskUsecaseTest) TestMoveTask_Success() {
+	moved := expectedTask
+	moved.ProjectId = "2"
+	t.trm.On("GetById", expectedTask.Id).Return(expectedTask, nil)
+	t.prm.On("GetById", "1").Return(model.Project{Id: "1", ManagerId: "manager"}, nil)
+	t.prm.On("GetById", "2").Return(model.Project{Id: "2", ManagerId: "manager"}, nil)
+	t.prm.On("GetByMemberId", expectedTask.PersonInCharge).Return([]model.Project{{Id: "2"}}, nil)
+	t.trm.On("MoveToProject", expectedTask.Id, "2").Return(moved, nil)
+
+	task, err := t.tc.MoveTask("manager", expectedTask.Id, "2")
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), "2", task.ProjectId)
+	assert.Len(t.T(), t.events, 1)
+	assert.Equal(t.T(), model.EventTaskMoved, t.events[0].Type)
+	assert.Equal(t.T(), "2", t.events[0].ProjectId)
+	assert.Equal(t.T(), "1", t.events[0].FromProjectId)
+}
+
+func (t *TaskUsecaseTest) TestMoveTask_AssigneeNotInTargetProject() {
+	t.trm.On("GetById", expectedTask.Id).Return(expectedTask, nil)
+	t.prm.On("GetById", "1").Return(model.Project{Id: "1", ManagerId: "manager"}, nil)
+	t.prm.On("GetById", "2").Return(model.Project{Id: "2", ManagerId: "manager"}, nil)
+	t.prm.On("GetByMemberId", expectedTask.PersonInCharge).Return([]model.Project{{Id: "1"}}, nil)
+
+	_, err := t.tc.MoveTask("manager", expectedTask.Id, "2")
+
+	assert.EqualError(t.T(), err, "failed to move task. person in charge is not a member of the target project")
+	t.trm.AssertNotCalled(t.T(), "MoveToProject", expectedTask.Id, "2")
+}
+
+func (t *TaskUsecaseTest) TestMoveTask_NotManagerOfTarget() {
+	t.trm.On("GetById", expectedTask.Id).Return(expectedTask, nil)
+	t.prm.On("GetById", "1").Return(model.Project{Id: "1", ManagerId: "manager"}, nil)
+	t.prm.On("GetById", "2").Return(model.Project{Id: "2", ManagerId: "other"}, nil)
+
+	_, err := t.tc.MoveTask("manager", expectedTask.Id, "2")
+
+	assert.True(t.T(), errors.Is(err, shared_model.ErrForbidden))
+}
+
+func (t *TaskUsecaseTest) TestCloneTask_DefaultsToSameProject() {
+	payload := model.TaskClone{CopyReports: true}
+	t.trm.On("GetById", expectedTask.Id).Return(expectedTask, nil)
+	t.prm.On("GetById", "1").Return(model.Project{Id: "1", ManagerId: "manager"}, nil)
+	t.prm.On("GetByMemberId", expectedTask.PersonInCharge).Return([]model.Project{{Id: "1"}}, nil)
+	t.trm.On("Clone", expectedTask.Id, model.TaskClone{ProjectId: "1", CopyReports: true}, "manager").Return(expectedTask, nil)
+
+	_, err := t.tc.CloneTask("manager", expectedTask.Id, payload)
+
+	assert.NoError(t.T(), err)
+	t.trm.AssertExpectations(t.T())
+	assert.Equal(t.T(), []string{model.EventTaskCreated, model.EventTaskAssigned}, []string{t.events[0].Type, t.events[1].Type})
+	assert.Equal(t.T(), "manager", t.events[0].ActorId)
+}
+
+func (t *TaskUsecaseTest) TestUpdateTaskByManager_RejectNotifiesPersonInCharge() {
+	previous := model.Task{Id: "3", Key: "WEB-3", Status: "Waiting Approval", PersonInCharge: "2", ProjectId: "1"}
+	payload := model.Task{Id: "3", Name: "task", Status: "Rejected", Feedback: "missing tests", PersonInCharge: "2", ProjectId: "1", Deadline: "2024-07-07"}
+	rejected := payload
+	rejected.Key = "WEB-3"
+	t.urm.On("GetById", "1").Return(model.User{Id: "1", Role: "MANAGER"}, nil)
+	t.urm.On("GetById", "2").Return(model.User{Id: "2", Role: "TEAM MEMBER"}, nil)
+	t.trm.On("GetById", "3").Return(previous, nil)
+	t.trm.On("UpdateTaskByManager", payload).Return(rejected, nil)
+
+	_, err := t.tc.UpdateTask("1", payload)
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), t.events, 2)
+	assert.Equal(t.T(), model.EventTaskStatusChanged, t.events[0].Type)
+	assert.Equal(t.T(), "Waiting Approval", t.events[0].Data["previous_status"])
+	assert.Equal(t.T(), model.EventTaskRejected, t.events[1].Type)
+	assert.Equal(t.T(), []string{"2"}, t.events[1].RecipientIds)
+	assert.Equal(t.T(), "missing tests", t.events[1].Data["feedback"])
+}
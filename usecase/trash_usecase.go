@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+type TrashUsecase interface {
+	FindDeleted(entity string, page int, size int) ([]model.TrashItem, shared_model.Paging, error)
+	Restore(userId string, entity string, id string) error
+	PurgeExpired() (model.PurgeResult, error)
+}
+
+type trashUsecase struct {
+	trashRepository repository.TrashRepository
+	retention       time.Duration
+}
+
+// FindDeleted implements TrashUsecase.
+func (t *trashUsecase) FindDeleted(entity string, page int, size int) ([]model.TrashItem, shared_model.Paging, error) {
+	if entity != "users" && entity != "projects" && entity != "tasks" && entity != "reports" {
+		return nil, shared_model.Paging{}, fmt.Errorf("invalid trash type. type: ('users', 'projects', 'tasks', 'reports')")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	return t.trashRepository.GetDeleted(entity, page, size)
+}
+
+// Restore implements TrashUsecase.
+func (t *trashUsecase) Restore(userId string, entity string, id string) error {
+	var err error
+	switch entity {
+	case "users":
+		err = t.trashRepository.RestoreUser(id)
+	case "projects":
+		err = t.trashRepository.RestoreProject(id, userId)
+	case "tasks":
+		err = t.trashRepository.RestoreTask(id)
+	case "reports":
+		err = t.trashRepository.RestoreReport(id, userId)
+	default:
+		return fmt.Errorf("invalid trash type. type: ('users', 'projects', 'tasks', 'reports')")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to restore %s. id %s not in trash or its parent is deleted", entity, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", entity, err)
+	}
+
+	log.Printf("Restore %s Successfully: %s", entity, id)
+	return nil
+}
+
+// PurgeExpired implements TrashUsecase.
+func (t *trashUsecase) PurgeExpired() (model.PurgeResult, error) {
+	before := time.Now().Add(-t.retention)
+	result, err := t.trashRepository.Purge(before)
+	if err != nil {
+		return model.PurgeResult{}, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	log.Printf("Purge trash before %s: %+v", before.Format(time.RFC3339), result)
+	return result, nil
+}
+
+func NewTrashUsecase(trashRepository repository.TrashRepository, retention time.Duration) TrashUsecase {
+	return &trashUsecase{
+		trashRepository: trashRepository,
+		retention:       retention,
+	}
+}
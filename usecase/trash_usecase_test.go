@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashUsecaseTest struct {
+	suite.Suite
+	trm *repository_mock.TrashRepositoryMock
+	tuc TrashUsecase
+}
+
+func (t *TrashUsecaseTest) SetupTest() {
+	t.trm = new(repository_mock.TrashRepositoryMock)
+	t.tuc = NewTrashUsecase(t.trm, 30*24*time.Hour)
+}
+
+func TestTrashUsecase(t *testing.T) {
+	suite.Run(t, new(TrashUsecaseTest))
+}
+
+func (t *TrashUsecaseTest) TestFindDeleted_DefaultsPaging() {
+	t.trm.On("GetDeleted", "users", 1, 10).Return([]model.TrashItem{{Id: "1"}}, shared_model.Paging{}, nil)
+
+	items, _, err := t.tuc.FindDeleted("users", 0, 0)
+
+	assert.NoError(t.T(), err)
+	assert.Len(t.T(), items, 1)
+	t.trm.AssertExpectations(t.T())
+}
+
+func (t *TrashUsecaseTest) TestFindDeleted_InvalidType() {
+	_, _, err := t.tuc.FindDeleted("comments", 1, 10)
+
+	assert.Error(t.T(), err)
+}
+
+func (t *TrashUsecaseTest) TestRestore_Project() {
+	t.trm.On("RestoreProject", "1", "2").Return(nil)
+
+	err := t.tuc.Restore("2", "projects", "1")
+
+	assert.NoError(t.T(), err)
+	t.trm.AssertExpectations(t.T())
+}
+
+func (t *TrashUsecaseTest) TestRestore_NotInTrash() {
+	t.trm.On("RestoreReport", "1", "2").Return(sql.ErrNoRows)
+
+	err := t.tuc.Restore("2", "reports", "1")
+
+	assert.Error(t.T(), err)
+	assert.Contains(t.T(), err.Error(), "not in trash")
+}
+
+func (t *TrashUsecaseTest) TestPurgeExpired_UsesRetention() {
+	t.trm.On("Purge", mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= 30*24*time.Hour
+	})).Return(model.PurgeResult{Users: 1}, nil)
+
+	result, err := t.tuc.PurgeExpired()
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), int64(1), result.Users)
+	t.trm.AssertExpectations(t.T())
+}
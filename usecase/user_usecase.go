@@ -15,7 +15,8 @@ type UserUseCase interface {
 	FindUserByEmail(email string) (model.User, error)
 	CreateUser(payload model.User) (model.User, error)
 	UpdateUser(payload model.User) (model.User, error)
-	DeleteUser(id string) error
+	DeleteUser(id string, reassign model.UserReassignment) error
+	DeleteUserPreview(id string, reassign model.UserReassignment) (model.DeleteImpact, error)
 }
 
 type userUseCase struct {
@@ -115,13 +116,13 @@ func (a *userUseCase) UpdateUser(payload model.User) (model.User, error) {
 	return user, nil
 }
 
-func (a *userUseCase) DeleteUser(id string) error {
+func (a *userUseCase) DeleteUser(id string, reassign model.UserReassignment) error {
 
-	if _, err := a.userRepository.GetById(id); err != nil {
-		return fmt.Errorf("failed to delete user. user id invalid")
+	if _, err := a.checkDelete(id, reassign); err != nil {
+		return err
 	}
 
-	err := a.userRepository.Delete(id)
+	err := a.userRepository.Delete(id, reassign)
 	if err != nil {
 
 		log.Println(err)
@@ -133,6 +134,54 @@ func (a *userUseCase) DeleteUser(id string) error {
 	return nil
 }
 
+func (a *userUseCase) DeleteUserPreview(id string, reassign model.UserReassignment) (model.DeleteImpact, error) {
+	return a.checkDelete(id, reassign)
+}
+
+// checkDelete validates that every task and project of the user has somewhere to go
+func (a *userUseCase) checkDelete(id string, reassign model.UserReassignment) (model.DeleteImpact, error) {
+
+	if _, err := a.userRepository.GetById(id); err != nil {
+		return model.DeleteImpact{}, fmt.Errorf("failed to delete user. user id invalid")
+	}
+
+	impact, err := a.userRepository.GetDeleteImpact(id)
+	if err != nil {
+		log.Println(err)
+		return model.DeleteImpact{}, err
+	}
+
+	if impact.Tasks > 0 && reassign.TasksTo == "" {
+		return impact, fmt.Errorf("failed to delete user. user still has %d task(s), reassign them with tasks_to", impact.Tasks)
+	}
+	if reassign.TasksTo != "" {
+		if reassign.TasksTo == id {
+			return impact, fmt.Errorf("failed to delete user. cannot reassign tasks to the deleted user")
+		}
+		if _, err := a.userRepository.GetById(reassign.TasksTo); err != nil {
+			return impact, fmt.Errorf("failed to delete user. tasks_to user id invalid")
+		}
+	}
+
+	if impact.Projects > 0 && reassign.ProjectsTo == "" {
+		return impact, fmt.Errorf("failed to delete user. user still manages %d project(s), reassign them with projects_to", impact.Projects)
+	}
+	if reassign.ProjectsTo != "" {
+		if reassign.ProjectsTo == id {
+			return impact, fmt.Errorf("failed to delete user. cannot reassign projects to the deleted user")
+		}
+		manager, err := a.userRepository.GetById(reassign.ProjectsTo)
+		if err != nil {
+			return impact, fmt.Errorf("failed to delete user. projects_to user id invalid")
+		}
+		if manager.Role != "MANAGER" {
+			return impact, fmt.Errorf("failed to delete user. projects_to user is not manager")
+		}
+	}
+
+	return impact, nil
+}
+
 func NewUserUseCase(userRepository repository.UserRepository) UserUseCase {
 	return &userUseCase{
 		userRepository: userRepository,
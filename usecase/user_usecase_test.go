@@ -106,17 +106,61 @@ func (a *UserUseCaseTest) TestFindUserByEmail_Failed() {
 func (a *UserUseCaseTest) TestDeleteUser_Success() {
 
 	a.urm.On("GetById", "1").Return(expectedUsers[0], nil)
-	a.urm.On("Delete", "1").Return(nil)
-	actual := a.uc.DeleteUser("1")
+	a.urm.On("GetDeleteImpact", "1").Return(model.DeleteImpact{}, nil)
+	a.urm.On("Delete", "1", model.UserReassignment{}).Return(nil)
+	actual := a.uc.DeleteUser("1", model.UserReassignment{})
 	a.NoError(actual)
 	a.urm.AssertExpectations(a.T())
 }
 
+// Test Delete User with tasks and projects reassigned
+func (a *UserUseCaseTest) TestDeleteUser_Reassign() {
+	reassign := model.UserReassignment{TasksTo: "2", ProjectsTo: "3"}
+	a.urm.On("GetById", "1").Return(expectedUsers[0], nil)
+	a.urm.On("GetDeleteImpact", "1").Return(model.DeleteImpact{Projects: 1, Tasks: 2}, nil)
+	a.urm.On("GetById", "2").Return(model.User{Id: "2", Role: "TEAM MEMBER"}, nil)
+	a.urm.On("GetById", "3").Return(model.User{Id: "3", Role: "MANAGER"}, nil)
+	a.urm.On("Delete", "1", reassign).Return(nil)
+	err := a.uc.DeleteUser("1", reassign)
+	a.NoError(err)
+	a.urm.AssertExpectations(a.T())
+}
+
+// Test Delete User refused while tasks are not reassigned
+func (a *UserUseCaseTest) TestDeleteUser_RequiresReassignment() {
+	a.urm.On("GetById", "1").Return(expectedUsers[0], nil)
+	a.urm.On("GetDeleteImpact", "1").Return(model.DeleteImpact{Tasks: 2}, nil)
+	err := a.uc.DeleteUser("1", model.UserReassignment{})
+	a.Error(err)
+	a.Contains(err.Error(), "tasks_to")
+	a.urm.AssertNotCalled(a.T(), "Delete", "1", model.UserReassignment{})
+}
+
+// Test Delete User refused when projects go to a non manager
+func (a *UserUseCaseTest) TestDeleteUser_ProjectsToNotManager() {
+	reassign := model.UserReassignment{ProjectsTo: "2"}
+	a.urm.On("GetById", "1").Return(expectedUsers[0], nil)
+	a.urm.On("GetDeleteImpact", "1").Return(model.DeleteImpact{Projects: 1}, nil)
+	a.urm.On("GetById", "2").Return(model.User{Id: "2", Role: "TEAM MEMBER"}, nil)
+	err := a.uc.DeleteUser("1", reassign)
+	a.Error(err)
+	a.Contains(err.Error(), "not manager")
+}
+
+// Test Delete User Preview
+func (a *UserUseCaseTest) TestDeleteUserPreview() {
+	a.urm.On("GetById", "1").Return(expectedUsers[0], nil)
+	a.urm.On("GetDeleteImpact", "1").Return(model.DeleteImpact{Members: 2}, nil)
+	impact, err := a.uc.DeleteUserPreview("1", model.UserReassignment{})
+	a.NoError(err)
+	a.Equal(2, impact.Members)
+	a.urm.AssertNotCalled(a.T(), "Delete", "1", model.UserReassignment{})
+}
+
 // Test Delete User Failed
 func (a *UserUseCaseTest) TestDeleteUser_Failed() {
 	a.urm.On("GetById", "1").Return(model.User{}, fmt.Errorf("user id not found"))
-	a.urm.On("Delete", "1").Return(fmt.Errorf("failed to delete user"))
-	err := a.uc.DeleteUser("1")
+	err := a.uc.DeleteUser("1", model.UserReassignment{})
 	a.Error(err)
 
 }
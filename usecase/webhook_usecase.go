@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/repository"
+	"enigma.com/projectmanagementhub/shared/service"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+)
+
+type WebhookUsecase interface {
+	Dispatch(event model.Event)
+	SendDue(now time.Time) (int, error)
+	CreateWebhook(userId string, payload model.Webhook) (model.Webhook, error)
+	GetWebhooks(userId string, projectId string) ([]model.Webhook, error)
+	UpdateWebhook(userId string, payload model.Webhook) (model.Webhook, error)
+	DeleteWebhook(userId string, id string) error
+	GetDeliveries(userId string, webhookId string, page int, size int) ([]model.WebhookDelivery, shared_model.Paging, error)
+	Redeliver(userId string, deliveryId string) (model.WebhookDelivery, error)
+}
+
+type webhookUsecase struct {
+	webhookRepository repository.WebhookRepository
+	projectRepository repository.ProjectRepository
+	userRepository    repository.UserRepository
+	client            service.WebhookClient
+	maxAttempts       int
+}
+
+const (
+	// webhookBatchSize caps the deliveries sent per run so one slow endpoint cannot stall the job
+	webhookBatchSize = 50
+	// webhookRetryDelay is the wait before the first retry, doubled after every failed attempt
+	// up to webhookMaxRetryDelay
+	webhookRetryDelay    = time.Minute
+	webhookMaxRetryDelay = 24 * time.Hour
+	// webhookClaimLease keeps a claimed batch from other instances, long enough for a whole batch
+	// to be sent; deliveries of an instance that dies mid-batch are picked up again after it
+	webhookClaimLease = 30 * time.Minute
+)
+
+// Dispatch implements WebhookUsecase.
+// It is subscribed to the event service and queues the event for every matching webhook;
+// the actual requests are made by SendDue.
+func (w *webhookUsecase) Dispatch(event model.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Println("webhookUsecase.Dispatch", err.Error())
+		return
+	}
+
+	if _, err := w.webhookRepository.Enqueue(event, payload); err != nil {
+		log.Println("webhookUsecase.Dispatch", err.Error())
+	}
+}
+
+// SendDue implements WebhookUsecase.
+// Any 2xx response marks the delivery successful. Other responses are retried with exponential
+// backoff until maxAttempts is reached, after which the delivery is marked failed.
+func (w *webhookUsecase) SendDue(now time.Time) (int, error) {
+	deliveries, err := w.webhookRepository.GetDueDeliveries(now, now.Add(webhookClaimLease), webhookBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhooks: %s", err.Error())
+	}
+
+	var sent int
+	for _, delivery := range deliveries {
+		code, err := w.client.Post(delivery.Url, delivery.Secret, delivery.EventType, delivery.Id, delivery.Payload)
+
+		delivery.Attempts++
+		delivery.LastStatusCode = code
+		delivery.LastError = ""
+		switch {
+		case err == nil && code >= 200 && code < 300:
+			delivered := now
+			delivery.Status = model.DeliverySuccess
+			delivery.DeliveredAt = &delivered
+			sent++
+		case delivery.Attempts >= w.maxAttempts:
+			delivery.Status = model.DeliveryFailed
+		default:
+			next := now.Add(webhookBackoff(delivery.Attempts))
+			delivery.NextAttemptAt = &next
+		}
+		if err != nil {
+			delivery.LastError = err.Error()
+		} else if delivery.Status != model.DeliverySuccess {
+			delivery.LastError = fmt.Sprintf("unexpected status %d", code)
+		}
+
+		if err := w.webhookRepository.SaveAttempt(delivery); err != nil {
+			log.Println("webhookUsecase.SendDue", err.Error())
+		}
+	}
+
+	return sent, nil
+}
+
+// webhookBackoff is the wait after the given number of failed attempts. It doubles instead of
+// shifting, so a high attempt limit cannot overflow the duration.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookRetryDelay
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetryDelay)
+}
+
+// CreateWebhook implements WebhookUsecase.
+// A secret is generated when none is given; it is only ever returned here.
+func (w *webhookUsecase) CreateWebhook(userId string, payload model.Webhook) (model.Webhook, error) {
+	if err := validateWebhook(payload); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to create webhook. %s", err.Error())
+	}
+
+	if err := w.checkWebhookAccess(userId, payload.ProjectId); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	if payload.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return model.Webhook{}, fmt.Errorf("failed to create webhook: %s", err.Error())
+		}
+		payload.Secret = hex.EncodeToString(secret)
+	}
+	payload.CreatedBy = userId
+
+	webhook, err := w.webhookRepository.Create(payload)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to create webhook: %s", err.Error())
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooks implements WebhookUsecase.
+// An empty projectId lists the global webhooks.
+func (w *webhookUsecase) GetWebhooks(userId string, projectId string) ([]model.Webhook, error) {
+	if err := w.checkWebhookAccess(userId, projectId); err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	webhooks, err := w.webhookRepository.GetByProjectId(projectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %s", err.Error())
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook implements WebhookUsecase.
+// The project and secret of a webhook cannot be changed.
+func (w *webhookUsecase) UpdateWebhook(userId string, payload model.Webhook) (model.Webhook, error) {
+	if err := validateWebhook(payload); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to update webhook. %s", err.Error())
+	}
+
+	if _, err := w.getOwnWebhook(userId, payload.Id); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	webhook, err := w.webhookRepository.Update(payload)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to update webhook: %s", err.Error())
+	}
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+// DeleteWebhook implements WebhookUsecase.
+func (w *webhookUsecase) DeleteWebhook(userId string, id string) error {
+	if _, err := w.getOwnWebhook(userId, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if err := w.webhookRepository.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %s", err.Error())
+	}
+
+	return nil
+}
+
+// GetDeliveries implements WebhookUsecase.
+func (w *webhookUsecase) GetDeliveries(userId string, webhookId string, page int, size int) ([]model.WebhookDelivery, shared_model.Paging, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	if _, err := w.getOwnWebhook(userId, webhookId); err != nil {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+
+	deliveries, paging, err := w.webhookRepository.GetDeliveries(webhookId, page, size)
+	if err != nil {
+		return nil, shared_model.Paging{}, fmt.Errorf("failed to get deliveries: %s", err.Error())
+	}
+
+	return deliveries, paging, nil
+}
+
+// Redeliver implements WebhookUsecase.
+// The original delivery is left untouched; its payload is queued again as a new delivery.
+func (w *webhookUsecase) Redeliver(userId string, deliveryId string) (model.WebhookDelivery, error) {
+	delivery, err := w.webhookRepository.GetDeliveryById(deliveryId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to redeliver. delivery id invalid")
+	}
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to redeliver: %s", err.Error())
+	}
+
+	if _, err := w.getOwnWebhook(userId, delivery.WebhookId); err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to redeliver: %w", err)
+	}
+
+	redelivery, err := w.webhookRepository.Redeliver(deliveryId)
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("failed to redeliver: %s", err.Error())
+	}
+
+	return redelivery, nil
+}
+
+// getOwnWebhook loads a webhook and checks that userId may manage it
+func (w *webhookUsecase) getOwnWebhook(userId string, id string) (model.Webhook, error) {
+	webhook, err := w.webhookRepository.GetById(id)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("invalid webhook id")
+	}
+
+	if err := w.checkWebhookAccess(userId, webhook.ProjectId); err != nil {
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// checkWebhookAccess lets admins manage every webhook and project managers the webhooks of their own projects
+func (w *webhookUsecase) checkWebhookAccess(userId string, projectId string) error {
+	user, err := w.userRepository.GetById(userId)
+	if err != nil {
+		return fmt.Errorf("invalid user id")
+	}
+	if user.Role == "ADMIN" {
+		return nil
+	}
+	if projectId == "" {
+		return shared_model.ErrForbidden
+	}
+
+	return checkProjectManager(w.projectRepository, projectId, userId)
+}
+
+// validateWebhook checks the url and the subscribed event types
+func validateWebhook(payload model.Webhook) error {
+	target, err := url.Parse(payload.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https url")
+	}
+	// names are checked again when the client connects, see service.NewWebhookClient
+	host := strings.ToLower(target.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("url must not point to a loopback, private or link-local address")
+	}
+	if ip := net.ParseIP(host); ip != nil && service.BlockedWebhookIP(ip) {
+		return fmt.Errorf("url must not point to a loopback, private or link-local address")
+	}
+
+	for _, eventType := range payload.EventTypes {
+		if !slices.Contains(model.EventTypes, eventType) {
+			return fmt.Errorf("unknown event type %s", eventType)
+		}
+	}
+
+	return nil
+}
+
+func NewWebhookUsecase(webhookRepository repository.WebhookRepository, projectRepository repository.ProjectRepository, userRepository repository.UserRepository, client service.WebhookClient, maxAttempts int) WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepository: webhookRepository,
+		projectRepository: projectRepository,
+		userRepository:    userRepository,
+		client:            client,
+		maxAttempts:       maxAttempts,
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"enigma.com/projectmanagementhub/mock/repository_mock"
+	"enigma.com/projectmanagementhub/mock/service_mock"
+	"enigma.com/projectmanagementhub/model"
+	"enigma.com/projectmanagementhub/shared/shared_model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookUsecaseTest struct {
+	suite.Suite
+	wrm *repository_mock.WebhookRepositoryMock
+	prm *repository_mock.ProjectRepositoryMock
+	urm *repository_mock.UserRepositoryMock
+	wcm *service_mock.WebhookClientMock
+	wuc WebhookUsecase
+}
+
+func (w *WebhookUsecaseTest) SetupTest() {
+	w.wrm = new(repository_mock.WebhookRepositoryMock)
+	w.prm = new(repository_mock.ProjectRepositoryMock)
+	w.urm = new(repository_mock.UserRepositoryMock)
+	w.wcm = new(service_mock.WebhookClientMock)
+	w.wuc = NewWebhookUsecase(w.wrm, w.prm, w.urm, w.wcm, 3)
+}
+
+func TestWebhookUsecase(t *testing.T) {
+	suite.Run(t, new(WebhookUsecaseTest))
+}
+
+func (w *WebhookUsecaseTest) TestDispatch_QueuesEventJson() {
+	event := model.Event{Type: model.EventTaskCreated, ProjectId: "p1", TaskId: "t1", Data: model.EventData{"key": "WEB-1"}}
+	payload, _ := json.Marshal(event)
+	w.wrm.On("Enqueue", event, payload).Return(int64(1), nil)
+
+	w.wuc.Dispatch(event)
+
+	w.wrm.AssertExpectations(w.T())
+}
+
+func (w *WebhookUsecaseTest) TestSendDue_SuccessRetryAndGiveUp() {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	deliveries := []model.WebhookDelivery{
+		{Id: "d1", EventType: model.EventTaskCreated, Payload: []byte(`{}`), Status: model.DeliveryPending, Url: "https://a.example.com", Secret: "s"},
+		{Id: "d2", EventType: model.EventTaskCreated, Payload: []byte(`{}`), Status: model.DeliveryPending, Attempts: 1, Url: "https://b.example.com", Secret: "s"},
+		{Id: "d3", EventType: model.EventTaskCreated, Payload: []byte(`{}`), Status: model.DeliveryPending, Attempts: 2, Url: "https://c.example.com", Secret: "s"},
+	}
+	w.wrm.On("GetDueDeliveries", now, now.Add(webhookClaimLease), webhookBatchSize).Return(deliveries, nil)
+	w.wcm.On("Post", "https://a.example.com", "s", model.EventTaskCreated, "d1", []byte(`{}`)).Return(204, nil)
+	w.wcm.On("Post", "https://b.example.com", "s", model.EventTaskCreated, "d2", []byte(`{}`)).Return(500, nil)
+	w.wcm.On("Post", "https://c.example.com", "s", model.EventTaskCreated, "d3", []byte(`{}`)).Return(0, errors.New("connection refused"))
+
+	var saved []model.WebhookDelivery
+	w.wrm.On("SaveAttempt", mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(0).(model.WebhookDelivery))
+	}).Return(nil)
+
+	sent, err := w.wuc.SendDue(now)
+
+	assert.NoError(w.T(), err)
+	assert.Equal(w.T(), 1, sent)
+	assert.Len(w.T(), saved, 3)
+
+	assert.Equal(w.T(), model.DeliverySuccess, saved[0].Status)
+	assert.Equal(w.T(), now, *saved[0].DeliveredAt)
+
+	assert.Equal(w.T(), model.DeliveryPending, saved[1].Status)
+	assert.Equal(w.T(), 2, saved[1].Attempts)
+	assert.Equal(w.T(), now.Add(2*time.Minute), *saved[1].NextAttemptAt)
+	assert.Equal(w.T(), "unexpected status 500", saved[1].LastError)
+
+	assert.Equal(w.T(), model.DeliveryFailed, saved[2].Status)
+	assert.Equal(w.T(), "connection refused", saved[2].LastError)
+}
+
+func (w *WebhookUsecaseTest) TestWebhookBackoff_Capped() {
+	assert.Equal(w.T(), time.Minute, webhookBackoff(1))
+	assert.Equal(w.T(), 8*time.Minute, webhookBackoff(4))
+	assert.Equal(w.T(), webhookMaxRetryDelay, webhookBackoff(12))
+	assert.Equal(w.T(), webhookMaxRetryDelay, webhookBackoff(100))
+}
+
+func (w *WebhookUsecaseTest) TestCreateWebhook_GeneratesSecret() {
+	payload := model.Webhook{ProjectId: "p1", Url: "https://ci.example.com/hook", EventTypes: []string{model.EventTaskCreated}, Active: true}
+	w.urm.On("GetById", "m1").Return(model.User{Id: "m1", Role: "MANAGER"}, nil)
+	w.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+	w.wrm.On("Create", mock.MatchedBy(func(webhook model.Webhook) bool {
+		return len(webhook.Secret) == 64 && webhook.CreatedBy == "m1"
+	})).Return(model.Webhook{Id: "w1", ProjectId: "p1", Secret: "generated"}, nil)
+
+	webhook, err := w.wuc.CreateWebhook("m1", payload)
+
+	assert.NoError(w.T(), err)
+	assert.Equal(w.T(), "generated", webhook.Secret)
+}
+
+func (w *WebhookUsecaseTest) TestCreateWebhook_InvalidInput() {
+	_, err := w.wuc.CreateWebhook("m1", model.Webhook{ProjectId: "p1", Url: "ftp://example.com"})
+	assert.Error(w.T(), err)
+
+	_, err = w.wuc.CreateWebhook("m1", model.Webhook{ProjectId: "p1", Url: "https://example.com", EventTypes: []string{"task.exploded"}})
+	assert.ErrorContains(w.T(), err, "unknown event type")
+}
+
+func (w *WebhookUsecaseTest) TestCreateWebhook_InternalAddress() {
+	for _, target := range []string{"http://127.0.0.1:8080/hook", "http://localhost/hook", "http://10.0.0.5/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]/hook"} {
+		_, err := w.wuc.CreateWebhook("m1", model.Webhook{ProjectId: "p1", Url: target})
+		assert.ErrorContains(w.T(), err, "loopback, private or link-local", target)
+	}
+	w.wrm.AssertNotCalled(w.T(), "Create", mock.Anything)
+}
+
+func (w *WebhookUsecaseTest) TestCreateWebhook_GlobalRequiresAdmin() {
+	w.urm.On("GetById", "m1").Return(model.User{Id: "m1", Role: "MANAGER"}, nil)
+
+	_, err := w.wuc.CreateWebhook("m1", model.Webhook{Url: "https://example.com"})
+
+	assert.ErrorIs(w.T(), err, shared_model.ErrForbidden)
+	w.wrm.AssertNotCalled(w.T(), "Create", mock.Anything)
+}
+
+func (w *WebhookUsecaseTest) TestGetWebhooks_HidesSecrets() {
+	w.urm.On("GetById", "a1").Return(model.User{Id: "a1", Role: "ADMIN"}, nil)
+	w.wrm.On("GetByProjectId", "").Return([]model.Webhook{{Id: "w1", Secret: "s"}}, nil)
+
+	webhooks, err := w.wuc.GetWebhooks("a1", "")
+
+	assert.NoError(w.T(), err)
+	assert.Empty(w.T(), webhooks[0].Secret)
+}
+
+func (w *WebhookUsecaseTest) TestRedeliver_ForbiddenForOtherManager() {
+	w.wrm.On("GetDeliveryById", "d1").Return(model.WebhookDelivery{Id: "d1", WebhookId: "w1"}, nil)
+	w.wrm.On("GetById", "w1").Return(model.Webhook{Id: "w1", ProjectId: "p1"}, nil)
+	w.urm.On("GetById", "m2").Return(model.User{Id: "m2", Role: "MANAGER"}, nil)
+	w.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+
+	_, err := w.wuc.Redeliver("m2", "d1")
+
+	assert.ErrorIs(w.T(), err, shared_model.ErrForbidden)
+	w.wrm.AssertNotCalled(w.T(), "Redeliver", mock.Anything)
+}
+
+func (w *WebhookUsecaseTest) TestRedeliver_Success() {
+	w.wrm.On("GetDeliveryById", "d1").Return(model.WebhookDelivery{Id: "d1", WebhookId: "w1"}, nil)
+	w.wrm.On("GetById", "w1").Return(model.Webhook{Id: "w1", ProjectId: "p1"}, nil)
+	w.urm.On("GetById", "m1").Return(model.User{Id: "m1", Role: "MANAGER"}, nil)
+	w.prm.On("GetById", "p1").Return(model.Project{Id: "p1", ManagerId: "m1"}, nil)
+	w.wrm.On("Redeliver", "d1").Return(model.WebhookDelivery{Id: "d2", WebhookId: "w1", Status: model.DeliveryPending}, nil)
+
+	delivery, err := w.wuc.Redeliver("m1", "d1")
+
+	assert.NoError(w.T(), err)
+	assert.Equal(w.T(), "d2", delivery.Id)
+}